@@ -80,6 +80,200 @@ const docTemplate = `{
                 }
             }
         },
+        "/attachments/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the attached file, only participants of the event can download it",
+                "produces": [
+                    "image/jpeg",
+                    "image/png",
+                    "image/gif",
+                    "image/webp",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "Download an attachment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Attachment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The attached file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a participant of the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Attachment not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to read the file",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove an attached file. The uploader, organizers and co-organizers can delete it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "Delete an attachment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Attachment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Attachment deleted",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - neither the uploader nor an organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Attachment not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete attachment",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/attachments/{id}/thumbnail": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a JPEG preview of an attached image that fits into 256x256 pixels. Documents have no thumbnail",
+                "produces": [
+                    "image/jpeg"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "Download an attachment thumbnail",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Attachment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The thumbnail",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a participant of the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Attachment or thumbnail not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to read the file",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/auth/google": {
             "get": {
                 "security": [
@@ -266,6 +460,58 @@ const docTemplate = `{
                 }
             }
         },
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new access token. The refresh token is rotated, the old one can not be used again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Refresh access token",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New token pair",
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid, reused or expired refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/auth/web-to-app": {
             "get": {
                 "description": "Redirects from web OAuth flow to mobile app via deeplink",
@@ -301,61 +547,73 @@ const docTemplate = `{
                 }
             }
         },
-        "/calendar/import": {
+        "/availability": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Import events from the user's Google Calendar for the next 4 weeks",
+                "description": "Get the weekly unavailable and preferred windows and the one-off blocks of the authenticated user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "calendar"
+                    "availability"
                 ],
-                "summary": "Import Google Calendar events",
+                "summary": "Get user's availability",
                 "responses": {
                     "200": {
-                        "description": "Events imported successfully",
+                        "description": "Availability retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/api.ImportCalendarEventsResponse"
+                            "$ref": "#/definitions/api.AvailabilitiesResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized or no token found",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to import events",
+                        "description": "Failed to retrieve availability",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     }
                 }
-            }
-        },
-        "/events": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all events where the user is a participant",
+                "description": "Add a weekly unavailable or preferred window, or a one-off block. Best time searches treat unavailable windows and blocks as busy and prefer slots inside preferred windows",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "availability"
+                ],
+                "summary": "Declare availability",
+                "parameters": [
+                    {
+                        "description": "Availability details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.AvailabilityRequest"
+                        }
+                    }
                 ],
-                "summary": "Get user's events",
                 "responses": {
                     "200": {
-                        "description": "Events retrieved successfully",
+                        "description": "Availability created successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -365,31 +623,42 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/api.EventResponse"
-                                            }
+                                            "$ref": "#/definitions/api.AvailabilityResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Failed to create availability",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/availability/{id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new event with the given details",
+                "description": "Replace an availability entry of the authenticated user",
                 "consumes": [
                     "application/json"
                 ],
@@ -397,23 +666,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "availability"
                 ],
-                "summary": "Create a new event",
+                "summary": "Update availability",
                 "parameters": [
                     {
-                        "description": "Event creation details",
+                        "type": "integer",
+                        "description": "Availability ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Availability details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.CreateEventRequest"
+                            "$ref": "#/definitions/api.AvailabilityRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Event created successfully",
+                        "description": "Availability updated successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -423,7 +699,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/api.EventResponse"
+                                            "$ref": "#/definitions/api.AvailabilityResponse"
                                         }
                                     }
                                 }
@@ -442,65 +718,70 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
+                    "404": {
+                        "description": "Availability not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
                     "500": {
-                        "description": "Failed to create event",
+                        "description": "Failed to update availability",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     }
                 }
-            }
-        },
-        "/events/find_best_time_for_day": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Find the best available time slots for an event based on participants' schedules and specified time range",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Delete an availability entry of the authenticated user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "availability"
                 ],
-                "summary": "Find best time slots for an event",
+                "summary": "Delete availability",
                 "parameters": [
                     {
-                        "description": "Find best time slots request details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.FindBestTimeSlotsRequest"
-                        }
+                        "type": "integer",
+                        "description": "Availability ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Time slots found successfully",
+                        "description": "Availability deleted successfully",
                         "schema": {
-                            "$ref": "#/definitions/api.FindBestTimeSlotsResponse"
+                            "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Invalid availability ID",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - not a participant of the event",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "404": {
-                        "description": "Event not found or no participants",
+                        "description": "Availability not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete availability",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -508,56 +789,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/events/invite": {
-            "post": {
+        "/calendar/import": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Generate a unique invite link for an event",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Import events from the user's Google Calendar for the next 4 weeks",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "invitations"
-                ],
-                "summary": "Generate event invite link",
-                "parameters": [
-                    {
-                        "description": "Event ID",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.GenerateInviteLinkRequest"
-                        }
-                    }
+                    "calendar"
                 ],
+                "summary": "Import Google Calendar events",
                 "responses": {
                     "200": {
-                        "description": "Invite link generated successfully",
-                        "schema": {
-                            "$ref": "#/definitions/api.GenerateInviteLinkResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid payload",
+                        "description": "Events imported successfully",
                         "schema": {
-                            "$ref": "#/definitions/api.APIResponse"
+                            "$ref": "#/definitions/api.ImportCalendarEventsResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Unauthorized or no token found",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
-                    "404": {
-                        "description": "Event not found",
+                    "500": {
+                        "description": "Failed to import events",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -565,44 +826,59 @@ const docTemplate = `{
                 }
             }
         },
-        "/events/join/{invite_code}": {
+        "/events": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Join an event using a unique invite code",
+                "description": "Get all events where the user is a participant. Recurring events are expanded into their occurrences\nin the given range, which defaults to the next 90 days",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "invitations"
+                    "events"
                 ],
-                "summary": "Join event using invite link",
+                "summary": "Get user's events",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Invite Code from path",
-                        "name": "invite_code",
-                        "in": "path"
+                        "description": "Start of the range for recurring events, RFC3339",
+                        "name": "from",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Invite Code from query parameter",
-                        "name": "code",
+                        "description": "End of the range for recurring events, RFC3339",
+                        "name": "to",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully joined event",
+                        "description": "Events retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/api.JoinEventResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/api.EventResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Already a participant",
+                        "description": "Invalid range",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -612,78 +888,40 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
-                    },
-                    "404": {
-                        "description": "Invalid invite link",
-                        "schema": {
-                            "$ref": "#/definitions/api.APIResponse"
-                        }
                     }
                 }
-            }
-        },
-        "/events/redirect/{invite_code}": {
-            "get": {
-                "description": "Redirects from web browser to iOS app deeplink for event joining",
-                "produces": [
-                    "text/html"
-                ],
-                "tags": [
-                    "invitations"
-                ],
-                "summary": "Redirect to iOS app deeplink",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Invite Code",
-                        "name": "invite_code",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "302": {
-                        "description": "Redirect to iOS app",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "404": {
-                        "description": "Invalid invite link",
-                        "schema": {
-                            "$ref": "#/definitions/api.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/events/{id}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get detailed information about a specific event",
+                "description": "Create a new event with the given details",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "events"
                 ],
-                "summary": "Get event details",
+                "summary": "Create a new event",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Event ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Event creation details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateEventRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Event details retrieved successfully",
+                        "description": "Event created successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -700,33 +938,35 @@ const docTemplate = `{
                             ]
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Invalid payload",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - not a participant of the event",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
-                    "404": {
-                        "description": "Event not found",
+                    "500": {
+                        "description": "Failed to create event",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/events/find_best_time": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing event's details",
+                "description": "Rank start times of every day in the range by busy required participants and weighted busyness, with working hours per weekday",
                 "consumes": [
                     "application/json"
                 ],
@@ -736,77 +976,54 @@ const docTemplate = `{
                 "tags": [
                     "events"
                 ],
-                "summary": "Update an event",
+                "summary": "Find best time slots across a date range",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Event ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Event update details",
+                        "description": "Find best time request details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.UpdateEventRequest"
+                            "$ref": "#/definitions/api.FindBestTimeRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Event updated successfully",
+                        "description": "Time slots found successfully",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/api.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/api.EventResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/api.FindBestTimeSlotsResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid payload",
-                        "schema": {
-                            "$ref": "#/definitions/api.APIResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "403": {
-                        "description": "Forbidden - not the organizer",
+                        "description": "Forbidden - not a participant of the event",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "404": {
-                        "description": "Event not found",
+                        "description": "Event not found or no participants",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/events/find_best_time_for_day": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete an event and all associated data (tasks, event scores, participations, invitations)",
+                "description": "Find the best available time slots for an event based on participants' schedules and specified time range.\nEvery candidate is checked over its whole duration, slots that suit all required participants come first",
                 "consumes": [
                     "application/json"
                 ],
@@ -816,43 +1033,39 @@ const docTemplate = `{
                 "tags": [
                     "events"
                 ],
-                "summary": "Delete an event",
+                "summary": "Find best time slots for an event",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Event ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Find best time slots request details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.FindBestTimeSlotsRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Event deleted successfully",
+                        "description": "Time slots found successfully",
                         "schema": {
-                            "$ref": "#/definitions/api.APIResponse"
+                            "$ref": "#/definitions/api.FindBestTimeSlotsResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "403": {
-                        "description": "Forbidden - not the organizer",
+                        "description": "Forbidden - not a participant of the event",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "404": {
-                        "description": "Event not found",
-                        "schema": {
-                            "$ref": "#/definitions/api.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Failed to delete event",
+                        "description": "Event not found or no participants",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -860,35 +1073,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/events/{id}/budget": {
-            "get": {
+        "/events/invite": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the budget details for an event, including initial budget, real budget, and difference",
+                "description": "Generate a unique invite link for an event, optionally limited in time and number of uses",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "invitations"
                 ],
-                "summary": "Get event budget details",
+                "summary": "Generate event invite link",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Event ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Event ID, optional expiry and usage limit",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.GenerateInviteLinkRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Budget details retrieved successfully",
+                        "description": "Invite link generated successfully",
                         "schema": {
-                            "$ref": "#/definitions/api.EventBudgetResponse"
+                            "$ref": "#/definitions/api.GenerateInviteLinkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload, expiry or usage limit",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "401": {
@@ -897,6 +1121,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden - not allowed to invite to the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
                     "404": {
                         "description": "Event not found",
                         "schema": {
@@ -906,35 +1136,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/events/{id}/leaderboard": {
-            "get": {
+        "/events/invite/{invite_code}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the leaderboard for an event",
+                "description": "Revoke an invite link so nobody can join with it anymore. Allowed for the creator of the link and event organizers",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "invitations"
                 ],
-                "summary": "Get event leaderboard",
+                "summary": "Revoke an invite link",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Event ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Invite Code",
+                        "name": "invite_code",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Leaderboard retrieved successfully",
+                        "description": "Invite link revoked",
                         "schema": {
-                            "$ref": "#/definitions/api.EventLeaderboardResponse"
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invite link already revoked",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "401": {
@@ -944,13 +1180,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Not a participant",
+                        "description": "Forbidden - not allowed to revoke the link",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "404": {
-                        "description": "Leaderboard not found",
+                        "description": "Invalid invite link",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to revoke invite link",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -958,39 +1200,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/events/{id}/leave": {
-            "delete": {
+        "/events/join/{invite_code}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Allow a user to leave an event they are participating in",
+                "description": "Join an event using a unique invite code. Links that require approval create a pending join request instead, a full event puts the user on its waitlist",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "invitations"
                 ],
-                "summary": "Leave an event",
+                "summary": "Join event using invite link",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Event ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Invite Code from path",
+                        "name": "invite_code",
+                        "in": "path"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Invite Code from query parameter",
+                        "name": "code",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully left event",
+                        "description": "Successfully joined event",
                         "schema": {
-                            "$ref": "#/definitions/api.APIResponse"
+                            "$ref": "#/definitions/api.JoinEventResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Join request sent or added to the waitlist",
+                        "schema": {
+                            "$ref": "#/definitions/api.JoinEventResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid event ID",
+                        "description": "Already a participant, waitlisted or join request pending",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -1002,13 +1255,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Cannot leave - you are the organizer",
+                        "description": "Personal invitation sent to another email or join request rejected",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "404": {
-                        "description": "Not a participant or event not found",
+                        "description": "Invalid invite link",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Invite link expired, used up or revoked",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -1016,24 +1275,62 @@ const docTemplate = `{
                 }
             }
         },
-        "/events/{id}/participants": {
+        "/events/redirect/{invite_code}": {
+            "get": {
+                "description": "Redirects from web browser to iOS app deeplink for event joining",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "invitations"
+                ],
+                "summary": "Redirect to iOS app deeplink",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invite Code",
+                        "name": "invite_code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Redirect to iOS app",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invalid invite link",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Invite link expired, used up or revoked",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of display names of all participants in an event",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get detailed information about a specific event including RSVP counts",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "events"
                 ],
-                "summary": "Get event participants",
+                "summary": "Get event details",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1045,15 +1342,21 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of participants' display names",
-                        "schema": {
-                            "$ref": "#/definitions/api.EventParticipantsResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid event ID",
+                        "description": "Event details retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/api.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.EventResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "401": {
@@ -1075,11 +1378,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/login": {
-            "post": {
-                "description": "Authenticate a user and return a JWT token",
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing event's details. For recurring events the scope selects whether one occurrence,\nthe occurrence and the following ones or the whole series changes",
                 "consumes": [
                     "application/json"
                 ],
@@ -1087,112 +1393,148 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "events"
                 ],
-                "summary": "User login",
+                "summary": "Update an event",
                 "parameters": [
                     {
-                        "description": "User login credentials",
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Event update details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.LoginRequest"
+                            "$ref": "#/definitions/api.UpdateEventRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login successful",
+                        "description": "Event updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/api.LoginResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.EventResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Invalid payload",
+                        "description": "Invalid payload, scope or occurrence",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid credentials",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
-                    "500": {
-                        "description": "Failed to generate token",
+                    "403": {
+                        "description": "Forbidden - not an organizer or co-organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     }
                 }
-            }
-        },
-        "/logout": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Logout the current user (client-side token removal)",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "User logout",
-                "responses": {
-                    "200": {
-                        "description": "Logged out successfully",
-                        "schema": {
-                            "$ref": "#/definitions/api.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/password/reset": {
-            "post": {
-                "description": "Reset user password using a valid reset token",
-                "consumes": [
+                "description": "Delete an event and all associated data (tasks, event scores, participations, invitations).\nFor recurring events the scope \"this\" cancels one occurrence and \"following\" ends the series before it",
+                "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "events"
                 ],
-                "summary": "Reset password",
+                "summary": "Delete an event",
                 "parameters": [
                     {
-                        "description": "Reset token and new password",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.ResetPasswordRequest"
-                        }
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "this",
+                            "following",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Scope for recurring events",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Occurrence for the this and following scopes, RFC3339",
+                        "name": "occurrence_time",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Password reset successfully",
+                        "description": "Event deleted successfully",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid payload or token",
+                        "description": "Invalid scope or occurrence",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Failed to delete event",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -1200,74 +1542,121 @@ const docTemplate = `{
                 }
             }
         },
-        "/password/reset-redirect": {
+        "/events/{id}/balances": {
             "get": {
-                "description": "Redirect password reset requests to mobile app with deeplink",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get what everybody paid and owes for the expenses of the event, the payments made between participants\nand the fewest transfers that settle the rest. A positive net means the participant gets money back.\nAmounts are in the event currency, expenses and payments in other currencies are converted at the current rate",
                 "produces": [
-                    "text/html"
+                    "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "budget"
                 ],
-                "summary": "Handle password reset redirect to mobile app",
+                "summary": "Get event balances",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Reset token",
-                        "name": "token",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "302": {
-                        "description": "Redirected to mobile app",
+                    "200": {
+                        "description": "Balances retrieved successfully",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/api.BalancesResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid token",
+                        "description": "Invalid ID",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a participant of the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve balances",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
                         }
                     }
                 }
             }
         },
-        "/password/reset-request": {
-            "post": {
-                "description": "Request a password reset token for a user",
-                "consumes": [
-                    "application/json"
+        "/events/{id}/budget": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Get the budget details for an event, including initial budget, real budget, and difference,\nwith planned budget against recorded expenses per task and per category.\nAmounts are in the event currency, expenses in other currencies are converted at the current exchange rate.\nBudget categories report their limit and what remains of it, over_budget warns when a category or the event is over its limit",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "events"
                 ],
-                "summary": "Request password reset",
+                "summary": "Get event budget details",
                 "parameters": [
                     {
-                        "description": "User email",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.PasswordResetRequest"
-                        }
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Reset token generated",
+                        "description": "Budget details retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/api.PasswordResetResponse"
+                            "$ref": "#/definitions/api.EventBudgetResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid payload",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a participant of the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve expenses or budget categories",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -1275,24 +1664,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/profile": {
+        "/events/{id}/budget/categories": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the authenticated user's profile information",
+                "description": "Get the budget categories of the event with how much of the initial budget is allocated to them",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "profile"
+                    "budget"
+                ],
+                "summary": "Get budget categories",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get user profile",
                 "responses": {
                     "200": {
-                        "description": "User profile",
+                        "description": "Budget categories retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.BudgetCategoriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -1303,21 +1707,33 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden - not a participant of the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
                     "404": {
-                        "description": "User not found",
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve budget categories",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update the authenticated user's profile information",
+                "description": "Set aside part of the initial budget for tasks and expenses of a category. Only organizers and co-organizers can do it",
                 "consumes": [
                     "application/json"
                 ],
@@ -1325,29 +1741,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "profile"
+                    "budget"
                 ],
-                "summary": "Update user profile",
+                "summary": "Create a budget category",
                 "parameters": [
                     {
-                        "description": "Profile update data",
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Budget category details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.ProfileUpdateRequest"
+                            "$ref": "#/definitions/api.BudgetCategoryRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Profile updated successfully",
+                        "description": "Budget category created successfully",
                         "schema": {
-                            "$ref": "#/definitions/api.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.BudgetCategoryResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Invalid payload",
+                        "description": "Invalid payload or limits exceed the initial budget",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -1358,8 +1793,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden - not an organizer or co-organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
                     "404": {
-                        "description": "User not found",
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create budget category",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -1367,9 +1814,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/register": {
-            "post": {
-                "description": "Register a new user with email and password",
+        "/events/{id}/budget/categories/{category_id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the name and limit of a budget category, renaming it renames the category of its tasks and expenses",
                 "consumes": [
                     "application/json"
                 ],
@@ -1377,62 +1829,126 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "budget"
                 ],
-                "summary": "Register a new user",
+                "summary": "Update a budget category",
                 "parameters": [
                     {
-                        "description": "User registration details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.RegisterRequest"
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Budget category ID",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Budget category details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.BudgetCategoryRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User registered successfully",
+                        "description": "Budget category updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/api.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.BudgetCategoryResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Invalid payload",
+                        "description": "Invalid payload or limits exceed the initial budget",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not an organizer or co-organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event or budget category not found",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to hash password",
+                        "description": "Failed to update budget category",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     }
                 }
-            }
-        },
-        "/task-status-events/unread": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all unread task status events for the authenticated user",
+                "description": "Remove the limit of a category, its tasks and expenses keep their category",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "budget"
+                ],
+                "summary": "Delete a budget category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Budget category ID",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get unread task status events",
                 "responses": {
                     "200": {
-                        "description": "Unread task status events retrieved successfully",
+                        "description": "Budget category deleted successfully",
                         "schema": {
-                            "$ref": "#/definitions/api.TaskStatusEventsResponse"
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "401": {
@@ -1441,8 +1957,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden - not an organizer or co-organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event or budget category not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Failed to delete budget category",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -1450,54 +1978,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks": {
+        "/events/{id}/budget/export": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of all tasks associated with a specific event",
+                "description": "Download a report of the event budget for spreadsheets or printing: a summary, every task with its category,\nassignee, status, points, planned budget and spending, and the budget categories, with totals.\nAmounts are in the event currency. PDF reports are A4 landscape",
                 "produces": [
-                    "application/json"
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
                 ],
                 "tags": [
-                    "tasks"
+                    "events"
                 ],
-                "summary": "Get all tasks for an event",
+                "summary": "Export the event budget",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "Event ID",
-                        "name": "event_id",
-                        "in": "query",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Report format: csv, xlsx or pdf, csv by default",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of tasks retrieved successfully",
+                        "description": "The budget report",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/api.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/api.TaskResponse"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Invalid event ID",
+                        "description": "Invalid format",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -1519,16 +2040,24 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Failed to generate report",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/events/{id}/clone": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new task for an event",
+                "description": "Copy an event with its tasks. The copy belongs to the caller, tasks are unassigned and not completed",
                 "consumes": [
                     "application/json"
                 ],
@@ -1536,23 +2065,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "events"
                 ],
-                "summary": "Create a new task",
+                "summary": "Clone an event",
                 "parameters": [
                     {
-                        "description": "Task creation details",
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Clone options",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.CreateTaskRequest"
+                            "$ref": "#/definitions/api.CloneEventRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Task created successfully",
+                        "description": "Event cloned successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -1562,7 +2097,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/api.TaskResponse"
+                                            "$ref": "#/definitions/api.EventResponse"
                                         }
                                     }
                                 }
@@ -1581,8 +2116,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden - not an organizer or co-organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
                     "500": {
-                        "description": "Failed to create task",
+                        "description": "Failed to clone event",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -1590,51 +2137,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks/{id}": {
+        "/events/{id}/expenses": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get detailed information about a specific task",
+                "description": "Get the expenses of the event, latest first, optionally only those of a task. The total is in the event currency",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "budget"
                 ],
-                "summary": "Get task details",
+                "summary": "Get event expenses",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Task ID",
+                        "description": "Event ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "task_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Task details retrieved successfully",
+                        "description": "Expenses retrieved successfully",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/api.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/api.TaskResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/api.ExpensesResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid task ID",
+                        "description": "Invalid ID",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -1652,20 +2193,26 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Task not found",
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve expenses",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update details of an existing task",
+                "description": "Record money spent for the event, optionally for one of its tasks. The payer is the requesting user unless given.\nThe expense is split equally, by shares or by exact amounts, by default equally among everybody in the event except viewers.\nIt is in the event currency unless another one with a known exchange rate is given",
                 "consumes": [
                     "application/json"
                 ],
@@ -1673,30 +2220,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "budget"
                 ],
-                "summary": "Update task details",
+                "summary": "Record an expense",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Task ID",
+                        "description": "Event ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Task update details",
+                        "description": "Expense details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.UpdateTaskRequest"
+                            "$ref": "#/definitions/api.ExpenseRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Task updated successfully",
+                        "description": "Expense recorded successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -1706,7 +2253,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/api.TaskResponse"
+                                            "$ref": "#/definitions/api.ExpenseResponse"
                                         }
                                     }
                                 }
@@ -1726,26 +2273,34 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - not a participant of the event or not the organizer",
+                        "description": "Forbidden - viewers can't record expenses",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "404": {
-                        "description": "Task not found",
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to record expense",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/events/{id}/expenses/{expense_id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a task and any associated task status events",
+                "description": "Replace an expense. Only its author, organizers and co-organizers can change it",
                 "consumes": [
                     "application/json"
                 ],
@@ -1753,21 +2308,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "budget"
                 ],
-                "summary": "Delete a task",
+                "summary": "Update an expense",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Task ID",
+                        "description": "Event ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Expense ID",
+                        "name": "expense_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Expense details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ExpenseRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Task deleted successfully",
+                        "description": "Expense updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.ExpenseResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -1779,56 +2368,129 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - not the event organizer",
+                        "description": "Forbidden - neither the author nor an organizer",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "404": {
-                        "description": "Task not found",
+                        "description": "Event or expense not found",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to delete task",
+                        "description": "Failed to update expense",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     }
                 }
-            }
-        },
-        "/tasks/{id}/assign": {
-            "put": {
-                "security": [
+            },
+            "delete": {
+                "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Assign the authenticated user to an unassigned task or unassign if already assigned",
-                "consumes": [
+                "description": "Delete an expense. Only its author, organizers and co-organizers can delete it",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "budget"
+                ],
+                "summary": "Delete an expense",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Expense ID",
+                        "name": "expense_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Expense deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - neither the author nor an organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event or expense not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete expense",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/expenses/{expense_id}/attachments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the receipts and documents attached to an expense, oldest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "budget"
                 ],
-                "summary": "Toggle task assignment",
+                "summary": "Get expense attachments",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Task ID",
+                        "description": "Event ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Expense ID",
+                        "name": "expense_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Task assignment toggled successfully",
+                        "description": "Attachments retrieved successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -1838,7 +2500,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/api.TaskResponse"
+                                            "$ref": "#/definitions/api.AttachmentsResponse"
                                         }
                                     }
                                 }
@@ -1846,7 +2508,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Task already assigned to another user",
+                        "description": "Invalid ID",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -1857,8 +2519,123 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden - not a participant of the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
                     "404": {
-                        "description": "Task not found",
+                        "description": "Event or expense not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve attachments",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload a receipt photo or document for an expense as multipart form data. JPEG, PNG, GIF and WebP images\nand PDF documents up to 10 MB are accepted, the type is detected from the content. Images other than WebP get a thumbnail.\nOrganizers, co-organizers and participants can attach files",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budget"
+                ],
+                "summary": "Attach a receipt to an expense",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Expense ID",
+                        "name": "expense_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "File to attach",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File attached successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.AttachmentResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "No file or a damaged image",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - viewers can't attach files",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event or expense not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "File is too large",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "File type is not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to store the file",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
@@ -1866,14 +2643,70 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks/{id}/complete": {
-            "put": {
+        "/events/{id}/invitations/email": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Mark a task as completed or uncompleted and update user scores accordingly",
+                "description": "Get all personal email invitations of an event with their status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invitations"
+                ],
+                "summary": "List email invitations of an event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Email invitations",
+                        "schema": {
+                            "$ref": "#/definitions/api.EmailInvitationsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not allowed to invite to the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve invitations",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Send a personal invitation email with a single use join link. If the address has no account yet, the invitation is linked once it registers",
                 "consumes": [
                     "application/json"
                 ],
@@ -1881,21 +2714,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "invitations"
                 ],
-                "summary": "Toggle task completion",
+                "summary": "Invite a person by email",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Task ID",
+                        "description": "Event ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Email address to invite",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.EmailInvitationRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Task completion toggled successfully",
+                        "description": "Invitation sent",
                         "schema": {
                             "allOf": [
                                 {
@@ -1905,13 +2747,19 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/api.TaskResponse"
+                                            "$ref": "#/definitions/api.EmailInvitationResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Invalid payload, already a participant or already invited",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -1919,308 +2767,5239 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - not assigned to the task",
+                        "description": "Forbidden - not allowed to invite to the event",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     },
                     "404": {
-                        "description": "Task not found",
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to send invitation",
                         "schema": {
                             "$ref": "#/definitions/api.APIResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.APIResponse": {
-            "type": "object",
+        },
+        "/events/{id}/invites": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get invite links of an event that are not revoked, expired or used up",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invitations"
+                ],
+                "summary": "List active invite links of an event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Active invite links",
+                        "schema": {
+                            "$ref": "#/definitions/api.InvitationsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not allowed to invite to the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve invite links",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/join-requests": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get requests to join the event that are waiting for a decision, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invitations"
+                ],
+                "summary": "List pending join requests",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pending join requests",
+                        "schema": {
+                            "$ref": "#/definitions/api.JoinRequestsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid event ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not an organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve join requests",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/join-requests/{request_id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Approve a pending join request, the requester becomes a participant or is waitlisted when the event is full and gets notified",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invitations"
+                ],
+                "summary": "Approve a join request",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Join request ID",
+                        "name": "request_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Join request approved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.JoinRequestResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or request already decided",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not an organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event or join request not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Invite link used up or revoked",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to approve join request",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/join-requests/{request_id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reject a pending join request, the requester gets notified",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invitations"
+                ],
+                "summary": "Reject a join request",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Join request ID",
+                        "name": "request_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Join request rejected",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.JoinRequestResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or request already decided",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not an organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event or join request not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to reject join request",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/leaderboard": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the leaderboard for an event",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get event leaderboard",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Leaderboard retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.EventLeaderboardResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a participant",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event or leaderboard not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/leave": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Allow a user to leave an event they are participating in or its waitlist. A freed place goes to the first waitlisted user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invitations"
+                ],
+                "summary": "Leave an event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully left event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid event ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Cannot leave - you are the organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not a participant or event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/participants": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all participants of an event with their role and RSVP, the organizer comes first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get event participants",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of participants",
+                        "schema": {
+                            "$ref": "#/definitions/api.EventParticipantsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid event ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a participant of the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/participants/{user_id}/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Promote or demote a participant of an event. Organizers and co-organizers can switch participants and viewers, only the organizer can grant or take away the co-organizer role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Change participant role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Participant user ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateParticipantRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.ParticipantRoleResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload or role",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not allowed to change this role",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event or participant not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update role",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/polls": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the polls of the event, newest first. Polls past their deadline are closed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Get polls of an event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Polls retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.PollsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid event ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a participant of the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve polls",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Put a question to the participants of the event with single or multiple choice, optionally anonymous,\nwith a deadline and limited visibility of the results",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Create a poll",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Poll details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreatePollRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Poll created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.PollResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - viewers can't create polls",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create poll",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/polls/{poll_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a poll of the event with the results the user is allowed to see",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Get a poll",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Poll ID",
+                        "name": "poll_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Poll retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.PollResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a participant of the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event or poll not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve poll",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a poll with its votes. Only the creator, organizers and co-organizers can delete a poll",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Delete a poll",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Poll ID",
+                        "name": "poll_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Poll deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - neither the creator nor an organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event or poll not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete poll",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/polls/{poll_id}/close": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Close an open poll before its deadline, the participants of the event get notified. Only the creator, organizers and co-organizers can close a poll",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Close a poll",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Poll ID",
+                        "name": "poll_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Poll closed successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.PollResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Poll already closed",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - neither the creator nor an organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event or poll not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to close poll",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/polls/{poll_id}/votes": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Choose options of an open poll, the choice replaces the previous one and an empty list withdraws the vote",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Vote in a poll",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Poll ID",
+                        "name": "poll_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Chosen options",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.PollVoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Vote saved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.PollResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid options or poll closed",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - viewers can't vote",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event or poll not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to save vote",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/rsvp": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the RSVP answer of the authenticated user for the event",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get my RSVP",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Current RSVP",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.EventParticipantResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a participant of the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Answer whether the authenticated user is coming to the event, optionally with plus-ones and a note. Plus-ones are dropped when not going, declining gives the place to the first waitlisted user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Set my RSVP",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "RSVP answer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.RSVPRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "RSVP saved",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.EventParticipantResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload, status or number of plus-ones",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a participant of the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Event is full",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to save RSVP",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/settlements": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record that one participant paid another to settle up. The payer, the receiver, organizers and co-organizers can record it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budget"
+                ],
+                "summary": "Mark a transfer as paid",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.SettlementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment recorded successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.SettlementResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - neither side of the payment nor an organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to record payment",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/settlements/{settlement_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Undo a recorded payment. Only whoever recorded it, organizers and co-organizers can delete it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budget"
+                ],
+                "summary": "Delete a payment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Settlement ID",
+                        "name": "settlement_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - neither the author nor an organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event or payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete payment",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/time-polls": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the time polls of the event, newest first, with the tally of every candidate time and the answers of the user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Get time polls of an event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Polls retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.TimePollsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid event ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a participant of the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve polls",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Put candidate times of the event to a vote. The times are given as options or taken from the best slots of a day.\nAn event has at most one open poll, recurring events can't be polled",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Create a time poll",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Candidate times",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateTimePollRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Poll created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.TimePollResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid options or a poll is already open",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not an organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create poll",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/time-polls/{poll_id}/close": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Close an open poll with the chosen candidate time. The event moves to that time and every participant gets notified",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Close a time poll",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Poll ID",
+                        "name": "poll_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Chosen option",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CloseTimePollRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Poll closed successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.TimePollResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid option or poll already closed",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not an organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event or poll not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to close poll",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/time-polls/{poll_id}/votes": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Answer yes, maybe or no to candidate times of an open poll, answering again replaces the previous answer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Vote in a time poll",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Poll ID",
+                        "name": "poll_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Answers",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.TimePollVoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Votes saved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.TimePollResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid answers or poll closed",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - viewers can't vote",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event or poll not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to save votes",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/transfer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Hand the event over to another participant. The new owner is notified, the previous organizer becomes a co-organizer and is free to leave the event",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Transfer event ownership",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New organizer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.TransferOwnershipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ownership transferred successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.EventResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event or participant not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to transfer ownership",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/waitlist": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get users waiting for a place in a full event in order. Waitlisted users only see their own entry",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get event waitlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Waitlist",
+                        "schema": {
+                            "$ref": "#/definitions/api.WaitlistResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - neither a participant nor waitlisted",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve waitlist",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/invitations/email": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get email invitations sent to the authenticated user that are waiting for an answer.\nThe join links are only in the invitation emails",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invitations"
+                ],
+                "summary": "List my email invitations",
+                "responses": {
+                    "200": {
+                        "description": "Pending email invitations",
+                        "schema": {
+                            "$ref": "#/definitions/api.EmailInvitationsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve invitations",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/invitations/email/{id}/decline": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Decline a personal invitation, its join link stops working and the inviter is notified",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invitations"
+                ],
+                "summary": "Decline an email invitation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Email invitation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invitation declined",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or invitation already answered",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Invitation not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to decline invitation",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Authenticate a user and return a short-lived JWT access token with a refresh token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "User login",
+                "parameters": [
+                    {
+                        "description": "User login credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful",
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to generate token",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Logout the current user by revoking the current session, its access and refresh tokens stop working",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "User logout",
+                "responses": {
+                    "200": {
+                        "description": "Logged out successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to revoke session",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/reset": {
+            "post": {
+                "description": "Reset user password using a valid reset token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Reset password",
+                "parameters": [
+                    {
+                        "description": "Reset token and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password reset successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload or token",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/reset-redirect": {
+            "get": {
+                "description": "Redirect password reset requests to mobile app with deeplink",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Handle password reset redirect to mobile app",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Reset token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Redirected to mobile app",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/reset-request": {
+            "post": {
+                "description": "Request a password reset token for a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Request password reset",
+                "parameters": [
+                    {
+                        "description": "User email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.PasswordResetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reset token generated",
+                        "schema": {
+                            "$ref": "#/definitions/api.PasswordResetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the authenticated user's profile information",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Get user profile",
+                "responses": {
+                    "200": {
+                        "description": "User profile",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the authenticated user's profile information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Update user profile",
+                "parameters": [
+                    {
+                        "description": "Profile update data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ProfileUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profile updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload, timezone or reminder hours",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Register a new user with email and password, pending email invitations to that address are linked to the account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "User registration details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User registered successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload, timezone or reminder hours",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to hash password",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all signed-in devices of the authenticated user, most recently used first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "List active sessions",
+                "responses": {
+                    "200": {
+                        "description": "Active sessions",
+                        "schema": {
+                            "$ref": "#/definitions/api.SessionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve sessions",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/others": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke all sessions of the authenticated user except the current one",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Sign out everywhere else",
+                "responses": {
+                    "200": {
+                        "description": "Other sessions revoked",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to revoke sessions",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sign out one of the authenticated user's devices",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Revoke a session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid session ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to revoke session",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/task-status-events/unread": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all unread task status events for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get unread task status events",
+                "responses": {
+                    "200": {
+                        "description": "Unread task status events retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.TaskStatusEventsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a list of all tasks associated with a specific event, optionally filtered and sorted.\nIn ascending order tasks without a due date come last and priorities go from urgent to low",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get all tasks for an event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "event_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "open",
+                            "completed"
+                        ],
+                        "type": "string",
+                        "description": "Only open or completed tasks",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "urgent",
+                            "high",
+                            "normal",
+                            "low"
+                        ],
+                        "type": "string",
+                        "description": "Only tasks of the priority",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only tasks of the assignee, a user ID or none for unassigned tasks",
+                        "name": "assigned_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only tasks due before the time, RFC3339",
+                        "name": "due_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only open tasks past their due date",
+                        "name": "overdue",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "due_at",
+                            "priority",
+                            "points",
+                            "title"
+                        ],
+                        "type": "string",
+                        "description": "Sort by id, due_at, priority, points or title, id by default",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order, asc by default",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of tasks retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/api.TaskResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid event ID, filter or sort",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a participant of the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new task for an event",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Create a new task",
+                "parameters": [
+                    {
+                        "description": "Task creation details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.TaskResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload, priority or a due date after the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not allowed to create tasks in the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create task",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get detailed information about a specific task",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get task details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task details retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.TaskResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a participant of the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update details of an existing task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Update task details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Task update details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.TaskResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload, priority or a due date after the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not an organizer or co-organizer of the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a task and any associated task status events",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Delete a task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not an organizer or co-organizer of the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete task",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/assign": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Assign the authenticated user to an unassigned task or unassign if already assigned",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Toggle task assignment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task assignment toggled successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.TaskResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Task already assigned to another user",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not allowed to take tasks in the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/attachments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the files attached to a task, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get task attachments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Attachments retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.AttachmentsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a participant of the event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve attachments",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload a receipt photo or document for a task as multipart form data. JPEG, PNG, GIF and WebP images\nand PDF documents up to 10 MB are accepted, the type is detected from the content. Images other than WebP get a thumbnail.\nOrganizers, co-organizers and participants can attach files",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Attach a file to a task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "File to attach",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File attached successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.AttachmentResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "No file or a damaged image",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - viewers can't attach files",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "File is too large",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "File type is not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to store the file",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{id}/complete": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark a task as completed or uncompleted and update user scores accordingly",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Toggle task completion",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task completion toggled successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.TaskResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not assigned to the task",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/templates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the event templates of the authenticated user, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Get user's templates",
+                "responses": {
+                    "200": {
+                        "description": "Templates retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.EventTemplatesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve templates",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a template from an event with its tasks, budget and points",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Save an event as a template",
+                "parameters": [
+                    {
+                        "description": "Event to save",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateEventTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Template created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.EventTemplateResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not an organizer or co-organizer",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Event not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create template",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/templates/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get an event template of the authenticated user with its tasks",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Get a template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Template retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.EventTemplateResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid template ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an event template of the authenticated user, events created from it are kept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Delete a template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Template deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid template ID",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete template",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/templates/{id}/events": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new event with the budget, capacity and tasks of a template, the caller becomes its organizer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Create an event from a template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Event details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.InstantiateEventTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/api.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/api.EventResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create event",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.APIResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "Invalid input"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Operation successful"
+                },
+                "user": {
+                    "$ref": "#/definitions/api.UserResponse"
+                }
+            }
+        },
+        "api.AttachmentResponse": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string",
+                    "example": "image/jpeg"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-03-15T14:30:00Z"
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "expense_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "file_name": {
+                    "type": "string",
+                    "example": "receipt.jpg"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 204800
+                },
+                "task_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "thumbnail_url": {
+                    "type": "string",
+                    "example": "/attachments/1/thumbnail"
+                },
+                "uploaded_by_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "url": {
+                    "type": "string",
+                    "example": "/attachments/1"
+                }
+            }
+        },
+        "api.AttachmentsResponse": {
+            "type": "object",
+            "properties": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.AttachmentResponse"
+                    }
+                }
+            }
+        },
+        "api.AvailabilitiesResponse": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.AvailabilityResponse"
+                    }
+                }
+            }
+        },
+        "api.AvailabilityRequest": {
+            "type": "object",
+            "required": [
+                "kind"
+            ],
+            "properties": {
+                "end_time": {
+                    "type": "string",
+                    "example": "22:00"
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2024-04-07T18:00:00Z"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "unavailable",
+                        "preferred",
+                        "block"
+                    ],
+                    "example": "unavailable"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Evening classes"
+                },
+                "start_time": {
+                    "type": "string",
+                    "example": "18:00"
+                },
+                "starts_at": {
+                    "description": "StartsAt and EndsAt bound a one-off block",
+                    "type": "string",
+                    "example": "2024-04-05T09:00:00Z"
+                },
+                "weekday": {
+                    "description": "Weekday counts from Sunday as 0",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "api.AvailabilityResponse": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string",
+                    "example": "22:00"
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2024-04-07T18:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "unavailable"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Evening classes"
+                },
+                "start_time": {
+                    "type": "string",
+                    "example": "18:00"
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2024-04-05T09:00:00Z"
+                },
+                "weekday": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "api.BalancesResponse": {
+            "type": "object",
+            "properties": {
+                "balances": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.MemberBalanceResponse"
+                    }
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "settlements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.SettlementResponse"
+                    }
+                },
+                "transfers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.TransferResponse"
+                    }
+                },
+                "unconverted_currencies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "USD"
+                    ]
+                }
+            }
+        },
+        "api.BudgetCategoriesResponse": {
+            "type": "object",
+            "properties": {
+                "allocated": {
+                    "type": "number",
+                    "example": 800
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.BudgetCategoryResponse"
+                    }
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "unallocated": {
+                    "type": "number",
+                    "example": 200
+                }
+            }
+        },
+        "api.BudgetCategoryRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "limit": {
+                    "type": "number",
+                    "example": 300
+                },
+                "name": {
+                    "description": "Name is matched with the category of tasks and expenses, case doesn't matter",
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "food"
+                }
+            }
+        },
+        "api.BudgetCategoryResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "limit": {
+                    "type": "number",
+                    "example": 300
+                },
+                "name": {
+                    "type": "string",
+                    "example": "food"
+                }
+            }
+        },
+        "api.CategoryBudgetResponse": {
+            "type": "object",
+            "properties": {
+                "actual": {
+                    "type": "number",
+                    "example": 120
+                },
+                "category": {
+                    "type": "string",
+                    "example": "decor"
+                },
+                "limit": {
+                    "type": "number",
+                    "example": 200
+                },
+                "over_budget": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "planned": {
+                    "type": "number",
+                    "example": 150
+                },
+                "remaining": {
+                    "type": "number",
+                    "example": 80
+                }
+            }
+        },
+        "api.CloneEventRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Name of the copy, the name of the original by default",
+                    "type": "string",
+                    "example": "Birthday party 2025"
+                },
+                "shift_days": {
+                    "description": "ShiftDays moves the copy relative to the original date",
+                    "type": "integer",
+                    "example": 365
+                }
+            }
+        },
+        "api.CloseTimePollRequest": {
+            "type": "object",
+            "required": [
+                "option_id"
+            ],
+            "properties": {
+                "option_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "api.CreateEventRequest": {
+            "type": "object",
+            "required": [
+                "event_date_time",
+                "name"
+            ],
+            "properties": {
+                "capacity": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "currency": {
+                    "description": "Currency is an ISO 4217 code the budget and tasks are planned in, RUB by default",
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Celebrating John's 30th birthday"
+                },
+                "event_date_time": {
+                    "type": "string",
+                    "example": "2024-04-01T18:00:00Z"
+                },
+                "initial_budget": {
+                    "type": "number",
+                    "example": 1000
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Birthday Party"
+                },
+                "place": {
+                    "type": "string",
+                    "example": "Central Park"
+                },
+                "recurrence_rule": {
+                    "description": "RecurrenceRule makes the event repeat, RFC 5545 RRULE with FREQ, INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY and BYMONTH",
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=MO"
+                },
+                "timezone": {
+                    "description": "Timezone is an IANA zone name, the organizer's zone by default. Recurring events repeat at the same local time in it",
+                    "type": "string",
+                    "example": "Europe/Moscow"
+                }
+            }
+        },
+        "api.CreateEventTemplateRequest": {
+            "type": "object",
+            "required": [
+                "event_id"
+            ],
+            "properties": {
+                "event_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "Name of the template, the name of the event by default",
+                    "type": "string",
+                    "example": "Birthday party"
+                }
+            }
+        },
+        "api.CreatePollRequest": {
+            "type": "object",
+            "required": [
+                "options",
+                "question"
+            ],
+            "properties": {
+                "anonymous": {
+                    "description": "Anonymous polls never show who voted for what",
+                    "type": "boolean",
+                    "example": false
+                },
+                "deadline": {
+                    "description": "Deadline closes the poll automatically",
+                    "type": "string",
+                    "example": "2024-04-01T12:00:00Z"
+                },
+                "multiple_choice": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Central Park",
+                        "Riverside"
+                    ]
+                },
+                "question": {
+                    "type": "string",
+                    "example": "Where do we meet?"
+                },
+                "results_visibility": {
+                    "description": "ResultsVisibility is always by default, organizers and co-organizers see results in any case",
+                    "type": "string",
+                    "enum": [
+                        "always",
+                        "after_vote",
+                        "after_close"
+                    ],
+                    "example": "after_vote"
+                }
+            }
+        },
+        "api.CreateTaskRequest": {
+            "type": "object",
+            "required": [
+                "event_id",
+                "points",
+                "title"
+            ],
+            "properties": {
+                "allow_due_after_event": {
+                    "description": "AllowDueAfterEvent accepts a due date after the event, for tasks like returning rented equipment",
+                    "type": "boolean",
+                    "example": false
+                },
+                "assigned_to": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "budget": {
+                    "type": "number",
+                    "example": 50
+                },
+                "category": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "decor"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Purchase party decorations from the store"
+                },
+                "due_at": {
+                    "type": "string",
+                    "example": "2024-03-30T18:00:00Z"
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "points": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "urgent",
+                        "high",
+                        "normal",
+                        "low"
+                    ],
+                    "example": "high"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Buy decorations"
+                }
+            }
+        },
+        "api.CreateTimePollRequest": {
+            "type": "object",
+            "properties": {
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2024-04-01T18:00:00Z",
+                        "2024-04-02T18:00:00Z"
+                    ]
+                },
+                "seed": {
+                    "$ref": "#/definitions/api.TimePollSeed"
+                }
+            }
+        },
+        "api.EmailInvitationRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "friend@example.com"
+                }
+            }
+        },
+        "api.EmailInvitationResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-03-16T12:00:00Z"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "friend@example.com"
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "event_name": {
+                    "type": "string",
+                    "example": "Birthday Party"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "invite_code": {
+                    "description": "InviteCode is only shown to those who can invite, the invitee finds the link in the email",
+                    "type": "string",
+                    "example": "abc123"
+                },
+                "invited_by_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "responded_at": {
+                    "type": "string",
+                    "example": "2024-03-17T09:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "sent",
+                        "accepted",
+                        "declined",
+                        "failed"
+                    ],
+                    "example": "sent"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "api.EmailInvitationsResponse": {
+            "type": "object",
+            "properties": {
+                "invitations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.EmailInvitationResponse"
+                    }
+                }
+            }
+        },
+        "api.EventBudgetResponse": {
+            "type": "object",
+            "properties": {
+                "actual": {
+                    "type": "number",
+                    "example": 870
+                },
+                "allocated": {
+                    "type": "number",
+                    "example": 800
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.CategoryBudgetResponse"
+                    }
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "difference": {
+                    "type": "number",
+                    "example": 50
+                },
+                "initial_budget": {
+                    "type": "number",
+                    "example": 1000
+                },
+                "over_budget": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "planned": {
+                    "type": "number",
+                    "example": 1100
+                },
+                "real_budget": {
+                    "type": "number",
+                    "example": 950
+                },
+                "remaining": {
+                    "type": "number",
+                    "example": 130
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.TaskBudgetResponse"
+                    }
+                },
+                "unconverted_currencies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "USD"
+                    ]
+                }
+            }
+        },
+        "api.EventLeaderboardEntry": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "score": {
+                    "type": "number",
+                    "example": 85.5
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "api.EventLeaderboardResponse": {
+            "type": "object",
+            "properties": {
+                "leaderboard": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.EventLeaderboardEntry"
+                    }
+                }
+            }
+        },
+        "api.EventParticipantResponse": {
+            "type": "object",
+            "properties": {
+                "avatar": {
+                    "type": "string",
+                    "example": "https://example.com/avatar.jpg"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "Jane Smith"
+                },
+                "plus_ones": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "role": {
+                    "type": "string",
+                    "example": "participant"
+                },
+                "rsvp_note": {
+                    "type": "string",
+                    "example": "Will be 15 minutes late"
+                },
+                "rsvp_status": {
+                    "type": "string",
+                    "enum": [
+                        "going",
+                        "maybe",
+                        "not_going",
+                        "no_response"
+                    ],
+                    "example": "going"
+                },
+                "rsvp_updated_at": {
+                    "type": "string",
+                    "example": "2024-03-20T10:00:00Z"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "api.EventParticipantsResponse": {
+            "type": "object",
+            "properties": {
+                "participants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.EventParticipantResponse"
+                    }
+                }
+            }
+        },
+        "api.EventResponse": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-03-16T12:00:00Z"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Celebrating John's 30th birthday"
+                },
+                "event_date_time": {
+                    "type": "string",
+                    "example": "2024-04-01T18:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "initial_budget": {
+                    "type": "number",
+                    "example": 1000
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Birthday Party"
+                },
+                "occurrence_time": {
+                    "description": "OccurrenceTime identifies an occurrence of a recurring event, it is the start produced by the rule\nand stays the same when the occurrence is moved",
+                    "type": "string",
+                    "example": "2024-04-08T10:00:00Z"
+                },
+                "organizer_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "place": {
+                    "type": "string",
+                    "example": "Central Park"
+                },
+                "recurrence_rule": {
+                    "description": "RecurrenceRule is set for recurring events, the RRULE value without the prefix",
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=MO"
+                },
+                "rsvp_counts": {
+                    "description": "RSVPCounts is only filled in for a single event",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/api.RSVPCounts"
+                        }
+                    ]
+                },
+                "timezone": {
+                    "description": "Timezone of the event, times of the event are given in it",
+                    "type": "string",
+                    "example": "Europe/Moscow"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-03-16T12:00:00Z"
+                }
+            }
+        },
+        "api.EventTemplateResponse": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-03-16T12:00:00Z"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Celebrating a birthday"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "initial_budget": {
+                    "type": "number",
+                    "example": 1000
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Birthday party"
+                },
+                "place": {
+                    "type": "string",
+                    "example": "Central Park"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.EventTemplateTaskResponse"
+                    }
+                }
+            }
+        },
+        "api.EventTemplateTaskResponse": {
+            "type": "object",
+            "properties": {
+                "budget": {
+                    "type": "number",
+                    "example": 50
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Purchase party decorations from the store"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "points": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Buy decorations"
+                }
+            }
+        },
+        "api.EventTemplatesResponse": {
+            "type": "object",
+            "properties": {
+                "templates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.EventTemplateResponse"
+                    }
+                }
+            }
+        },
+        "api.ExpenseRequest": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 42.5
+                },
+                "category": {
+                    "description": "Category is taken from the task when left out",
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "decor"
+                },
+                "currency": {
+                    "description": "Currency is an ISO 4217 code, the event currency by default",
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Balloons and garlands"
+                },
+                "payer_id": {
+                    "description": "PayerID is the participant who paid, the requesting user by default",
+                    "type": "integer",
+                    "example": 2
+                },
+                "spent_at": {
+                    "description": "SpentAt is the time of the purchase, now by default",
+                    "type": "string",
+                    "example": "2024-03-30T15:00:00Z"
+                },
+                "split": {
+                    "description": "Split lists who shares the expense, everybody in the event except viewers when left out in equal mode",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.ExpenseSplitRequest"
+                    }
+                },
+                "split_mode": {
+                    "description": "SplitMode is equal, shares or exact, equal by default",
+                    "type": "string",
+                    "enum": [
+                        "equal",
+                        "shares",
+                        "exact"
+                    ],
+                    "example": "equal"
+                },
+                "task_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "api.ExpenseResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 42.5
+                },
+                "category": {
+                    "type": "string",
+                    "example": "decor"
+                },
+                "created_by_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Balloons and garlands"
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "payer_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "payer_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "spent_at": {
+                    "type": "string",
+                    "example": "2024-03-30T15:00:00Z"
+                },
+                "split": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.ExpenseShareResponse"
+                    }
+                },
+                "split_mode": {
+                    "type": "string",
+                    "example": "equal"
+                },
+                "task_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "api.ExpenseShareResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 21.25
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "shares": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "api.ExpenseSplitRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Amount is what the participant owes in exact mode",
+                    "type": "number",
+                    "example": 21.25
+                },
+                "shares": {
+                    "description": "Shares weighs the participant in shares mode",
+                    "type": "integer",
+                    "example": 2
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "api.ExpensesResponse": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "expenses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.ExpenseResponse"
+                    }
+                },
+                "total": {
+                    "type": "number",
+                    "example": 250
+                },
+                "unconverted_currencies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "USD"
+                    ]
+                }
+            }
+        },
+        "api.FindBestTimeRequest": {
+            "type": "object",
+            "required": [
+                "end_date",
+                "event_id",
+                "start_date"
+            ],
+            "properties": {
+                "duration_mins": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2024-04-07"
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "granularity_mins": {
+                    "description": "GranularityMins is the step between start times, 15, 30 or 60",
+                    "type": "integer",
+                    "example": 30
+                },
+                "limit": {
+                    "description": "Limit of suggestions, 10 by default",
+                    "type": "integer",
+                    "example": 10
+                },
+                "participants": {
+                    "description": "Participants lists optional participants and weights, everybody else is required with weight 1",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.SchedulingParticipant"
+                    }
+                },
+                "start_date": {
+                    "description": "StartDate and EndDate are both included, dates and working hours are read in the timezone of the requesting user",
+                    "type": "string",
+                    "example": "2024-04-01"
+                },
+                "working_hours": {
+                    "description": "WorkingHours by lowercase weekday name, only listed weekdays are searched.\nWithout any entry every day is searched from 08:00 to 22:00",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/api.WorkingHours"
+                    }
+                }
+            }
+        },
+        "api.FindBestTimeSlotsRequest": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "Date, StartTime and EndTime are read in the timezone of the requesting user, suggested slots are given in it too",
+                    "type": "string",
+                    "example": "2024-04-01"
+                },
+                "duration_mins": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "end_time": {
+                    "type": "string",
+                    "example": "22:00"
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "participants": {
+                    "description": "Participants lists optional participants and weights, everybody else is required with weight 1",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.SchedulingParticipant"
+                    }
+                },
+                "start_time": {
+                    "type": "string",
+                    "example": "08:00"
+                }
+            }
+        },
+        "api.FindBestTimeSlotsResponse": {
+            "type": "object",
             "properties": {
-                "data": {
-                    "type": "object"
+                "suggestions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.TimeSlotSuggestion"
+                    }
+                }
+            }
+        },
+        "api.GenerateInviteLinkRequest": {
+            "type": "object",
+            "properties": {
+                "event_id": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "error": {
+                "expires_at": {
                     "type": "string",
-                    "example": "Invalid input"
+                    "example": "2024-04-01T18:00:00Z"
                 },
-                "message": {
-                    "type": "string",
-                    "example": "Operation successful"
+                "max_uses": {
+                    "type": "integer",
+                    "example": 10
                 },
-                "user": {
-                    "$ref": "#/definitions/api.UserResponse"
+                "requires_approval": {
+                    "description": "Joining with the link only sends a join request that organizers approve or reject",
+                    "type": "boolean",
+                    "example": false
                 }
             }
         },
-        "api.CreateEventRequest": {
+        "api.GenerateInviteLinkResponse": {
             "type": "object",
-            "required": [
-                "event_date_time",
-                "name"
-            ],
             "properties": {
-                "description": {
+                "expires_at": {
                     "type": "string",
-                    "example": "Celebrating John's 30th birthday"
+                    "example": "2024-04-01T18:00:00Z"
                 },
-                "event_date_time": {
+                "invite_code": {
                     "type": "string",
-                    "example": "2024-04-01T18:00:00Z"
+                    "example": "abc123"
                 },
-                "initial_budget": {
-                    "type": "number",
-                    "example": 1000
+                "invite_link": {
+                    "type": "string",
+                    "example": "http://localhost:8080/events/redirect/abc123"
                 },
-                "name": {
+                "max_uses": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "requires_approval": {
+                    "description": "RequiresApproval mirrors the request flag",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "api.GoogleOAuthCallbackResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
                     "type": "string",
-                    "example": "Birthday Party"
+                    "example": "ya29.a0AfB_byC..."
                 },
-                "place": {
+                "expiry": {
                     "type": "string",
-                    "example": "Central Park"
+                    "example": "2024-03-16T15:04:05Z"
+                },
+                "refresh_token": {
+                    "type": "string",
+                    "example": "1//04dK..."
                 }
             }
         },
-        "api.CreateTaskRequest": {
+        "api.GoogleOAuthURLResponse": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "type": "string",
+                    "example": "https://accounts.google.com/o/oauth2/auth?..."
+                }
+            }
+        },
+        "api.ImportCalendarEventsResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Events imported successfully"
+                }
+            }
+        },
+        "api.InstantiateEventTemplateRequest": {
             "type": "object",
             "required": [
-                "event_id",
-                "points",
-                "title"
+                "event_date_time"
             ],
             "properties": {
-                "assigned_to": {
-                    "type": "integer",
-                    "example": 2
-                },
-                "budget": {
-                    "type": "number",
-                    "example": 50
+                "event_date_time": {
+                    "type": "string",
+                    "example": "2024-04-01T18:00:00Z"
                 },
-                "description": {
+                "name": {
+                    "description": "Name of the event, the name of the template by default",
                     "type": "string",
-                    "example": "Purchase party decorations from the store"
+                    "example": "Anna's birthday"
+                }
+            }
+        },
+        "api.InvitationResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-03-16T12:00:00Z"
+                },
+                "created_by_id": {
+                    "type": "integer",
+                    "example": 1
                 },
                 "event_id": {
                     "type": "integer",
                     "example": 1
                 },
-                "points": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-04-01T18:00:00Z"
+                },
+                "invite_code": {
+                    "description": "InviteCode is only shown to those who can invite, the invitee finds the link in the email",
+                    "type": "string",
+                    "example": "abc123"
+                },
+                "invite_link": {
+                    "type": "string",
+                    "example": "http://localhost:8080/events/redirect/abc123"
+                },
+                "max_uses": {
                     "type": "integer",
                     "example": 10
                 },
-                "title": {
-                    "type": "string",
-                    "example": "Buy decorations"
+                "requires_approval": {
+                    "description": "RequiresApproval is true for links that create join requests",
+                    "type": "boolean",
+                    "example": false
+                },
+                "uses": {
+                    "type": "integer",
+                    "example": 3
                 }
             }
         },
-        "api.EventBudgetResponse": {
+        "api.InvitationsResponse": {
             "type": "object",
             "properties": {
-                "difference": {
-                    "type": "number",
-                    "example": 50
+                "invitations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.InvitationResponse"
+                    }
+                }
+            }
+        },
+        "api.JoinEventResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Successfully joined event"
                 },
-                "initial_budget": {
-                    "type": "number",
-                    "example": 1000
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "joined",
+                        "pending",
+                        "waitlisted"
+                    ],
+                    "example": "joined"
                 },
-                "real_budget": {
-                    "type": "number",
-                    "example": 950
+                "waitlist_position": {
+                    "description": "WaitlistPosition is set when the event is full",
+                    "type": "integer",
+                    "example": 2
                 }
             }
         },
-        "api.EventLeaderboardEntry": {
+        "api.JoinRequestResponse": {
             "type": "object",
             "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-03-16T12:00:00Z"
+                },
+                "decided_at": {
+                    "type": "string",
+                    "example": "2024-03-16T13:00:00Z"
+                },
                 "display_name": {
                     "type": "string",
-                    "example": "John Doe"
+                    "example": "Jane Smith"
                 },
                 "event_id": {
                     "type": "integer",
                     "example": 1
                 },
-                "score": {
-                    "type": "number",
-                    "example": 85.5
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
                 },
                 "user_id": {
                     "type": "integer",
-                    "example": 1
+                    "example": 3
                 }
             }
         },
-        "api.EventLeaderboardResponse": {
+        "api.JoinRequestsResponse": {
             "type": "object",
             "properties": {
-                "leaderboard": {
+                "join_requests": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/api.EventLeaderboardEntry"
+                        "$ref": "#/definitions/api.JoinRequestResponse"
                     }
                 }
             }
         },
-        "api.EventParticipantsResponse": {
+        "api.LoginRequest": {
             "type": "object",
             "properties": {
-                "participants": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"John Doe\"",
-                        " \"Jane Smith\"]"
-                    ]
+                "device_name": {
+                    "type": "string",
+                    "example": "John's iPhone"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "secretpassword123"
                 }
             }
         },
-        "api.EventResponse": {
+        "api.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-03-16T12:15:00Z"
+                },
+                "refresh_token": {
+                    "type": "string",
+                    "example": "q1w2e3r4t5y6u7i8o9p0..."
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                }
+            }
+        },
+        "api.MemberBalanceResponse": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "net": {
+                    "type": "number",
+                    "example": 21.25
+                },
+                "owed": {
+                    "type": "number",
+                    "example": 21.25
+                },
+                "paid": {
+                    "type": "number",
+                    "example": 42.5
+                },
+                "received": {
+                    "type": "number",
+                    "example": 0
+                },
+                "sent": {
+                    "type": "number",
+                    "example": 0
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "api.ParticipantRoleResponse": {
+            "type": "object",
+            "properties": {
+                "event_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "role": {
+                    "type": "string",
+                    "example": "co_organizer"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "api.PasswordResetRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                }
+            }
+        },
+        "api.PasswordResetResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string",
-                    "example": "2024-03-16T12:00:00Z"
-                },
-                "description": {
+                "message": {
                     "type": "string",
-                    "example": "Celebrating John's 30th birthday"
+                    "example": "If the email exists, a reset link will be sent"
                 },
-                "event_date_time": {
+                "reset_token": {
                     "type": "string",
-                    "example": "2024-04-01T18:00:00Z"
-                },
+                    "example": "abc123def456"
+                }
+            }
+        },
+        "api.PollOptionResponse": {
+            "type": "object",
+            "properties": {
                 "id": {
                     "type": "integer",
                     "example": 1
                 },
-                "initial_budget": {
-                    "type": "number",
-                    "example": 1000
-                },
-                "name": {
-                    "type": "string",
-                    "example": "Birthday Party"
-                },
-                "organizer_id": {
-                    "type": "integer",
-                    "example": 1
+                "selected": {
+                    "description": "Selected tells whether the requesting user voted for the option",
+                    "type": "boolean",
+                    "example": true
                 },
-                "place": {
+                "text": {
                     "type": "string",
                     "example": "Central Park"
                 },
-                "updated_at": {
-                    "type": "string",
-                    "example": "2024-03-16T12:00:00Z"
+                "voter_ids": {
+                    "description": "VoterIDs are shown for polls that aren't anonymous",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "votes": {
+                    "type": "integer",
+                    "example": 3
                 }
             }
         },
-        "api.FindBestTimeSlotsRequest": {
+        "api.PollResponse": {
             "type": "object",
             "properties": {
-                "date": {
+                "anonymous": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "closed_at": {
                     "type": "string",
-                    "example": "2024-04-01"
+                    "example": "2024-04-01T12:00:00Z"
                 },
-                "duration_mins": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-03-16T12:00:00Z"
+                },
+                "created_by_id": {
                     "type": "integer",
-                    "example": 120
+                    "example": 1
                 },
-                "end_time": {
+                "deadline": {
                     "type": "string",
-                    "example": "22:00"
+                    "example": "2024-04-01T12:00:00Z"
                 },
                 "event_id": {
                     "type": "integer",
                     "example": 1
                 },
-                "start_time": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "multiple_choice": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.PollOptionResponse"
+                    }
+                },
+                "question": {
                     "type": "string",
-                    "example": "08:00"
+                    "example": "Where do we meet?"
+                },
+                "results_visibility": {
+                    "type": "string",
+                    "example": "always"
+                },
+                "results_visible": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "status": {
+                    "type": "string",
+                    "example": "poll_open"
                 }
             }
         },
-        "api.FindBestTimeSlotsResponse": {
+        "api.PollVoteRequest": {
             "type": "object",
             "properties": {
-                "suggestions": {
+                "option_ids": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/api.TimeSlotSuggestion"
-                    }
+                        "type": "integer"
+                    },
+                    "example": [
+                        1
+                    ]
                 }
             }
         },
-        "api.GenerateInviteLinkRequest": {
+        "api.PollsResponse": {
             "type": "object",
             "properties": {
-                "event_id": {
-                    "type": "integer",
-                    "example": 1
+                "polls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.PollResponse"
+                    }
                 }
             }
         },
-        "api.GenerateInviteLinkResponse": {
+        "api.ProfileUpdateRequest": {
             "type": "object",
             "properties": {
-                "invite_link": {
+                "avatar": {
                     "type": "string",
-                    "example": "http://localhost:8080/events/join/abc123"
-                }
-            }
-        },
-        "api.GoogleOAuthCallbackResponse": {
-            "type": "object",
-            "properties": {
-                "access_token": {
+                    "example": "https://example.com/avatar.jpg"
+                },
+                "bio": {
                     "type": "string",
-                    "example": "ya29.a0AfB_byC..."
+                    "example": "Software developer and tech enthusiast"
                 },
-                "expiry": {
+                "display_name": {
                     "type": "string",
-                    "example": "2024-03-16T15:04:05Z"
+                    "example": "John Doe"
                 },
-                "refresh_token": {
+                "event_reminder_hours": {
+                    "type": "integer",
+                    "example": 48
+                },
+                "task_reminder_hours": {
+                    "description": "TaskReminderHours and EventReminderHours are up to 720 hours, 0 turns the reminders off",
+                    "type": "integer",
+                    "example": 24
+                },
+                "timezone": {
                     "type": "string",
-                    "example": "1//04dK..."
+                    "example": "Europe/Moscow"
                 }
             }
         },
-        "api.GoogleOAuthURLResponse": {
+        "api.RSVPCounts": {
             "type": "object",
             "properties": {
-                "url": {
-                    "type": "string",
-                    "example": "https://accounts.google.com/o/oauth2/auth?..."
+                "expected_guests": {
+                    "description": "ExpectedGuests is going participants together with their plus-ones",
+                    "type": "integer",
+                    "example": 7
+                },
+                "going": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "maybe": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "no_response": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "not_going": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "plus_ones": {
+                    "description": "PlusOnes counts guests of participants who are going",
+                    "type": "integer",
+                    "example": 2
                 }
             }
         },
-        "api.ImportCalendarEventsResponse": {
+        "api.RSVPRequest": {
             "type": "object",
+            "required": [
+                "status"
+            ],
             "properties": {
-                "message": {
+                "note": {
                     "type": "string",
-                    "example": "Events imported successfully"
+                    "example": "Will be 15 minutes late"
+                },
+                "plus_ones": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "going",
+                        "maybe",
+                        "not_going"
+                    ],
+                    "example": "going"
                 }
             }
         },
-        "api.JoinEventResponse": {
+        "api.RefreshTokenRequest": {
             "type": "object",
+            "required": [
+                "refresh_token"
+            ],
             "properties": {
-                "message": {
+                "refresh_token": {
                     "type": "string",
-                    "example": "Successfully joined event"
+                    "example": "q1w2e3r4t5y6u7i8o9p0..."
                 }
             }
         },
-        "api.LoginRequest": {
+        "api.RegisterRequest": {
             "type": "object",
             "properties": {
                 "email": {
@@ -2230,101 +8009,210 @@ const docTemplate = `{
                 "password": {
                     "type": "string",
                     "example": "secretpassword123"
+                },
+                "timezone": {
+                    "description": "Timezone is an IANA zone name, UTC by default",
+                    "type": "string",
+                    "example": "Europe/Moscow"
                 }
             }
         },
-        "api.LoginResponse": {
+        "api.ResetPasswordRequest": {
             "type": "object",
             "properties": {
+                "new_password": {
+                    "type": "string",
+                    "example": "newpassword123"
+                },
                 "token": {
                     "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                    "example": "abc123def456"
                 }
             }
         },
-        "api.PasswordResetRequest": {
+        "api.SaveOAuthTokenRequest": {
             "type": "object",
+            "required": [
+                "access_token",
+                "expiry"
+            ],
             "properties": {
-                "email": {
+                "access_token": {
                     "type": "string",
-                    "example": "user@example.com"
+                    "example": "ya29.a0AfB_byC..."
+                },
+                "expiry": {
+                    "type": "string",
+                    "example": "2024-03-16T15:04:05Z"
+                },
+                "refresh_token": {
+                    "type": "string",
+                    "example": "1//04dK..."
                 }
             }
         },
-        "api.PasswordResetResponse": {
+        "api.SchedulingParticipant": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "If the email exists, a reset link will be sent"
+                "optional": {
+                    "description": "Optional participants can be busy in a suggested slot when nobody required is",
+                    "type": "boolean",
+                    "example": true
                 },
-                "reset_token": {
-                    "type": "string",
-                    "example": "abc123def456"
+                "user_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "weight": {
+                    "description": "Weight of the participant, 1 by default",
+                    "type": "number",
+                    "example": 0.5
                 }
             }
         },
-        "api.ProfileUpdateRequest": {
+        "api.SessionResponse": {
             "type": "object",
             "properties": {
-                "avatar": {
+                "created_at": {
                     "type": "string",
-                    "example": "https://example.com/avatar.jpg"
+                    "example": "2024-03-10T09:00:00Z"
                 },
-                "bio": {
+                "device_name": {
                     "type": "string",
-                    "example": "Software developer and tech enthusiast"
+                    "example": "John's iPhone"
                 },
-                "display_name": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "ip_address": {
                     "type": "string",
-                    "example": "John Doe"
+                    "example": "192.168.1.10"
+                },
+                "is_current": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "last_seen_at": {
+                    "type": "string",
+                    "example": "2024-03-16T12:00:00Z"
+                },
+                "user_agent": {
+                    "type": "string",
+                    "example": "ItsPlanned/1.0 (iPhone; iOS 17.4)"
                 }
             }
         },
-        "api.RegisterRequest": {
+        "api.SessionsResponse": {
             "type": "object",
             "properties": {
-                "email": {
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.SessionResponse"
+                    }
+                }
+            }
+        },
+        "api.SettlementRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "from_user_id",
+                "to_user_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 21.25
+                },
+                "currency": {
+                    "description": "Currency is an ISO 4217 code, the event currency by default",
                     "type": "string",
-                    "example": "user@example.com"
+                    "example": "EUR"
                 },
-                "password": {
+                "from_user_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "paid_at": {
+                    "description": "PaidAt is the time of the payment, now by default",
                     "type": "string",
-                    "example": "secretpassword123"
+                    "example": "2024-04-02T10:00:00Z"
+                },
+                "to_user_id": {
+                    "type": "integer",
+                    "example": 2
                 }
             }
         },
-        "api.ResetPasswordRequest": {
+        "api.SettlementResponse": {
             "type": "object",
             "properties": {
-                "new_password": {
+                "amount": {
+                    "type": "number",
+                    "example": 21.25
+                },
+                "created_by_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "currency": {
                     "type": "string",
-                    "example": "newpassword123"
+                    "example": "RUB"
                 },
-                "token": {
+                "from_name": {
                     "type": "string",
-                    "example": "abc123def456"
+                    "example": "Jane Doe"
+                },
+                "from_user_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "paid_at": {
+                    "type": "string",
+                    "example": "2024-04-02T10:00:00Z"
+                },
+                "to_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "to_user_id": {
+                    "type": "integer",
+                    "example": 2
                 }
             }
         },
-        "api.SaveOAuthTokenRequest": {
+        "api.TaskBudgetResponse": {
             "type": "object",
-            "required": [
-                "access_token",
-                "expiry"
-            ],
             "properties": {
-                "access_token": {
-                    "type": "string",
-                    "example": "ya29.a0AfB_byC..."
+                "actual": {
+                    "type": "number",
+                    "example": 42.5
                 },
-                "expiry": {
+                "category": {
                     "type": "string",
-                    "example": "2024-03-16T15:04:05Z"
+                    "example": "decor"
                 },
-                "refresh_token": {
+                "is_completed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "planned": {
+                    "type": "number",
+                    "example": 50
+                },
+                "task_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "title": {
                     "type": "string",
-                    "example": "1//04dK..."
+                    "example": "Buy decorations"
                 }
             }
         },
@@ -2343,10 +8231,23 @@ const docTemplate = `{
                     "type": "number",
                     "example": 50
                 },
+                "category": {
+                    "type": "string",
+                    "example": "decor"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
                 "description": {
                     "type": "string",
                     "example": "Purchase party decorations from the store"
                 },
+                "due_at": {
+                    "description": "DueAt is when the task has to be done, IsOverdue is set for open tasks past it",
+                    "type": "string",
+                    "example": "2024-03-30T18:00:00Z"
+                },
                 "event_id": {
                     "type": "integer",
                     "example": 1
@@ -2359,10 +8260,24 @@ const docTemplate = `{
                     "type": "boolean",
                     "example": false
                 },
+                "is_overdue": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "points": {
                     "type": "integer",
                     "example": 10
                 },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "urgent",
+                        "high",
+                        "normal",
+                        "low"
+                    ],
+                    "example": "normal"
+                },
                 "title": {
                     "type": "string",
                     "example": "Buy decorations"
@@ -2380,6 +8295,10 @@ const docTemplate = `{
                     "type": "string",
                     "example": "John Doe"
                 },
+                "event_id": {
+                    "type": "integer",
+                    "example": 1
+                },
                 "event_time": {
                     "type": "string",
                     "example": "2024-03-16T12:00:00Z"
@@ -2421,6 +8340,145 @@ const docTemplate = `{
                 }
             }
         },
+        "api.TimePollAnswer": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "string",
+                    "enum": [
+                        "yes",
+                        "maybe",
+                        "no"
+                    ],
+                    "example": "yes"
+                },
+                "option_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "api.TimePollOptionResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "maybe": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "my_answer": {
+                    "description": "MyAnswer is the answer of the requesting user, empty when they haven't voted",
+                    "type": "string",
+                    "example": "yes"
+                },
+                "no": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "start_time": {
+                    "type": "string",
+                    "example": "2024-04-01T18:00:00Z"
+                },
+                "yes": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "api.TimePollResponse": {
+            "type": "object",
+            "properties": {
+                "chosen_option_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "closed_at": {
+                    "type": "string",
+                    "example": "2024-03-20T12:00:00Z"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-03-16T12:00:00Z"
+                },
+                "created_by_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.TimePollOptionResponse"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "poll_open"
+                }
+            }
+        },
+        "api.TimePollSeed": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2024-04-01"
+                },
+                "duration_mins": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "end_time": {
+                    "type": "string",
+                    "example": "22:00"
+                },
+                "participants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.SchedulingParticipant"
+                    }
+                },
+                "start_time": {
+                    "type": "string",
+                    "example": "08:00"
+                }
+            }
+        },
+        "api.TimePollVoteRequest": {
+            "type": "object",
+            "required": [
+                "votes"
+            ],
+            "properties": {
+                "votes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.TimePollAnswer"
+                    }
+                }
+            }
+        },
+        "api.TimePollsResponse": {
+            "type": "object",
+            "properties": {
+                "polls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.TimePollResponse"
+                    }
+                }
+            }
+        },
         "api.TimeSlotSuggestion": {
             "type": "object",
             "properties": {
@@ -2428,12 +8486,70 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 2
                 },
+                "busy_participant_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "outside_preferred_count": {
+                    "description": "OutsidePreferredCount counts participants whose preferred hours don't cover the slot",
+                    "type": "integer",
+                    "example": 0
+                },
+                "required_busy_count": {
+                    "description": "RequiredBusyCount counts busy participants who aren't optional",
+                    "type": "integer",
+                    "example": 1
+                },
+                "score": {
+                    "description": "Score is the summed weight of busy participants, lower is better",
+                    "type": "number",
+                    "example": 1.5
+                },
                 "slot": {
                     "type": "string",
                     "example": "2024-04-01 18:00"
                 }
             }
         },
+        "api.TransferOwnershipRequest": {
+            "type": "object",
+            "required": [
+                "new_organizer_id"
+            ],
+            "properties": {
+                "new_organizer_id": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "api.TransferResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 21.25
+                },
+                "from_name": {
+                    "type": "string",
+                    "example": "Jane Doe"
+                },
+                "from_user_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "to_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "to_user_id": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
         "api.UpdateEventRequest": {
             "type": "object",
             "properties": {
@@ -2441,6 +8557,16 @@ const docTemplate = `{
                     "type": "number",
                     "example": 1500
                 },
+                "capacity": {
+                    "description": "Capacity of 0 removes the limit",
+                    "type": "integer",
+                    "example": 30
+                },
+                "currency": {
+                    "description": "Currency changes the currency of the budget, the tasks and the budget categories, their amounts are converted at the current rate",
+                    "type": "string",
+                    "example": "EUR"
+                },
                 "description": {
                     "type": "string",
                     "example": "Celebrating John's 30th birthday"
@@ -2453,27 +8579,96 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Birthday Party"
                 },
+                "occurrence_time": {
+                    "description": "OccurrenceTime selects the occurrence for the \"this\" and \"following\" scopes",
+                    "type": "string",
+                    "example": "2024-04-08T10:00:00Z"
+                },
                 "place": {
                     "type": "string",
                     "example": "Central Park"
+                },
+                "recurrence_rule": {
+                    "description": "RecurrenceRule replaces the rule of a recurring event, an empty string stops the repetition",
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=MO"
+                },
+                "scope": {
+                    "description": "Scope of the change for recurring events, \"all\" by default",
+                    "type": "string",
+                    "enum": [
+                        "this",
+                        "following",
+                        "all"
+                    ],
+                    "example": "this"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Europe/Moscow"
+                }
+            }
+        },
+        "api.UpdateParticipantRoleRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "co_organizer",
+                        "participant",
+                        "viewer"
+                    ],
+                    "example": "co_organizer"
                 }
             }
         },
         "api.UpdateTaskRequest": {
             "type": "object",
             "properties": {
+                "allow_due_after_event": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "budget": {
                     "type": "number",
                     "example": 60
                 },
+                "category": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "decor"
+                },
+                "clear_due_at": {
+                    "description": "ClearDueAt removes the due date of the task",
+                    "type": "boolean",
+                    "example": false
+                },
                 "description": {
                     "type": "string",
                     "example": "Purchase decorations from the party store"
                 },
+                "due_at": {
+                    "type": "string",
+                    "example": "2024-03-30T18:00:00Z"
+                },
                 "points": {
                     "type": "integer",
                     "example": 15
                 },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "urgent",
+                        "high",
+                        "normal",
+                        "low"
+                    ],
+                    "example": "high"
+                },
                 "title": {
                     "type": "string",
                     "example": "Buy party decorations"
@@ -2503,16 +8698,78 @@ const docTemplate = `{
                     "type": "string",
                     "example": "user@example.com"
                 },
+                "event_reminder_hours": {
+                    "type": "integer",
+                    "example": 48
+                },
                 "id": {
                     "type": "integer",
                     "example": 1
                 },
+                "task_reminder_hours": {
+                    "description": "TaskReminderHours and EventReminderHours are how long before due dates and events reminders are sent",
+                    "type": "integer",
+                    "example": 24
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Europe/Moscow"
+                },
                 "updated_at": {
                     "type": "string",
                     "example": "2024-03-16T12:00:00Z"
                 }
             }
         },
+        "api.WaitlistEntryResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-03-16T12:00:00Z"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "Jane Smith"
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "api.WaitlistResponse": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "waitlist": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.WaitlistEntryResponse"
+                    }
+                }
+            }
+        },
+        "api.WorkingHours": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string",
+                    "example": "18:00"
+                },
+                "start_time": {
+                    "type": "string",
+                    "example": "09:00"
+                }
+            }
+        },
         "api.YandexGPTMessage": {
             "type": "object",
             "properties": {
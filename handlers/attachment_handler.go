@@ -0,0 +1,527 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/permissions"
+	"itsplanned/services/storage"
+	"itsplanned/thumbnail"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxAttachmentSize caps the size of an uploaded file
+const maxAttachmentSize = 10 << 20
+
+// thumbnailSize is the longest side of image thumbnails in pixels
+const thumbnailSize = 256
+
+const maxAttachmentNameLength = 255
+
+// attachmentExtensions lists the file types that can be attached with the extension they are stored with.
+// The type is sniffed from the content, whatever the client claims.
+var attachmentExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+func toAttachmentResponse(attachment *models.Attachment) api.AttachmentResponse {
+	response := api.AttachmentResponse{
+		ID:           attachment.ID,
+		EventID:      attachment.EventID,
+		TaskID:       attachment.TaskID,
+		ExpenseID:    attachment.ExpenseID,
+		UploadedByID: attachment.UploadedByID,
+		FileName:     attachment.FileName,
+		ContentType:  attachment.ContentType,
+		Size:         attachment.Size,
+		URL:          fmt.Sprintf("/attachments/%d", attachment.ID),
+		CreatedAt:    attachment.CreatedAt,
+	}
+	if attachment.ThumbnailKey != "" {
+		response.ThumbnailURL = fmt.Sprintf("/attachments/%d/thumbnail", attachment.ID)
+	}
+	return response
+}
+
+// loadAttachmentTask loads the task from the path and its event, the user has to be a participant of the event
+func loadAttachmentTask(c *gin.Context, db *gorm.DB) (*models.Task, *models.Event, string, bool) {
+	var taskID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &taskID); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid task ID format"})
+		return nil, nil, "", false
+	}
+
+	var task models.Task
+	if err := db.First(&task, taskID).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Task not found"})
+		return nil, nil, "", false
+	}
+
+	var event models.Event
+	if err := db.First(&event, task.EventID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve event"})
+		return nil, nil, "", false
+	}
+
+	userID, _ := c.Get("user_id")
+	role, _ := permissions.GetRole(db, &event, userID.(uint))
+	if !permissions.CanView(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not a participant of this event"})
+		return nil, nil, "", false
+	}
+	return &task, &event, role, true
+}
+
+// loadAttachment loads the attachment from the path, the user has to be a participant of its event
+func loadAttachment(c *gin.Context, db *gorm.DB) (*models.Attachment, string, bool) {
+	var attachmentID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &attachmentID); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid attachment ID format"})
+		return nil, "", false
+	}
+
+	var attachment models.Attachment
+	if err := db.First(&attachment, attachmentID).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Attachment not found"})
+		return nil, "", false
+	}
+
+	var event models.Event
+	if err := db.First(&event, attachment.EventID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve event"})
+		return nil, "", false
+	}
+
+	userID, _ := c.Get("user_id")
+	role, _ := permissions.GetRole(db, &event, userID.(uint))
+	if !permissions.CanView(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not a participant of this event"})
+		return nil, "", false
+	}
+	return &attachment, role, true
+}
+
+// attachmentFileName keeps the base name of the uploaded file, trimmed to a sane length
+func attachmentFileName(name, extension string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return "attachment" + extension
+	}
+	for len(name) > maxAttachmentNameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
+
+// newAttachmentKey makes a storage key nobody can guess for a file of the event
+func newAttachmentKey(eventID uint) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("events/%d/%s", eventID, hex.EncodeToString(buf)), nil
+}
+
+// uploadAttachment validates the file in the "file" form field, stores it with a thumbnail for images
+// and records the attachment, which already has its event and task or expense set
+func uploadAttachment(c *gin.Context, db *gorm.DB, attachment models.Attachment) {
+	// Leave room for the rest of the multipart body around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxAttachmentSize+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, api.APIResponse{Error: fmt.Sprintf("Files can't be larger than %d MB", maxAttachmentSize>>20)})
+			return
+		}
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Attach the file in the \"file\" form field"})
+		return
+	}
+	if header.Size > maxAttachmentSize {
+		c.JSON(http.StatusRequestEntityTooLarge, api.APIResponse{Error: fmt.Sprintf("Files can't be larger than %d MB", maxAttachmentSize>>20)})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Failed to read the file"})
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(file, maxAttachmentSize+1))
+	file.Close()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Failed to read the file"})
+		return
+	}
+	if len(data) == 0 {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "The file is empty"})
+		return
+	}
+	if len(data) > maxAttachmentSize {
+		c.JSON(http.StatusRequestEntityTooLarge, api.APIResponse{Error: fmt.Sprintf("Files can't be larger than %d MB", maxAttachmentSize>>20)})
+		return
+	}
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	extension, ok := attachmentExtensions[contentType]
+	if !ok {
+		c.JSON(http.StatusUnsupportedMediaType, api.APIResponse{Error: "Only JPEG, PNG, GIF and WebP images and PDF documents can be attached"})
+		return
+	}
+
+	var thumb []byte
+	if thumbnail.Supported(contentType) {
+		thumb, err = thumbnail.Generate(data, thumbnailSize)
+		if errors.Is(err, thumbnail.ErrTooLarge) {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "The image has too many pixels"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "The image is damaged and can't be read"})
+			return
+		}
+	}
+
+	key, err := newAttachmentKey(attachment.EventID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to store the file"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	backend := storage.Current()
+	attachment.StorageKey = key + extension
+	if err := backend.Put(ctx, attachment.StorageKey, data, contentType); err != nil {
+		log.Printf("Failed to store attachment %s: %v", attachment.StorageKey, err)
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to store the file"})
+		return
+	}
+	if thumb != nil {
+		attachment.ThumbnailKey = key + "_thumb.jpg"
+		if err := backend.Put(ctx, attachment.ThumbnailKey, thumb, "image/jpeg"); err != nil {
+			log.Printf("Failed to store thumbnail %s: %v", attachment.ThumbnailKey, err)
+			removeAttachmentFiles(ctx, []models.Attachment{attachment})
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to store the file"})
+			return
+		}
+	}
+
+	userID, _ := c.Get("user_id")
+	attachment.UploadedByID = userID.(uint)
+	attachment.FileName = attachmentFileName(header.Filename, extension)
+	attachment.ContentType = contentType
+	attachment.Size = int64(len(data))
+
+	if err := db.Create(&attachment).Error; err != nil {
+		removeAttachmentFiles(ctx, []models.Attachment{attachment})
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to save the attachment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "File attached successfully",
+		Data:    toAttachmentResponse(&attachment),
+	})
+}
+
+// deleteAttachmentRows removes the attachments matching the condition and returns them,
+// their files have to be removed with removeAttachmentFiles once the transaction commits
+func deleteAttachmentRows(tx *gorm.DB, query string, args ...interface{}) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	if err := tx.Where(query, args...).Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+	if err := tx.Unscoped().Where(query, args...).Delete(&models.Attachment{}).Error; err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// removeAttachmentFiles deletes the stored files of the attachments, failures only leave orphaned files behind
+func removeAttachmentFiles(ctx context.Context, attachments []models.Attachment) {
+	backend := storage.Current()
+	for _, attachment := range attachments {
+		for _, key := range []string{attachment.StorageKey, attachment.ThumbnailKey} {
+			if key == "" {
+				continue
+			}
+			if err := backend.Delete(ctx, key); err != nil {
+				log.Printf("Failed to delete stored file %s: %v", key, err)
+			}
+		}
+	}
+}
+
+func listAttachments(c *gin.Context, db *gorm.DB, query string, args ...interface{}) {
+	var attachments []models.Attachment
+	if err := db.Where(query, args...).Order("created_at ASC, id ASC").Find(&attachments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve attachments"})
+		return
+	}
+
+	response := api.AttachmentsResponse{Attachments: make([]api.AttachmentResponse, 0, len(attachments))}
+	for i := range attachments {
+		response.Attachments = append(response.Attachments, toAttachmentResponse(&attachments[i]))
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Attachments retrieved successfully",
+		Data:    response,
+	})
+}
+
+// serveStoredFile streams a stored file to the client
+func serveStoredFile(c *gin.Context, key, contentType string, size int64, fileName string) {
+	reader, err := storage.Current().Get(c.Request.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "File not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to read stored file %s: %v", key, err)
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to read the file"})
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(http.StatusOK, size, contentType, reader, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("inline", map[string]string{"filename": fileName}),
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, max-age=86400",
+	})
+}
+
+// UploadTaskAttachment godoc
+// @Summary Attach a file to a task
+// @Description Upload a receipt photo or document for a task as multipart form data. JPEG, PNG, GIF and WebP images
+// @Description and PDF documents up to 10 MB are accepted, the type is detected from the content. Images other than WebP get a thumbnail.
+// @Description Organizers, co-organizers and participants can attach files
+// @Tags tasks
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param file formData file true "File to attach"
+// @Success 200 {object} api.APIResponse{data=api.AttachmentResponse} "File attached successfully"
+// @Failure 400 {object} api.APIResponse "No file or a damaged image"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - viewers can't attach files"
+// @Failure 404 {object} api.APIResponse "Task not found"
+// @Failure 413 {object} api.APIResponse "File is too large"
+// @Failure 415 {object} api.APIResponse "File type is not allowed"
+// @Failure 500 {object} api.APIResponse "Failed to store the file"
+// @Router /tasks/{id}/attachments [post]
+func UploadTaskAttachment(c *gin.Context, db *gorm.DB) {
+	task, event, role, ok := loadAttachmentTask(c, db)
+	if !ok {
+		return
+	}
+
+	if !permissions.CanAttachFiles(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Viewers can't attach files"})
+		return
+	}
+
+	uploadAttachment(c, db, models.Attachment{EventID: event.ID, TaskID: &task.ID})
+}
+
+// GetTaskAttachments godoc
+// @Summary Get task attachments
+// @Description Get the files attached to a task, oldest first
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} api.APIResponse{data=api.AttachmentsResponse} "Attachments retrieved successfully"
+// @Failure 400 {object} api.APIResponse "Invalid ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not a participant of the event"
+// @Failure 404 {object} api.APIResponse "Task not found"
+// @Failure 500 {object} api.APIResponse "Failed to retrieve attachments"
+// @Router /tasks/{id}/attachments [get]
+func GetTaskAttachments(c *gin.Context, db *gorm.DB) {
+	task, _, _, ok := loadAttachmentTask(c, db)
+	if !ok {
+		return
+	}
+
+	listAttachments(c, db, "task_id = ?", task.ID)
+}
+
+// UploadExpenseAttachment godoc
+// @Summary Attach a receipt to an expense
+// @Description Upload a receipt photo or document for an expense as multipart form data. JPEG, PNG, GIF and WebP images
+// @Description and PDF documents up to 10 MB are accepted, the type is detected from the content. Images other than WebP get a thumbnail.
+// @Description Organizers, co-organizers and participants can attach files
+// @Tags budget
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param expense_id path int true "Expense ID"
+// @Param file formData file true "File to attach"
+// @Success 200 {object} api.APIResponse{data=api.AttachmentResponse} "File attached successfully"
+// @Failure 400 {object} api.APIResponse "No file or a damaged image"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - viewers can't attach files"
+// @Failure 404 {object} api.APIResponse "Event or expense not found"
+// @Failure 413 {object} api.APIResponse "File is too large"
+// @Failure 415 {object} api.APIResponse "File type is not allowed"
+// @Failure 500 {object} api.APIResponse "Failed to store the file"
+// @Router /events/{id}/expenses/{expense_id}/attachments [post]
+func UploadExpenseAttachment(c *gin.Context, db *gorm.DB) {
+	event, role, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	expense, ok := loadExpense(c, db, event)
+	if !ok {
+		return
+	}
+
+	if !permissions.CanAttachFiles(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Viewers can't attach files"})
+		return
+	}
+
+	uploadAttachment(c, db, models.Attachment{EventID: event.ID, ExpenseID: &expense.ID})
+}
+
+// GetExpenseAttachments godoc
+// @Summary Get expense attachments
+// @Description Get the receipts and documents attached to an expense, oldest first
+// @Tags budget
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param expense_id path int true "Expense ID"
+// @Success 200 {object} api.APIResponse{data=api.AttachmentsResponse} "Attachments retrieved successfully"
+// @Failure 400 {object} api.APIResponse "Invalid ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not a participant of the event"
+// @Failure 404 {object} api.APIResponse "Event or expense not found"
+// @Failure 500 {object} api.APIResponse "Failed to retrieve attachments"
+// @Router /events/{id}/expenses/{expense_id}/attachments [get]
+func GetExpenseAttachments(c *gin.Context, db *gorm.DB) {
+	event, _, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	expense, ok := loadExpense(c, db, event)
+	if !ok {
+		return
+	}
+
+	listAttachments(c, db, "expense_id = ?", expense.ID)
+}
+
+// DownloadAttachment godoc
+// @Summary Download an attachment
+// @Description Get the attached file, only participants of the event can download it
+// @Tags attachments
+// @Produce image/jpeg,image/png,image/gif,image/webp,application/pdf
+// @Security BearerAuth
+// @Param id path int true "Attachment ID"
+// @Success 200 {file} file "The attached file"
+// @Failure 400 {object} api.APIResponse "Invalid ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not a participant of the event"
+// @Failure 404 {object} api.APIResponse "Attachment not found"
+// @Failure 500 {object} api.APIResponse "Failed to read the file"
+// @Router /attachments/{id} [get]
+func DownloadAttachment(c *gin.Context, db *gorm.DB) {
+	attachment, _, ok := loadAttachment(c, db)
+	if !ok {
+		return
+	}
+
+	serveStoredFile(c, attachment.StorageKey, attachment.ContentType, attachment.Size, attachment.FileName)
+}
+
+// DownloadAttachmentThumbnail godoc
+// @Summary Download an attachment thumbnail
+// @Description Get a JPEG preview of an attached image that fits into 256x256 pixels. Documents have no thumbnail
+// @Tags attachments
+// @Produce image/jpeg
+// @Security BearerAuth
+// @Param id path int true "Attachment ID"
+// @Success 200 {file} file "The thumbnail"
+// @Failure 400 {object} api.APIResponse "Invalid ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not a participant of the event"
+// @Failure 404 {object} api.APIResponse "Attachment or thumbnail not found"
+// @Failure 500 {object} api.APIResponse "Failed to read the file"
+// @Router /attachments/{id}/thumbnail [get]
+func DownloadAttachmentThumbnail(c *gin.Context, db *gorm.DB) {
+	attachment, _, ok := loadAttachment(c, db)
+	if !ok {
+		return
+	}
+
+	if attachment.ThumbnailKey == "" {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "This attachment has no thumbnail"})
+		return
+	}
+
+	name := strings.TrimSuffix(attachment.FileName, filepath.Ext(attachment.FileName)) + "_thumb.jpg"
+	serveStoredFile(c, attachment.ThumbnailKey, "image/jpeg", -1, name)
+}
+
+// DeleteAttachment godoc
+// @Summary Delete an attachment
+// @Description Remove an attached file. The uploader, organizers and co-organizers can delete it
+// @Tags attachments
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Attachment ID"
+// @Success 200 {object} api.APIResponse "Attachment deleted"
+// @Failure 400 {object} api.APIResponse "Invalid ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - neither the uploader nor an organizer"
+// @Failure 404 {object} api.APIResponse "Attachment not found"
+// @Failure 500 {object} api.APIResponse "Failed to delete attachment"
+// @Router /attachments/{id} [delete]
+func DeleteAttachment(c *gin.Context, db *gorm.DB) {
+	attachment, role, ok := loadAttachment(c, db)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if attachment.UploadedByID != userID.(uint) && !permissions.CanManageAttachments(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only the uploader, organizers and co-organizers can delete this attachment"})
+		return
+	}
+
+	if err := db.Unscoped().Delete(attachment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete attachment"})
+		return
+	}
+	removeAttachmentFiles(c.Request.Context(), []models.Attachment{*attachment})
+
+	c.JSON(http.StatusOK, api.APIResponse{Message: "Attachment deleted"})
+}
@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"fmt"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/scheduling"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func toAvailabilityResponse(availability *models.Availability) api.AvailabilityResponse {
+	return api.AvailabilityResponse{
+		ID:        availability.ID,
+		Kind:      availability.Kind,
+		Weekday:   availability.Weekday,
+		StartTime: availability.StartTime,
+		EndTime:   availability.EndTime,
+		StartsAt:  availability.StartsAt,
+		EndsAt:    availability.EndsAt,
+		Note:      availability.Note,
+	}
+}
+
+// applyAvailabilityRequest validates the request and copies it into the entry, it returns an error message on failure
+func applyAvailabilityRequest(availability *models.Availability, request *api.AvailabilityRequest) string {
+	if !models.IsValidAvailabilityKind(request.Kind) {
+		return "Invalid kind. Use unavailable, preferred or block."
+	}
+
+	availability.Kind = request.Kind
+	availability.Note = request.Note
+
+	if request.Kind == models.AvailabilityBlock {
+		if request.Weekday != nil || request.StartTime != "" || request.EndTime != "" {
+			return "Blocks take starts_at and ends_at, not a weekday"
+		}
+		if request.StartsAt == nil || request.EndsAt == nil {
+			return "Blocks need starts_at and ends_at"
+		}
+		if !request.EndsAt.After(*request.StartsAt) {
+			return "Block must end after it starts"
+		}
+		startsAt, endsAt := request.StartsAt.UTC(), request.EndsAt.UTC()
+		availability.StartsAt, availability.EndsAt = &startsAt, &endsAt
+		availability.Weekday, availability.StartTime, availability.EndTime = nil, "", ""
+		return ""
+	}
+
+	if request.StartsAt != nil || request.EndsAt != nil {
+		return "Weekly windows take a weekday, not starts_at and ends_at"
+	}
+	if request.Weekday == nil || *request.Weekday < int(time.Sunday) || *request.Weekday > int(time.Saturday) {
+		return "Weekday must be between 0 (Sunday) and 6 (Saturday)"
+	}
+	start, startErr := time.Parse("15:04", request.StartTime)
+	end, endErr := time.Parse("15:04", request.EndTime)
+	if startErr != nil || endErr != nil || len(request.StartTime) != 5 || len(request.EndTime) != 5 {
+		return "Invalid time format. Use HH:MM format (24-hour)."
+	}
+	if !start.Before(end) {
+		return "End time must be after start time"
+	}
+	weekday := *request.Weekday
+	availability.Weekday = &weekday
+	availability.StartTime, availability.EndTime = request.StartTime, request.EndTime
+	availability.StartsAt, availability.EndsAt = nil, nil
+	return ""
+}
+
+// weeklyIntervals returns the occurrences of a weekly window that overlap the window,
+// wall clock times are read in the zone of the user
+func weeklyIntervals(availability *models.Availability, location *time.Location, window scheduling.Interval) []scheduling.Interval {
+	var intervals []scheduling.Interval
+	from := window.Start.In(location)
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, location)
+	for ; day.Before(window.End); day = day.AddDate(0, 0, 1) {
+		if int(day.Weekday()) != *availability.Weekday {
+			continue
+		}
+		interval := dayWindow(day, availability.StartTime, availability.EndTime)
+		if interval.Overlaps(window) {
+			intervals = append(intervals, interval)
+		}
+	}
+	return intervals
+}
+
+// getUserAvailabilityIntervals returns the declared unavailable and preferred intervals of the user inside the window
+func getUserAvailabilityIntervals(db *gorm.DB, user *models.User, window scheduling.Interval) ([]scheduling.Interval, []scheduling.Interval) {
+	var entries []models.Availability
+	db.Where("user_id = ?", user.ID).Find(&entries)
+
+	var unavailable, preferred []scheduling.Interval
+	for i := range entries {
+		entry := &entries[i]
+		switch entry.Kind {
+		case models.AvailabilityBlock:
+			block := scheduling.Interval{Start: *entry.StartsAt, End: *entry.EndsAt}
+			if block.Overlaps(window) {
+				unavailable = append(unavailable, block)
+			}
+		case models.AvailabilityUnavailable:
+			unavailable = append(unavailable, weeklyIntervals(entry, user.Location(), window)...)
+		case models.AvailabilityPreferred:
+			preferred = append(preferred, weeklyIntervals(entry, user.Location(), window)...)
+		}
+	}
+	return unavailable, preferred
+}
+
+// findOwnAvailability loads the availability entry from the path, it has to belong to the caller
+func findOwnAvailability(c *gin.Context, db *gorm.DB) (*models.Availability, bool) {
+	var availabilityID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &availabilityID); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid availability ID format"})
+		return nil, false
+	}
+
+	userID, _ := c.Get("user_id")
+	var availability models.Availability
+	if err := db.Where("id = ? AND user_id = ?", availabilityID, userID).First(&availability).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Availability not found"})
+		return nil, false
+	}
+	return &availability, true
+}
+
+// @Summary Get user's availability
+// @Description Get the weekly unavailable and preferred windows and the one-off blocks of the authenticated user
+// @Tags availability
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} api.AvailabilitiesResponse "Availability retrieved successfully"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 500 {object} api.APIResponse "Failed to retrieve availability"
+// @Router /availability [get]
+func GetAvailability(c *gin.Context, db *gorm.DB) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, api.APIResponse{Error: "User not authenticated"})
+		return
+	}
+
+	var entries []models.Availability
+	if err := db.Where("user_id = ?", userID).Order("kind, weekday, start_time, starts_at").Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve availability"})
+		return
+	}
+
+	response := api.AvailabilitiesResponse{Availability: []api.AvailabilityResponse{}}
+	for _, entry := range entries {
+		response.Availability = append(response.Availability, toAvailabilityResponse(&entry))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Declare availability
+// @Description Add a weekly unavailable or preferred window, or a one-off block. Best time searches treat unavailable windows and blocks as busy and prefer slots inside preferred windows
+// @Tags availability
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body api.AvailabilityRequest true "Availability details"
+// @Success 200 {object} api.APIResponse{data=api.AvailabilityResponse} "Availability created successfully"
+// @Failure 400 {object} api.APIResponse "Invalid payload"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 500 {object} api.APIResponse "Failed to create availability"
+// @Router /availability [post]
+func CreateAvailability(c *gin.Context, db *gorm.DB) {
+	var request api.AvailabilityRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	availability := models.Availability{UserID: userID.(uint)}
+	if message := applyAvailabilityRequest(&availability, &request); message != "" {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: message})
+		return
+	}
+
+	if err := db.Create(&availability).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to create availability"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Availability created",
+		Data:    toAvailabilityResponse(&availability),
+	})
+}
+
+// @Summary Update availability
+// @Description Replace an availability entry of the authenticated user
+// @Tags availability
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Availability ID"
+// @Param request body api.AvailabilityRequest true "Availability details"
+// @Success 200 {object} api.APIResponse{data=api.AvailabilityResponse} "Availability updated successfully"
+// @Failure 400 {object} api.APIResponse "Invalid payload"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 404 {object} api.APIResponse "Availability not found"
+// @Failure 500 {object} api.APIResponse "Failed to update availability"
+// @Router /availability/{id} [put]
+func UpdateAvailability(c *gin.Context, db *gorm.DB) {
+	availability, ok := findOwnAvailability(c, db)
+	if !ok {
+		return
+	}
+
+	var request api.AvailabilityRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	if message := applyAvailabilityRequest(availability, &request); message != "" {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: message})
+		return
+	}
+
+	if err := db.Save(availability).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update availability"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Availability updated",
+		Data:    toAvailabilityResponse(availability),
+	})
+}
+
+// @Summary Delete availability
+// @Description Delete an availability entry of the authenticated user
+// @Tags availability
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Availability ID"
+// @Success 200 {object} api.APIResponse "Availability deleted successfully"
+// @Failure 400 {object} api.APIResponse "Invalid availability ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 404 {object} api.APIResponse "Availability not found"
+// @Failure 500 {object} api.APIResponse "Failed to delete availability"
+// @Router /availability/{id} [delete]
+func DeleteAvailability(c *gin.Context, db *gorm.DB) {
+	availability, ok := findOwnAvailability(c, db)
+	if !ok {
+		return
+	}
+
+	if err := db.Delete(availability).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete availability"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{Message: "Availability deleted"})
+}
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"fmt"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/money"
+	"itsplanned/permissions"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func toBudgetCategoryResponse(category *models.BudgetCategory, currency string) api.BudgetCategoryResponse {
+	return api.BudgetCategoryResponse{
+		ID:    category.ID,
+		Name:  category.Name,
+		Limit: money.FromMinor(category.Limit, currency),
+	}
+}
+
+// loadBudgetCategory loads the budget category from the path, it has to belong to the event
+func loadBudgetCategory(c *gin.Context, db *gorm.DB, event *models.Event) (*models.BudgetCategory, bool) {
+	var categoryID uint
+	if _, err := fmt.Sscanf(c.Param("category_id"), "%d", &categoryID); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid category ID format"})
+		return nil, false
+	}
+
+	var category models.BudgetCategory
+	if err := db.Where("id = ? AND event_id = ?", categoryID, event.ID).First(&category).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Budget category not found"})
+		return nil, false
+	}
+	return &category, true
+}
+
+// loadBudgetManagedEvent loads the event from the path for a user who manages its budget
+func loadBudgetManagedEvent(c *gin.Context, db *gorm.DB) (*models.Event, bool) {
+	event, role, ok := loadViewableEvent(c, db)
+	if !ok {
+		return nil, false
+	}
+	if !permissions.CanManageBudget(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only organizers and co-organizers can manage budget categories"})
+		return nil, false
+	}
+	return event, true
+}
+
+// applyBudgetCategoryRequest validates the request and copies it into the category, the limits of all categories
+// together can't exceed the initial budget. It returns an error message on failure.
+func applyBudgetCategoryRequest(db *gorm.DB, event *models.Event, category *models.BudgetCategory, request *api.BudgetCategoryRequest) string {
+	name := normalizeCategory(request.Name)
+	if name == "" {
+		return "Name is required"
+	}
+	if name == uncategorized {
+		return "The uncategorized category can't have a limit"
+	}
+	if request.Limit < 0 {
+		return "Limit can't be negative"
+	}
+
+	var others []models.BudgetCategory
+	db.Where("event_id = ? AND id <> ?", event.ID, category.ID).Find(&others)
+
+	limit := money.ToMinor(request.Limit, event.Currency)
+	allocated := limit
+	for _, other := range others {
+		if other.Name == name {
+			return "A budget category with this name already exists"
+		}
+		allocated += other.Limit
+	}
+	if allocated > event.InitialBudget {
+		return fmt.Sprintf("Category limits can't exceed the initial budget, %.*f is left to allocate",
+			money.Exponent(event.Currency), money.FromMinor(event.InitialBudget-allocated+limit, event.Currency))
+	}
+
+	category.Name = name
+	category.Limit = limit
+	return ""
+}
+
+// @Summary Get budget categories
+// @Description Get the budget categories of the event with how much of the initial budget is allocated to them
+// @Tags budget
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Success 200 {object} api.BudgetCategoriesResponse "Budget categories retrieved successfully"
+// @Failure 400 {object} api.APIResponse "Invalid ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not a participant of the event"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to retrieve budget categories"
+// @Router /events/{id}/budget/categories [get]
+func GetBudgetCategories(c *gin.Context, db *gorm.DB) {
+	event, _, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	var categories []models.BudgetCategory
+	if err := db.Where("event_id = ?", event.ID).Order("name ASC").Find(&categories).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve budget categories"})
+		return
+	}
+
+	response := api.BudgetCategoriesResponse{Categories: []api.BudgetCategoryResponse{}, Currency: event.Currency}
+	var allocated int64
+	for _, category := range categories {
+		response.Categories = append(response.Categories, toBudgetCategoryResponse(&category, event.Currency))
+		allocated += category.Limit
+	}
+	response.Allocated = money.FromMinor(allocated, event.Currency)
+	response.Unallocated = money.FromMinor(event.InitialBudget-allocated, event.Currency)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Create a budget category
+// @Description Set aside part of the initial budget for tasks and expenses of a category. Only organizers and co-organizers can do it
+// @Tags budget
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param request body api.BudgetCategoryRequest true "Budget category details"
+// @Success 200 {object} api.APIResponse{data=api.BudgetCategoryResponse} "Budget category created successfully"
+// @Failure 400 {object} api.APIResponse "Invalid payload or limits exceed the initial budget"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not an organizer or co-organizer"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to create budget category"
+// @Router /events/{id}/budget/categories [post]
+func CreateBudgetCategory(c *gin.Context, db *gorm.DB) {
+	event, ok := loadBudgetManagedEvent(c, db)
+	if !ok {
+		return
+	}
+
+	var request api.BudgetCategoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	category := models.BudgetCategory{EventID: event.ID}
+	if message := applyBudgetCategoryRequest(db, event, &category, &request); message != "" {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: message})
+		return
+	}
+
+	if err := db.Create(&category).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to create budget category"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Budget category created",
+		Data:    toBudgetCategoryResponse(&category, event.Currency),
+	})
+}
+
+// @Summary Update a budget category
+// @Description Replace the name and limit of a budget category, renaming it renames the category of its tasks and expenses
+// @Tags budget
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param category_id path int true "Budget category ID"
+// @Param request body api.BudgetCategoryRequest true "Budget category details"
+// @Success 200 {object} api.APIResponse{data=api.BudgetCategoryResponse} "Budget category updated successfully"
+// @Failure 400 {object} api.APIResponse "Invalid payload or limits exceed the initial budget"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not an organizer or co-organizer"
+// @Failure 404 {object} api.APIResponse "Event or budget category not found"
+// @Failure 500 {object} api.APIResponse "Failed to update budget category"
+// @Router /events/{id}/budget/categories/{category_id} [put]
+func UpdateBudgetCategory(c *gin.Context, db *gorm.DB) {
+	event, ok := loadBudgetManagedEvent(c, db)
+	if !ok {
+		return
+	}
+
+	category, ok := loadBudgetCategory(c, db, event)
+	if !ok {
+		return
+	}
+
+	var request api.BudgetCategoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	previousName := category.Name
+	if message := applyBudgetCategoryRequest(db, event, category, &request); message != "" {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: message})
+		return
+	}
+
+	tx := db.Begin()
+
+	if err := tx.Save(category).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update budget category"})
+		return
+	}
+
+	if category.Name != previousName {
+		if err := tx.Model(&models.Task{}).Where("event_id = ? AND category = ?", event.ID, previousName).Update("category", category.Name).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update budget category"})
+			return
+		}
+		if err := tx.Model(&models.Expense{}).Where("event_id = ? AND category = ?", event.ID, previousName).Update("category", category.Name).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update budget category"})
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update budget category"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Budget category updated",
+		Data:    toBudgetCategoryResponse(category, event.Currency),
+	})
+}
+
+// @Summary Delete a budget category
+// @Description Remove the limit of a category, its tasks and expenses keep their category
+// @Tags budget
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param category_id path int true "Budget category ID"
+// @Success 200 {object} api.APIResponse "Budget category deleted successfully"
+// @Failure 400 {object} api.APIResponse "Invalid ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not an organizer or co-organizer"
+// @Failure 404 {object} api.APIResponse "Event or budget category not found"
+// @Failure 500 {object} api.APIResponse "Failed to delete budget category"
+// @Router /events/{id}/budget/categories/{category_id} [delete]
+func DeleteBudgetCategory(c *gin.Context, db *gorm.DB) {
+	event, ok := loadBudgetManagedEvent(c, db)
+	if !ok {
+		return
+	}
+
+	category, ok := loadBudgetCategory(c, db, event)
+	if !ok {
+		return
+	}
+
+	if err := db.Delete(category).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete budget category"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{Message: "Budget category deleted"})
+}
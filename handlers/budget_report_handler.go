@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/money"
+	"itsplanned/report"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// reportFontPaths are where fonts with Cyrillic letters are installed on common distributions,
+// REPORT_FONT_FILE overrides them
+var reportFontPaths = []string{
+	"/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf",
+	"/usr/share/fonts/dejavu/DejaVuSans.ttf",
+	"/usr/share/fonts/TTF/DejaVuSans.ttf",
+}
+
+var (
+	reportFont     *report.Font
+	reportFontOnce sync.Once
+)
+
+// loadReportFont finds the font PDF reports are set in, without one they fall back to Helvetica
+func loadReportFont() *report.Font {
+	reportFontOnce.Do(func() {
+		paths := reportFontPaths
+		if path := os.Getenv("REPORT_FONT_FILE"); path != "" {
+			paths = []string{path}
+		}
+		for _, path := range paths {
+			font, err := report.LoadFont(path)
+			if err == nil {
+				reportFont = font
+				return
+			}
+			if !os.IsNotExist(err) {
+				log.Printf("Failed to load report font %s: %v", path, err)
+			}
+		}
+		log.Println("No TrueType font for PDF reports found, non-Latin text will be lost, set REPORT_FONT_FILE")
+	})
+	return reportFont
+}
+
+// budgetReportFormats maps the export formats to their content types
+var budgetReportFormats = map[string]string{
+	"csv":  "text/csv; charset=utf-8",
+	"xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"pdf":  "application/pdf",
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
+
+// budgetReport lays out the budget of the event with its tasks and categories
+func budgetReport(db *gorm.DB, event *models.Event, budget *api.EventBudgetResponse, generatedAt time.Time) *report.Report {
+	currency := event.Currency
+	decimals := money.Exponent(currency)
+	amount := func(value float64) report.Cell {
+		return report.Number(value, decimals)
+	}
+
+	tasks := append([]models.Task{}, event.Tasks...)
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	spentByTask := map[uint]float64{}
+	for _, line := range budget.Tasks {
+		spentByTask[line.TaskID] = line.Actual
+	}
+
+	assignees := map[uint]string{}
+	var completed, points int
+	var plannedTotal, spentTotal int64
+	taskRows := make([][]report.Cell, 0, len(tasks))
+	for _, task := range tasks {
+		assignee := ""
+		if task.AssignedTo != nil {
+			if _, ok := assignees[*task.AssignedTo]; !ok {
+				assignees[*task.AssignedTo] = getUserDisplayName(db, *task.AssignedTo)
+			}
+			assignee = assignees[*task.AssignedTo]
+		}
+		status := "Open"
+		if task.IsCompleted {
+			status = "Completed"
+			completed++
+		}
+		category := task.Category
+		if category == "" {
+			category = uncategorized
+		}
+
+		spent := money.ToMinor(spentByTask[task.ID], currency)
+		plannedTotal += task.Budget
+		spentTotal += spent
+		points += task.Points
+
+		taskRows = append(taskRows, []report.Cell{
+			report.Text(task.Title),
+			report.Text(category),
+			report.Text(assignee),
+			report.Text(status),
+			report.Int(task.Points),
+			amount(money.FromMinor(task.Budget, currency)),
+			amount(money.FromMinor(spent, currency)),
+			amount(money.FromMinor(task.Budget-spent, currency)),
+		})
+	}
+
+	categoryRows := make([][]report.Cell, 0, len(budget.Categories))
+	for _, line := range budget.Categories {
+		row := []report.Cell{report.Text(line.Category), {}, amount(line.Planned), amount(line.Actual), {}, report.Text(yesNo(line.OverBudget))}
+		if line.Limit != nil {
+			row[1], row[4] = amount(*line.Limit), amount(*line.Remaining)
+		}
+		categoryRows = append(categoryRows, row)
+	}
+
+	summaryRows := [][]report.Cell{
+		report.Texts("Event", event.Name),
+		report.Texts("Date", seriesStart(event).Format("2006-01-02 15:04 MST")),
+		report.Texts("Currency", currency),
+		{report.Text("Initial budget"), amount(budget.InitialBudget)},
+		{report.Text("Planned for tasks"), amount(budget.Planned)},
+		{report.Text("Planned for completed tasks"), amount(budget.RealBudget)},
+		{report.Text("Spent"), amount(budget.Actual)},
+		{report.Text("Remaining"), amount(budget.Remaining)},
+		{report.Text("Allocated to categories"), amount(budget.Allocated)},
+		report.Texts("Tasks completed", fmt.Sprintf("%d of %d", completed, len(tasks))),
+		report.Texts("Over budget", yesNo(budget.OverBudget)),
+	}
+	if len(budget.UnconvertedCurrencies) > 0 {
+		summaryRows = append(summaryRows, report.Texts("Expenses left out, no exchange rate", strings.Join(budget.UnconvertedCurrencies, ", ")))
+	}
+	summaryRows = append(summaryRows, report.Texts("Generated", generatedAt.UTC().Format("2006-01-02 15:04 UTC")))
+
+	return &report.Report{
+		Title: "Budget report: " + event.Name,
+		Sections: []report.Section{
+			{
+				Title: "Summary",
+				Rows:  summaryRows,
+			},
+			{
+				Title:   "Tasks",
+				Columns: []string{"Task", "Category", "Assignee", "Status", "Points", "Planned", "Spent", "Difference"},
+				Rows:    taskRows,
+				Total: []report.Cell{
+					report.Text("Total"), {}, {},
+					report.Text(fmt.Sprintf("%d of %d completed", completed, len(tasks))),
+					report.Int(points),
+					amount(money.FromMinor(plannedTotal, currency)),
+					amount(money.FromMinor(spentTotal, currency)),
+					amount(money.FromMinor(plannedTotal-spentTotal, currency)),
+				},
+			},
+			{
+				Title:   "Categories",
+				Columns: []string{"Category", "Limit", "Planned", "Spent", "Remaining", "Over budget"},
+				Rows:    categoryRows,
+				Total:   []report.Cell{report.Text("Total"), amount(budget.Allocated), amount(budget.Planned), amount(budget.Actual), {}, {}},
+			},
+		},
+	}
+}
+
+// @Summary Export the event budget
+// @Description Download a report of the event budget for spreadsheets or printing: a summary, every task with its category,
+// @Description assignee, status, points, planned budget and spending, and the budget categories, with totals.
+// @Description Amounts are in the event currency. PDF reports are A4 landscape
+// @Tags events
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param format query string false "Report format: csv, xlsx or pdf, csv by default"
+// @Success 200 {file} file "The budget report"
+// @Failure 400 {object} api.APIResponse "Invalid format"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not a participant of the event"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to generate report"
+// @Router /events/{id}/budget/export [get]
+func ExportEventBudget(c *gin.Context, db *gorm.DB) {
+	format := c.DefaultQuery("format", "csv")
+	contentType, ok := budgetReportFormats[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid format. Use csv, xlsx or pdf."})
+		return
+	}
+
+	event, budget, ok := loadEventBudget(c, db)
+	if !ok {
+		return
+	}
+
+	document := budgetReport(db, event, budget, time.Now())
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "csv":
+		err = report.WriteCSV(&buf, document)
+	case "xlsx":
+		err = report.WriteXLSX(&buf, document)
+	case "pdf":
+		err = report.WritePDF(&buf, document, loadReportFont())
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to generate report"})
+		return
+	}
+
+	fileName := fmt.Sprintf("budget-event-%d.%s", event.ID, format)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
@@ -66,14 +66,14 @@ func ImportCalendarEventsForUser(db *gorm.DB, userID uint, accessToken string) e
 			log.Printf("Error parsing start time for event %s: %v", item.Id, err)
 			continue
 		}
-		startTime = startTime.In(time.FixedZone("MSK", 3*60*60))
+		startTime = startTime.UTC()
 
 		endTime, err := time.Parse(time.RFC3339, item.End.DateTime)
 		if err != nil {
 			log.Printf("Error parsing end time for event %s: %v", item.Id, err)
 			continue
 		}
-		endTime = endTime.In(time.FixedZone("MSK", 3*60*60))
+		endTime = endTime.UTC()
 
 		var existingEvent models.CalendarEvent
 		if err := db.Where("user_id = ? AND title = ? AND start_time = ?", userID, item.Summary, startTime).First(&existingEvent).Error; err == nil {
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"fmt"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/permissions"
+	"itsplanned/services/email"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// toEmailInvitationResponse leaves the join link out unless withInviteCode is set. Addresses aren't verified,
+// so the invitee only gets the link through the email itself
+func toEmailInvitationResponse(db *gorm.DB, invitation *models.EmailInvitation, withInviteCode bool) api.EmailInvitationResponse {
+	response := api.EmailInvitationResponse{
+		ID:          invitation.ID,
+		EventID:     invitation.EventID,
+		Email:       invitation.Email,
+		InvitedByID: invitation.InvitedByID,
+		UserID:      invitation.UserID,
+		Status:      invitation.Status,
+		CreatedAt:   invitation.CreatedAt,
+		RespondedAt: invitation.RespondedAt,
+	}
+
+	var event models.Event
+	if err := db.Select("name").First(&event, invitation.EventID).Error; err == nil {
+		response.EventName = event.Name
+	}
+
+	if !withInviteCode {
+		return response
+	}
+	var eventInvitation models.EventInvitation
+	if err := db.Select("invite_code").First(&eventInvitation, invitation.InvitationID).Error; err == nil {
+		response.InviteCode = eventInvitation.InviteCode
+	}
+
+	return response
+}
+
+// failEmailInvitation marks an invitation whose email could not be sent and revokes its join link,
+// the address can be invited again
+func failEmailInvitation(db *gorm.DB, invitation *models.EmailInvitation) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(invitation).Update("status", models.EmailInvitationFailed).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.EventInvitation{}).Where("id = ?", invitation.InvitationID).Update("revoked_at", time.Now()).Error
+	})
+}
+
+// @Summary Invite a person by email
+// @Description Send a personal invitation email with a single use join link. If the address has no account yet, the invitation is linked once it registers
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param request body api.EmailInvitationRequest true "Email address to invite"
+// @Success 200 {object} api.APIResponse{data=api.EmailInvitationResponse} "Invitation sent"
+// @Failure 400 {object} api.APIResponse "Invalid payload, already a participant or already invited"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not allowed to invite to the event"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to send invitation"
+// @Router /events/{id}/invitations/email [post]
+func SendEmailInvitation(c *gin.Context, db *gorm.DB) {
+	var request api.EmailInvitationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	var event models.Event
+	if err := db.First(&event, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if role, _ := permissions.GetRole(db, &event, userID.(uint)); !permissions.CanInvite(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not allowed to invite people to this event"})
+		return
+	}
+
+	address := models.NormalizeEmail(request.Email)
+
+	var invitedUserID *uint
+	var invitedUser models.User
+	if err := db.Where("LOWER(email) = ?", address).First(&invitedUser).Error; err == nil {
+		if _, ok := permissions.GetRole(db, &event, invitedUser.ID); ok {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "This person is already in this event"})
+			return
+		}
+		invitedUserID = &invitedUser.ID
+	}
+
+	oneUse := 1
+	eventInvitation := models.EventInvitation{
+		EventID:     event.ID,
+		InviteCode:  models.GenerateUniqueInviteCode(db),
+		CreatedByID: userID.(uint),
+		MaxUses:     &oneUse,
+	}
+
+	// The event lock keeps concurrent requests from inviting the same address twice
+	tx := db.Begin()
+	if _, err := lockEvent(tx, event.ID); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to create invitation"})
+		return
+	}
+
+	var existing models.EmailInvitation
+	if err := tx.Where("event_id = ? AND email = ? AND status = ?", event.ID, address, models.EmailInvitationSent).First(&existing).Error; err == nil {
+		tx.Rollback()
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "This email has already been invited"})
+		return
+	}
+
+	if err := tx.Create(&eventInvitation).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to create invitation"})
+		return
+	}
+
+	invitation := models.EmailInvitation{
+		EventID:      event.ID,
+		InvitationID: eventInvitation.ID,
+		Email:        address,
+		InvitedByID:  userID.(uint),
+		UserID:       invitedUserID,
+		Status:       models.EmailInvitationSent,
+	}
+	if err := tx.Create(&invitation).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to create invitation"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to create invitation"})
+		return
+	}
+
+	// The email goes out after the commit, no connection or lock is held during the delivery
+	if err := email.SendEventInvitationEmail(address, email.EventInvitationEmail{
+		EventName:     event.Name,
+		EventDateTime: event.EventDateTime.In(event.Location()).Format("02.01.2006 15:04 MST"),
+		InviterName:   getUserDisplayName(db, userID.(uint)),
+		InviteLink:    inviteLinkFor(eventInvitation.InviteCode),
+	}); err != nil {
+		log.Printf("Failed to send invitation email for event %d: %v", event.ID, err)
+		if err := failEmailInvitation(db, &invitation); err != nil {
+			log.Printf("Failed to revoke undelivered invitation %d: %v", invitation.ID, err)
+		}
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to send invitation email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Invitation sent",
+		Data:    toEmailInvitationResponse(db, &invitation, true),
+	})
+}
+
+// @Summary List email invitations of an event
+// @Description Get all personal email invitations of an event with their status
+// @Tags invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Success 200 {object} api.EmailInvitationsResponse "Email invitations"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not allowed to invite to the event"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to retrieve invitations"
+// @Router /events/{id}/invitations/email [get]
+func GetEventEmailInvitations(c *gin.Context, db *gorm.DB) {
+	var event models.Event
+	if err := db.First(&event, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if role, _ := permissions.GetRole(db, &event, userID.(uint)); !permissions.CanInvite(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not allowed to invite people to this event"})
+		return
+	}
+
+	var invitations []models.EmailInvitation
+	if err := db.Where("event_id = ?", event.ID).Order("created_at DESC").Find(&invitations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve invitations"})
+		return
+	}
+
+	response := api.EmailInvitationsResponse{Invitations: []api.EmailInvitationResponse{}}
+	for _, invitation := range invitations {
+		response.Invitations = append(response.Invitations, toEmailInvitationResponse(db, &invitation, true))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary List my email invitations
+// @Description Get email invitations sent to the authenticated user that are waiting for an answer.
+// @Description The join links are only in the invitation emails
+// @Tags invitations
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} api.EmailInvitationsResponse "Pending email invitations"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 500 {object} api.APIResponse "Failed to retrieve invitations"
+// @Router /invitations/email [get]
+func GetMyEmailInvitations(c *gin.Context, db *gorm.DB) {
+	userID, _ := c.Get("user_id")
+
+	var invitations []models.EmailInvitation
+	if err := db.Where("user_id = ? AND status = ?", userID, models.EmailInvitationSent).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve invitations"})
+		return
+	}
+
+	response := api.EmailInvitationsResponse{Invitations: []api.EmailInvitationResponse{}}
+	for _, invitation := range invitations {
+		response.Invitations = append(response.Invitations, toEmailInvitationResponse(db, &invitation, false))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Decline an email invitation
+// @Description Decline a personal invitation, its join link stops working and the inviter is notified
+// @Tags invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Email invitation ID"
+// @Success 200 {object} api.APIResponse "Invitation declined"
+// @Failure 400 {object} api.APIResponse "Invalid ID or invitation already answered"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 404 {object} api.APIResponse "Invitation not found"
+// @Failure 500 {object} api.APIResponse "Failed to decline invitation"
+// @Router /invitations/email/{id}/decline [post]
+func DeclineEmailInvitation(c *gin.Context, db *gorm.DB) {
+	var invitationID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &invitationID); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid invitation ID format"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	var invitation models.EmailInvitation
+	if err := db.Where("id = ? AND user_id = ?", invitationID, userID).First(&invitation).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Invitation not found"})
+		return
+	}
+
+	if invitation.Status != models.EmailInvitationSent {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invitation has already been answered"})
+		return
+	}
+
+	now := time.Now()
+	tx := db.Begin()
+
+	if err := tx.Model(&invitation).Updates(map[string]interface{}{
+		"status":       models.EmailInvitationDeclined,
+		"responded_at": now,
+	}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to decline invitation"})
+		return
+	}
+
+	if err := tx.Model(&models.EventInvitation{}).Where("id = ?", invitation.InvitationID).Update("revoked_at", now).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to decline invitation"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to decline invitation"})
+		return
+	}
+
+	var event models.Event
+	if err := db.First(&event, invitation.EventID).Error; err == nil {
+		notifyEventUser(db, &event, invitation.InvitedByID, userID.(uint), models.EmailInvitationSent, models.EmailInvitationDeclined)
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{Message: "Invitation declined"})
+}
@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"itsplanned/models"
 	"itsplanned/models/api"
+	"itsplanned/money"
+	"itsplanned/permissions"
+	"itsplanned/scheduling"
+	"itsplanned/services/exchange"
 	"net/http"
-	"sort"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,91 +20,149 @@ import (
 const leftTimeBoundForBusySlots string = "08:00"
 const rightTimeBoundForBusySlots string = "22:00"
 
+const defaultSlotGranularityMins int64 = 30
+const defaultSuggestionsLimit = 10
+
+// maxSearchDays bounds the date range of a best time search
+const maxSearchDays = 31
+
 func toEventResponse(event *models.Event) *api.EventResponse {
 	if event == nil {
 		return nil
 	}
 	return &api.EventResponse{
-		ID:            event.ID,
-		CreatedAt:     event.CreatedAt,
-		UpdatedAt:     event.UpdatedAt,
-		Name:          event.Name,
-		Description:   event.Description,
-		EventDateTime: event.EventDateTime,
-		InitialBudget: event.InitialBudget,
-		OrganizerID:   event.OrganizerID,
-		Place:         event.Place,
+		ID:             event.ID,
+		CreatedAt:      event.CreatedAt,
+		UpdatedAt:      event.UpdatedAt,
+		Name:           event.Name,
+		Description:    event.Description,
+		EventDateTime:  event.EventDateTime.In(event.Location()),
+		InitialBudget:  money.FromMinor(event.InitialBudget, event.Currency),
+		Currency:       event.Currency,
+		OrganizerID:    event.OrganizerID,
+		Place:          event.Place,
+		Capacity:       event.Capacity,
+		Timezone:       event.Timezone,
+		RecurrenceRule: event.RecurrenceRule,
 	}
 }
 
-func getUserBusySlotsForDay(db *gorm.DB, userID uint, date string, durationMins int64, busySlots *map[string]int) {
-	var events []models.CalendarEvent
-	db.Where("user_id = ? AND DATE(start_time) = ?", userID, date).Find(&events)
+// loadViewableEvent loads the event from the path with the role of the current user, who has to see the event
+func loadViewableEvent(c *gin.Context, db *gorm.DB) (*models.Event, string, bool) {
+	var eventID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &eventID); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid event ID format"})
+		return nil, "", false
+	}
 
-	fmt.Println(len(events))
+	var event models.Event
+	if err := db.First(&event, eventID).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+		return nil, "", false
+	}
 
-	for _, event := range events {
-		start := event.StartTime
-		end := event.EndTime
+	userID, _ := c.Get("user_id")
+	role, _ := permissions.GetRole(db, &event, userID.(uint))
+	if !permissions.CanView(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not a participant of this event"})
+		return nil, "", false
+	}
 
-		for start.Minute() != 0 && start.Minute() != 30 {
-			start = start.Add(-time.Minute)
-		}
+	return &event, role, true
+}
 
-		for end.Minute() != 0 && end.Minute() != 30 {
-			end = end.Add(time.Minute)
-		}
+// getUserBusyIntervals returns the calendar events of the user that overlap the window
+func getUserBusyIntervals(db *gorm.DB, userID uint, window scheduling.Interval) []scheduling.Interval {
+	var events []models.CalendarEvent
+	// Times are stored in UTC, comparing in the same zone keeps the query correct in every database
+	db.Where("user_id = ? AND start_time < ? AND end_time > ?", userID, window.End.UTC(), window.Start.UTC()).Find(&events)
 
-		start = start.Add(-time.Duration(max(0, durationMins-30)) * time.Minute)
-		end = end.Add(time.Duration(max(0, durationMins-30)) * time.Minute)
+	intervals := make([]scheduling.Interval, 0, len(events))
+	for _, event := range events {
+		intervals = append(intervals, scheduling.Interval{Start: event.StartTime, End: event.EndTime})
+	}
+	return intervals
+}
 
-		end = end.Add(time.Minute)
+// loadSchedulingParticipants loads the participants of the event with their busy and preferred intervals inside the window,
+// busy intervals come from imported calendars and declared availability.
+// Preferences make listed participants optional or change their weight, they must belong to the event.
+func loadSchedulingParticipants(db *gorm.DB, eventID uint, preferences []api.SchedulingParticipant, window scheduling.Interval) ([]scheduling.Participant, string) {
+	var users []models.User
+	db.Joins("JOIN event_participations ON event_participations.user_id = users.id").
+		Where("event_participations.event_id = ?", eventID).
+		Find(&users)
+
+	participants := make([]scheduling.Participant, 0, len(users))
+	for _, user := range users {
+		unavailable, preferred := getUserAvailabilityIntervals(db, &user, window)
+		participants = append(participants, scheduling.Participant{
+			UserID:    user.ID,
+			Busy:      append(getUserBusyIntervals(db, user.ID, window), unavailable...),
+			Preferred: preferred,
+		})
+	}
 
-		for t := start; t.Before(end); t = t.Add(time.Minute * 30) {
-			key := t.Format("15:04")
-			(*busySlots)[key]++
-			fmt.Println(key)
+	for _, preference := range preferences {
+		if preference.Weight < 0 {
+			return nil, "Participant weight cannot be negative"
+		}
+		found := false
+		for i := range participants {
+			if participants[i].UserID == preference.UserID {
+				participants[i].Optional = preference.Optional
+				participants[i].Weight = preference.Weight
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Sprintf("User %d is not a participant of this event", preference.UserID)
 		}
 	}
+	return participants, ""
 }
 
-func suggestTimeSlotsForDay(busySlots *map[string]int, date string, durationMins int64, startTime string, endTime string) []api.TimeSlotSuggestion {
+// dayWindow returns the part of the day between the HH:MM bounds, read as wall clock time in the zone of the day
+func dayWindow(day time.Time, startTime, endTime string) scheduling.Interval {
 	start, _ := time.Parse("15:04", startTime)
 	end, _ := time.Parse("15:04", endTime)
+	return scheduling.Interval{
+		Start: time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, day.Location()),
+		End:   time.Date(day.Year(), day.Month(), day.Day(), end.Hour(), end.Minute(), 0, 0, day.Location()),
+	}
+}
 
-	var timeSlots []api.TimeSlotSuggestion
-	timeCursor := start
-
-	for timeCursor.Before(end) {
-		maxBusy := 0
-
-		// for i := int64(0); i < durationMins; i += 30 {
-		key := timeCursor.Format("15:04")
-		if (*busySlots)[key] > 0 {
-			if (*busySlots)[key] > maxBusy {
-				maxBusy = (*busySlots)[key]
-			}
-		}
-		// }
-
-		timeSlots = append(timeSlots, api.TimeSlotSuggestion{
-			Slot:      date + " " + timeCursor.Format("15:04"),
-			BusyCount: maxBusy,
-		})
-
-		timeCursor = timeCursor.Add(time.Minute * 30)
+func toTimeSlotSuggestion(slot scheduling.Slot) api.TimeSlotSuggestion {
+	return api.TimeSlotSuggestion{
+		Slot:                  slot.Start.Format("2006-01-02 15:04"),
+		BusyCount:             len(slot.BusyUserIDs),
+		BusyParticipantIDs:    slot.BusyUserIDs,
+		RequiredBusyCount:     slot.RequiredBusy,
+		Score:                 slot.Score,
+		OutsidePreferredCount: slot.OutsidePreferred,
 	}
+}
 
-	// Sort by ascending number of busy participants (fewer is better)
-	sort.Slice(timeSlots, func(i, j int) bool {
-		return timeSlots[i].BusyCount < timeSlots[j].BusyCount
-	})
+// rankTimeSlots ranks the slots of all windows and returns at most limit of them
+func rankTimeSlots(participants []scheduling.Participant, windows []scheduling.Interval, durationMins, granularityMins int64, limit int) []scheduling.Slot {
+	var slots []scheduling.Slot
+	for _, window := range windows {
+		slots = append(slots, scheduling.Suggest(participants, window, time.Duration(durationMins)*time.Minute, time.Duration(granularityMins)*time.Minute)...)
+	}
+	scheduling.Rank(slots)
 
-	if len(timeSlots) > 5 {
-		timeSlots = timeSlots[:5]
+	if len(slots) > limit {
+		slots = slots[:limit]
 	}
+	return slots
+}
 
-	return timeSlots
+func toTimeSlotSuggestions(slots []scheduling.Slot) []api.TimeSlotSuggestion {
+	suggestions := make([]api.TimeSlotSuggestion, 0, len(slots))
+	for _, slot := range slots {
+		suggestions = append(suggestions, toTimeSlotSuggestion(slot))
+	}
+	return suggestions
 }
 
 // @Summary Create a new event
@@ -127,14 +190,42 @@ func CreateEvent(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
+	if request.Capacity != nil && *request.Capacity < 1 {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Capacity must be at least 1"})
+		return
+	}
+
+	recurrenceRule, err := normalizeRecurrenceRule(request.RecurrenceRule)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid recurrence rule: " + err.Error()})
+		return
+	}
+
+	currency := money.Normalize(request.Currency)
+	if !money.IsSupported(currency) {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Unsupported currency"})
+		return
+	}
+
 	userID, _ := c.Get("user_id")
+	if request.Timezone == "" {
+		request.Timezone = getUserTimezone(db, userID.(uint))
+	}
+	if _, err := models.LoadTimezone(request.Timezone); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid timezone"})
+		return
+	}
 	event := models.Event{
-		Name:          request.Name,
-		Description:   request.Description,
-		EventDateTime: eventTime,
-		InitialBudget: request.InitialBudget,
-		OrganizerID:   userID.(uint),
-		Place:         request.Place,
+		Name:           request.Name,
+		Description:    request.Description,
+		EventDateTime:  eventTime.UTC(),
+		InitialBudget:  money.ToMinor(request.InitialBudget, currency),
+		Currency:       currency,
+		OrganizerID:    userID.(uint),
+		Place:          request.Place,
+		Capacity:       request.Capacity,
+		Timezone:       request.Timezone,
+		RecurrenceRule: recurrenceRule,
 	}
 
 	if err := db.Create(&event).Error; err != nil {
@@ -145,6 +236,7 @@ func CreateEvent(c *gin.Context, db *gorm.DB) {
 	eventParticipation := models.EventParticipation{
 		EventID: event.ID,
 		UserID:  event.OrganizerID,
+		Role:    models.RoleOrganizer,
 	}
 
 	if err := db.Create(&eventParticipation).Error; err != nil {
@@ -159,7 +251,8 @@ func CreateEvent(c *gin.Context, db *gorm.DB) {
 }
 
 // @Summary Update an event
-// @Description Update an existing event's details
+// @Description Update an existing event's details. For recurring events the scope selects whether one occurrence,
+// @Description the occurrence and the following ones or the whole series changes
 // @Tags events
 // @Accept json
 // @Produce json
@@ -167,9 +260,9 @@ func CreateEvent(c *gin.Context, db *gorm.DB) {
 // @Param id path int true "Event ID"
 // @Param request body api.UpdateEventRequest true "Event update details"
 // @Success 200 {object} api.APIResponse{data=api.EventResponse} "Event updated successfully"
-// @Failure 400 {object} api.APIResponse "Invalid payload"
+// @Failure 400 {object} api.APIResponse "Invalid payload, scope or occurrence"
 // @Failure 401 {object} api.APIResponse "Unauthorized"
-// @Failure 403 {object} api.APIResponse "Forbidden - not the organizer"
+// @Failure 403 {object} api.APIResponse "Forbidden - not an organizer or co-organizer"
 // @Failure 404 {object} api.APIResponse "Event not found"
 // @Router /events/{id} [put]
 func UpdateEvent(c *gin.Context, db *gorm.DB) {
@@ -182,8 +275,9 @@ func UpdateEvent(c *gin.Context, db *gorm.DB) {
 	}
 
 	userID, _ := c.Get("user_id")
-	if userID.(uint) != event.OrganizerID {
-		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not the organizer of this event"})
+	role, _ := permissions.GetRole(db, &event, userID.(uint))
+	if !permissions.CanEditEvent(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only organizers and co-organizers can edit this event"})
 		return
 	}
 
@@ -193,6 +287,157 @@ func UpdateEvent(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
+	if (request.Budget != nil || request.Currency != nil) && !permissions.CanManageBudget(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not allowed to manage the budget of this event"})
+		return
+	}
+
+	scope := request.Scope
+	if scope == "" {
+		scope = models.EditScopeAll
+	}
+	if !isValidEditScope(scope) {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid scope. Use this, following or all."})
+		return
+	}
+
+	if scope != models.EditScopeAll {
+		occurrence, message := resolveOccurrence(&event, request.OccurrenceTime)
+		if message != "" {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: message})
+			return
+		}
+		if scope == models.EditScopeThis {
+			updateOccurrence(c, db, &event, occurrence, &request)
+			return
+		}
+		// Editing from the first occurrence on is the same as editing the whole series
+		if !occurrence.Equal(event.EventDateTime) {
+			updateFollowingOccurrences(c, db, &event, occurrence, &request)
+			return
+		}
+	}
+
+	previousStart, previousRule, previousTimezone, previousCurrency := event.EventDateTime, event.RecurrenceRule, event.Timezone, event.Currency
+	if message := applyEventUpdate(&event, &request); message != "" {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: message})
+		return
+	}
+	// Overrides are keyed by the times the old rule produced
+	rescheduled := previousRule != "" &&
+		(event.RecurrenceRule != previousRule || !event.EventDateTime.Equal(previousStart) || event.Timezone != previousTimezone)
+
+	currencyChanged := event.Currency != previousCurrency
+
+	if request.Capacity == nil && !rescheduled && !currencyChanged {
+		db.Save(&event)
+	} else {
+		// A raised or removed limit lets waitlisted users in
+		tx := db.Begin()
+		if err := tx.Save(&event).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update event"})
+			return
+		}
+		if rescheduled {
+			if err := tx.Unscoped().Where("event_id = ?", event.ID).Delete(&models.EventOccurrenceOverride{}).Error; err != nil {
+				tx.Rollback()
+				c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update event"})
+				return
+			}
+		}
+		if currencyChanged {
+			if err := convertEventBudgets(tx, event.ID, previousCurrency, event.Currency); err != nil {
+				tx.Rollback()
+				if errors.Is(err, errNoExchangeRate) {
+					c.JSON(http.StatusBadRequest, api.APIResponse{Error: noExchangeRateMessage(previousCurrency, event.Currency)})
+					return
+				}
+				c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update event"})
+				return
+			}
+		}
+		var promoted []uint
+		if request.Capacity != nil {
+			var err error
+			if promoted, err = promoteFromWaitlist(tx, event.ID); err != nil {
+				tx.Rollback()
+				c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update event"})
+				return
+			}
+		}
+		if err := tx.Commit().Error; err != nil {
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update event"})
+			return
+		}
+		notifyPromoted(db, &event, promoted, userID.(uint))
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Event updated successfully",
+		Data:    toEventResponse(&event),
+	})
+}
+
+var errNoExchangeRate = errors.New("no exchange rate between the currencies")
+
+func noExchangeRateMessage(from, to string) string {
+	return fmt.Sprintf("No exchange rate from %s to %s", from, to)
+}
+
+// convertBudget converts an amount into another currency at the current rate, there's nothing to convert in an empty budget
+func convertBudget(minor int64, from, to string) (int64, error) {
+	if minor == 0 {
+		return 0, nil
+	}
+	converted, err := exchange.Convert(minor, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", errNoExchangeRate, err)
+	}
+	return converted, nil
+}
+
+// convertEventBudgets converts task budgets and category limits at the current rate when the event moves to another currency
+func convertEventBudgets(tx *gorm.DB, eventID uint, from, to string) error {
+	var tasks []models.Task
+	if err := tx.Where("event_id = ?", eventID).Find(&tasks).Error; err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		budget, err := convertBudget(task.Budget, from, to)
+		if err != nil {
+			return err
+		}
+		if budget == task.Budget {
+			continue
+		}
+		if err := tx.Model(&task).Update("budget_minor", budget).Error; err != nil {
+			return err
+		}
+	}
+
+	var categories []models.BudgetCategory
+	if err := tx.Where("event_id = ?", eventID).Find(&categories).Error; err != nil {
+		return err
+	}
+	for _, category := range categories {
+		limit, err := convertBudget(category.Limit, from, to)
+		if err != nil {
+			return err
+		}
+		if limit == category.Limit {
+			continue
+		}
+		if err := tx.Model(&category).Update("limit_minor", limit).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyEventUpdate copies the fields set in the request to the event, it returns the message
+// to respond with when the request is invalid
+func applyEventUpdate(event *models.Event, request *api.UpdateEventRequest) string {
 	if request.Name != nil {
 		event.Name = *request.Name
 	}
@@ -202,56 +447,147 @@ func UpdateEvent(c *gin.Context, db *gorm.DB) {
 	if request.EventDateTime != nil {
 		eventTime, err := time.Parse(time.RFC3339, *request.EventDateTime)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid date format"})
-			return
+			return "Invalid date format"
 		}
-		event.EventDateTime = eventTime
+		event.EventDateTime = eventTime.UTC()
+	}
+	// The currency goes first so a new budget is read in it
+	if request.Currency != nil {
+		currency := money.Normalize(*request.Currency)
+		if !money.IsSupported(currency) {
+			return "Unsupported currency"
+		}
+		budget, err := convertBudget(event.InitialBudget, event.Currency, currency)
+		if err != nil {
+			return noExchangeRateMessage(event.Currency, currency)
+		}
+		event.InitialBudget = budget
+		event.Currency = currency
 	}
 	if request.Budget != nil {
-		event.InitialBudget = *request.Budget
+		event.InitialBudget = money.ToMinor(*request.Budget, event.Currency)
+	}
+	if request.Timezone != nil {
+		if _, err := models.LoadTimezone(*request.Timezone); err != nil || *request.Timezone == "" {
+			return "Invalid timezone"
+		}
+		event.Timezone = *request.Timezone
 	}
 	if request.Place != nil {
 		event.Place = *request.Place
 	}
+	if request.Capacity != nil {
+		if *request.Capacity < 0 {
+			return "Capacity cannot be negative"
+		}
+		event.Capacity = request.Capacity
+		if *request.Capacity == 0 {
+			event.Capacity = nil
+		}
+	}
+	if request.RecurrenceRule != nil {
+		recurrenceRule, err := normalizeRecurrenceRule(*request.RecurrenceRule)
+		if err != nil {
+			return "Invalid recurrence rule: " + err.Error()
+		}
+		event.RecurrenceRule = recurrenceRule
+	}
+	return ""
+}
 
-	db.Save(&event)
-	c.JSON(http.StatusOK, api.APIResponse{
-		Message: "Event updated successfully",
-		Data:    toEventResponse(&event),
-	})
+// loadEventBudget loads the event from the path with its tasks and works out its budget,
+// the user has to be a participant of the event
+func loadEventBudget(c *gin.Context, db *gorm.DB) (*models.Event, *api.EventBudgetResponse, bool) {
+	var event models.Event
+	id := c.Param("id")
+
+	if err := db.Preload("Tasks").First(&event, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+		return nil, nil, false
+	}
+
+	userID, _ := c.Get("user_id")
+	if role, _ := permissions.GetRole(db, &event, userID.(uint)); !permissions.CanView(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not a participant of this event"})
+		return nil, nil, false
+	}
+
+	var expenses []models.Expense
+	if err := db.Where("event_id = ?", event.ID).Find(&expenses).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve expenses"})
+		return nil, nil, false
+	}
+
+	expenses, unconverted := convertExpenses(&event, expenses)
+
+	var budgetCategories []models.BudgetCategory
+	if err := db.Where("event_id = ?", event.ID).Find(&budgetCategories).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve budget categories"})
+		return nil, nil, false
+	}
+
+	var realBudget, planned int64
+	for _, task := range event.Tasks {
+		planned += task.Budget
+		if task.IsCompleted {
+			realBudget += task.Budget
+		}
+	}
+
+	var actual int64
+	for _, expense := range expenses {
+		actual += expense.Amount
+	}
+
+	var allocated int64
+	for _, budgetCategory := range budgetCategories {
+		allocated += budgetCategory.Limit
+	}
+
+	tasks, categories := budgetBreakdown(event.Currency, event.Tasks, expenses, budgetCategories)
+	overBudget := planned > event.InitialBudget || actual > event.InitialBudget
+	for _, category := range categories {
+		overBudget = overBudget || category.OverBudget
+	}
+
+	return &event, &api.EventBudgetResponse{
+		Currency:              event.Currency,
+		InitialBudget:         money.FromMinor(event.InitialBudget, event.Currency),
+		RealBudget:            money.FromMinor(realBudget, event.Currency),
+		Difference:            money.FromMinor(event.InitialBudget-realBudget, event.Currency),
+		Planned:               money.FromMinor(planned, event.Currency),
+		Actual:                money.FromMinor(actual, event.Currency),
+		Remaining:             money.FromMinor(event.InitialBudget-actual, event.Currency),
+		Allocated:             money.FromMinor(allocated, event.Currency),
+		OverBudget:            overBudget,
+		Tasks:                 tasks,
+		Categories:            categories,
+		UnconvertedCurrencies: unconverted,
+	}, true
 }
 
 // @Summary Get event budget details
-// @Description Get the budget details for an event, including initial budget, real budget, and difference
+// @Description Get the budget details for an event, including initial budget, real budget, and difference,
+// @Description with planned budget against recorded expenses per task and per category.
+// @Description Amounts are in the event currency, expenses in other currencies are converted at the current exchange rate.
+// @Description Budget categories report their limit and what remains of it, over_budget warns when a category or the event is over its limit
 // @Tags events
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Event ID"
 // @Success 200 {object} api.EventBudgetResponse "Budget details retrieved successfully"
 // @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not a participant of the event"
 // @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to retrieve expenses or budget categories"
 // @Router /events/{id}/budget [get]
 func GetEventBudget(c *gin.Context, db *gorm.DB) {
-	var event models.Event
-	id := c.Param("id")
-
-	if err := db.Preload("Tasks").First(&event, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+	_, budget, ok := loadEventBudget(c, db)
+	if !ok {
 		return
 	}
 
-	realBudget := 0.0
-	for _, task := range event.Tasks {
-		if task.IsCompleted {
-			realBudget += task.Budget
-		}
-	}
-
-	c.JSON(http.StatusOK, api.EventBudgetResponse{
-		InitialBudget: event.InitialBudget,
-		RealBudget:    realBudget,
-		Difference:    event.InitialBudget - realBudget,
-	})
+	c.JSON(http.StatusOK, budget)
 }
 
 // @Summary Get event leaderboard
@@ -263,15 +599,19 @@ func GetEventBudget(c *gin.Context, db *gorm.DB) {
 // @Success 200 {object} api.EventLeaderboardResponse "Leaderboard retrieved successfully"
 // @Failure 401 {object} api.APIResponse "Unauthorized"
 // @Failure 403 {object} api.APIResponse "Not a participant"
-// @Failure 404 {object} api.APIResponse "Leaderboard not found"
+// @Failure 404 {object} api.APIResponse "Event or leaderboard not found"
 // @Router /events/{id}/leaderboard [get]
 func GetEventLeaderboard(c *gin.Context, db *gorm.DB) {
 	userID, _ := c.Get("user_id")
 	eventID := c.Param("id")
 
-	// Check if the user is the organizer or a participant of the event
-	var participation models.EventParticipation
-	if err := db.Where("event_id = ? AND user_id = ?", eventID, userID).First(&participation).Error; err != nil {
+	var event models.Event
+	if err := db.First(&event, eventID).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+		return
+	}
+
+	if role, _ := permissions.GetRole(db, &event, userID.(uint)); !permissions.CanView(role) {
 		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not a participant of this event"})
 		return
 	}
@@ -306,11 +646,15 @@ func GetEventLeaderboard(c *gin.Context, db *gorm.DB) {
 }
 
 // @Summary Get user's events
-// @Description Get all events where the user is a participant
+// @Description Get all events where the user is a participant. Recurring events are expanded into their occurrences
+// @Description in the given range, which defaults to the next 90 days
 // @Tags events
 // @Produce json
 // @Security BearerAuth
+// @Param from query string false "Start of the range for recurring events, RFC3339"
+// @Param to query string false "End of the range for recurring events, RFC3339"
 // @Success 200 {object} api.APIResponse{data=[]api.EventResponse} "Events retrieved successfully"
+// @Failure 400 {object} api.APIResponse "Invalid range"
 // @Failure 401 {object} api.APIResponse "Unauthorized"
 // @Router /events [get]
 func GetEvents(c *gin.Context, db *gorm.DB) {
@@ -320,6 +664,30 @@ func GetEvents(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
+	now := time.Now().In(getUserLocation(db, userID.(uint)))
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid from format. Use RFC3339 format."})
+			return
+		}
+		from = parsed
+	}
+	to := from.Add(occurrenceWindow)
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid to format. Use RFC3339 format."})
+			return
+		}
+		to = parsed
+	}
+	if !to.After(from) {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "The end of the range must be after its start"})
+		return
+	}
+
 	var events []models.Event
 	db.Joins("JOIN event_participations ON events.id = event_participations.event_id").
 		Where("event_participations.user_id = ?", userID).
@@ -327,16 +695,25 @@ func GetEvents(c *gin.Context, db *gorm.DB) {
 
 	var response []api.EventResponse
 	for _, event := range events {
-		if eventResponse := toEventResponse(&event); eventResponse != nil {
-			response = append(response, *eventResponse)
+		if event.RecurrenceRule == "" {
+			response = append(response, *toEventResponse(&event))
+			continue
 		}
+
+		overrides, err := loadOverrides(db, event.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to load events"})
+			return
+		}
+		response = append(response, expandEvent(&event, overrides, from, to)...)
 	}
 
 	c.JSON(http.StatusOK, api.APIResponse{Data: response})
 }
 
 // @Summary Find best time slots for an event
-// @Description Find the best available time slots for an event based on participants' schedules and specified time range
+// @Description Find the best available time slots for an event based on participants' schedules and specified time range.
+// @Description Every candidate is checked over its whole duration, slots that suit all required participants come first
 // @Tags events
 // @Accept json
 // @Produce json
@@ -354,6 +731,20 @@ func FindBestTimeSlotsForDay(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
+	userID, _ := c.Get("user_id")
+	slots, status, message := findBestSlotsForDay(db, userID.(uint), &request)
+	if message != "" {
+		c.JSON(status, api.APIResponse{Error: message})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.FindBestTimeSlotsResponse{
+		Suggestions: toTimeSlotSuggestions(slots),
+	})
+}
+
+// findBestSlotsForDay runs the search of a single day for the user, on failure it returns the HTTP status with an error message
+func findBestSlotsForDay(db *gorm.DB, userID uint, request *api.FindBestTimeSlotsRequest) ([]scheduling.Slot, int, string) {
 	if request.StartTime == "" {
 		request.StartTime = leftTimeBoundForBusySlots
 	}
@@ -366,66 +757,176 @@ func FindBestTimeSlotsForDay(c *gin.Context, db *gorm.DB) {
 	_, endErr := time.Parse("15:04", request.EndTime)
 
 	if startErr != nil || endErr != nil {
-		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid time format. Use HH:MM format (24-hour)."})
-		return
+		return nil, http.StatusBadRequest, "Invalid time format. Use HH:MM format (24-hour)."
 	}
 
 	if len(request.StartTime) != 5 || request.StartTime[2] != ':' {
-		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid start time format. Use HH:MM format (24-hour)."})
-		return
+		return nil, http.StatusBadRequest, "Invalid start time format. Use HH:MM format (24-hour)."
 	}
 
 	if len(request.EndTime) != 5 || request.EndTime[2] != ':' {
-		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid end time format. Use HH:MM format (24-hour)."})
-		return
+		return nil, http.StatusBadRequest, "Invalid end time format. Use HH:MM format (24-hour)."
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", request.Date, getUserLocation(db, userID))
+	if err != nil {
+		return nil, http.StatusBadRequest, "Invalid date format. Use YYYY-MM-DD format."
+	}
+
+	if request.DurationMins < 0 {
+		return nil, http.StatusBadRequest, "Duration cannot be negative"
 	}
 
 	var event models.Event
 	if err := db.First(&event, request.EventID).Error; err != nil {
-		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+		return nil, http.StatusNotFound, "Event not found"
+	}
+
+	if role, _ := permissions.GetRole(db, &event, userID); !permissions.CanView(role) {
+		return nil, http.StatusForbidden, "You are not a participant of this event"
+	}
+
+	window := dayWindow(date, request.StartTime, request.EndTime)
+	participants, message := loadSchedulingParticipants(db, request.EventID, request.Participants, window)
+	if message != "" {
+		return nil, http.StatusBadRequest, message
+	}
+
+	if len(participants) == 0 {
+		return nil, http.StatusNotFound, "No participants found"
+	}
+
+	return rankTimeSlots(participants, []scheduling.Interval{window}, request.DurationMins, defaultSlotGranularityMins, 5), http.StatusOK, ""
+}
+
+// parseWorkingHours checks the HH:MM bounds of a day, they have to be aligned to the granularity
+func parseWorkingHours(hours api.WorkingHours, granularityMins int64) bool {
+	start, startErr := time.Parse("15:04", hours.StartTime)
+	end, endErr := time.Parse("15:04", hours.EndTime)
+	if startErr != nil || endErr != nil || len(hours.StartTime) != 5 || len(hours.EndTime) != 5 {
+		return false
+	}
+	return start.Before(end) && int64(start.Minute())%granularityMins == 0 && int64(end.Minute())%granularityMins == 0
+}
+
+// @Summary Find best time slots across a date range
+// @Description Rank start times of every day in the range by busy required participants and weighted busyness, with working hours per weekday
+// @Tags events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body api.FindBestTimeRequest true "Find best time request details"
+// @Success 200 {object} api.FindBestTimeSlotsResponse "Time slots found successfully"
+// @Failure 400 {object} api.APIResponse "Invalid request"
+// @Failure 403 {object} api.APIResponse "Forbidden - not a participant of the event"
+// @Failure 404 {object} api.APIResponse "Event not found or no participants"
+// @Router /events/find_best_time [post]
+func FindBestTimeSlots(c *gin.Context, db *gorm.DB) {
+	var request api.FindBestTimeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	if request.GranularityMins == 0 {
+		request.GranularityMins = defaultSlotGranularityMins
+	}
+	if request.GranularityMins != 15 && request.GranularityMins != 30 && request.GranularityMins != 60 {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Granularity must be 15, 30 or 60 minutes"})
 		return
 	}
+	if request.DurationMins < 0 {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Duration cannot be negative"})
+		return
+	}
+	if request.Limit <= 0 {
+		request.Limit = defaultSuggestionsLimit
+	}
 
 	userID, _ := c.Get("user_id")
-	if event.OrganizerID != userID.(uint) {
-		var participation models.EventParticipation
-		if err := db.Where("event_id = ? AND user_id = ?", request.EventID, userID).First(&participation).Error; err != nil {
-			c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not a participant of this event"})
+	location := getUserLocation(db, userID.(uint))
+	startDate, startErr := time.ParseInLocation("2006-01-02", request.StartDate, location)
+	endDate, endErr := time.ParseInLocation("2006-01-02", request.EndDate, location)
+	if startErr != nil || endErr != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid date format. Use YYYY-MM-DD format."})
+		return
+	}
+	if endDate.Before(startDate) {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "End date cannot be before start date"})
+		return
+	}
+	if endDate.Sub(startDate) >= maxSearchDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: fmt.Sprintf("Date range cannot be longer than %d days", maxSearchDays)})
+		return
+	}
+
+	workingHours := make(map[time.Weekday]api.WorkingHours)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		hours, ok := request.WorkingHours[strings.ToLower(day.String())]
+		if len(request.WorkingHours) == 0 {
+			hours, ok = api.WorkingHours{StartTime: leftTimeBoundForBusySlots, EndTime: rightTimeBoundForBusySlots}, true
+		}
+		if !ok {
+			continue
+		}
+		if !parseWorkingHours(hours, request.GranularityMins) {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: fmt.Sprintf("Invalid working hours for %s. Use HH:MM format (24-hour) aligned to the granularity.", strings.ToLower(day.String()))})
 			return
 		}
+		workingHours[day] = hours
+	}
+	if len(workingHours) != len(request.WorkingHours) && len(request.WorkingHours) > 0 {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Working hours must be keyed by weekday name, like monday"})
+		return
 	}
 
-	var participants []models.User
-	db.Joins("JOIN event_participations ON event_participations.user_id = users.id").
-		Where("event_participations.event_id = ?", request.EventID).
-		Find(&participants)
+	var event models.Event
+	if err := db.First(&event, request.EventID).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+		return
+	}
 
-	if len(participants) == 0 {
-		c.JSON(http.StatusNotFound, api.APIResponse{Error: "No participants found"})
+	if role, _ := permissions.GetRole(db, &event, userID.(uint)); !permissions.CanView(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not a participant of this event"})
+		return
+	}
+
+	var windows []scheduling.Interval
+	for day := startDate; !day.After(endDate); day = day.AddDate(0, 0, 1) {
+		if hours, ok := workingHours[day.Weekday()]; ok {
+			windows = append(windows, dayWindow(day, hours.StartTime, hours.EndTime))
+		}
+	}
+
+	// Busy intervals are loaded once for the whole range
+	searchRange := scheduling.Interval{Start: startDate, End: endDate.AddDate(0, 0, 1)}
+	participants, message := loadSchedulingParticipants(db, request.EventID, request.Participants, searchRange)
+	if message != "" {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: message})
 		return
 	}
 
-	busySlots := make(map[string]int)
-	for _, user := range participants {
-		getUserBusySlotsForDay(db, user.ID, request.Date, request.DurationMins, &busySlots)
+	if len(participants) == 0 {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "No participants found"})
+		return
 	}
 
-	suggestedSlots := suggestTimeSlotsForDay(&busySlots, request.Date, request.DurationMins, request.StartTime, request.EndTime)
+	slots := rankTimeSlots(participants, windows, request.DurationMins, request.GranularityMins, request.Limit)
 
 	c.JSON(http.StatusOK, api.FindBestTimeSlotsResponse{
-		Suggestions: suggestedSlots,
+		Suggestions: toTimeSlotSuggestions(slots),
 	})
 }
 
 // GetEventParticipants godoc
 // @Summary Get event participants
-// @Description Get a list of display names of all participants in an event
+// @Description Get all participants of an event with their role and RSVP, the organizer comes first
 // @Tags events
 // @Accept json
 // @Produce json
 // @Param id path int true "Event ID"
 // @Security BearerAuth
-// @Success 200 {object} api.EventParticipantsResponse "List of participants' display names"
+// @Success 200 {object} api.EventParticipantsResponse "List of participants"
 // @Failure 400 {object} api.APIResponse "Invalid event ID"
 // @Failure 401 {object} api.APIResponse "Unauthorized"
 // @Failure 403 {object} api.APIResponse "Forbidden - not a participant of the event"
@@ -456,13 +957,9 @@ func GetEventParticipants(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
-	var participation models.EventParticipation
-	if err := db.Where("event_id = ? AND user_id = ?", eventID, userID).First(&participation).Error; err != nil {
-		// Also check if the user is the organizer
-		if event.OrganizerID != userID.(uint) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You are not a participant of this event"})
-			return
-		}
+	if role, _ := permissions.GetRole(db, &event, userID.(uint)); !permissions.CanView(role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not a participant of this event"})
+		return
 	}
 
 	var participations []models.EventParticipation
@@ -471,11 +968,21 @@ func GetEventParticipants(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
-	var participants []string
+	participants := []api.EventParticipantResponse{}
 
-	var organizer models.User
-	if err := db.First(&organizer, event.OrganizerID).Error; err == nil {
-		participants = append(participants, organizer.DisplayName)
+	// Events created before participations were stored have no row for the organizer
+	organizerParticipation := models.EventParticipation{
+		EventID:    event.ID,
+		UserID:     event.OrganizerID,
+		RSVPStatus: models.RSVPNoResponse,
+	}
+	for _, p := range participations {
+		if p.UserID == event.OrganizerID {
+			organizerParticipation = p
+		}
+	}
+	if participant, ok := toEventParticipantResponse(db, &event, &organizerParticipation); ok {
+		participants = append(participants, participant)
 	}
 
 	for _, p := range participations {
@@ -483,9 +990,8 @@ func GetEventParticipants(c *gin.Context, db *gorm.DB) {
 			continue
 		}
 
-		var user models.User
-		if err := db.First(&user, p.UserID).Error; err == nil {
-			participants = append(participants, user.DisplayName)
+		if participant, ok := toEventParticipantResponse(db, &event, &p); ok {
+			participants = append(participants, participant)
 		}
 	}
 
@@ -496,7 +1002,7 @@ func GetEventParticipants(c *gin.Context, db *gorm.DB) {
 
 // GetEvent godoc
 // @Summary Get event details
-// @Description Get detailed information about a specific event
+// @Description Get detailed information about a specific event including RSVP counts
 // @Tags events
 // @Produce json
 // @Security BearerAuth
@@ -521,28 +1027,37 @@ func GetEvent(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
-	if event.OrganizerID != userID.(uint) {
-		var participation models.EventParticipation
-		if err := db.Where("event_id = ? AND user_id = ?", event.ID, userID).First(&participation).Error; err != nil {
-			c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not a participant of this event"})
-			return
-		}
+	if role, _ := permissions.GetRole(db, &event, userID.(uint)); !permissions.CanView(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not a participant of this event"})
+		return
 	}
 
+	response := toEventResponse(&event)
+	counts, err := countRSVPs(db, event.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to count RSVPs"})
+		return
+	}
+	response.RSVPCounts = counts
+
 	c.JSON(http.StatusOK, api.APIResponse{
-		Data: toEventResponse(&event),
+		Data: response,
 	})
 }
 
 // @Summary Delete an event
-// @Description Delete an event and all associated data (tasks, event scores, participations, invitations)
+// @Description Delete an event and all associated data (tasks, event scores, participations, invitations).
+// @Description For recurring events the scope "this" cancels one occurrence and "following" ends the series before it
 // @Tags events
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Event ID"
+// @Param scope query string false "Scope for recurring events" Enums(this, following, all)
+// @Param occurrence_time query string false "Occurrence for the this and following scopes, RFC3339"
 // @Success 200 {object} api.APIResponse "Event deleted successfully"
 // @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 400 {object} api.APIResponse "Invalid scope or occurrence"
 // @Failure 403 {object} api.APIResponse "Forbidden - not the organizer"
 // @Failure 404 {object} api.APIResponse "Event not found"
 // @Failure 500 {object} api.APIResponse "Failed to delete event"
@@ -557,15 +1072,38 @@ func DeleteEvent(c *gin.Context, db *gorm.DB) {
 	}
 
 	userID, _ := c.Get("user_id")
-	if userID.(uint) != event.OrganizerID {
+	if role, _ := permissions.GetRole(db, &event, userID.(uint)); !permissions.CanDeleteEvent(role) {
 		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not the organizer of this event"})
 		return
 	}
 
+	scope := c.DefaultQuery("scope", models.EditScopeAll)
+	if !isValidEditScope(scope) {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid scope. Use this, following or all."})
+		return
+	}
+
+	if scope != models.EditScopeAll {
+		occurrenceTime := c.Query("occurrence_time")
+		occurrence, message := resolveOccurrence(&event, &occurrenceTime)
+		if message != "" {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: message})
+			return
+		}
+		if scope == models.EditScopeThis {
+			cancelOccurrence(c, db, &event, occurrence)
+			return
+		}
+		if !occurrence.Equal(event.EventDateTime) {
+			deleteFollowingOccurrences(c, db, &event, occurrence)
+			return
+		}
+	}
+
 	// Delete all related data in a transaction
 	tx := db.Begin()
 
-	if err := tx.Exec("DELETE FROM task_status_events WHERE task_id IN (SELECT id FROM tasks WHERE event_id = ?)", eventID).Error; err != nil {
+	if err := tx.Exec("DELETE FROM task_status_events WHERE event_id = ? OR task_id IN (SELECT id FROM tasks WHERE event_id = ?)", event.ID, event.ID).Error; err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete task status events"})
 		return
@@ -595,6 +1133,103 @@ func DeleteEvent(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
+	if err := tx.Where("event_id = ?", eventID).Delete(&models.EmailInvitation{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete event email invitations"})
+		return
+	}
+
+	if err := tx.Where("event_id = ?", eventID).Delete(&models.WaitlistEntry{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete event waitlist"})
+		return
+	}
+
+	if err := tx.Where("event_id = ?", eventID).Delete(&models.JoinRequest{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete event join requests"})
+		return
+	}
+
+	if err := tx.Where("event_id = ?", eventID).Delete(&models.EventOwnershipTransfer{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete event ownership history"})
+		return
+	}
+
+	if err := tx.Where("event_id = ?", eventID).Delete(&models.EventOccurrenceOverride{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete event occurrence changes"})
+		return
+	}
+
+	if err := tx.Exec("DELETE FROM time_poll_votes WHERE option_id IN (SELECT id FROM time_poll_options WHERE poll_id IN (SELECT id FROM time_polls WHERE event_id = ?))", event.ID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete event time poll votes"})
+		return
+	}
+
+	if err := tx.Exec("DELETE FROM time_poll_options WHERE poll_id IN (SELECT id FROM time_polls WHERE event_id = ?)", event.ID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete event time poll options"})
+		return
+	}
+
+	if err := tx.Unscoped().Where("event_id = ?", eventID).Delete(&models.TimePoll{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete event time polls"})
+		return
+	}
+
+	if err := tx.Exec("DELETE FROM poll_votes WHERE poll_id IN (SELECT id FROM polls WHERE event_id = ?)", event.ID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete event poll votes"})
+		return
+	}
+
+	if err := tx.Exec("DELETE FROM poll_options WHERE poll_id IN (SELECT id FROM polls WHERE event_id = ?)", event.ID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete event poll options"})
+		return
+	}
+
+	if err := tx.Unscoped().Where("event_id = ?", eventID).Delete(&models.Poll{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete event polls"})
+		return
+	}
+
+	if err := tx.Exec("DELETE FROM expense_shares WHERE expense_id IN (SELECT id FROM expenses WHERE event_id = ?)", event.ID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete event expense shares"})
+		return
+	}
+
+	if err := tx.Where("event_id = ?", eventID).Delete(&models.Settlement{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete event settlements"})
+		return
+	}
+
+	if err := tx.Where("event_id = ?", eventID).Delete(&models.BudgetCategory{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete event budget categories"})
+		return
+	}
+
+	attachments, err := deleteAttachmentRows(tx, "event_id = ?", event.ID)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete event attachments"})
+		return
+	}
+
+	if err := tx.Where("event_id = ?", eventID).Delete(&models.Expense{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete event expenses"})
+		return
+	}
+
 	if err := tx.Delete(&event).Error; err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete event"})
@@ -605,8 +1240,104 @@ func DeleteEvent(c *gin.Context, db *gorm.DB) {
 		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete event and associated data"})
 		return
 	}
+	removeAttachmentFiles(c.Request.Context(), attachments)
 
 	c.JSON(http.StatusOK, api.APIResponse{
 		Message: "Event and all associated data deleted successfully",
 	})
 }
+
+// @Summary Transfer event ownership
+// @Description Hand the event over to another participant. The new owner is notified, the previous organizer becomes a co-organizer and is free to leave the event
+// @Tags events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param request body api.TransferOwnershipRequest true "New organizer"
+// @Success 200 {object} api.APIResponse{data=api.EventResponse} "Ownership transferred successfully"
+// @Failure 400 {object} api.APIResponse "Invalid payload"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not the organizer"
+// @Failure 404 {object} api.APIResponse "Event or participant not found"
+// @Failure 500 {object} api.APIResponse "Failed to transfer ownership"
+// @Router /events/{id}/transfer [post]
+func TransferEventOwnership(c *gin.Context, db *gorm.DB) {
+	eventID := c.Param("id")
+
+	var event models.Event
+	if err := db.First(&event, eventID).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	userIDUint := userID.(uint)
+	if role, _ := permissions.GetRole(db, &event, userIDUint); !permissions.CanTransferOwnership(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not the organizer of this event"})
+		return
+	}
+
+	var request api.TransferOwnershipRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	if request.NewOrganizerID == userIDUint {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "You are already the organizer of this event"})
+		return
+	}
+
+	var newOwnerParticipation models.EventParticipation
+	if err := db.Where("event_id = ? AND user_id = ?", event.ID, request.NewOrganizerID).First(&newOwnerParticipation).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "New organizer must be a participant of this event"})
+		return
+	}
+	previousRole := newOwnerParticipation.Role
+
+	tx := db.Begin()
+
+	if err := tx.Model(&event).Update("organizer_id", request.NewOrganizerID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to transfer ownership"})
+		return
+	}
+
+	if err := tx.Model(&newOwnerParticipation).Update("role", models.RoleOrganizer).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update participant roles"})
+		return
+	}
+
+	if err := tx.Model(&models.EventParticipation{}).
+		Where("event_id = ? AND user_id = ?", event.ID, userIDUint).
+		Update("role", models.RoleCoOrganizer).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update participant roles"})
+		return
+	}
+
+	transfer := models.EventOwnershipTransfer{
+		EventID:    event.ID,
+		FromUserID: userIDUint,
+		ToUserID:   request.NewOrganizerID,
+	}
+	if err := tx.Create(&transfer).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to record ownership transfer"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to transfer ownership"})
+		return
+	}
+
+	notifyEventUser(db, &event, request.NewOrganizerID, userIDUint, previousRole, models.RoleOrganizer)
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Event ownership transferred",
+		Data:    toEventResponse(&event),
+	})
+}
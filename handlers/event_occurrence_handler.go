@@ -0,0 +1,382 @@
+package handlers
+
+import (
+	"errors"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/recurrence"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// occurrenceWindow is how far ahead GetEvents expands recurring events when no range is given
+const occurrenceWindow = 90 * 24 * time.Hour
+
+// maxOccurrencesPerEvent caps the expansion of a single series in one response
+const maxOccurrencesPerEvent = 500
+
+// normalizeRecurrenceRule validates an RRULE and returns it in canonical form, an empty rule stays empty
+func normalizeRecurrenceRule(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	rule, err := recurrence.Parse(value)
+	if err != nil {
+		return "", err
+	}
+	return rule.String(), nil
+}
+
+// seriesStart returns the first occurrence in the zone of the event, the rule repeats it at the same local time
+func seriesStart(event *models.Event) time.Time {
+	return event.EventDateTime.In(event.Location())
+}
+
+// occurrenceResponse builds the response of a single occurrence with its override applied
+func occurrenceResponse(event *models.Event, occurrence time.Time, override *models.EventOccurrenceOverride) api.EventResponse {
+	response := *toEventResponse(event)
+	occurrenceTime := occurrence.In(event.Location())
+	response.OccurrenceTime = &occurrenceTime
+	response.EventDateTime = occurrenceTime
+
+	if override != nil {
+		if override.Name != nil {
+			response.Name = *override.Name
+		}
+		if override.Description != nil {
+			response.Description = *override.Description
+		}
+		if override.EventDateTime != nil {
+			response.EventDateTime = override.EventDateTime.In(event.Location())
+		}
+		if override.Place != nil {
+			response.Place = *override.Place
+		}
+	}
+	return response
+}
+
+// findOverride returns the override of the occurrence at the given time, nil when there is none
+func findOverride(overrides []models.EventOccurrenceOverride, occurrence time.Time) *models.EventOccurrenceOverride {
+	for i := range overrides {
+		if overrides[i].OccurrenceTime.Equal(occurrence) {
+			return &overrides[i]
+		}
+	}
+	return nil
+}
+
+func loadOverrides(db *gorm.DB, eventID uint) ([]models.EventOccurrenceOverride, error) {
+	var overrides []models.EventOccurrenceOverride
+	err := db.Where("event_id = ?", eventID).Find(&overrides).Error
+	return overrides, err
+}
+
+// expandEvent returns the occurrences of a recurring event that start inside [from, to),
+// cancelled occurrences are left out
+func expandEvent(event *models.Event, overrides []models.EventOccurrenceOverride, from, to time.Time) []api.EventResponse {
+	rule, err := recurrence.Parse(event.RecurrenceRule)
+	if err != nil {
+		return nil
+	}
+
+	var occurrences []api.EventResponse
+	for _, occurrence := range rule.Between(seriesStart(event), from, to, maxOccurrencesPerEvent) {
+		override := findOverride(overrides, occurrence)
+		if override != nil && override.Cancelled {
+			continue
+		}
+		occurrences = append(occurrences, occurrenceResponse(event, occurrence, override))
+	}
+	return occurrences
+}
+
+// resolveOccurrence checks that the recurring event has an occurrence at the given RFC3339 time
+func resolveOccurrence(event *models.Event, value *string) (time.Time, string) {
+	if event.RecurrenceRule == "" {
+		return time.Time{}, "Only recurring events can be changed per occurrence"
+	}
+	if value == nil || *value == "" {
+		return time.Time{}, "Occurrence time is required for this scope"
+	}
+	occurrence, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return time.Time{}, "Invalid occurrence time format. Use RFC3339 format."
+	}
+	rule, err := recurrence.Parse(event.RecurrenceRule)
+	if err != nil || !rule.Occurs(seriesStart(event), occurrence) {
+		return time.Time{}, "Event has no occurrence at the given time"
+	}
+	return occurrence, ""
+}
+
+func isValidEditScope(scope string) bool {
+	return scope == models.EditScopeThis || scope == models.EditScopeFollowing || scope == models.EditScopeAll
+}
+
+// saveOccurrenceOverride creates the override of an occurrence or updates the existing one
+func saveOccurrenceOverride(tx *gorm.DB, eventID uint, occurrence time.Time, change func(*models.EventOccurrenceOverride)) (*models.EventOccurrenceOverride, error) {
+	overrides, err := loadOverrides(tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	override := findOverride(overrides, occurrence)
+	if override == nil {
+		override = &models.EventOccurrenceOverride{EventID: eventID, OccurrenceTime: occurrence.UTC()}
+	}
+	change(override)
+
+	return override, tx.Save(override).Error
+}
+
+// endSeriesBefore shortens the rule of the event so that its last occurrence comes before the given one.
+// It returns the rule for a series continuing from that occurrence.
+func endSeriesBefore(event *models.Event, occurrence time.Time) (string, error) {
+	rule, err := recurrence.Parse(event.RecurrenceRule)
+	if err != nil {
+		return "", err
+	}
+	following := *rule
+
+	if rule.Count > 0 {
+		before := rule.CountBefore(seriesStart(event), occurrence)
+		rule.Count = before
+		following.Count -= before
+	} else {
+		until := occurrence.Add(-time.Second).UTC()
+		rule.Until = &until
+	}
+
+	event.RecurrenceRule = rule.String()
+	return following.String(), nil
+}
+
+// overridesFrom returns the overrides of occurrences at or after the given one
+func overridesFrom(tx *gorm.DB, eventID uint, occurrence time.Time) ([]models.EventOccurrenceOverride, error) {
+	overrides, err := loadOverrides(tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	var following []models.EventOccurrenceOverride
+	for _, override := range overrides {
+		if !override.OccurrenceTime.Before(occurrence) {
+			following = append(following, override)
+		}
+	}
+	return following, nil
+}
+
+func deleteOverrides(tx *gorm.DB, overrides []models.EventOccurrenceOverride) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(overrides))
+	for i, override := range overrides {
+		ids[i] = override.ID
+	}
+	return tx.Unscoped().Delete(&models.EventOccurrenceOverride{}, ids).Error
+}
+
+// splitSeries saves the shortened series of the event and creates the series following it.
+// Participants, tasks and budget categories are copied to the new series, budgets are converted when its currency differs.
+// The overrides of later occurrences move along when keepOverrides is set,
+// otherwise those overrides no longer line up with the new series and are dropped.
+func splitSeries(tx *gorm.DB, event, following *models.Event, occurrence time.Time, keepOverrides bool) error {
+	if err := tx.Save(event).Error; err != nil {
+		return err
+	}
+
+	following.ID = 0
+	following.CreatedAt = time.Time{}
+	following.UpdatedAt = time.Time{}
+	following.RecurrenceParentID = &event.ID
+	if err := tx.Create(following).Error; err != nil {
+		return err
+	}
+
+	var participations []models.EventParticipation
+	if err := tx.Where("event_id = ?", event.ID).Find(&participations).Error; err != nil {
+		return err
+	}
+	for _, participation := range participations {
+		participation.ID = 0
+		participation.EventID = following.ID
+		if err := tx.Create(&participation).Error; err != nil {
+			return err
+		}
+	}
+
+	var tasks []models.Task
+	if err := tx.Where("event_id = ?", event.ID).Order("id").Find(&tasks).Error; err != nil {
+		return err
+	}
+	if err := copyTasks(tx, following.ID, tasks); err != nil {
+		return err
+	}
+
+	var categories []models.BudgetCategory
+	if err := tx.Where("event_id = ?", event.ID).Order("id").Find(&categories).Error; err != nil {
+		return err
+	}
+	for _, category := range categories {
+		category.ID = 0
+		category.EventID = following.ID
+		if err := tx.Create(&category).Error; err != nil {
+			return err
+		}
+	}
+
+	if following.Currency != event.Currency {
+		if err := convertEventBudgets(tx, following.ID, event.Currency, following.Currency); err != nil {
+			return err
+		}
+	}
+
+	overrides, err := overridesFrom(tx, event.ID, occurrence)
+	if err != nil {
+		return err
+	}
+	if !keepOverrides {
+		return deleteOverrides(tx, overrides)
+	}
+	for _, override := range overrides {
+		if err := tx.Model(&override).Update("event_id", following.ID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateOccurrence changes a single occurrence of a recurring event through its override
+func updateOccurrence(c *gin.Context, db *gorm.DB, event *models.Event, occurrence time.Time, request *api.UpdateEventRequest) {
+	if request.Budget != nil || request.Currency != nil || request.Capacity != nil || request.RecurrenceRule != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Budget, currency, capacity and recurrence cannot be changed for a single occurrence"})
+		return
+	}
+
+	var eventTime *time.Time
+	if request.EventDateTime != nil {
+		parsed, err := time.Parse(time.RFC3339, *request.EventDateTime)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid date format"})
+			return
+		}
+		parsed = parsed.UTC()
+		eventTime = &parsed
+	}
+
+	override, err := saveOccurrenceOverride(db, event.ID, occurrence, func(override *models.EventOccurrenceOverride) {
+		if request.Name != nil {
+			override.Name = request.Name
+		}
+		if request.Description != nil {
+			override.Description = request.Description
+		}
+		if eventTime != nil {
+			override.EventDateTime = eventTime
+		}
+		if request.Place != nil {
+			override.Place = request.Place
+		}
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update occurrence"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Occurrence updated successfully",
+		Data:    occurrenceResponse(event, occurrence, override),
+	})
+}
+
+// updateFollowingOccurrences ends the series before the occurrence and continues it as a new event with the changes applied
+func updateFollowingOccurrences(c *gin.Context, db *gorm.DB, event *models.Event, occurrence time.Time, request *api.UpdateEventRequest) {
+	following := *event
+	followingRule, err := endSeriesBefore(event, occurrence)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update event"})
+		return
+	}
+
+	following.EventDateTime = occurrence.UTC()
+	following.RecurrenceRule = followingRule
+	if message := applyEventUpdate(&following, request); message != "" {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: message})
+		return
+	}
+	keepOverrides := following.RecurrenceRule == followingRule && following.EventDateTime.Equal(occurrence)
+
+	tx := db.Begin()
+	if err := splitSeries(tx, event, &following, occurrence, keepOverrides); err != nil {
+		tx.Rollback()
+		if errors.Is(err, errNoExchangeRate) {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: noExchangeRateMessage(event.Currency, following.Currency)})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update event"})
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update event"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Event updated successfully",
+		Data:    toEventResponse(&following),
+	})
+}
+
+// cancelOccurrence removes a single occurrence of a recurring event
+func cancelOccurrence(c *gin.Context, db *gorm.DB, event *models.Event, occurrence time.Time) {
+	_, err := saveOccurrenceOverride(db, event.ID, occurrence, func(override *models.EventOccurrenceOverride) {
+		override.Cancelled = true
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to cancel occurrence"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{Message: "Occurrence cancelled successfully"})
+}
+
+// deleteFollowingOccurrences ends the series before the occurrence, earlier occurrences are kept
+func deleteFollowingOccurrences(c *gin.Context, db *gorm.DB, event *models.Event, occurrence time.Time) {
+	if _, err := endSeriesBefore(event, occurrence); err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete occurrences"})
+		return
+	}
+
+	tx := db.Begin()
+	if err := tx.Save(event).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete occurrences"})
+		return
+	}
+
+	overrides, err := overridesFrom(tx, event.ID, occurrence)
+	if err == nil {
+		err = deleteOverrides(tx, overrides)
+	}
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete occurrences"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete occurrences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Following occurrences deleted successfully",
+		Data:    toEventResponse(event),
+	})
+}
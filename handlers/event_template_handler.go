@@ -0,0 +1,388 @@
+package handlers
+
+import (
+	"fmt"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/money"
+	"itsplanned/permissions"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func toEventTemplateResponse(template *models.EventTemplate) api.EventTemplateResponse {
+	response := api.EventTemplateResponse{
+		ID:            template.ID,
+		CreatedAt:     template.CreatedAt,
+		Name:          template.Name,
+		Description:   template.Description,
+		Place:         template.Place,
+		InitialBudget: money.FromMinor(template.InitialBudget, template.Currency),
+		Currency:      template.Currency,
+		Capacity:      template.Capacity,
+		Tasks:         []api.EventTemplateTaskResponse{},
+	}
+	for _, task := range template.Tasks {
+		response.Tasks = append(response.Tasks, api.EventTemplateTaskResponse{
+			ID:          task.ID,
+			Title:       task.Title,
+			Description: task.Description,
+			Budget:      money.FromMinor(task.Budget, template.Currency),
+			Points:      task.Points,
+		})
+	}
+	return response
+}
+
+// createEventWithTasks creates the event with the organizer as its first participant and fresh
+// copies of the tasks, nobody is assigned, nothing is completed yet and nothing is due
+func createEventWithTasks(tx *gorm.DB, event *models.Event, tasks []models.Task) error {
+	if err := tx.Create(event).Error; err != nil {
+		return err
+	}
+
+	participation := models.EventParticipation{
+		EventID: event.ID,
+		UserID:  event.OrganizerID,
+		Role:    models.RoleOrganizer,
+	}
+	if err := tx.Create(&participation).Error; err != nil {
+		return err
+	}
+	return copyTasks(tx, event.ID, tasks)
+}
+
+// copyTasks creates the tasks again in the event, the copies are unassigned and not completed
+func copyTasks(tx *gorm.DB, eventID uint, tasks []models.Task) error {
+	for _, task := range tasks {
+		copied := models.Task{
+			Title:       task.Title,
+			Description: task.Description,
+			Budget:      task.Budget,
+			Points:      task.Points,
+			Category:    task.Category,
+			Priority:    task.Priority,
+			EventID:     eventID,
+		}
+		if err := tx.Create(&copied).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findOwnTemplate loads a template of the user with its tasks
+func findOwnTemplate(c *gin.Context, db *gorm.DB) (*models.EventTemplate, bool) {
+	var templateID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &templateID); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid template ID format"})
+		return nil, false
+	}
+
+	userID, _ := c.Get("user_id")
+	var template models.EventTemplate
+	if err := db.Preload("Tasks").Where("id = ? AND owner_id = ?", templateID, userID).First(&template).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Template not found"})
+		return nil, false
+	}
+	return &template, true
+}
+
+// @Summary Clone an event
+// @Description Copy an event with its tasks. The copy belongs to the caller, tasks are unassigned and not completed
+// @Tags events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param request body api.CloneEventRequest false "Clone options"
+// @Success 200 {object} api.APIResponse{data=api.EventResponse} "Event cloned successfully"
+// @Failure 400 {object} api.APIResponse "Invalid payload"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not an organizer or co-organizer"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to clone event"
+// @Router /events/{id}/clone [post]
+func CloneEvent(c *gin.Context, db *gorm.DB) {
+	var original models.Event
+	if err := db.Preload("Tasks").First(&original, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if role, _ := permissions.GetRole(db, &original, userID.(uint)); !permissions.CanEditEvent(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only organizers and co-organizers can clone this event"})
+		return
+	}
+
+	var request api.CloneEventRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+			return
+		}
+	}
+
+	event := models.Event{
+		Name:           original.Name,
+		Description:    original.Description,
+		EventDateTime:  seriesStart(&original).AddDate(0, 0, request.ShiftDays).UTC(),
+		InitialBudget:  original.InitialBudget,
+		Currency:       original.Currency,
+		OrganizerID:    userID.(uint),
+		Place:          original.Place,
+		Capacity:       original.Capacity,
+		Timezone:       original.Timezone,
+		RecurrenceRule: original.RecurrenceRule,
+	}
+	if request.Name != nil {
+		event.Name = *request.Name
+	}
+
+	tx := db.Begin()
+	if err := createEventWithTasks(tx, &event, original.Tasks); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to clone event"})
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to clone event"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Event cloned",
+		Data:    toEventResponse(&event),
+	})
+}
+
+// @Summary Save an event as a template
+// @Description Create a template from an event with its tasks, budget and points
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body api.CreateEventTemplateRequest true "Event to save"
+// @Success 200 {object} api.APIResponse{data=api.EventTemplateResponse} "Template created successfully"
+// @Failure 400 {object} api.APIResponse "Invalid payload"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not an organizer or co-organizer"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to create template"
+// @Router /templates [post]
+func CreateEventTemplate(c *gin.Context, db *gorm.DB) {
+	var request api.CreateEventTemplateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	var event models.Event
+	if err := db.Preload("Tasks").First(&event, request.EventID).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if role, _ := permissions.GetRole(db, &event, userID.(uint)); !permissions.CanEditEvent(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only organizers and co-organizers can save this event as a template"})
+		return
+	}
+
+	template := models.EventTemplate{
+		OwnerID:       userID.(uint),
+		Name:          event.Name,
+		Description:   event.Description,
+		Place:         event.Place,
+		InitialBudget: event.InitialBudget,
+		Currency:      event.Currency,
+		Capacity:      event.Capacity,
+	}
+	if request.Name != "" {
+		template.Name = request.Name
+	}
+	for _, task := range event.Tasks {
+		template.Tasks = append(template.Tasks, models.EventTemplateTask{
+			Title:       task.Title,
+			Description: task.Description,
+			Budget:      task.Budget,
+			Points:      task.Points,
+		})
+	}
+
+	// The tasks are created together with the template
+	if err := db.Create(&template).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to create template"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Template created",
+		Data:    toEventTemplateResponse(&template),
+	})
+}
+
+// @Summary Get user's templates
+// @Description Get the event templates of the authenticated user, newest first
+// @Tags templates
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} api.EventTemplatesResponse "Templates retrieved successfully"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 500 {object} api.APIResponse "Failed to retrieve templates"
+// @Router /templates [get]
+func GetEventTemplates(c *gin.Context, db *gorm.DB) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, api.APIResponse{Error: "User not authenticated"})
+		return
+	}
+
+	var templates []models.EventTemplate
+	if err := db.Preload("Tasks").Where("owner_id = ?", userID).Order("created_at DESC").Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve templates"})
+		return
+	}
+
+	response := api.EventTemplatesResponse{Templates: []api.EventTemplateResponse{}}
+	for _, template := range templates {
+		response.Templates = append(response.Templates, toEventTemplateResponse(&template))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Get a template
+// @Description Get an event template of the authenticated user with its tasks
+// @Tags templates
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Template ID"
+// @Success 200 {object} api.APIResponse{data=api.EventTemplateResponse} "Template retrieved successfully"
+// @Failure 400 {object} api.APIResponse "Invalid template ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 404 {object} api.APIResponse "Template not found"
+// @Router /templates/{id} [get]
+func GetEventTemplate(c *gin.Context, db *gorm.DB) {
+	template, ok := findOwnTemplate(c, db)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{Data: toEventTemplateResponse(template)})
+}
+
+// @Summary Delete a template
+// @Description Delete an event template of the authenticated user, events created from it are kept
+// @Tags templates
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Template ID"
+// @Success 200 {object} api.APIResponse "Template deleted successfully"
+// @Failure 400 {object} api.APIResponse "Invalid template ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 404 {object} api.APIResponse "Template not found"
+// @Failure 500 {object} api.APIResponse "Failed to delete template"
+// @Router /templates/{id} [delete]
+func DeleteEventTemplate(c *gin.Context, db *gorm.DB) {
+	template, ok := findOwnTemplate(c, db)
+	if !ok {
+		return
+	}
+
+	tx := db.Begin()
+	if err := tx.Where("template_id = ?", template.ID).Delete(&models.EventTemplateTask{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete template tasks"})
+		return
+	}
+	if err := tx.Delete(template).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete template"})
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete template"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{Message: "Template deleted"})
+}
+
+// @Summary Create an event from a template
+// @Description Create a new event with the budget, capacity and tasks of a template, the caller becomes its organizer
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Template ID"
+// @Param request body api.InstantiateEventTemplateRequest true "Event details"
+// @Success 200 {object} api.APIResponse{data=api.EventResponse} "Event created successfully"
+// @Failure 400 {object} api.APIResponse "Invalid payload"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 404 {object} api.APIResponse "Template not found"
+// @Failure 500 {object} api.APIResponse "Failed to create event"
+// @Router /templates/{id}/events [post]
+func InstantiateEventTemplate(c *gin.Context, db *gorm.DB) {
+	template, ok := findOwnTemplate(c, db)
+	if !ok {
+		return
+	}
+
+	var request api.InstantiateEventTemplateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	eventTime, err := time.Parse(time.RFC3339, request.EventDateTime)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid date format. Use RFC3339 format."})
+		return
+	}
+
+	event := models.Event{
+		Name:          template.Name,
+		Description:   template.Description,
+		EventDateTime: eventTime.UTC(),
+		InitialBudget: template.InitialBudget,
+		Currency:      template.Currency,
+		OrganizerID:   template.OwnerID,
+		Place:         template.Place,
+		Capacity:      template.Capacity,
+		Timezone:      getUserTimezone(db, template.OwnerID),
+	}
+	if request.Name != nil {
+		event.Name = *request.Name
+	}
+
+	tasks := make([]models.Task, len(template.Tasks))
+	for i, task := range template.Tasks {
+		tasks[i] = models.Task{
+			Title:       task.Title,
+			Description: task.Description,
+			Budget:      task.Budget,
+			Points:      task.Points,
+		}
+	}
+
+	tx := db.Begin()
+	if err := createEventWithTasks(tx, &event, tasks); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to create event"})
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to create event"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Event created",
+		Data:    toEventResponse(&event),
+	})
+}
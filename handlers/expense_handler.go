@@ -0,0 +1,549 @@
+package handlers
+
+import (
+	"fmt"
+	"itsplanned/ledger"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/money"
+	"itsplanned/permissions"
+	"itsplanned/services/exchange"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// uncategorized names the budget group of tasks and expenses without a category
+const uncategorized = "uncategorized"
+
+// normalizeCategory makes categories that differ only in case or surrounding spaces the same
+func normalizeCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+func toExpenseResponse(db *gorm.DB, expense *models.Expense) api.ExpenseResponse {
+	split := make([]api.ExpenseShareResponse, 0, len(expense.Shares))
+	for _, share := range expense.Shares {
+		split = append(split, api.ExpenseShareResponse{
+			UserID:      share.UserID,
+			DisplayName: getUserDisplayName(db, share.UserID),
+			Shares:      share.Weight,
+			Amount:      money.FromMinor(share.Amount, expense.Currency),
+		})
+	}
+
+	return api.ExpenseResponse{
+		ID:          expense.ID,
+		EventID:     expense.EventID,
+		PayerID:     expense.PayerID,
+		PayerName:   getUserDisplayName(db, expense.PayerID),
+		CreatedByID: expense.CreatedByID,
+		TaskID:      expense.TaskID,
+		Amount:      money.FromMinor(expense.Amount, expense.Currency),
+		Currency:    expense.Currency,
+		Description: expense.Description,
+		Category:    expense.Category,
+		SpentAt:     expense.SpentAt,
+		SplitMode:   expense.SplitMode,
+		Split:       split,
+	}
+}
+
+// applyExpenseRequest validates the request against the event and copies it into the expense, it returns an error message on failure
+func applyExpenseRequest(db *gorm.DB, event *models.Event, expense *models.Expense, request *api.ExpenseRequest) string {
+	currency := event.Currency
+	if request.Currency != "" {
+		currency = money.Normalize(request.Currency)
+	}
+	if !money.IsSupported(currency) {
+		return "Unsupported currency"
+	}
+	if _, err := exchange.Convert(0, currency, event.Currency); err != nil {
+		return noExchangeRateMessage(currency, event.Currency)
+	}
+
+	amount := money.ToMinor(request.Amount, currency)
+	if amount <= 0 {
+		return "Amount must be positive"
+	}
+
+	if request.PayerID != nil {
+		if _, ok := permissions.GetRole(db, event, *request.PayerID); !ok {
+			return "Payer must be a participant of this event"
+		}
+		expense.PayerID = *request.PayerID
+	}
+
+	category := normalizeCategory(request.Category)
+	expense.TaskID = nil
+	if request.TaskID != nil {
+		var task models.Task
+		if err := db.Where("id = ? AND event_id = ?", *request.TaskID, event.ID).First(&task).Error; err != nil {
+			return "Task not found in this event"
+		}
+		expense.TaskID = &task.ID
+		if category == "" {
+			category = task.Category
+		}
+	}
+
+	expense.Amount = amount
+	expense.Currency = currency
+	expense.Description = strings.TrimSpace(request.Description)
+	expense.Category = category
+	if request.SpentAt != nil {
+		expense.SpentAt = request.SpentAt.UTC()
+	} else if expense.SpentAt.IsZero() {
+		expense.SpentAt = time.Now().UTC()
+	}
+	return splitExpense(db, event, expense, request)
+}
+
+// splitExpense works out what each participant of the split owes for the expense, it returns an error message on failure
+func splitExpense(db *gorm.DB, event *models.Event, expense *models.Expense, request *api.ExpenseRequest) string {
+	mode := request.SplitMode
+	if mode == "" {
+		mode = models.SplitEqual
+	}
+	if !models.IsValidSplitMode(mode) {
+		return "Invalid split mode. Use equal, shares or exact."
+	}
+
+	split := request.Split
+	if len(split) == 0 {
+		if mode != models.SplitEqual {
+			return "Split is required for shares and exact modes"
+		}
+		var participations []models.EventParticipation
+		db.Where("event_id = ? AND role <> ?", event.ID, models.RoleViewer).Order("user_id").Find(&participations)
+		for _, participation := range participations {
+			split = append(split, api.ExpenseSplitRequest{UserID: participation.UserID})
+		}
+		if len(split) == 0 {
+			return "Nobody to split the expense with"
+		}
+	}
+
+	total := expense.Amount
+	weights := make([]int64, len(split))
+	seen := map[uint]bool{}
+	for i, entry := range split {
+		if seen[entry.UserID] {
+			return "Each participant can appear in the split only once"
+		}
+		seen[entry.UserID] = true
+		if _, ok := permissions.GetRole(db, event, entry.UserID); !ok {
+			return "Everybody in the split must be a participant of this event"
+		}
+
+		switch mode {
+		case models.SplitShares:
+			if entry.Shares <= 0 {
+				return "Shares must be positive"
+			}
+			weights[i] = entry.Shares
+		case models.SplitExact:
+			if entry.Amount < 0 {
+				return "Split amounts can't be negative"
+			}
+			weights[i] = money.ToMinor(entry.Amount, expense.Currency)
+		}
+	}
+
+	var amounts []int64
+	switch mode {
+	case models.SplitEqual:
+		amounts = ledger.SplitEqual(total, len(split))
+	case models.SplitShares:
+		amounts = ledger.SplitByShares(total, weights)
+	case models.SplitExact:
+		var sum int64
+		for _, weight := range weights {
+			sum += weight
+		}
+		if sum != total {
+			return "Split amounts must add up to the expense amount"
+		}
+		amounts = weights
+	}
+
+	expense.SplitMode = mode
+	expense.Shares = make([]models.ExpenseShare, 0, len(split))
+	for i, entry := range split {
+		share := models.ExpenseShare{UserID: entry.UserID, Amount: amounts[i]}
+		if mode == models.SplitShares {
+			share.Weight = weights[i]
+		}
+		expense.Shares = append(expense.Shares, share)
+	}
+	return ""
+}
+
+// loadExpense loads the expense from the path, it has to belong to the event
+func loadExpense(c *gin.Context, db *gorm.DB, event *models.Event) (*models.Expense, bool) {
+	var expenseID uint
+	if _, err := fmt.Sscanf(c.Param("expense_id"), "%d", &expenseID); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid expense ID format"})
+		return nil, false
+	}
+
+	var expense models.Expense
+	if err := db.Preload("Shares").Where("id = ? AND event_id = ?", expenseID, event.ID).First(&expense).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Expense not found"})
+		return nil, false
+	}
+	return &expense, true
+}
+
+// canChangeExpense allows the author of an expense and budget managers to change it
+func canChangeExpense(expense *models.Expense, userID uint, role string) bool {
+	return expense.CreatedByID == userID || permissions.CanManageBudget(role)
+}
+
+// convertExpenses turns the amounts of the expenses and their shares into the event currency at the current rate,
+// shares are split from the converted amount so they still add up to it. Expenses in a currency without a rate
+// are left out of the result, their currencies are returned so responses can tell the totals miss them.
+func convertExpenses(event *models.Event, expenses []models.Expense) ([]models.Expense, []string) {
+	converted := make([]models.Expense, 0, len(expenses))
+	var unconverted []string
+	for i := range expenses {
+		expense := &expenses[i]
+		if expense.Currency == event.Currency {
+			converted = append(converted, *expense)
+			continue
+		}
+
+		amount, err := exchange.Convert(expense.Amount, expense.Currency, event.Currency)
+		if err != nil {
+			unconverted = addCurrency(unconverted, expense.Currency)
+			continue
+		}
+
+		if len(expense.Shares) > 0 {
+			weights := make([]int64, len(expense.Shares))
+			for j, share := range expense.Shares {
+				weights[j] = share.Amount
+			}
+			for j, part := range ledger.SplitByShares(amount, weights) {
+				expense.Shares[j].Amount = part
+			}
+		}
+		expense.Amount = amount
+		expense.Currency = event.Currency
+		converted = append(converted, *expense)
+	}
+	return converted, unconverted
+}
+
+// addCurrency adds the currency to the sorted list unless it is there already
+func addCurrency(currencies []string, currency string) []string {
+	i := sort.SearchStrings(currencies, currency)
+	if i < len(currencies) && currencies[i] == currency {
+		return currencies
+	}
+	return append(currencies[:i], append([]string{currency}, currencies[i:]...)...)
+}
+
+// budgetBreakdown compares the planned budget of tasks with the recorded expenses per task and per category,
+// categories with a limit are checked against it. The expenses have to be converted into the event currency first.
+func budgetBreakdown(currency string, tasks []models.Task, expenses []models.Expense, budgetCategories []models.BudgetCategory) ([]api.TaskBudgetResponse, []api.CategoryBudgetResponse) {
+	type totals struct {
+		planned, actual int64
+		limit           *int64
+	}
+	taskActual := map[uint]int64{}
+	categories := map[string]*totals{}
+	category := func(name string) *totals {
+		if name == "" {
+			name = uncategorized
+		}
+		if categories[name] == nil {
+			categories[name] = &totals{}
+		}
+		return categories[name]
+	}
+
+	for _, budgetCategory := range budgetCategories {
+		limit := budgetCategory.Limit
+		category(budgetCategory.Name).limit = &limit
+	}
+
+	for _, task := range tasks {
+		category(task.Category).planned += task.Budget
+	}
+
+	for _, expense := range expenses {
+		if expense.TaskID != nil {
+			taskActual[*expense.TaskID] += expense.Amount
+		}
+		category(expense.Category).actual += expense.Amount
+	}
+
+	taskLines := make([]api.TaskBudgetResponse, 0, len(tasks))
+	for _, task := range tasks {
+		taskLines = append(taskLines, api.TaskBudgetResponse{
+			TaskID:      task.ID,
+			Title:       task.Title,
+			Category:    task.Category,
+			IsCompleted: task.IsCompleted,
+			Planned:     money.FromMinor(task.Budget, currency),
+			Actual:      money.FromMinor(taskActual[task.ID], currency),
+		})
+	}
+
+	categoryLines := make([]api.CategoryBudgetResponse, 0, len(categories))
+	for name, line := range categories {
+		categoryLine := api.CategoryBudgetResponse{
+			Category: name,
+			Planned:  money.FromMinor(line.planned, currency),
+			Actual:   money.FromMinor(line.actual, currency),
+		}
+		if line.limit != nil {
+			limit := money.FromMinor(*line.limit, currency)
+			remaining := money.FromMinor(*line.limit-line.actual, currency)
+			categoryLine.Limit, categoryLine.Remaining = &limit, &remaining
+			categoryLine.OverBudget = line.planned > *line.limit || line.actual > *line.limit
+		}
+		categoryLines = append(categoryLines, categoryLine)
+	}
+	sort.Slice(categoryLines, func(i, j int) bool { return categoryLines[i].Category < categoryLines[j].Category })
+	return taskLines, categoryLines
+}
+
+// @Summary Record an expense
+// @Description Record money spent for the event, optionally for one of its tasks. The payer is the requesting user unless given.
+// @Description The expense is split equally, by shares or by exact amounts, by default equally among everybody in the event except viewers.
+// @Description It is in the event currency unless another one with a known exchange rate is given
+// @Tags budget
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param request body api.ExpenseRequest true "Expense details"
+// @Success 200 {object} api.APIResponse{data=api.ExpenseResponse} "Expense recorded successfully"
+// @Failure 400 {object} api.APIResponse "Invalid payload"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - viewers can't record expenses"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to record expense"
+// @Router /events/{id}/expenses [post]
+func CreateExpense(c *gin.Context, db *gorm.DB) {
+	event, role, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	if !permissions.CanAddExpense(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Viewers can't record expenses"})
+		return
+	}
+
+	var request api.ExpenseRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	expense := models.Expense{EventID: event.ID, PayerID: userID.(uint), CreatedByID: userID.(uint)}
+	if message := applyExpenseRequest(db, event, &expense, &request); message != "" {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: message})
+		return
+	}
+
+	if err := db.Create(&expense).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to record expense"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Expense recorded",
+		Data:    toExpenseResponse(db, &expense),
+	})
+}
+
+// @Summary Get event expenses
+// @Description Get the expenses of the event, latest first, optionally only those of a task. The total is in the event currency
+// @Tags budget
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param task_id query int false "Task ID"
+// @Success 200 {object} api.ExpensesResponse "Expenses retrieved successfully"
+// @Failure 400 {object} api.APIResponse "Invalid ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not a participant of the event"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to retrieve expenses"
+// @Router /events/{id}/expenses [get]
+func GetExpenses(c *gin.Context, db *gorm.DB) {
+	event, _, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	query := db.Where("event_id = ?", event.ID)
+	if taskIDStr := c.Query("task_id"); taskIDStr != "" {
+		var taskID uint
+		if _, err := fmt.Sscanf(taskIDStr, "%d", &taskID); err != nil {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid task ID format"})
+			return
+		}
+		query = query.Where("task_id = ?", taskID)
+	}
+
+	var expenses []models.Expense
+	if err := query.Preload("Shares").Order("spent_at DESC, id DESC").Find(&expenses).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve expenses"})
+		return
+	}
+
+	response := api.ExpensesResponse{Expenses: []api.ExpenseResponse{}, Currency: event.Currency}
+	for _, expense := range expenses {
+		response.Expenses = append(response.Expenses, toExpenseResponse(db, &expense))
+	}
+
+	expenses, response.UnconvertedCurrencies = convertExpenses(event, expenses)
+	var total int64
+	for _, expense := range expenses {
+		total += expense.Amount
+	}
+	response.Total = money.FromMinor(total, event.Currency)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Update an expense
+// @Description Replace an expense. Only its author, organizers and co-organizers can change it
+// @Tags budget
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param expense_id path int true "Expense ID"
+// @Param request body api.ExpenseRequest true "Expense details"
+// @Success 200 {object} api.APIResponse{data=api.ExpenseResponse} "Expense updated successfully"
+// @Failure 400 {object} api.APIResponse "Invalid payload"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - neither the author nor an organizer"
+// @Failure 404 {object} api.APIResponse "Event or expense not found"
+// @Failure 500 {object} api.APIResponse "Failed to update expense"
+// @Router /events/{id}/expenses/{expense_id} [put]
+func UpdateExpense(c *gin.Context, db *gorm.DB) {
+	event, role, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	expense, ok := loadExpense(c, db, event)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if !canChangeExpense(expense, userID.(uint), role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only the author, organizers and co-organizers can change this expense"})
+		return
+	}
+
+	var request api.ExpenseRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	if message := applyExpenseRequest(db, event, expense, &request); message != "" {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: message})
+		return
+	}
+
+	tx := db.Begin()
+
+	if err := tx.Where("expense_id = ?", expense.ID).Delete(&models.ExpenseShare{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update expense"})
+		return
+	}
+
+	// The old split is gone, saving the expense inserts the new one
+	if err := tx.Save(expense).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update expense"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update expense"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Expense updated",
+		Data:    toExpenseResponse(db, expense),
+	})
+}
+
+// @Summary Delete an expense
+// @Description Delete an expense. Only its author, organizers and co-organizers can delete it
+// @Tags budget
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param expense_id path int true "Expense ID"
+// @Success 200 {object} api.APIResponse "Expense deleted successfully"
+// @Failure 400 {object} api.APIResponse "Invalid ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - neither the author nor an organizer"
+// @Failure 404 {object} api.APIResponse "Event or expense not found"
+// @Failure 500 {object} api.APIResponse "Failed to delete expense"
+// @Router /events/{id}/expenses/{expense_id} [delete]
+func DeleteExpense(c *gin.Context, db *gorm.DB) {
+	event, role, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	expense, ok := loadExpense(c, db, event)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if !canChangeExpense(expense, userID.(uint), role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only the author, organizers and co-organizers can delete this expense"})
+		return
+	}
+
+	tx := db.Begin()
+
+	if err := tx.Where("expense_id = ?", expense.ID).Delete(&models.ExpenseShare{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete expense"})
+		return
+	}
+
+	attachments, err := deleteAttachmentRows(tx, "expense_id = ?", expense.ID)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete expense attachments"})
+		return
+	}
+
+	if err := tx.Delete(expense).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete expense"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete expense"})
+		return
+	}
+	removeAttachmentFiles(c.Request.Context(), attachments)
+
+	c.JSON(http.StatusOK, api.APIResponse{Message: "Expense deleted"})
+}
@@ -1,25 +1,80 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"itsplanned/models"
 	"itsplanned/models/api"
+	"itsplanned/permissions"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+func inviteLinkFor(inviteCode string) string {
+	return fmt.Sprintf("http://localhost:8080/events/redirect/%s", inviteCode)
+}
+
+func toInvitationResponse(invitation *models.EventInvitation) api.InvitationResponse {
+	return api.InvitationResponse{
+		InviteCode:  invitation.InviteCode,
+		InviteLink:  inviteLinkFor(invitation.InviteCode),
+		EventID:     invitation.EventID,
+		CreatedByID: invitation.CreatedByID,
+		CreatedAt:   invitation.CreatedAt,
+		ExpiresAt:   invitation.ExpiresAt,
+		MaxUses:     invitation.MaxUses,
+		Uses:        invitation.Uses,
+
+		RequiresApproval: invitation.RequiresApproval,
+	}
+}
+
+// Expired, exhausted and revoked links are all gone for good, the message tells which one it is
+func respondUnusableInvitation(c *gin.Context, err error) {
+	message := "Invalid invite link"
+	switch {
+	case errors.Is(err, models.ErrInvitationExpired):
+		message = "Invite link has expired"
+	case errors.Is(err, models.ErrInvitationExhausted):
+		message = "Invite link has reached its usage limit"
+	case errors.Is(err, models.ErrInvitationRevoked):
+		message = "Invite link has been revoked"
+	}
+	c.JSON(http.StatusGone, api.APIResponse{Error: message})
+}
+
+// useInvitation counts a use of the invite link, the conditional update keeps the limit even under concurrent joins
+func useInvitation(tx *gorm.DB, invitationID uint) error {
+	result := tx.Model(&models.EventInvitation{}).
+		Where("id = ? AND revoked_at IS NULL AND (max_uses IS NULL OR uses < max_uses)", invitationID).
+		Update("uses", gorm.Expr("uses + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var invitation models.EventInvitation
+		if err := tx.First(&invitation, invitationID).Error; err == nil && invitation.RevokedAt != nil {
+			return models.ErrInvitationRevoked
+		}
+		return models.ErrInvitationExhausted
+	}
+	return nil
+}
+
 // @Summary Generate event invite link
-// @Description Generate a unique invite link for an event
+// @Description Generate a unique invite link for an event, optionally limited in time and number of uses
 // @Tags invitations
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param request body api.GenerateInviteLinkRequest true "Event ID"
+// @Param request body api.GenerateInviteLinkRequest true "Event ID, optional expiry and usage limit"
 // @Success 200 {object} api.GenerateInviteLinkResponse "Invite link generated successfully"
-// @Failure 400 {object} api.APIResponse "Invalid payload"
+// @Failure 400 {object} api.APIResponse "Invalid payload, expiry or usage limit"
 // @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not allowed to invite to the event"
 // @Failure 404 {object} api.APIResponse "Event not found"
 // @Router /events/invite [post]
 func GenerateInviteLink(c *gin.Context, db *gorm.DB) {
@@ -29,23 +84,141 @@ func GenerateInviteLink(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Expiry time must be in the future"})
+		return
+	}
+
+	if request.MaxUses != nil && *request.MaxUses < 1 {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Max uses must be at least 1"})
+		return
+	}
+
 	var event models.Event
 	if err := db.First(&event, request.EventID).Error; err != nil {
 		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
 		return
 	}
 
+	userID, _ := c.Get("user_id")
+	if role, _ := permissions.GetRole(db, &event, userID.(uint)); !permissions.CanInvite(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not allowed to invite people to this event"})
+		return
+	}
+
 	inviteCode := models.GenerateUniqueInviteCode(db)
 
 	invitation := models.EventInvitation{
-		EventID:    request.EventID,
+		EventID:     request.EventID,
+		InviteCode:  inviteCode,
+		CreatedByID: userID.(uint),
+		ExpiresAt:   request.ExpiresAt,
+		MaxUses:     request.MaxUses,
+
+		RequiresApproval: request.RequiresApproval,
+	}
+	if err := db.Create(&invitation).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to create invite link"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.GenerateInviteLinkResponse{
+		InviteLink: inviteLinkFor(inviteCode),
 		InviteCode: inviteCode,
+		ExpiresAt:  invitation.ExpiresAt,
+		MaxUses:    invitation.MaxUses,
+
+		RequiresApproval: invitation.RequiresApproval,
+	})
+}
+
+// @Summary List active invite links of an event
+// @Description Get invite links of an event that are not revoked, expired or used up
+// @Tags invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Success 200 {object} api.InvitationsResponse "Active invite links"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not allowed to invite to the event"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to retrieve invite links"
+// @Router /events/{id}/invites [get]
+func GetEventInvitations(c *gin.Context, db *gorm.DB) {
+	var event models.Event
+	if err := db.First(&event, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+		return
 	}
-	db.Create(&invitation)
 
-	inviteLink := fmt.Sprintf("http://localhost:8080/events/redirect/%s", inviteCode)
+	userID, _ := c.Get("user_id")
+	if role, _ := permissions.GetRole(db, &event, userID.(uint)); !permissions.CanInvite(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not allowed to invite people to this event"})
+		return
+	}
+
+	var invitations []models.EventInvitation
+	if err := db.Where("event_id = ? AND revoked_at IS NULL", event.ID).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Where("max_uses IS NULL OR uses < max_uses").
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve invite links"})
+		return
+	}
 
-	c.JSON(http.StatusOK, api.GenerateInviteLinkResponse{InviteLink: inviteLink})
+	response := api.InvitationsResponse{Invitations: []api.InvitationResponse{}}
+	for _, invitation := range invitations {
+		response.Invitations = append(response.Invitations, toInvitationResponse(&invitation))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Revoke an invite link
+// @Description Revoke an invite link so nobody can join with it anymore. Allowed for the creator of the link and event organizers
+// @Tags invitations
+// @Produce json
+// @Security BearerAuth
+// @Param invite_code path string true "Invite Code"
+// @Success 200 {object} api.APIResponse "Invite link revoked"
+// @Failure 400 {object} api.APIResponse "Invite link already revoked"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not allowed to revoke the link"
+// @Failure 404 {object} api.APIResponse "Invalid invite link"
+// @Failure 500 {object} api.APIResponse "Failed to revoke invite link"
+// @Router /events/invite/{invite_code} [delete]
+func RevokeInviteLink(c *gin.Context, db *gorm.DB) {
+	var invitation models.EventInvitation
+	if err := db.Where("invite_code = ?", c.Param("invite_code")).First(&invitation).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Invalid invite link"})
+		return
+	}
+
+	var event models.Event
+	if err := db.First(&event, invitation.EventID).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	role, ok := permissions.GetRole(db, &event, userID.(uint))
+	if !ok || (invitation.CreatedByID != userID.(uint) && !permissions.CanManageInvites(role)) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not allowed to revoke this invite link"})
+		return
+	}
+
+	if invitation.RevokedAt != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invite link has already been revoked"})
+		return
+	}
+
+	if err := db.Model(&invitation).Update("revoked_at", time.Now()).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to revoke invite link"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{Message: "Invite link revoked"})
 }
 
 // @Summary Redirect to iOS app deeplink
@@ -55,6 +228,7 @@ func GenerateInviteLink(c *gin.Context, db *gorm.DB) {
 // @Param invite_code path string true "Invite Code"
 // @Success 302 {string} string "Redirect to iOS app"
 // @Failure 404 {object} api.APIResponse "Invalid invite link"
+// @Failure 410 {object} api.APIResponse "Invite link expired, used up or revoked"
 // @Router /events/redirect/{invite_code} [get]
 func DeepLinkRedirect(c *gin.Context, db *gorm.DB) {
 	inviteCode := c.Param("invite_code")
@@ -65,6 +239,11 @@ func DeepLinkRedirect(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
+	if err := invitation.CheckUsable(); err != nil {
+		respondUnusableInvitation(c, err)
+		return
+	}
+
 	deepLink := fmt.Sprintf("itsplanned://event/join?code=%s", inviteCode)
 
 	html := fmt.Sprintf(`
@@ -95,16 +274,19 @@ func DeepLinkRedirect(c *gin.Context, db *gorm.DB) {
 }
 
 // @Summary Join event using invite link
-// @Description Join an event using a unique invite code
+// @Description Join an event using a unique invite code. Links that require approval create a pending join request instead, a full event puts the user on its waitlist
 // @Tags invitations
 // @Produce json
 // @Security BearerAuth
 // @Param invite_code path string false "Invite Code from path"
 // @Param code query string false "Invite Code from query parameter"
 // @Success 200 {object} api.JoinEventResponse "Successfully joined event"
-// @Failure 400 {object} api.APIResponse "Already a participant"
+// @Success 202 {object} api.JoinEventResponse "Join request sent or added to the waitlist"
+// @Failure 400 {object} api.APIResponse "Already a participant, waitlisted or join request pending"
 // @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Personal invitation sent to another email or join request rejected"
 // @Failure 404 {object} api.APIResponse "Invalid invite link"
+// @Failure 410 {object} api.APIResponse "Invite link expired, used up or revoked"
 // @Router /events/join/{invite_code} [get]
 func JoinEvent(c *gin.Context, db *gorm.DB) {
 	// Get invite code from path parameter or query parameter
@@ -125,6 +307,11 @@ func JoinEvent(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
+	if err := invitation.CheckUsable(); err != nil {
+		respondUnusableInvitation(c, err)
+		return
+	}
+
 	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, api.APIResponse{Error: "You must be logged in to join"})
@@ -137,17 +324,119 @@ func JoinEvent(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
-	participation := models.EventParticipation{
-		EventID: invitation.EventID,
-		UserID:  userID.(uint),
+	var existingEntry models.WaitlistEntry
+	if err := db.Where("event_id = ? AND user_id = ?", invitation.EventID, userID).First(&existingEntry).Error; err == nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "You are already on the waitlist of this event"})
+		return
+	}
+
+	// Links of email invitations only work for the person they were sent to
+	var emailInvitation *models.EmailInvitation
+	var personal models.EmailInvitation
+	if err := db.Where("invitation_id = ?", invitation.ID).First(&personal).Error; err == nil {
+		var user models.User
+		if err := db.First(&user, userID).Error; err != nil || !personal.IsFor(&user) {
+			c.JSON(http.StatusForbidden, api.APIResponse{Error: "This invitation was sent to another email"})
+			return
+		}
+		emailInvitation = &personal
+	}
+
+	// The use of a link that requires approval is counted once the request gets approved
+	if invitation.RequiresApproval {
+		var previousRequest models.JoinRequest
+		if err := db.Where("event_id = ? AND user_id = ? AND status IN ?", invitation.EventID, userID,
+			[]string{models.JoinRequestPending, models.JoinRequestRejected}).First(&previousRequest).Error; err == nil {
+			if previousRequest.Status == models.JoinRequestRejected {
+				c.JSON(http.StatusForbidden, api.APIResponse{Error: "Your request to join this event has been rejected"})
+				return
+			}
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "You have already requested to join this event"})
+			return
+		}
+
+		joinRequest := models.JoinRequest{
+			EventID:      invitation.EventID,
+			UserID:       userID.(uint),
+			InvitationID: invitation.ID,
+			Status:       models.JoinRequestPending,
+		}
+		if err := db.Create(&joinRequest).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to send join request"})
+			return
+		}
+
+		c.JSON(http.StatusAccepted, api.JoinEventResponse{
+			Message: "Join request sent, waiting for organizer approval",
+			Status:  models.JoinRequestPending,
+		})
+		return
 	}
-	db.Create(&participation)
 
-	c.JSON(http.StatusOK, api.JoinEventResponse{Message: "Successfully joined event"})
+	tx := db.Begin()
+
+	if err := useInvitation(tx, invitation.ID); err != nil {
+		tx.Rollback()
+		if errors.Is(err, models.ErrInvitationExhausted) || errors.Is(err, models.ErrInvitationRevoked) {
+			respondUnusableInvitation(c, err)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to join event"})
+		return
+	}
+
+	position, err := reservePlace(tx, invitation.EventID, userID.(uint))
+	if err != nil {
+		tx.Rollback()
+		switch {
+		case errors.Is(err, errAlreadyParticipant):
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "You are already in this event"})
+		case errors.Is(err, errAlreadyWaitlisted):
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "You are already on the waitlist of this event"})
+		default:
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to join event"})
+		}
+		return
+	}
+
+	if emailInvitation != nil {
+		if err := tx.Model(emailInvitation).Updates(map[string]interface{}{
+			"status":       models.EmailInvitationAccepted,
+			"user_id":      userID,
+			"responded_at": time.Now(),
+		}).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to join event"})
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to join event"})
+		return
+	}
+
+	if emailInvitation != nil {
+		var event models.Event
+		if err := db.First(&event, invitation.EventID).Error; err == nil {
+			notifyEventUser(db, &event, emailInvitation.InvitedByID, userID.(uint), models.EmailInvitationSent, models.EmailInvitationAccepted)
+		}
+	}
+
+	if position > 0 {
+		c.JSON(http.StatusAccepted, api.JoinEventResponse{
+			Message:          "Event is full, you have been added to the waitlist",
+			Status:           models.ParticipationWaitlisted,
+			WaitlistPosition: position,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.JoinEventResponse{Message: "Successfully joined event", Status: models.ParticipationJoined})
 }
 
 // @Summary Leave an event
-// @Description Allow a user to leave an event they are participating in
+// @Description Allow a user to leave an event they are participating in or its waitlist. A freed place goes to the first waitlisted user
 // @Tags invitations
 // @Produce json
 // @Security BearerAuth
@@ -186,22 +475,49 @@ func LeaveEvent(c *gin.Context, db *gorm.DB) {
 
 	// Prevent organizer from leaving their own event
 	if event.OrganizerID == userID.(uint) {
-		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Organizers cannot leave their own events"})
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Organizers cannot leave their own events, transfer ownership first"})
 		return
 	}
 
 	// Check if the user is a participant
 	var participation models.EventParticipation
 	if err := db.Where("event_id = ? AND user_id = ?", eventID, userID).First(&participation).Error; err != nil {
+		var entry models.WaitlistEntry
+		if err := db.Where("event_id = ? AND user_id = ?", eventID, userID).First(&entry).Error; err == nil {
+			if err := db.Delete(&entry).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to leave waitlist"})
+				return
+			}
+			c.JSON(http.StatusOK, api.APIResponse{Message: "Successfully left waitlist"})
+			return
+		}
+
 		c.JSON(http.StatusNotFound, api.APIResponse{Error: "You are not a participant of this event"})
 		return
 	}
 
+	tx := db.Begin()
+
 	// Delete the participation record
-	if err := db.Delete(&participation).Error; err != nil {
+	if err := tx.Delete(&participation).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to leave event"})
+		return
+	}
+
+	promoted, err := promoteFromWaitlist(tx, eventID)
+	if err != nil {
+		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to leave event"})
 		return
 	}
 
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to leave event"})
+		return
+	}
+
+	notifyPromoted(db, &event, promoted, userID.(uint))
+
 	c.JSON(http.StatusOK, api.APIResponse{Message: "Successfully left event"})
 }
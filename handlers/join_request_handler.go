@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/permissions"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func toJoinRequestResponse(db *gorm.DB, joinRequest *models.JoinRequest) api.JoinRequestResponse {
+	return api.JoinRequestResponse{
+		ID:          joinRequest.ID,
+		EventID:     joinRequest.EventID,
+		UserID:      joinRequest.UserID,
+		DisplayName: getUserDisplayName(db, joinRequest.UserID),
+		Status:      joinRequest.Status,
+		CreatedAt:   joinRequest.CreatedAt,
+		DecidedAt:   joinRequest.DecidedAt,
+	}
+}
+
+// loadEventForJoinRequests loads the event from the path and checks that the current user may decide on its join requests
+func loadEventForJoinRequests(c *gin.Context, db *gorm.DB) (*models.Event, bool) {
+	var eventID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &eventID); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid event ID format"})
+		return nil, false
+	}
+
+	var event models.Event
+	if err := db.First(&event, eventID).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+		return nil, false
+	}
+
+	userID, _ := c.Get("user_id")
+	if role, _ := permissions.GetRole(db, &event, userID.(uint)); !permissions.CanManageJoinRequests(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only organizers and co-organizers can manage join requests"})
+		return nil, false
+	}
+
+	return &event, true
+}
+
+// @Summary List pending join requests
+// @Description Get requests to join the event that are waiting for a decision, oldest first
+// @Tags invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Success 200 {object} api.JoinRequestsResponse "Pending join requests"
+// @Failure 400 {object} api.APIResponse "Invalid event ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not an organizer"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to retrieve join requests"
+// @Router /events/{id}/join-requests [get]
+func GetJoinRequests(c *gin.Context, db *gorm.DB) {
+	event, ok := loadEventForJoinRequests(c, db)
+	if !ok {
+		return
+	}
+
+	var joinRequests []models.JoinRequest
+	if err := db.Where("event_id = ? AND status = ?", event.ID, models.JoinRequestPending).
+		Order("created_at ASC").
+		Find(&joinRequests).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve join requests"})
+		return
+	}
+
+	response := api.JoinRequestsResponse{JoinRequests: []api.JoinRequestResponse{}}
+	for _, joinRequest := range joinRequests {
+		response.JoinRequests = append(response.JoinRequests, toJoinRequestResponse(db, &joinRequest))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Approve a join request
+// @Description Approve a pending join request, the requester becomes a participant or is waitlisted when the event is full and gets notified
+// @Tags invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param request_id path int true "Join request ID"
+// @Success 200 {object} api.APIResponse{data=api.JoinRequestResponse} "Join request approved"
+// @Failure 400 {object} api.APIResponse "Invalid ID or request already decided"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not an organizer"
+// @Failure 404 {object} api.APIResponse "Event or join request not found"
+// @Failure 410 {object} api.APIResponse "Invite link used up or revoked"
+// @Failure 500 {object} api.APIResponse "Failed to approve join request"
+// @Router /events/{id}/join-requests/{request_id}/approve [post]
+func ApproveJoinRequest(c *gin.Context, db *gorm.DB) {
+	decideJoinRequest(c, db, models.JoinRequestApproved)
+}
+
+// @Summary Reject a join request
+// @Description Reject a pending join request, the requester gets notified
+// @Tags invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param request_id path int true "Join request ID"
+// @Success 200 {object} api.APIResponse{data=api.JoinRequestResponse} "Join request rejected"
+// @Failure 400 {object} api.APIResponse "Invalid ID or request already decided"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not an organizer"
+// @Failure 404 {object} api.APIResponse "Event or join request not found"
+// @Failure 500 {object} api.APIResponse "Failed to reject join request"
+// @Router /events/{id}/join-requests/{request_id}/reject [post]
+func RejectJoinRequest(c *gin.Context, db *gorm.DB) {
+	decideJoinRequest(c, db, models.JoinRequestRejected)
+}
+
+func decideJoinRequest(c *gin.Context, db *gorm.DB, status string) {
+	action := "approve"
+	if status == models.JoinRequestRejected {
+		action = "reject"
+	}
+
+	event, ok := loadEventForJoinRequests(c, db)
+	if !ok {
+		return
+	}
+
+	var requestID uint
+	if _, err := fmt.Sscanf(c.Param("request_id"), "%d", &requestID); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid join request ID format"})
+		return
+	}
+
+	var joinRequest models.JoinRequest
+	if err := db.Where("id = ? AND event_id = ?", requestID, event.ID).First(&joinRequest).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Join request not found"})
+		return
+	}
+
+	if joinRequest.Status != models.JoinRequestPending {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Join request has already been decided"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	decidedByID := userID.(uint)
+	now := time.Now()
+
+	tx := db.Begin()
+
+	// Only one organizer can decide, a concurrent decision leaves the request untouched
+	result := tx.Model(&models.JoinRequest{}).
+		Where("id = ? AND status = ?", joinRequest.ID, models.JoinRequestPending).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"decided_by_id": decidedByID,
+			"decided_at":    now,
+		})
+	if result.Error != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: fmt.Sprintf("Failed to %s join request", action)})
+		return
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Join request has already been decided"})
+		return
+	}
+
+	notifiedStatus := status
+	if status == models.JoinRequestApproved {
+		position, err := reservePlace(tx, event.ID, joinRequest.UserID)
+		switch {
+		case errors.Is(err, errAlreadyParticipant):
+		case errors.Is(err, errAlreadyWaitlisted):
+			notifiedStatus = models.ParticipationWaitlisted
+		case err != nil:
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to approve join request"})
+			return
+		default:
+			// The request used the invite link only now that it lets the user in
+			if err := useInvitation(tx, joinRequest.InvitationID); err != nil {
+				tx.Rollback()
+				if errors.Is(err, models.ErrInvitationExhausted) || errors.Is(err, models.ErrInvitationRevoked) {
+					respondUnusableInvitation(c, err)
+					return
+				}
+				c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to approve join request"})
+				return
+			}
+			if position > 0 {
+				notifiedStatus = models.ParticipationWaitlisted
+			}
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: fmt.Sprintf("Failed to %s join request", action)})
+		return
+	}
+
+	notifyEventUser(db, event, joinRequest.UserID, decidedByID, models.JoinRequestPending, notifiedStatus)
+
+	joinRequest.Status = status
+	joinRequest.DecidedByID = &decidedByID
+	joinRequest.DecidedAt = &now
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: fmt.Sprintf("Join request %s", status),
+		Data:    toJoinRequestResponse(db, &joinRequest),
+	})
+}
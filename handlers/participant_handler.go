@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"fmt"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/permissions"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// toEventParticipantResponse returns false when the user of the participation no longer exists
+func toEventParticipantResponse(db *gorm.DB, event *models.Event, participation *models.EventParticipation) (api.EventParticipantResponse, bool) {
+	var user models.User
+	if err := db.First(&user, participation.UserID).Error; err != nil {
+		return api.EventParticipantResponse{}, false
+	}
+
+	role := participation.Role
+	if participation.UserID == event.OrganizerID {
+		role = models.RoleOrganizer
+	} else if role == models.RoleOrganizer {
+		role = models.RoleCoOrganizer
+	}
+
+	return api.EventParticipantResponse{
+		UserID:        user.ID,
+		DisplayName:   user.DisplayName,
+		Avatar:        user.Avatar,
+		Role:          role,
+		RSVPStatus:    participation.RSVPStatus,
+		PlusOnes:      participation.PlusOnes,
+		RSVPNote:      participation.RSVPNote,
+		RSVPUpdatedAt: participation.RSVPUpdatedAt,
+	}, true
+}
+
+// @Summary Change participant role
+// @Description Promote or demote a participant of an event. Organizers and co-organizers can switch participants and viewers, only the organizer can grant or take away the co-organizer role
+// @Tags events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param user_id path int true "Participant user ID"
+// @Param request body api.UpdateParticipantRoleRequest true "New role"
+// @Success 200 {object} api.APIResponse{data=api.ParticipantRoleResponse} "Role updated successfully"
+// @Failure 400 {object} api.APIResponse "Invalid payload or role"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not allowed to change this role"
+// @Failure 404 {object} api.APIResponse "Event or participant not found"
+// @Failure 500 {object} api.APIResponse "Failed to update role"
+// @Router /events/{id}/participants/{user_id}/role [put]
+func UpdateParticipantRole(c *gin.Context, db *gorm.DB) {
+	var eventID, participantID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &eventID); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid event ID format"})
+		return
+	}
+	if _, err := fmt.Sscanf(c.Param("user_id"), "%d", &participantID); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid user ID format"})
+		return
+	}
+
+	var request api.UpdateParticipantRoleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	if !models.IsValidEventRole(request.Role) {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid role"})
+		return
+	}
+
+	var event models.Event
+	if err := db.First(&event, eventID).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	role, ok := permissions.GetRole(db, &event, userID.(uint))
+	if !ok {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not a participant of this event"})
+		return
+	}
+
+	var participation models.EventParticipation
+	if err := db.Where("event_id = ? AND user_id = ?", eventID, participantID).First(&participation).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Participant not found"})
+		return
+	}
+
+	currentRole, _ := permissions.GetRole(db, &event, participantID)
+	if !permissions.CanChangeRole(role, currentRole, request.Role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not allowed to change this role"})
+		return
+	}
+
+	participation.Role = request.Role
+	if err := db.Save(&participation).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update role"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Role updated successfully",
+		Data: api.ParticipantRoleResponse{
+			EventID: eventID,
+			UserID:  participantID,
+			Role:    participation.Role,
+		},
+	})
+}
@@ -0,0 +1,537 @@
+package handlers
+
+import (
+	"fmt"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/permissions"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxPollOptions caps the options of an event poll
+const maxPollOptions = 20
+
+func preloadPollOptions(db *gorm.DB) *gorm.DB {
+	return db.Preload("Options", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") })
+}
+
+// loadPoll loads the poll from the path, it has to belong to the event. A poll past its deadline gets closed first.
+func loadPoll(c *gin.Context, db *gorm.DB, event *models.Event) (*models.Poll, bool) {
+	var pollID uint
+	if _, err := fmt.Sscanf(c.Param("poll_id"), "%d", &pollID); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid poll ID format"})
+		return nil, false
+	}
+
+	var poll models.Poll
+	if err := preloadPollOptions(db).Where("id = ? AND event_id = ?", pollID, event.ID).First(&poll).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Poll not found"})
+		return nil, false
+	}
+
+	if err := closeIfExpired(db, event, &poll); err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to close expired poll"})
+		return nil, false
+	}
+	return &poll, true
+}
+
+// closePoll closes an open poll and notifies the participants of the event except skipUserID.
+// It reports false when the poll had been closed already.
+func closePoll(db *gorm.DB, event *models.Event, poll *models.Poll, closedByID, skipUserID uint) (bool, error) {
+	now := time.Now()
+
+	// Only one close succeeds, concurrent ones leave the poll untouched
+	result := db.Model(&models.Poll{}).
+		Where("id = ? AND status = ?", poll.ID, models.PollOpen).
+		Updates(map[string]interface{}{
+			"status":    models.PollClosed,
+			"closed_at": now,
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	poll.Status = models.PollClosed
+	poll.ClosedAt = &now
+
+	var participantIDs []uint
+	db.Model(&models.EventParticipation{}).Where("event_id = ?", event.ID).Pluck("user_id", &participantIDs)
+	for _, participantID := range participantIDs {
+		if participantID != skipUserID {
+			notifyEventUserAbout(db, event, poll.Question, participantID, closedByID, models.PollOpen, models.PollClosed)
+		}
+	}
+	return true, nil
+}
+
+// closeIfExpired closes the poll on behalf of its creator once the deadline has passed, everybody gets notified
+func closeIfExpired(db *gorm.DB, event *models.Event, poll *models.Poll) error {
+	if poll.Status != models.PollOpen || poll.Deadline == nil || poll.Deadline.After(time.Now()) {
+		return nil
+	}
+	_, err := closePoll(db, event, poll, poll.CreatedByID, 0)
+	return err
+}
+
+// CloseExpiredPolls closes every open poll past its deadline, the scheduler runs it periodically
+func CloseExpiredPolls(db *gorm.DB) {
+	var polls []models.Poll
+	if err := db.Where("status = ? AND deadline <= ?", models.PollOpen, time.Now().UTC()).Find(&polls).Error; err != nil {
+		log.Printf("Error fetching expired polls: %v", err)
+		return
+	}
+
+	for i := range polls {
+		var event models.Event
+		if err := db.First(&event, polls[i].EventID).Error; err != nil {
+			continue
+		}
+		if err := closeIfExpired(db, &event, &polls[i]); err != nil {
+			log.Printf("Error closing poll %d: %v", polls[i].ID, err)
+		}
+	}
+}
+
+// toPollResponse builds the response for the user, tallies are left out while the results are hidden from them
+func toPollResponse(db *gorm.DB, poll *models.Poll, userID uint, role string) (api.PollResponse, error) {
+	response := api.PollResponse{
+		ID:                poll.ID,
+		EventID:           poll.EventID,
+		CreatedByID:       poll.CreatedByID,
+		CreatedAt:         poll.CreatedAt,
+		Question:          poll.Question,
+		MultipleChoice:    poll.MultipleChoice,
+		Anonymous:         poll.Anonymous,
+		ResultsVisibility: poll.ResultsVisibility,
+		Deadline:          poll.Deadline,
+		Status:            poll.Status,
+		ClosedAt:          poll.ClosedAt,
+		Options:           []api.PollOptionResponse{},
+	}
+
+	var votes []models.PollVote
+	if err := db.Where("poll_id = ?", poll.ID).Order("id ASC").Find(&votes).Error; err != nil {
+		return response, err
+	}
+
+	voted := false
+	for _, vote := range votes {
+		voted = voted || vote.UserID == userID
+	}
+	switch {
+	case permissions.CanManagePolls(role), poll.Status == models.PollClosed, poll.ResultsVisibility == models.PollResultsAlways:
+		response.ResultsVisible = true
+	case poll.ResultsVisibility == models.PollResultsAfterVote:
+		response.ResultsVisible = voted
+	}
+
+	for _, option := range poll.Options {
+		optionResponse := api.PollOptionResponse{ID: option.ID, Text: option.Text}
+		count := 0
+		for _, vote := range votes {
+			if vote.OptionID != option.ID {
+				continue
+			}
+			count++
+			if vote.UserID == userID {
+				optionResponse.Selected = true
+			}
+			if response.ResultsVisible && !poll.Anonymous {
+				optionResponse.VoterIDs = append(optionResponse.VoterIDs, vote.UserID)
+			}
+		}
+		if response.ResultsVisible {
+			optionResponse.Votes = &count
+		}
+		response.Options = append(response.Options, optionResponse)
+	}
+	return response, nil
+}
+
+// @Summary Create a poll
+// @Description Put a question to the participants of the event with single or multiple choice, optionally anonymous,
+// @Description with a deadline and limited visibility of the results
+// @Tags polls
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param request body api.CreatePollRequest true "Poll details"
+// @Success 200 {object} api.APIResponse{data=api.PollResponse} "Poll created successfully"
+// @Failure 400 {object} api.APIResponse "Invalid payload"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - viewers can't create polls"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to create poll"
+// @Router /events/{id}/polls [post]
+func CreatePoll(c *gin.Context, db *gorm.DB) {
+	event, role, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	if !permissions.CanCreatePoll(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Viewers can't create polls"})
+		return
+	}
+
+	var request api.CreatePollRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	request.Question = strings.TrimSpace(request.Question)
+	if request.Question == "" {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Question cannot be empty"})
+		return
+	}
+
+	if request.ResultsVisibility == "" {
+		request.ResultsVisibility = models.PollResultsAlways
+	}
+	if !models.IsValidPollResultsVisibility(request.ResultsVisibility) {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid results visibility. Use always, after_vote or after_close."})
+		return
+	}
+
+	if request.Deadline != nil && !request.Deadline.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Deadline must be in the future"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	poll := models.Poll{
+		EventID:           event.ID,
+		CreatedByID:       userID.(uint),
+		Question:          request.Question,
+		MultipleChoice:    request.MultipleChoice,
+		Anonymous:         request.Anonymous,
+		ResultsVisibility: request.ResultsVisibility,
+		Status:            models.PollOpen,
+	}
+	if request.Deadline != nil {
+		deadline := request.Deadline.UTC()
+		poll.Deadline = &deadline
+	}
+
+	seen := map[string]bool{}
+	for _, text := range request.Options {
+		text = strings.TrimSpace(text)
+		if text == "" || seen[strings.ToLower(text)] {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Options must be non-empty and different"})
+			return
+		}
+		seen[strings.ToLower(text)] = true
+		poll.Options = append(poll.Options, models.PollOption{Text: text, Position: len(poll.Options)})
+	}
+	if len(poll.Options) < 2 || len(poll.Options) > maxPollOptions {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: fmt.Sprintf("A poll needs between 2 and %d options", maxPollOptions)})
+		return
+	}
+
+	if err := db.Create(&poll).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to create poll"})
+		return
+	}
+
+	response, err := toPollResponse(db, &poll, userID.(uint), role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to create poll"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Poll created",
+		Data:    response,
+	})
+}
+
+// @Summary Get polls of an event
+// @Description Get the polls of the event, newest first. Polls past their deadline are closed
+// @Tags polls
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Success 200 {object} api.PollsResponse "Polls retrieved successfully"
+// @Failure 400 {object} api.APIResponse "Invalid event ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not a participant of the event"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to retrieve polls"
+// @Router /events/{id}/polls [get]
+func GetPolls(c *gin.Context, db *gorm.DB) {
+	event, role, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	var polls []models.Poll
+	if err := preloadPollOptions(db).Where("event_id = ?", event.ID).Order("created_at DESC").Find(&polls).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve polls"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	response := api.PollsResponse{Polls: []api.PollResponse{}}
+	for i := range polls {
+		if err := closeIfExpired(db, event, &polls[i]); err != nil {
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to close expired poll"})
+			return
+		}
+		pollResponse, err := toPollResponse(db, &polls[i], userID.(uint), role)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve polls"})
+			return
+		}
+		response.Polls = append(response.Polls, pollResponse)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Get a poll
+// @Description Get a poll of the event with the results the user is allowed to see
+// @Tags polls
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param poll_id path int true "Poll ID"
+// @Success 200 {object} api.APIResponse{data=api.PollResponse} "Poll retrieved successfully"
+// @Failure 400 {object} api.APIResponse "Invalid ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not a participant of the event"
+// @Failure 404 {object} api.APIResponse "Event or poll not found"
+// @Failure 500 {object} api.APIResponse "Failed to retrieve poll"
+// @Router /events/{id}/polls/{poll_id} [get]
+func GetPoll(c *gin.Context, db *gorm.DB) {
+	event, role, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	poll, ok := loadPoll(c, db, event)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	response, err := toPollResponse(db, poll, userID.(uint), role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve poll"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{Data: response})
+}
+
+// @Summary Vote in a poll
+// @Description Choose options of an open poll, the choice replaces the previous one and an empty list withdraws the vote
+// @Tags polls
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param poll_id path int true "Poll ID"
+// @Param request body api.PollVoteRequest true "Chosen options"
+// @Success 200 {object} api.APIResponse{data=api.PollResponse} "Vote saved successfully"
+// @Failure 400 {object} api.APIResponse "Invalid options or poll closed"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - viewers can't vote"
+// @Failure 404 {object} api.APIResponse "Event or poll not found"
+// @Failure 500 {object} api.APIResponse "Failed to save vote"
+// @Router /events/{id}/polls/{poll_id}/votes [put]
+func VotePoll(c *gin.Context, db *gorm.DB) {
+	event, role, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	if !permissions.CanVote(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Viewers can't vote"})
+		return
+	}
+
+	poll, ok := loadPoll(c, db, event)
+	if !ok {
+		return
+	}
+
+	var request api.PollVoteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	if poll.Status != models.PollOpen {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Poll is closed"})
+		return
+	}
+
+	if !poll.MultipleChoice && len(request.OptionIDs) > 1 {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Only one option can be chosen in this poll"})
+		return
+	}
+
+	chosen := map[uint]bool{}
+	for _, optionID := range request.OptionIDs {
+		found := false
+		for _, option := range poll.Options {
+			found = found || option.ID == optionID
+		}
+		if !found || chosen[optionID] {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: fmt.Sprintf("Option %d is not part of this poll or chosen twice", optionID)})
+			return
+		}
+		chosen[optionID] = true
+	}
+
+	userID, _ := c.Get("user_id")
+	tx := db.Begin()
+	if err := tx.Where("poll_id = ? AND user_id = ?", poll.ID, userID).Delete(&models.PollVote{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to save vote"})
+		return
+	}
+	for _, optionID := range request.OptionIDs {
+		if err := tx.Create(&models.PollVote{PollID: poll.ID, OptionID: optionID, UserID: userID.(uint)}).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to save vote"})
+			return
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to save vote"})
+		return
+	}
+
+	response, err := toPollResponse(db, poll, userID.(uint), role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to save vote"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Vote saved",
+		Data:    response,
+	})
+}
+
+// @Summary Close a poll
+// @Description Close an open poll before its deadline, the participants of the event get notified. Only the creator, organizers and co-organizers can close a poll
+// @Tags polls
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param poll_id path int true "Poll ID"
+// @Success 200 {object} api.APIResponse{data=api.PollResponse} "Poll closed successfully"
+// @Failure 400 {object} api.APIResponse "Poll already closed"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - neither the creator nor an organizer"
+// @Failure 404 {object} api.APIResponse "Event or poll not found"
+// @Failure 500 {object} api.APIResponse "Failed to close poll"
+// @Router /events/{id}/polls/{poll_id}/close [post]
+func ClosePoll(c *gin.Context, db *gorm.DB) {
+	event, role, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	poll, ok := loadPoll(c, db, event)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if poll.CreatedByID != userID.(uint) && !permissions.CanManagePolls(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only the creator, organizers and co-organizers can close this poll"})
+		return
+	}
+
+	if poll.Status != models.PollOpen {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Poll is already closed"})
+		return
+	}
+
+	closed, err := closePoll(db, event, poll, userID.(uint), userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to close poll"})
+		return
+	}
+	if !closed {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Poll is already closed"})
+		return
+	}
+
+	response, err := toPollResponse(db, poll, userID.(uint), role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to close poll"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Poll closed",
+		Data:    response,
+	})
+}
+
+// @Summary Delete a poll
+// @Description Delete a poll with its votes. Only the creator, organizers and co-organizers can delete a poll
+// @Tags polls
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param poll_id path int true "Poll ID"
+// @Success 200 {object} api.APIResponse "Poll deleted successfully"
+// @Failure 400 {object} api.APIResponse "Invalid ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - neither the creator nor an organizer"
+// @Failure 404 {object} api.APIResponse "Event or poll not found"
+// @Failure 500 {object} api.APIResponse "Failed to delete poll"
+// @Router /events/{id}/polls/{poll_id} [delete]
+func DeletePoll(c *gin.Context, db *gorm.DB) {
+	event, role, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	poll, ok := loadPoll(c, db, event)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if poll.CreatedByID != userID.(uint) && !permissions.CanManagePolls(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only the creator, organizers and co-organizers can delete this poll"})
+		return
+	}
+
+	tx := db.Begin()
+	if err := tx.Where("poll_id = ?", poll.ID).Delete(&models.PollVote{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete poll votes"})
+		return
+	}
+	if err := tx.Where("poll_id = ?", poll.ID).Delete(&models.PollOption{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete poll options"})
+		return
+	}
+	if err := tx.Delete(poll).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete poll"})
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete poll"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{Message: "Poll deleted"})
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/permissions"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func countRSVPs(db *gorm.DB, eventID uint) (*api.RSVPCounts, error) {
+	var rows []struct {
+		RSVPStatus string
+		Count      int
+		PlusOnes   int
+	}
+	if err := db.Model(&models.EventParticipation{}).
+		Select("rsvp_status, COUNT(*) AS count, COALESCE(SUM(plus_ones), 0) AS plus_ones").
+		Where("event_id = ?", eventID).
+		Group("rsvp_status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := &api.RSVPCounts{}
+	for _, row := range rows {
+		switch row.RSVPStatus {
+		case models.RSVPGoing:
+			counts.Going = row.Count
+			counts.PlusOnes = row.PlusOnes
+		case models.RSVPMaybe:
+			counts.Maybe = row.Count
+		case models.RSVPNotGoing:
+			counts.NotGoing = row.Count
+		default:
+			counts.NoResponse += row.Count
+		}
+	}
+	counts.ExpectedGuests = counts.Going + counts.PlusOnes
+
+	return counts, nil
+}
+
+// @Summary Set my RSVP
+// @Description Answer whether the authenticated user is coming to the event, optionally with plus-ones and a note. Plus-ones are dropped when not going, declining gives the place to the first waitlisted user
+// @Tags events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param request body api.RSVPRequest true "RSVP answer"
+// @Success 200 {object} api.APIResponse{data=api.EventParticipantResponse} "RSVP saved"
+// @Failure 400 {object} api.APIResponse "Invalid payload, status or number of plus-ones"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not a participant of the event"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 409 {object} api.APIResponse "Event is full"
+// @Failure 500 {object} api.APIResponse "Failed to save RSVP"
+// @Router /events/{id}/rsvp [put]
+func SetRSVP(c *gin.Context, db *gorm.DB) {
+	var request api.RSVPRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	if !models.IsValidRSVPStatus(request.Status) {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid RSVP status"})
+		return
+	}
+
+	if request.PlusOnes < 0 || request.PlusOnes > models.MaxPlusOnes {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid number of plus-ones"})
+		return
+	}
+
+	if request.Status == models.RSVPNotGoing {
+		request.PlusOnes = 0
+	}
+
+	var event models.Event
+	if err := db.First(&event, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	participation, ok := findParticipation(db, &event, userID.(uint))
+	if !ok {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not a participant of this event"})
+		return
+	}
+
+	// The answer is read again under the event lock and only the RSVP columns are written, so a role changed meanwhile stays
+	tx := db.Begin()
+	lockedEvent, err := lockEvent(tx, event.ID)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to save RSVP"})
+		return
+	}
+	if err := tx.First(participation, participation.ID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not a participant of this event"})
+		return
+	}
+
+	wasDeclined := participation.RSVPStatus == models.RSVPNotGoing
+	declines := request.Status == models.RSVPNotGoing
+
+	// Declining frees a place, changing your mind afterwards needs one
+	if wasDeclined && !declines {
+		free, err := hasFreePlace(tx, lockedEvent)
+		if err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to save RSVP"})
+			return
+		}
+		if !free {
+			tx.Rollback()
+			c.JSON(http.StatusConflict, api.APIResponse{Error: "Event is full"})
+			return
+		}
+	}
+
+	now := time.Now()
+	if err := tx.Model(participation).Updates(map[string]interface{}{
+		"rsvp_status":     request.Status,
+		"plus_ones":       request.PlusOnes,
+		"rsvp_note":       request.Note,
+		"rsvp_updated_at": &now,
+	}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to save RSVP"})
+		return
+	}
+	participation.RSVPStatus = request.Status
+	participation.PlusOnes = request.PlusOnes
+	participation.RSVPNote = request.Note
+	participation.RSVPUpdatedAt = &now
+
+	var promoted []uint
+	if declines && !wasDeclined {
+		if promoted, err = promoteFromWaitlist(tx, event.ID); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to save RSVP"})
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to save RSVP"})
+		return
+	}
+
+	notifyPromoted(db, &event, promoted, userID.(uint))
+
+	response, _ := toEventParticipantResponse(db, &event, participation)
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "RSVP saved",
+		Data:    response,
+	})
+}
+
+// @Summary Get my RSVP
+// @Description Get the RSVP answer of the authenticated user for the event
+// @Tags events
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Success 200 {object} api.APIResponse{data=api.EventParticipantResponse} "Current RSVP"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not a participant of the event"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Router /events/{id}/rsvp [get]
+func GetMyRSVP(c *gin.Context, db *gorm.DB) {
+	var event models.Event
+	if err := db.First(&event, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	participation, ok := findParticipation(db, &event, userID.(uint))
+	if !ok {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not a participant of this event"})
+		return
+	}
+
+	response, _ := toEventParticipantResponse(db, &event, participation)
+	c.JSON(http.StatusOK, api.APIResponse{Data: response})
+}
+
+// findParticipation loads the participation of a user, the organizer of an event without one gets it created
+func findParticipation(db *gorm.DB, event *models.Event, userID uint) (*models.EventParticipation, bool) {
+	if _, ok := permissions.GetRole(db, event, userID); !ok {
+		return nil, false
+	}
+
+	var participation models.EventParticipation
+	err := db.Where("event_id = ? AND user_id = ?", event.ID, userID).First(&participation).Error
+	if err == nil {
+		return &participation, true
+	}
+
+	participation = models.EventParticipation{
+		EventID:    event.ID,
+		UserID:     userID,
+		Role:       models.RoleOrganizer,
+		RSVPStatus: models.RSVPNoResponse,
+	}
+	if err := db.Create(&participation).Error; err != nil {
+		return nil, false
+	}
+	return &participation, true
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"fmt"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/security"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const unknownDeviceName = "Unknown device"
+
+func toSessionResponse(session *models.Session, currentSessionID uint) api.SessionResponse {
+	return api.SessionResponse{
+		ID:         session.ID,
+		DeviceName: session.DeviceName,
+		IPAddress:  session.IPAddress,
+		UserAgent:  session.UserAgent,
+		LastSeenAt: session.LastSeenAt,
+		CreatedAt:  session.CreatedAt,
+		IsCurrent:  session.ID == currentSessionID,
+	}
+}
+
+// Creates a new session for the device the request came from and issues the first access/refresh token pair
+func startSession(c *gin.Context, db *gorm.DB, userID uint, deviceName string) (*api.LoginResponse, error) {
+	refreshToken := security.GenerateRandomToken()
+
+	if deviceName == "" {
+		deviceName = unknownDeviceName
+	}
+
+	now := time.Now()
+	session := models.Session{
+		UserID:           userID,
+		TokenID:          security.GenerateRandomToken(),
+		RefreshTokenHash: security.HashToken(refreshToken),
+		DeviceName:       deviceName,
+		IPAddress:        c.ClientIP(),
+		UserAgent:        c.Request.UserAgent(),
+		LastSeenAt:       now,
+		ExpiresAt:        now.Add(security.RefreshTokenTTL),
+	}
+
+	if err := db.Create(&session).Error; err != nil {
+		return nil, err
+	}
+
+	accessToken, expiresAt, err := security.GenerateToken(userID, session.TokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.LoginResponse{
+		Token:        accessToken,
+		ExpiresAt:    expiresAt,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func revokeSession(db *gorm.DB, sessionID uint) error {
+	return db.Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access token. The refresh token is rotated, the old one can not be used again
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body api.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} api.LoginResponse "New token pair"
+// @Failure 400 {object} api.APIResponse "Invalid payload"
+// @Failure 401 {object} api.APIResponse "Invalid, reused or expired refresh token"
+// @Failure 500 {object} api.APIResponse "Failed to refresh token"
+// @Router /auth/refresh [post]
+func RefreshToken(c *gin.Context, db *gorm.DB) {
+	var request api.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	refreshTokenHash := security.HashToken(request.RefreshToken)
+
+	var session models.Session
+	if err := db.Where("refresh_token_hash = ?", refreshTokenHash).First(&session).Error; err != nil {
+		// A rotated token presented again means it has leaked, so the whole session is revoked
+		var reusedSession models.Session
+		if err := db.Where("previous_refresh_token_hash = ?", refreshTokenHash).First(&reusedSession).Error; err == nil {
+			if err := revokeSession(db, reusedSession.ID); err != nil {
+				log.Printf("Failed to revoke session %d after refresh token reuse: %v", reusedSession.ID, err)
+			}
+		}
+		c.JSON(http.StatusUnauthorized, api.APIResponse{Error: "Invalid refresh token"})
+		return
+	}
+
+	if !session.IsActive() {
+		c.JSON(http.StatusUnauthorized, api.APIResponse{Error: "Session has been revoked or expired"})
+		return
+	}
+
+	newRefreshToken := security.GenerateRandomToken()
+
+	// Conditional update so that two concurrent refreshes with the same token can not both succeed
+	result := db.Model(&models.Session{}).
+		Where("id = ? AND refresh_token_hash = ?", session.ID, refreshTokenHash).
+		Updates(map[string]interface{}{
+			"previous_refresh_token_hash": refreshTokenHash,
+			"refresh_token_hash":          security.HashToken(newRefreshToken),
+			"ip_address":                  c.ClientIP(),
+			"user_agent":                  c.Request.UserAgent(),
+			"last_seen_at":                time.Now(),
+			"expires_at":                  time.Now().Add(security.RefreshTokenTTL),
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to refresh token"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusUnauthorized, api.APIResponse{Error: "Invalid refresh token"})
+		return
+	}
+
+	accessToken, expiresAt, err := security.GenerateToken(session.UserID, session.TokenID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.LoginResponse{
+		Token:        accessToken,
+		ExpiresAt:    expiresAt,
+		RefreshToken: newRefreshToken,
+	})
+}
+
+// @Summary List active sessions
+// @Description Get all signed-in devices of the authenticated user, most recently used first
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} api.SessionsResponse "Active sessions"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 500 {object} api.APIResponse "Failed to retrieve sessions"
+// @Router /sessions [get]
+func GetSessions(c *gin.Context, db *gorm.DB) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, api.APIResponse{Error: "User not authenticated"})
+		return
+	}
+	currentSessionID, _ := c.Get("session_id")
+	currentSessionIDUint, _ := currentSessionID.(uint)
+
+	var sessions []models.Session
+	if err := db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve sessions"})
+		return
+	}
+
+	response := api.SessionsResponse{Sessions: []api.SessionResponse{}}
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, toSessionResponse(&session, currentSessionIDUint))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Revoke a session
+// @Description Sign out one of the authenticated user's devices
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Session ID"
+// @Success 200 {object} api.APIResponse "Session revoked"
+// @Failure 400 {object} api.APIResponse "Invalid session ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 404 {object} api.APIResponse "Session not found"
+// @Failure 500 {object} api.APIResponse "Failed to revoke session"
+// @Router /sessions/{id} [delete]
+func RevokeSession(c *gin.Context, db *gorm.DB) {
+	var sessionID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &sessionID); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid session ID format"})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, api.APIResponse{Error: "User not authenticated"})
+		return
+	}
+
+	var session models.Session
+	if err := db.Where("id = ? AND user_id = ?", sessionID, userID).First(&session).Error; err != nil || !session.IsActive() {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Session not found"})
+		return
+	}
+
+	if err := revokeSession(db, session.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to revoke session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{Message: "Session revoked"})
+}
+
+// @Summary Sign out everywhere else
+// @Description Revoke all sessions of the authenticated user except the current one
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} api.APIResponse "Other sessions revoked"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 500 {object} api.APIResponse "Failed to revoke sessions"
+// @Router /sessions/others [delete]
+func RevokeOtherSessions(c *gin.Context, db *gorm.DB) {
+	userID, exists := c.Get("user_id")
+	sessionID, sessionExists := c.Get("session_id")
+	if !exists || !sessionExists {
+		c.JSON(http.StatusUnauthorized, api.APIResponse{Error: "User not authenticated"})
+		return
+	}
+
+	if err := db.Model(&models.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, sessionID).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to revoke sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{Message: "Signed out from all other devices"})
+}
@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"fmt"
+	"itsplanned/ledger"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/money"
+	"itsplanned/permissions"
+	"itsplanned/services/exchange"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func toSettlementResponse(db *gorm.DB, settlement *models.Settlement) api.SettlementResponse {
+	return api.SettlementResponse{
+		ID:          settlement.ID,
+		FromUserID:  settlement.FromUserID,
+		FromName:    getUserDisplayName(db, settlement.FromUserID),
+		ToUserID:    settlement.ToUserID,
+		ToName:      getUserDisplayName(db, settlement.ToUserID),
+		Amount:      money.FromMinor(settlement.Amount, settlement.Currency),
+		Currency:    settlement.Currency,
+		CreatedByID: settlement.CreatedByID,
+		PaidAt:      settlement.PaidAt,
+	}
+}
+
+// memberTotals sums up the expenses and payments of one participant in minor units of the event currency
+type memberTotals struct {
+	paid, owed, sent, received int64
+}
+
+func (t *memberTotals) net() int64 {
+	return t.paid - t.owed + t.sent - t.received
+}
+
+// eventBalances sums up the expenses and payments of everybody who took part in them in the event currency,
+// expenses recorded before splitting existed have no shares and stay out of the balances.
+// Expenses and payments in a currency without an exchange rate stay out as well, their currencies are returned.
+func eventBalances(event *models.Event, expenses []models.Expense, settlements []models.Settlement) (map[uint]*memberTotals, []string) {
+	expenses, unconverted := convertExpenses(event, expenses)
+
+	totals := map[uint]*memberTotals{}
+	member := func(userID uint) *memberTotals {
+		if totals[userID] == nil {
+			totals[userID] = &memberTotals{}
+		}
+		return totals[userID]
+	}
+
+	for _, expense := range expenses {
+		if len(expense.Shares) == 0 {
+			continue
+		}
+		member(expense.PayerID).paid += expense.Amount
+		for _, share := range expense.Shares {
+			member(share.UserID).owed += share.Amount
+		}
+	}
+
+	for _, settlement := range settlements {
+		amount, err := exchange.Convert(settlement.Amount, settlement.Currency, event.Currency)
+		if err != nil {
+			unconverted = addCurrency(unconverted, settlement.Currency)
+			continue
+		}
+		member(settlement.FromUserID).sent += amount
+		member(settlement.ToUserID).received += amount
+	}
+	return totals, unconverted
+}
+
+// loadSettlement loads the settlement from the path, it has to belong to the event
+func loadSettlement(c *gin.Context, db *gorm.DB, event *models.Event) (*models.Settlement, bool) {
+	var settlementID uint
+	if _, err := fmt.Sscanf(c.Param("settlement_id"), "%d", &settlementID); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid settlement ID format"})
+		return nil, false
+	}
+
+	var settlement models.Settlement
+	if err := db.Where("id = ? AND event_id = ?", settlementID, event.ID).First(&settlement).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Settlement not found"})
+		return nil, false
+	}
+	return &settlement, true
+}
+
+// @Summary Get event balances
+// @Description Get what everybody paid and owes for the expenses of the event, the payments made between participants
+// @Description and the fewest transfers that settle the rest. A positive net means the participant gets money back.
+// @Description Amounts are in the event currency, expenses and payments in other currencies are converted at the current rate
+// @Tags budget
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Success 200 {object} api.BalancesResponse "Balances retrieved successfully"
+// @Failure 400 {object} api.APIResponse "Invalid ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not a participant of the event"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to retrieve balances"
+// @Router /events/{id}/balances [get]
+func GetBalances(c *gin.Context, db *gorm.DB) {
+	event, _, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	var expenses []models.Expense
+	if err := db.Preload("Shares").Where("event_id = ?", event.ID).Find(&expenses).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve balances"})
+		return
+	}
+
+	var settlements []models.Settlement
+	if err := db.Where("event_id = ?", event.ID).Order("paid_at ASC, id ASC").Find(&settlements).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve balances"})
+		return
+	}
+
+	totals, unconverted := eventBalances(event, expenses, settlements)
+
+	userIDs := make([]uint, 0, len(totals))
+	nets := map[uint]int64{}
+	for userID, member := range totals {
+		userIDs = append(userIDs, userID)
+		nets[userID] = member.net()
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+
+	response := api.BalancesResponse{
+		Currency:              event.Currency,
+		Balances:              []api.MemberBalanceResponse{},
+		Transfers:             []api.TransferResponse{},
+		Settlements:           []api.SettlementResponse{},
+		UnconvertedCurrencies: unconverted,
+	}
+	for _, userID := range userIDs {
+		member := totals[userID]
+		response.Balances = append(response.Balances, api.MemberBalanceResponse{
+			UserID:      userID,
+			DisplayName: getUserDisplayName(db, userID),
+			Paid:        money.FromMinor(member.paid, event.Currency),
+			Owed:        money.FromMinor(member.owed, event.Currency),
+			Sent:        money.FromMinor(member.sent, event.Currency),
+			Received:    money.FromMinor(member.received, event.Currency),
+			Net:         money.FromMinor(member.net(), event.Currency),
+		})
+	}
+
+	for _, transfer := range ledger.Settle(nets) {
+		response.Transfers = append(response.Transfers, api.TransferResponse{
+			FromUserID: transfer.From,
+			FromName:   getUserDisplayName(db, transfer.From),
+			ToUserID:   transfer.To,
+			ToName:     getUserDisplayName(db, transfer.To),
+			Amount:     money.FromMinor(transfer.Amount, event.Currency),
+		})
+	}
+
+	for _, settlement := range settlements {
+		response.Settlements = append(response.Settlements, toSettlementResponse(db, &settlement))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Mark a transfer as paid
+// @Description Record that one participant paid another to settle up. The payer, the receiver, organizers and co-organizers can record it
+// @Tags budget
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param request body api.SettlementRequest true "Payment details"
+// @Success 200 {object} api.APIResponse{data=api.SettlementResponse} "Payment recorded successfully"
+// @Failure 400 {object} api.APIResponse "Invalid payload"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - neither side of the payment nor an organizer"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to record payment"
+// @Router /events/{id}/settlements [post]
+func CreateSettlement(c *gin.Context, db *gorm.DB) {
+	event, role, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	var request api.SettlementRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if userID.(uint) != request.FromUserID && userID.(uint) != request.ToUserID && !permissions.CanManageBudget(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only the payer, the receiver, organizers and co-organizers can record this payment"})
+		return
+	}
+
+	currency := event.Currency
+	if request.Currency != "" {
+		currency = money.Normalize(request.Currency)
+	}
+	if !money.IsSupported(currency) {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Unsupported currency"})
+		return
+	}
+	if _, err := exchange.Convert(0, currency, event.Currency); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: noExchangeRateMessage(currency, event.Currency)})
+		return
+	}
+
+	amount := money.ToMinor(request.Amount, currency)
+	if amount <= 0 {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Amount must be positive"})
+		return
+	}
+
+	if request.FromUserID == request.ToUserID {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Payer and receiver must be different"})
+		return
+	}
+
+	for _, participantID := range []uint{request.FromUserID, request.ToUserID} {
+		if _, ok := permissions.GetRole(db, event, participantID); !ok {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Payer and receiver must be participants of this event"})
+			return
+		}
+	}
+
+	settlement := models.Settlement{
+		EventID:     event.ID,
+		FromUserID:  request.FromUserID,
+		ToUserID:    request.ToUserID,
+		Amount:      amount,
+		Currency:    currency,
+		CreatedByID: userID.(uint),
+		PaidAt:      time.Now().UTC(),
+	}
+	if request.PaidAt != nil {
+		settlement.PaidAt = request.PaidAt.UTC()
+	}
+
+	if err := db.Create(&settlement).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to record payment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Payment recorded",
+		Data:    toSettlementResponse(db, &settlement),
+	})
+}
+
+// @Summary Delete a payment
+// @Description Undo a recorded payment. Only whoever recorded it, organizers and co-organizers can delete it
+// @Tags budget
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param settlement_id path int true "Settlement ID"
+// @Success 200 {object} api.APIResponse "Payment deleted successfully"
+// @Failure 400 {object} api.APIResponse "Invalid ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - neither the author nor an organizer"
+// @Failure 404 {object} api.APIResponse "Event or payment not found"
+// @Failure 500 {object} api.APIResponse "Failed to delete payment"
+// @Router /events/{id}/settlements/{settlement_id} [delete]
+func DeleteSettlement(c *gin.Context, db *gorm.DB) {
+	event, role, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	settlement, ok := loadSettlement(c, db, event)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if settlement.CreatedByID != userID.(uint) && !permissions.CanManageBudget(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only whoever recorded the payment, organizers and co-organizers can delete it"})
+		return
+	}
+
+	if err := db.Delete(settlement).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete payment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{Message: "Payment deleted"})
+}
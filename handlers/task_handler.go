@@ -4,8 +4,12 @@ import (
 	"fmt"
 	"itsplanned/models"
 	"itsplanned/models/api"
+	"itsplanned/money"
+	"itsplanned/permissions"
 	"log"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -20,7 +24,8 @@ func getUserDisplayName(db *gorm.DB, userID uint) string {
 	return user.DisplayName
 }
 
-func toTaskResponse(task *models.Task, db *gorm.DB) *api.TaskResponse {
+// toTaskResponse gives the budget of the task in the event currency
+func toTaskResponse(task *models.Task, currency string, db *gorm.DB) *api.TaskResponse {
 	if task == nil {
 		return nil
 	}
@@ -29,11 +34,16 @@ func toTaskResponse(task *models.Task, db *gorm.DB) *api.TaskResponse {
 		ID:          task.ID,
 		Title:       task.Title,
 		Description: task.Description,
-		Budget:      task.Budget,
+		Budget:      money.FromMinor(task.Budget, currency),
+		Currency:    currency,
 		Points:      task.Points,
 		EventID:     task.EventID,
 		AssignedTo:  task.AssignedTo,
 		IsCompleted: task.IsCompleted,
+		Category:    task.Category,
+		DueAt:       task.DueAt,
+		Priority:    task.Priority,
+		IsOverdue:   task.IsOverdue(time.Now()),
 	}
 
 	if task.AssignedTo != nil {
@@ -43,6 +53,34 @@ func toTaskResponse(task *models.Task, db *gorm.DB) *api.TaskResponse {
 	return response
 }
 
+// checkTaskSchedule validates the priority and the due date of a task, tasks are due before the event
+// unless the request allows otherwise
+func checkTaskSchedule(c *gin.Context, event *models.Event, priority string, dueAt *time.Time, allowAfterEvent bool) bool {
+	if !models.IsValidPriority(priority) {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid priority. Use urgent, high, normal or low."})
+		return false
+	}
+	if dueAt != nil && dueAt.After(event.EventDateTime) && !allowAfterEvent {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "The due date is after the event, set allow_due_after_event to keep it"})
+		return false
+	}
+	return true
+}
+
+// taskOrders are the orders GetTasks can sort by, ties keep the order of creation
+var taskOrders = map[string]func(a, b *models.Task) bool{
+	"id": func(a, b *models.Task) bool { return a.ID < b.ID },
+	"due_at": func(a, b *models.Task) bool {
+		if a.DueAt == nil || b.DueAt == nil {
+			return a.DueAt != nil && b.DueAt == nil
+		}
+		return a.DueAt.Before(*b.DueAt)
+	},
+	"priority": func(a, b *models.Task) bool { return models.PriorityRank(a.Priority) < models.PriorityRank(b.Priority) },
+	"points":   func(a, b *models.Task) bool { return a.Points < b.Points },
+	"title":    func(a, b *models.Task) bool { return strings.ToLower(a.Title) < strings.ToLower(b.Title) },
+}
+
 // @Summary Create a new task
 // @Description Create a new task for an event
 // @Tags tasks
@@ -51,8 +89,10 @@ func toTaskResponse(task *models.Task, db *gorm.DB) *api.TaskResponse {
 // @Security BearerAuth
 // @Param request body api.CreateTaskRequest true "Task creation details"
 // @Success 200 {object} api.APIResponse{data=api.TaskResponse} "Task created successfully"
-// @Failure 400 {object} api.APIResponse "Invalid payload"
+// @Failure 400 {object} api.APIResponse "Invalid payload, priority or a due date after the event"
 // @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not allowed to create tasks in the event"
+// @Failure 404 {object} api.APIResponse "Event not found"
 // @Failure 500 {object} api.APIResponse "Failed to create task"
 // @Router /tasks [post]
 func CreateTask(c *gin.Context, db *gorm.DB) {
@@ -62,13 +102,46 @@ func CreateTask(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
+	var event models.Event
+	if err := db.First(&event, request.EventID).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	role, _ := permissions.GetRole(db, &event, userID.(uint))
+	if !permissions.CanCreateTask(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not allowed to create tasks in this event"})
+		return
+	}
+
+	// Handing a task to somebody else is task management, taking it yourself is not
+	if request.AssignedTo != nil && *request.AssignedTo != userID.(uint) && !permissions.CanEditTask(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only organizers and co-organizers can assign tasks to other participants"})
+		return
+	}
+
+	if request.Priority == "" {
+		request.Priority = models.PriorityNormal
+	}
+	if request.DueAt != nil {
+		dueAt := request.DueAt.UTC()
+		request.DueAt = &dueAt
+	}
+	if !checkTaskSchedule(c, &event, request.Priority, request.DueAt, request.AllowDueAfterEvent) {
+		return
+	}
+
 	task := models.Task{
 		Title:       request.Title,
 		Description: request.Description,
-		Budget:      request.Budget,
+		Budget:      money.ToMinor(request.Budget, event.Currency),
 		Points:      request.Points,
 		EventID:     request.EventID,
 		AssignedTo:  request.AssignedTo,
+		Category:    normalizeCategory(request.Category),
+		DueAt:       request.DueAt,
+		Priority:    request.Priority,
 	}
 
 	if err := db.Create(&task).Error; err != nil {
@@ -78,7 +151,7 @@ func CreateTask(c *gin.Context, db *gorm.DB) {
 
 	c.JSON(http.StatusOK, api.APIResponse{
 		Message: "Task created",
-		Data:    toTaskResponse(&task, db),
+		Data:    toTaskResponse(&task, event.Currency, db),
 	})
 }
 
@@ -92,6 +165,7 @@ func CreateTask(c *gin.Context, db *gorm.DB) {
 // @Success 200 {object} api.APIResponse{data=api.TaskResponse} "Task assignment toggled successfully"
 // @Failure 400 {object} api.APIResponse "Task already assigned to another user"
 // @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not allowed to take tasks in the event"
 // @Failure 404 {object} api.APIResponse "Task not found"
 // @Router /tasks/{id}/assign [put]
 func AssignToTask(c *gin.Context, db *gorm.DB) {
@@ -112,34 +186,40 @@ func AssignToTask(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
+	if role, _ := permissions.GetRole(db, &event, userIDUint); !permissions.CanTakeTask(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not allowed to take tasks in this event"})
+		return
+	}
+
 	var oldStatus string
 	var newStatus string
 
 	if task.AssignedTo != nil && *task.AssignedTo == userIDUint {
-		oldStatus = "assigned"
-		newStatus = "unassigned"
+		oldStatus = models.TaskAssigned
+		newStatus = models.TaskUnassigned
 		task.AssignedTo = nil
 		db.Save(&task)
 		c.JSON(http.StatusOK, api.APIResponse{
 			Message: "You have been unassigned from the task",
-			Data:    toTaskResponse(&task, db),
+			Data:    toTaskResponse(&task, event.Currency, db),
 		})
 	} else if task.AssignedTo != nil && *task.AssignedTo != userIDUint {
 		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Task already assigned to another user"})
 		return
 	} else {
-		oldStatus = "unassigned"
-		newStatus = "assigned"
+		oldStatus = models.TaskUnassigned
+		newStatus = models.TaskAssigned
 		task.AssignedTo = &userIDUint
 		db.Save(&task)
 		c.JSON(http.StatusOK, api.APIResponse{
 			Message: "You have been assigned to the task",
-			Data:    toTaskResponse(&task, db),
+			Data:    toTaskResponse(&task, event.Currency, db),
 		})
 	}
 
 	taskStatusEvent := models.TaskStatusEvent{
 		TaskID:        task.ID,
+		EventID:       task.EventID,
 		TaskName:      task.Title,
 		OldStatus:     oldStatus,
 		NewStatus:     newStatus,
@@ -194,11 +274,11 @@ func CompleteTask(c *gin.Context, db *gorm.DB) {
 	var newStatus string
 
 	if task.IsCompleted {
-		oldStatus = "completed"
-		newStatus = "assigned"
+		oldStatus = models.TaskCompleted
+		newStatus = models.TaskAssigned
 	} else {
-		oldStatus = "assigned"
-		newStatus = "completed"
+		oldStatus = models.TaskAssigned
+		newStatus = models.TaskCompleted
 	}
 
 	task.IsCompleted = !task.IsCompleted
@@ -235,6 +315,7 @@ func CompleteTask(c *gin.Context, db *gorm.DB) {
 
 	taskStatusEvent := models.TaskStatusEvent{
 		TaskID:        task.ID,
+		EventID:       task.EventID,
 		TaskName:      task.Title,
 		OldStatus:     oldStatus,
 		NewStatus:     newStatus,
@@ -256,19 +337,27 @@ func CompleteTask(c *gin.Context, db *gorm.DB) {
 
 	c.JSON(http.StatusOK, api.APIResponse{
 		Message: message,
-		Data:    toTaskResponse(&task, db),
+		Data:    toTaskResponse(&task, event.Currency, db),
 	})
 }
 
 // GetTasks godoc
 // @Summary Get all tasks for an event
-// @Description Get a list of all tasks associated with a specific event
+// @Description Get a list of all tasks associated with a specific event, optionally filtered and sorted.
+// @Description In ascending order tasks without a due date come last and priorities go from urgent to low
 // @Tags tasks
 // @Produce json
 // @Security BearerAuth
 // @Param event_id query int true "Event ID"
+// @Param status query string false "Only open or completed tasks" Enums(open, completed)
+// @Param priority query string false "Only tasks of the priority" Enums(urgent, high, normal, low)
+// @Param assigned_to query string false "Only tasks of the assignee, a user ID or none for unassigned tasks"
+// @Param due_before query string false "Only tasks due before the time, RFC3339"
+// @Param overdue query bool false "Only open tasks past their due date"
+// @Param sort query string false "Sort by id, due_at, priority, points or title, id by default" Enums(id, due_at, priority, points, title)
+// @Param order query string false "Sort order, asc by default" Enums(asc, desc)
 // @Success 200 {object} api.APIResponse{data=[]api.TaskResponse} "List of tasks retrieved successfully"
-// @Failure 400 {object} api.APIResponse "Invalid event ID"
+// @Failure 400 {object} api.APIResponse "Invalid event ID, filter or sort"
 // @Failure 401 {object} api.APIResponse "Unauthorized"
 // @Failure 403 {object} api.APIResponse "Forbidden - not a participant of the event"
 // @Failure 404 {object} api.APIResponse "Event not found"
@@ -298,38 +387,81 @@ func GetTasks(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
-	if event.OrganizerID != userID.(uint) {
-		var participation models.EventParticipation
-		if err := db.Where("event_id = ? AND user_id = ?", eventID, userID).First(&participation).Error; err != nil {
-			c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not a participant of this event"})
+	if role, _ := permissions.GetRole(db, &event, userID.(uint)); !permissions.CanView(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not a participant of this event"})
+		return
+	}
+
+	query := db.Where("event_id = ?", eventID)
+	switch c.Query("status") {
+	case "":
+	case "open":
+		query = query.Where("is_completed = ?", false)
+	case "completed":
+		query = query.Where("is_completed = ?", true)
+	default:
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid status. Use open or completed."})
+		return
+	}
+	if priority := c.Query("priority"); priority != "" {
+		if !models.IsValidPriority(priority) {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid priority. Use urgent, high, normal or low."})
 			return
 		}
+		query = query.Where("priority = ?", priority)
+	}
+	if assignee := c.Query("assigned_to"); assignee == "none" {
+		query = query.Where("assigned_to IS NULL")
+	} else if assignee != "" {
+		var assigneeID uint
+		if _, err := fmt.Sscanf(assignee, "%d", &assigneeID); err != nil {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid assignee. Use a user ID or none."})
+			return
+		}
+		query = query.Where("assigned_to = ?", assigneeID)
+	}
+	if value := c.Query("due_before"); value != "" {
+		dueBefore, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid due_before format. Use RFC3339 format."})
+			return
+		}
+		query = query.Where("due_at IS NOT NULL AND due_at < ?", dueBefore.UTC())
+	}
+	if c.Query("overdue") == "true" {
+		query = query.Where("is_completed = ? AND due_at IS NOT NULL AND due_at < ?", false, time.Now().UTC())
+	}
+
+	less, ok := taskOrders[c.DefaultQuery("sort", "id")]
+	if !ok {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid sort. Use id, due_at, priority, points or title."})
+		return
+	}
+	descending := false
+	switch c.DefaultQuery("order", "asc") {
+	case "asc":
+	case "desc":
+		descending = true
+	default:
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid order. Use asc or desc."})
+		return
 	}
 
 	var tasks []models.Task
-	if err := db.Where("event_id = ?", eventID).Find(&tasks).Error; err != nil {
+	if err := query.Order("id").Find(&tasks).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve tasks"})
 		return
 	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if descending {
+			return less(&tasks[j], &tasks[i])
+		}
+		return less(&tasks[i], &tasks[j])
+	})
 
 	var response []api.TaskResponse
 	for _, task := range tasks {
-		taskResponse := api.TaskResponse{
-			ID:          task.ID,
-			Title:       task.Title,
-			Description: task.Description,
-			Budget:      task.Budget,
-			Points:      task.Points,
-			EventID:     task.EventID,
-			AssignedTo:  task.AssignedTo,
-			IsCompleted: task.IsCompleted,
-		}
-
-		if task.AssignedTo != nil {
-			taskResponse.AssignedToName = getUserDisplayName(db, *task.AssignedTo)
-		}
-
-		response = append(response, taskResponse)
+		response = append(response, *toTaskResponse(&task, event.Currency, db))
 	}
 
 	c.JSON(http.StatusOK, api.APIResponse{Data: response})
@@ -379,30 +511,12 @@ func GetTask(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
-	if event.OrganizerID != userID.(uint) {
-		var participation models.EventParticipation
-		if err := db.Where("event_id = ? AND user_id = ?", task.EventID, userID).First(&participation).Error; err != nil {
-			c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not a participant of this event"})
-			return
-		}
-	}
-
-	response := api.TaskResponse{
-		ID:          task.ID,
-		Title:       task.Title,
-		Description: task.Description,
-		Budget:      task.Budget,
-		Points:      task.Points,
-		EventID:     task.EventID,
-		AssignedTo:  task.AssignedTo,
-		IsCompleted: task.IsCompleted,
-	}
-
-	if task.AssignedTo != nil {
-		response.AssignedToName = getUserDisplayName(db, *task.AssignedTo)
+	if role, _ := permissions.GetRole(db, &event, userID.(uint)); !permissions.CanView(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not a participant of this event"})
+		return
 	}
 
-	c.JSON(http.StatusOK, api.APIResponse{Data: response})
+	c.JSON(http.StatusOK, api.APIResponse{Data: toTaskResponse(&task, event.Currency, db)})
 }
 
 // @Summary Update task details
@@ -414,9 +528,9 @@ func GetTask(c *gin.Context, db *gorm.DB) {
 // @Param id path int true "Task ID"
 // @Param request body api.UpdateTaskRequest true "Task update details"
 // @Success 200 {object} api.APIResponse{data=api.TaskResponse} "Task updated successfully"
-// @Failure 400 {object} api.APIResponse "Invalid payload"
+// @Failure 400 {object} api.APIResponse "Invalid payload, priority or a due date after the event"
 // @Failure 401 {object} api.APIResponse "Unauthorized"
-// @Failure 403 {object} api.APIResponse "Forbidden - not a participant of the event or not the organizer"
+// @Failure 403 {object} api.APIResponse "Forbidden - not an organizer or co-organizer of the event"
 // @Failure 404 {object} api.APIResponse "Task not found"
 // @Router /tasks/{id} [put]
 func UpdateTask(c *gin.Context, db *gorm.DB) {
@@ -450,8 +564,8 @@ func UpdateTask(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
-	if event.OrganizerID != userID.(uint) {
-		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only the event organizer can update tasks"})
+	if role, _ := permissions.GetRole(db, &event, userID.(uint)); !permissions.CanEditTask(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only organizers and co-organizers can update tasks"})
 		return
 	}
 
@@ -468,11 +582,29 @@ func UpdateTask(c *gin.Context, db *gorm.DB) {
 		task.Description = *request.Description
 	}
 	if request.Budget != nil {
-		task.Budget = *request.Budget
+		task.Budget = money.ToMinor(*request.Budget, event.Currency)
 	}
 	if request.Points != nil {
 		task.Points = *request.Points
 	}
+	if request.Category != nil {
+		task.Category = normalizeCategory(*request.Category)
+	}
+	if request.Priority != nil {
+		task.Priority = *request.Priority
+	}
+	// A due date set earlier stays when the event moves, only new ones are checked
+	var dueAt *time.Time
+	if request.ClearDueAt {
+		task.DueAt = nil
+	} else if request.DueAt != nil {
+		utc := request.DueAt.UTC()
+		task.DueAt = &utc
+		dueAt = &utc
+	}
+	if !checkTaskSchedule(c, &event, task.Priority, dueAt, request.AllowDueAfterEvent) {
+		return
+	}
 
 	if err := db.Save(&task).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update task"})
@@ -481,7 +613,7 @@ func UpdateTask(c *gin.Context, db *gorm.DB) {
 
 	c.JSON(http.StatusOK, api.APIResponse{
 		Message: "Task updated successfully",
-		Data:    toTaskResponse(&task, db),
+		Data:    toTaskResponse(&task, event.Currency, db),
 	})
 }
 
@@ -494,7 +626,7 @@ func UpdateTask(c *gin.Context, db *gorm.DB) {
 // @Param id path int true "Task ID"
 // @Success 200 {object} api.APIResponse "Task deleted successfully"
 // @Failure 401 {object} api.APIResponse "Unauthorized"
-// @Failure 403 {object} api.APIResponse "Forbidden - not the event organizer"
+// @Failure 403 {object} api.APIResponse "Forbidden - not an organizer or co-organizer of the event"
 // @Failure 404 {object} api.APIResponse "Task not found"
 // @Failure 500 {object} api.APIResponse "Failed to delete task"
 // @Router /tasks/{id} [delete]
@@ -516,11 +648,13 @@ func DeleteTask(c *gin.Context, db *gorm.DB) {
 	userID, _ := c.Get("user_id")
 	userIDUint := userID.(uint)
 
-	if userIDUint != event.OrganizerID {
-		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only the event organizer can delete tasks"})
+	if role, _ := permissions.GetRole(db, &event, userIDUint); !permissions.CanEditTask(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only organizers and co-organizers can delete tasks"})
 		return
 	}
 
+	var attachments []models.Attachment
+	var err error
 	if task.IsCompleted && task.AssignedTo != nil {
 		tx := db.Begin()
 
@@ -550,6 +684,19 @@ func DeleteTask(c *gin.Context, db *gorm.DB) {
 			return
 		}
 
+		if err := tx.Model(&models.Expense{}).Where("task_id = ?", task.ID).Update("task_id", nil).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to unlink task expenses"})
+			return
+		}
+
+		attachments, err = deleteAttachmentRows(tx, "task_id = ?", task.ID)
+		if err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete task attachments"})
+			return
+		}
+
 		if err := tx.Delete(&task).Error; err != nil {
 			tx.Rollback()
 			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete task"})
@@ -569,6 +716,19 @@ func DeleteTask(c *gin.Context, db *gorm.DB) {
 			return
 		}
 
+		if err := tx.Model(&models.Expense{}).Where("task_id = ?", task.ID).Update("task_id", nil).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to unlink task expenses"})
+			return
+		}
+
+		attachments, err = deleteAttachmentRows(tx, "task_id = ?", task.ID)
+		if err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete task attachments"})
+			return
+		}
+
 		if err := tx.Delete(&task).Error; err != nil {
 			tx.Rollback()
 			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to delete task"})
@@ -580,6 +740,7 @@ func DeleteTask(c *gin.Context, db *gorm.DB) {
 			return
 		}
 	}
+	removeAttachmentFiles(c.Request.Context(), attachments)
 
 	c.JSON(http.StatusOK, api.APIResponse{
 		Message: "Task deleted successfully",
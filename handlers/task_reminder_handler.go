@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"itsplanned/models"
+	"itsplanned/services/email"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// reminderTimeLayout is how due dates and event times read in reminder emails, in the zone of the recipient
+const reminderTimeLayout = "Mon, 2 Jan 2006 15:04 MST"
+
+// SendTaskReminders reminds assignees of their open tasks due within their reminder hours and organizers
+// of the unassigned tasks of events starting within theirs, the scheduler runs it periodically
+func SendTaskReminders(db *gorm.DB, now time.Time) {
+	users := map[uint]*models.User{}
+	sendDueTaskReminders(db, users, now)
+	sendUnassignedTaskReminders(db, users, now)
+}
+
+// reminderUser loads the recipient of a reminder once per run, nil when the user is gone
+func reminderUser(db *gorm.DB, users map[uint]*models.User, userID uint) *models.User {
+	if user, ok := users[userID]; ok {
+		return user
+	}
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		users[userID] = nil
+		return nil
+	}
+	users[userID] = &user
+	return &user
+}
+
+// claimReminder records the reminder before it is delivered, so overlapping runs can't both send it.
+// It is false when the reminder has been sent already
+func claimReminder(db *gorm.DB, kind string, userID, subjectID uint, remindedAt, now time.Time) bool {
+	reminder := models.SentReminder{
+		Kind:       kind,
+		UserID:     userID,
+		SubjectID:  subjectID,
+		RemindedAt: remindedAt.UTC(),
+		SentAt:     now.UTC(),
+	}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&reminder)
+	if result.Error != nil {
+		log.Printf("Error recording %s reminder for user %d: %v", kind, userID, result.Error)
+		return false
+	}
+	return result.RowsAffected == 1
+}
+
+func sendDueTaskReminders(db *gorm.DB, users map[uint]*models.User, now time.Time) {
+	var tasks []models.Task
+	if err := db.Where("is_completed = ? AND assigned_to IS NOT NULL AND due_at > ? AND due_at <= ?",
+		false, now.UTC(), now.Add(models.MaxReminderHours*time.Hour).UTC()).
+		Order("due_at ASC").Find(&tasks).Error; err != nil {
+		log.Printf("Error fetching tasks due soon: %v", err)
+		return
+	}
+
+	events := map[uint]*models.Event{}
+	for i := range tasks {
+		task := &tasks[i]
+		user := reminderUser(db, users, *task.AssignedTo)
+		if user == nil || user.TaskReminderHours == 0 || task.DueAt.Sub(now) > time.Duration(user.TaskReminderHours)*time.Hour {
+			continue
+		}
+
+		event, ok := events[task.EventID]
+		if !ok {
+			event = &models.Event{}
+			if err := db.First(event, task.EventID).Error; err != nil {
+				event = nil
+			}
+			events[task.EventID] = event
+		}
+		if event == nil || !claimReminder(db, models.ReminderTaskDue, user.ID, task.ID, *task.DueAt, now) {
+			continue
+		}
+
+		// Reminders come on behalf of the organizer, like every other change of the event
+		notification := models.TaskStatusEvent{
+			TaskID:        task.ID,
+			EventID:       task.EventID,
+			TaskName:      task.Title,
+			OldStatus:     models.TaskAssigned,
+			NewStatus:     models.ReminderTaskDue,
+			UserID:        user.ID,
+			ChangedByID:   event.OrganizerID,
+			ChangedByName: getUserDisplayName(db, event.OrganizerID),
+			IsRead:        false,
+			EventTime:     now,
+		}
+		if err := db.Create(&notification).Error; err != nil {
+			log.Printf("Failed to create task reminder for user %d: %v", user.ID, err)
+		}
+
+		if err := email.SendTaskReminderEmail(user.Email, email.TaskReminderEmail{
+			TaskName:  task.Title,
+			EventName: event.Name,
+			DueAt:     task.DueAt.In(user.Location()).Format(reminderTimeLayout),
+		}); err != nil {
+			log.Printf("Failed to send task reminder email to user %d: %v", user.ID, err)
+		}
+	}
+}
+
+// nextEventStart finds when the event starts within [from, to), the next occurrence for recurring events
+func nextEventStart(db *gorm.DB, event *models.Event, from, to time.Time) (time.Time, bool) {
+	if event.RecurrenceRule == "" {
+		return event.EventDateTime, !event.EventDateTime.Before(from) && event.EventDateTime.Before(to)
+	}
+
+	overrides, err := loadOverrides(db, event.ID)
+	if err != nil {
+		log.Printf("Error fetching occurrence overrides of event %d: %v", event.ID, err)
+		return time.Time{}, false
+	}
+	occurrences := expandEvent(event, overrides, from, to)
+	if len(occurrences) == 0 {
+		return time.Time{}, false
+	}
+	return occurrences[0].EventDateTime, true
+}
+
+func sendUnassignedTaskReminders(db *gorm.DB, users map[uint]*models.User, now time.Time) {
+	// Recurring events are only loaded while their series goes on
+	horizon := now.Add(models.MaxReminderHours * time.Hour).UTC()
+	var events []models.Event
+	if err := db.Where("(recurrence_rule = '' AND event_date_time >= ? AND event_date_time < ?) OR "+
+		"(recurrence_rule <> '' AND event_date_time < ? AND (series_ends_at IS NULL OR series_ends_at >= ?))",
+		now.UTC(), horizon, horizon, now.UTC()).
+		Find(&events).Error; err != nil {
+		log.Printf("Error fetching upcoming events: %v", err)
+		return
+	}
+
+	for i := range events {
+		event := &events[i]
+		organizer := reminderUser(db, users, event.OrganizerID)
+		if organizer == nil || organizer.EventReminderHours == 0 {
+			continue
+		}
+		start, ok := nextEventStart(db, event, now, now.Add(time.Duration(organizer.EventReminderHours)*time.Hour))
+		if !ok {
+			continue
+		}
+
+		var tasks []models.Task
+		if err := db.Where("event_id = ? AND assigned_to IS NULL AND is_completed = ?", event.ID, false).
+			Order("id ASC").Find(&tasks).Error; err != nil {
+			log.Printf("Error fetching unassigned tasks of event %d: %v", event.ID, err)
+			continue
+		}
+		if len(tasks) == 0 || !claimReminder(db, models.ReminderUnassignedTasks, organizer.ID, event.ID, start, now) {
+			continue
+		}
+
+		notifyEventUser(db, event, organizer.ID, organizer.ID, "", models.ReminderUnassignedTasks)
+
+		taskNames := make([]string, len(tasks))
+		for j, task := range tasks {
+			taskNames[j] = task.Title
+		}
+		if err := email.SendUnassignedTasksEmail(organizer.Email, email.UnassignedTasksEmail{
+			EventName:     event.Name,
+			EventDateTime: start.In(organizer.Location()).Format(reminderTimeLayout),
+			TaskNames:     taskNames,
+		}); err != nil {
+			log.Printf("Failed to send unassigned tasks email to user %d: %v", organizer.ID, err)
+		}
+	}
+}
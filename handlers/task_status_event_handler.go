@@ -3,7 +3,9 @@ package handlers
 import (
 	"itsplanned/models"
 	"itsplanned/models/api"
+	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -13,6 +15,7 @@ func toTaskStatusEventResponse(event *models.TaskStatusEvent) api.TaskStatusEven
 	return api.TaskStatusEventResponse{
 		ID:            event.ID,
 		TaskID:        event.TaskID,
+		EventID:       event.EventID,
 		TaskName:      event.TaskName,
 		OldStatus:     event.OldStatus,
 		NewStatus:     event.NewStatus,
@@ -23,6 +26,30 @@ func toTaskStatusEventResponse(event *models.TaskStatusEvent) api.TaskStatusEven
 	}
 }
 
+// Puts an event-level notification (not bound to a task) into the user's unread feed
+func notifyEventUser(db *gorm.DB, event *models.Event, userID, changedByID uint, oldStatus, newStatus string) {
+	notifyEventUserAbout(db, event, event.Name, userID, changedByID, oldStatus, newStatus)
+}
+
+// notifyEventUserAbout is notifyEventUser for a part of the event named in the notification instead of the event, like a poll
+func notifyEventUserAbout(db *gorm.DB, event *models.Event, name string, userID, changedByID uint, oldStatus, newStatus string) {
+	notification := models.TaskStatusEvent{
+		EventID:       event.ID,
+		TaskName:      name,
+		OldStatus:     oldStatus,
+		NewStatus:     newStatus,
+		UserID:        userID,
+		ChangedByID:   changedByID,
+		ChangedByName: getUserDisplayName(db, changedByID),
+		IsRead:        false,
+		EventTime:     time.Now(),
+	}
+
+	if err := db.Create(&notification).Error; err != nil {
+		log.Printf("Failed to create notification for user %d: %v", userID, err)
+	}
+}
+
 // @Summary Get unread task status events
 // @Description Get all unread task status events for the authenticated user
 // @Tags events
@@ -0,0 +1,446 @@
+package handlers
+
+import (
+	"fmt"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/permissions"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxTimePollOptions caps the candidate times of a poll
+const maxTimePollOptions = 20
+
+// loadTimePoll loads the poll from the path with its options in time order, it has to belong to the event
+func loadTimePoll(c *gin.Context, db *gorm.DB, event *models.Event) (*models.TimePoll, bool) {
+	var pollID uint
+	if _, err := fmt.Sscanf(c.Param("poll_id"), "%d", &pollID); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid poll ID format"})
+		return nil, false
+	}
+
+	var poll models.TimePoll
+	if err := db.Preload("Options", func(db *gorm.DB) *gorm.DB { return db.Order("start_time ASC") }).
+		Where("id = ? AND event_id = ?", pollID, event.ID).
+		First(&poll).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Poll not found"})
+		return nil, false
+	}
+	return &poll, true
+}
+
+// toTimePollResponse tallies the votes of every option, times are given in the zone of the event
+func toTimePollResponse(db *gorm.DB, event *models.Event, poll *models.TimePoll, userID uint) (api.TimePollResponse, error) {
+	response := api.TimePollResponse{
+		ID:             poll.ID,
+		EventID:        poll.EventID,
+		CreatedByID:    poll.CreatedByID,
+		CreatedAt:      poll.CreatedAt,
+		Status:         poll.Status,
+		ChosenOptionID: poll.ChosenOptionID,
+		ClosedAt:       poll.ClosedAt,
+		Options:        []api.TimePollOptionResponse{},
+	}
+
+	optionIDs := make([]uint, 0, len(poll.Options))
+	for _, option := range poll.Options {
+		optionIDs = append(optionIDs, option.ID)
+	}
+	var votes []models.TimePollVote
+	if len(optionIDs) > 0 {
+		if err := db.Where("option_id IN ?", optionIDs).Find(&votes).Error; err != nil {
+			return response, err
+		}
+	}
+
+	for _, option := range poll.Options {
+		optionResponse := api.TimePollOptionResponse{ID: option.ID, StartTime: option.StartTime.In(event.Location())}
+		for _, vote := range votes {
+			if vote.OptionID != option.ID {
+				continue
+			}
+			switch vote.Answer {
+			case models.TimePollYes:
+				optionResponse.Yes++
+			case models.TimePollMaybe:
+				optionResponse.Maybe++
+			case models.TimePollNo:
+				optionResponse.No++
+			}
+			if vote.UserID == userID {
+				optionResponse.MyAnswer = vote.Answer
+			}
+		}
+		response.Options = append(response.Options, optionResponse)
+	}
+	return response, nil
+}
+
+// timePollOptions returns the candidate times of a new poll, either given in the request or the best slots of a day
+func timePollOptions(db *gorm.DB, userID uint, event *models.Event, request *api.CreateTimePollRequest) ([]time.Time, int, string) {
+	if len(request.Options) > 0 && request.Seed != nil {
+		return nil, http.StatusBadRequest, "Give either options or a seed, not both"
+	}
+
+	var options []time.Time
+	if request.Seed != nil {
+		slots, status, message := findBestSlotsForDay(db, userID, &api.FindBestTimeSlotsRequest{
+			EventID:      event.ID,
+			Date:         request.Seed.Date,
+			DurationMins: request.Seed.DurationMins,
+			StartTime:    request.Seed.StartTime,
+			EndTime:      request.Seed.EndTime,
+			Participants: request.Seed.Participants,
+		})
+		if message != "" {
+			return nil, status, message
+		}
+		for _, slot := range slots {
+			options = append(options, slot.Start.UTC())
+		}
+	}
+
+	for _, value := range request.Options {
+		option, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, http.StatusBadRequest, "Invalid option format. Use RFC3339 format."
+		}
+		for _, existing := range options {
+			if existing.Equal(option) {
+				return nil, http.StatusBadRequest, "Options must be different times"
+			}
+		}
+		options = append(options, option.UTC())
+	}
+
+	if len(options) < 2 {
+		return nil, http.StatusBadRequest, "A poll needs at least two candidate times"
+	}
+	if len(options) > maxTimePollOptions {
+		return nil, http.StatusBadRequest, fmt.Sprintf("A poll can have at most %d candidate times", maxTimePollOptions)
+	}
+	return options, http.StatusOK, ""
+}
+
+// @Summary Create a time poll
+// @Description Put candidate times of the event to a vote. The times are given as options or taken from the best slots of a day.
+// @Description An event has at most one open poll, recurring events can't be polled
+// @Tags polls
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param request body api.CreateTimePollRequest true "Candidate times"
+// @Success 200 {object} api.APIResponse{data=api.TimePollResponse} "Poll created successfully"
+// @Failure 400 {object} api.APIResponse "Invalid options or a poll is already open"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not an organizer"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to create poll"
+// @Router /events/{id}/time-polls [post]
+func CreateTimePoll(c *gin.Context, db *gorm.DB) {
+	event, role, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	if !permissions.CanManagePolls(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only organizers and co-organizers can create polls"})
+		return
+	}
+
+	var request api.CreateTimePollRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	if event.RecurrenceRule != "" {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Recurring events can't be polled"})
+		return
+	}
+
+	var openPolls int64
+	db.Model(&models.TimePoll{}).Where("event_id = ? AND status = ?", event.ID, models.PollOpen).Count(&openPolls)
+	if openPolls > 0 {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "The event already has an open poll"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	options, status, message := timePollOptions(db, userID.(uint), event, &request)
+	if message != "" {
+		c.JSON(status, api.APIResponse{Error: message})
+		return
+	}
+
+	poll := models.TimePoll{EventID: event.ID, CreatedByID: userID.(uint), Status: models.PollOpen}
+	for _, option := range options {
+		poll.Options = append(poll.Options, models.TimePollOption{StartTime: option})
+	}
+	if err := db.Create(&poll).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to create poll"})
+		return
+	}
+
+	response, err := toTimePollResponse(db, event, &poll, userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to create poll"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Poll created",
+		Data:    response,
+	})
+}
+
+// @Summary Get time polls of an event
+// @Description Get the time polls of the event, newest first, with the tally of every candidate time and the answers of the user
+// @Tags polls
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Success 200 {object} api.TimePollsResponse "Polls retrieved successfully"
+// @Failure 400 {object} api.APIResponse "Invalid event ID"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not a participant of the event"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to retrieve polls"
+// @Router /events/{id}/time-polls [get]
+func GetTimePolls(c *gin.Context, db *gorm.DB) {
+	event, _, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	var polls []models.TimePoll
+	if err := db.Preload("Options", func(db *gorm.DB) *gorm.DB { return db.Order("start_time ASC") }).
+		Where("event_id = ?", event.ID).
+		Order("created_at DESC").
+		Find(&polls).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve polls"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	response := api.TimePollsResponse{Polls: []api.TimePollResponse{}}
+	for _, poll := range polls {
+		pollResponse, err := toTimePollResponse(db, event, &poll, userID.(uint))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve polls"})
+			return
+		}
+		response.Polls = append(response.Polls, pollResponse)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Vote in a time poll
+// @Description Answer yes, maybe or no to candidate times of an open poll, answering again replaces the previous answer
+// @Tags polls
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param poll_id path int true "Poll ID"
+// @Param request body api.TimePollVoteRequest true "Answers"
+// @Success 200 {object} api.APIResponse{data=api.TimePollResponse} "Votes saved successfully"
+// @Failure 400 {object} api.APIResponse "Invalid answers or poll closed"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - viewers can't vote"
+// @Failure 404 {object} api.APIResponse "Event or poll not found"
+// @Failure 500 {object} api.APIResponse "Failed to save votes"
+// @Router /events/{id}/time-polls/{poll_id}/votes [put]
+func VoteTimePoll(c *gin.Context, db *gorm.DB) {
+	event, role, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	if !permissions.CanVote(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Viewers can't vote"})
+		return
+	}
+
+	poll, ok := loadTimePoll(c, db, event)
+	if !ok {
+		return
+	}
+
+	var request api.TimePollVoteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	if poll.Status != models.PollOpen {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Poll is closed"})
+		return
+	}
+
+	for _, vote := range request.Votes {
+		if !models.IsValidTimePollAnswer(vote.Answer) {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid answer. Use yes, maybe or no."})
+			return
+		}
+		found := false
+		for _, option := range poll.Options {
+			found = found || option.ID == vote.OptionID
+		}
+		if !found {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: fmt.Sprintf("Option %d is not part of this poll", vote.OptionID)})
+			return
+		}
+	}
+
+	userID, _ := c.Get("user_id")
+	tx := db.Begin()
+	for _, vote := range request.Votes {
+		var existing models.TimePollVote
+		err := tx.Where("option_id = ? AND user_id = ?", vote.OptionID, userID).First(&existing).Error
+		if err == nil {
+			err = tx.Model(&existing).Update("answer", vote.Answer).Error
+		} else {
+			err = tx.Create(&models.TimePollVote{OptionID: vote.OptionID, UserID: userID.(uint), Answer: vote.Answer}).Error
+		}
+		if err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to save votes"})
+			return
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to save votes"})
+		return
+	}
+
+	response, err := toTimePollResponse(db, event, poll, userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to save votes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Votes saved",
+		Data:    response,
+	})
+}
+
+// @Summary Close a time poll
+// @Description Close an open poll with the chosen candidate time. The event moves to that time and every participant gets notified
+// @Tags polls
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param poll_id path int true "Poll ID"
+// @Param request body api.CloseTimePollRequest true "Chosen option"
+// @Success 200 {object} api.APIResponse{data=api.TimePollResponse} "Poll closed successfully"
+// @Failure 400 {object} api.APIResponse "Invalid option or poll already closed"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - not an organizer"
+// @Failure 404 {object} api.APIResponse "Event or poll not found"
+// @Failure 500 {object} api.APIResponse "Failed to close poll"
+// @Router /events/{id}/time-polls/{poll_id}/close [post]
+func CloseTimePoll(c *gin.Context, db *gorm.DB) {
+	event, role, ok := loadViewableEvent(c, db)
+	if !ok {
+		return
+	}
+
+	if !permissions.CanManagePolls(role) {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "Only organizers and co-organizers can close polls"})
+		return
+	}
+
+	poll, ok := loadTimePoll(c, db, event)
+	if !ok {
+		return
+	}
+
+	var request api.CloseTimePollRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid payload"})
+		return
+	}
+
+	var chosen *models.TimePollOption
+	for i := range poll.Options {
+		if poll.Options[i].ID == request.OptionID {
+			chosen = &poll.Options[i]
+		}
+	}
+	if chosen == nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Option is not part of this poll"})
+		return
+	}
+
+	if poll.Status != models.PollOpen {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Poll is already closed"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	closedByID := userID.(uint)
+	now := time.Now()
+
+	tx := db.Begin()
+
+	// A concurrent close leaves the poll untouched
+	result := tx.Model(&models.TimePoll{}).
+		Where("id = ? AND status = ?", poll.ID, models.PollOpen).
+		Updates(map[string]interface{}{
+			"status":           models.PollClosed,
+			"chosen_option_id": chosen.ID,
+			"closed_at":        now,
+		})
+	if result.Error != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to close poll"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Poll is already closed"})
+		return
+	}
+
+	if err := tx.Model(event).Update("event_date_time", chosen.StartTime.UTC()).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to update event time"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to close poll"})
+		return
+	}
+
+	var participantIDs []uint
+	db.Model(&models.EventParticipation{}).Where("event_id = ?", event.ID).Pluck("user_id", &participantIDs)
+	for _, participantID := range participantIDs {
+		if participantID != closedByID {
+			notifyEventUser(db, event, participantID, closedByID, models.PollOpen, models.EventTimeChanged)
+		}
+	}
+
+	poll.Status = models.PollClosed
+	poll.ChosenOptionID = &chosen.ID
+	poll.ClosedAt = &now
+	response, err := toTimePollResponse(db, event, poll, closedByID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to close poll"})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.APIResponse{
+		Message: "Poll closed, event time updated",
+		Data:    response,
+	})
+}
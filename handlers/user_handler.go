@@ -18,24 +18,42 @@ func toUserResponse(user *models.User) *api.UserResponse {
 		return nil
 	}
 	return &api.UserResponse{
-		ID:          user.ID,
-		CreatedAt:   user.CreatedAt,
-		UpdatedAt:   user.UpdatedAt,
-		Email:       user.Email,
-		DisplayName: user.DisplayName,
-		Bio:         user.Bio,
-		Avatar:      user.Avatar,
+		ID:                 user.ID,
+		CreatedAt:          user.CreatedAt,
+		UpdatedAt:          user.UpdatedAt,
+		Email:              user.Email,
+		DisplayName:        user.DisplayName,
+		Bio:                user.Bio,
+		Avatar:             user.Avatar,
+		Timezone:           user.Timezone,
+		TaskReminderHours:  user.TaskReminderHours,
+		EventReminderHours: user.EventReminderHours,
 	}
 }
 
+// getUserTimezone returns the zone name of the user, UTC when the user is unknown
+func getUserTimezone(db *gorm.DB, userID uint) string {
+	var user models.User
+	if err := db.Select("timezone").First(&user, userID).Error; err != nil || user.Timezone == "" {
+		return models.DefaultTimezone
+	}
+	return user.Timezone
+}
+
+// getUserLocation returns the zone of the user, dates and times without an offset are read in it
+func getUserLocation(db *gorm.DB, userID uint) *time.Location {
+	user := models.User{Timezone: getUserTimezone(db, userID)}
+	return user.Location()
+}
+
 // @Summary Register a new user
-// @Description Register a new user with email and password
+// @Description Register a new user with email and password, pending email invitations to that address are linked to the account
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body api.RegisterRequest true "User registration details"
 // @Success 200 {object} api.APIResponse "User registered successfully"
-// @Failure 400 {object} api.APIResponse "Invalid payload"
+// @Failure 400 {object} api.APIResponse "Invalid payload, timezone or reminder hours"
 // @Failure 500 {object} api.APIResponse "Failed to hash password"
 // @Router /register [post]
 func Register(c *gin.Context, db *gorm.DB) {
@@ -45,6 +63,14 @@ func Register(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
+	if request.Timezone == "" {
+		request.Timezone = models.DefaultTimezone
+	}
+	if _, err := models.LoadTimezone(request.Timezone); err != nil {
+		c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid timezone"})
+		return
+	}
+
 	hashedPassword, err := security.HashPassword(request.Password)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to hash password"})
@@ -55,14 +81,21 @@ func Register(c *gin.Context, db *gorm.DB) {
 		Email:        request.Email,
 		PasswordHash: hashedPassword,
 		DisplayName:  "New User",
+		Timezone:     request.Timezone,
+	}
+
+	if err := db.Create(&user).Error; err == nil {
+		// Email invitations sent before the account existed now belong to it
+		db.Model(&models.EmailInvitation{}).
+			Where("email = ? AND user_id IS NULL", models.NormalizeEmail(user.Email)).
+			Update("user_id", user.ID)
 	}
 
-	db.Create(&user)
 	c.JSON(http.StatusOK, api.APIResponse{Message: "User registered", User: toUserResponse(&user)})
 }
 
 // @Summary User login
-// @Description Authenticate a user and return a JWT token
+// @Description Authenticate a user and return a short-lived JWT access token with a refresh token
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -90,13 +123,13 @@ func Login(c *gin.Context, db *gorm.DB) {
 		return
 	}
 
-	token, err := security.GenerateToken(user.ID)
+	tokens, err := startSession(c, db, user.ID, request.DeviceName)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Не удалось сгенерировать токен"})
 		return
 	}
 
-	c.JSON(http.StatusOK, api.LoginResponse{Token: token})
+	c.JSON(http.StatusOK, tokens)
 }
 
 // @Summary Request password reset
@@ -202,7 +235,7 @@ func ResetPassword(c *gin.Context, db *gorm.DB) {
 // @Security BearerAuth
 // @Param request body api.ProfileUpdateRequest true "Profile update data"
 // @Success 200 {object} api.APIResponse "Profile updated successfully"
-// @Failure 400 {object} api.APIResponse "Invalid payload"
+// @Failure 400 {object} api.APIResponse "Invalid payload, timezone or reminder hours"
 // @Failure 401 {object} api.APIResponse "Unauthorized"
 // @Failure 404 {object} api.APIResponse "User not found"
 // @Router /profile [put]
@@ -230,6 +263,25 @@ func UpdateProfile(c *gin.Context, db *gorm.DB) {
 	if request.Avatar != nil {
 		user.Avatar = *request.Avatar
 	}
+	if request.Timezone != nil {
+		if _, err := models.LoadTimezone(*request.Timezone); err != nil || *request.Timezone == "" {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: "Invalid timezone"})
+			return
+		}
+		user.Timezone = *request.Timezone
+	}
+	for _, hours := range []*int{request.TaskReminderHours, request.EventReminderHours} {
+		if hours != nil && (*hours < 0 || *hours > models.MaxReminderHours) {
+			c.JSON(http.StatusBadRequest, api.APIResponse{Error: fmt.Sprintf("Reminder hours must be between 0 and %d", models.MaxReminderHours)})
+			return
+		}
+	}
+	if request.TaskReminderHours != nil {
+		user.TaskReminderHours = *request.TaskReminderHours
+	}
+	if request.EventReminderHours != nil {
+		user.EventReminderHours = *request.EventReminderHours
+	}
 
 	db.Save(&user)
 	c.JSON(http.StatusOK, api.APIResponse{Message: "Profile updated successfully", User: toUserResponse(&user)})
@@ -257,13 +309,26 @@ func GetProfile(c *gin.Context, db *gorm.DB) {
 }
 
 // @Summary User logout
-// @Description Logout the current user (client-side token removal)
+// @Description Logout the current user by revoking the current session, its access and refresh tokens stop working
 // @Tags auth
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {object} api.APIResponse "Logged out successfully"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 500 {object} api.APIResponse "Failed to revoke session"
 // @Router /logout [post]
 func Logout(c *gin.Context, db *gorm.DB) {
+	sessionID, exists := c.Get("session_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, api.APIResponse{Error: "User not authenticated"})
+		return
+	}
+
+	if err := revokeSession(db, sessionID.(uint)); err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to revoke session"})
+		return
+	}
+
 	c.JSON(http.StatusOK, api.APIResponse{Message: "Logged out successfully"})
 }
 
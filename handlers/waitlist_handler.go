@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"errors"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/permissions"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// lockEvent reloads the event with a row lock, capacity decisions made in the same
+// transaction are then serialized between concurrent requests
+func lockEvent(tx *gorm.DB, eventID uint) (*models.Event, error) {
+	var event models.Event
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&event, eventID).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// takenPlaces counts participants who haven't declined, plus-ones don't take places
+func takenPlaces(tx *gorm.DB, eventID uint) (int, error) {
+	var count int64
+	err := tx.Model(&models.EventParticipation{}).
+		Where("event_id = ? AND rsvp_status <> ?", eventID, models.RSVPNotGoing).
+		Count(&count).Error
+	return int(count), err
+}
+
+func hasFreePlace(tx *gorm.DB, event *models.Event) (bool, error) {
+	if event.Capacity == nil {
+		return true, nil
+	}
+	taken, err := takenPlaces(tx, event.ID)
+	if err != nil {
+		return false, err
+	}
+	return taken < *event.Capacity, nil
+}
+
+func waitlistPosition(tx *gorm.DB, entry *models.WaitlistEntry) (int, error) {
+	var position int64
+	err := tx.Model(&models.WaitlistEntry{}).
+		Where("event_id = ? AND id <= ?", entry.EventID, entry.ID).
+		Count(&position).Error
+	return int(position), err
+}
+
+var (
+	errAlreadyParticipant = errors.New("already a participant of the event")
+	errAlreadyWaitlisted  = errors.New("already on the waitlist of the event")
+)
+
+// reservePlace adds the user to the event as a participant or, when the event is full, to the
+// end of its waitlist. It returns the waitlist position or 0 if the user joined right away.
+func reservePlace(tx *gorm.DB, eventID, userID uint) (int, error) {
+	event, err := lockEvent(tx, eventID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Checks made before the lock can be outdated by a concurrent join of the same user
+	var count int64
+	if err := tx.Model(&models.EventParticipation{}).Where("event_id = ? AND user_id = ?", eventID, userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		return 0, errAlreadyParticipant
+	}
+	if err := tx.Model(&models.WaitlistEntry{}).Where("event_id = ? AND user_id = ?", eventID, userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		return 0, errAlreadyWaitlisted
+	}
+
+	free, err := hasFreePlace(tx, event)
+	if err != nil {
+		return 0, err
+	}
+
+	if free {
+		participation := models.EventParticipation{
+			EventID: eventID,
+			UserID:  userID,
+			Role:    models.RoleParticipant,
+		}
+		return 0, tx.Create(&participation).Error
+	}
+
+	entry := models.WaitlistEntry{EventID: eventID, UserID: userID}
+	if err := tx.Create(&entry).Error; err != nil {
+		return 0, err
+	}
+	return waitlistPosition(tx, &entry)
+}
+
+// promoteFromWaitlist fills free places of the event with waitlisted users in order and
+// returns who got in. The caller notifies them once the transaction is committed.
+func promoteFromWaitlist(tx *gorm.DB, eventID uint) ([]uint, error) {
+	event, err := lockEvent(tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	var promoted []uint
+	for {
+		free, err := hasFreePlace(tx, event)
+		if err != nil {
+			return nil, err
+		}
+		if !free {
+			return promoted, nil
+		}
+
+		var entry models.WaitlistEntry
+		if err := tx.Where("event_id = ?", eventID).Order("id ASC").First(&entry).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return promoted, nil
+			}
+			return nil, err
+		}
+
+		if err := tx.Delete(&entry).Error; err != nil {
+			return nil, err
+		}
+
+		var existing models.EventParticipation
+		if err := tx.Where("event_id = ? AND user_id = ?", eventID, entry.UserID).First(&existing).Error; err == nil {
+			continue
+		}
+
+		participation := models.EventParticipation{
+			EventID: eventID,
+			UserID:  entry.UserID,
+			Role:    models.RoleParticipant,
+		}
+		if err := tx.Create(&participation).Error; err != nil {
+			return nil, err
+		}
+		promoted = append(promoted, entry.UserID)
+	}
+}
+
+func notifyPromoted(db *gorm.DB, event *models.Event, promoted []uint, changedByID uint) {
+	for _, userID := range promoted {
+		notifyEventUser(db, event, userID, changedByID, models.ParticipationWaitlisted, models.ParticipationJoined)
+	}
+}
+
+// @Summary Get event waitlist
+// @Description Get users waiting for a place in a full event in order. Waitlisted users only see their own entry
+// @Tags events
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Success 200 {object} api.WaitlistResponse "Waitlist"
+// @Failure 401 {object} api.APIResponse "Unauthorized"
+// @Failure 403 {object} api.APIResponse "Forbidden - neither a participant nor waitlisted"
+// @Failure 404 {object} api.APIResponse "Event not found"
+// @Failure 500 {object} api.APIResponse "Failed to retrieve waitlist"
+// @Router /events/{id}/waitlist [get]
+func GetWaitlist(c *gin.Context, db *gorm.DB) {
+	var event models.Event
+	if err := db.First(&event, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, api.APIResponse{Error: "Event not found"})
+		return
+	}
+
+	var entries []models.WaitlistEntry
+	if err := db.Where("event_id = ?", event.ID).Order("id ASC").Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, api.APIResponse{Error: "Failed to retrieve waitlist"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	role, _ := permissions.GetRole(db, &event, userID.(uint))
+	canView := permissions.CanView(role)
+
+	response := api.WaitlistResponse{Capacity: event.Capacity, Waitlist: []api.WaitlistEntryResponse{}}
+	waitlisted := false
+	for i, entry := range entries {
+		if !canView && entry.UserID != userID.(uint) {
+			continue
+		}
+		waitlisted = waitlisted || entry.UserID == userID.(uint)
+		response.Waitlist = append(response.Waitlist, api.WaitlistEntryResponse{
+			UserID:      entry.UserID,
+			DisplayName: getUserDisplayName(db, entry.UserID),
+			Position:    i + 1,
+			CreatedAt:   entry.CreatedAt,
+		})
+	}
+
+	if !canView && !waitlisted {
+		c.JSON(http.StatusForbidden, api.APIResponse{Error: "You are not a participant of this event"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
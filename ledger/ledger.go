@@ -0,0 +1,198 @@
+// Package ledger splits shared expenses between people and works out who pays whom to settle up.
+// Amounts are integer minor units (cents), so splits always add up to the total.
+package ledger
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// SplitEqual divides the total into n parts, the first parts get a unit more when it doesn't divide evenly
+func SplitEqual(total int64, n int) []int64 {
+	if n <= 0 {
+		return nil
+	}
+	weights := make([]int64, n)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return SplitByShares(total, weights)
+}
+
+// SplitByShares divides the total in proportion to the shares. Parts are rounded down and the units
+// left over go one by one to the parts that lost the most in rounding, earlier parts first on ties.
+func SplitByShares(total int64, shares []int64) []int64 {
+	var sum int64
+	for _, share := range shares {
+		if share < 0 {
+			return nil
+		}
+		sum += share
+	}
+	if sum == 0 {
+		return nil
+	}
+
+	parts := make([]int64, len(shares))
+	remainders := make([]int64, len(shares))
+	var assigned int64
+	for i, share := range shares {
+		parts[i] = total * share / sum
+		remainders[i] = total * share % sum
+		assigned += parts[i]
+	}
+
+	order := make([]int, len(shares))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+	for i := 0; assigned < total; i++ {
+		parts[order[i%len(order)]]++
+		assigned++
+	}
+	return parts
+}
+
+// Transfer is a payment that settles debts, From pays Amount to To
+type Transfer struct {
+	From   uint
+	To     uint
+	Amount int64
+}
+
+// exactSettleLimit is the most people with a balance for which Settle searches for the fewest transfers,
+// the search takes 2^n steps so larger groups are settled greedily
+const exactSettleLimit = 18
+
+type balance struct {
+	userID uint
+	amount int64
+}
+
+// Settle turns net balances into the fewest transfers, a positive balance means the person gets money back.
+// People whose balances add up to zero can settle among themselves with one transfer less than there are of them,
+// so the fewest transfers is the number of people with a balance minus the most such groups they split into.
+// Above exactSettleLimit people the largest debtor pays the largest creditor instead, which needs at most
+// one transfer less than there are people. Balances have to add up to zero.
+// Transfers are ordered by amount, largest first.
+func Settle(balances map[uint]int64) []Transfer {
+	var entries []balance
+	for userID, amount := range balances {
+		if amount != 0 {
+			entries = append(entries, balance{userID, amount})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].userID < entries[j].userID })
+
+	var transfers []Transfer
+	if len(entries) > exactSettleLimit {
+		transfers = settleGreedy(entries)
+	} else {
+		for _, group := range zeroSumGroups(entries) {
+			transfers = append(transfers, settleGreedy(group)...)
+		}
+	}
+
+	sort.Slice(transfers, func(i, j int) bool {
+		if transfers[i].Amount != transfers[j].Amount {
+			return transfers[i].Amount > transfers[j].Amount
+		}
+		if transfers[i].From != transfers[j].From {
+			return transfers[i].From < transfers[j].From
+		}
+		return transfers[i].To < transfers[j].To
+	})
+	return transfers
+}
+
+// zeroSumGroups splits the balances into the most groups that add up to zero.
+// most[mask] is the most zero sum groups the people in mask split into when they are taken one by one
+// and a group closes every time the people taken so far add up to zero.
+func zeroSumGroups(entries []balance) [][]balance {
+	n := len(entries)
+	full := 1<<n - 1
+	sums := make([]int64, full+1)
+	most := make([]int8, full+1)
+	for mask := 1; mask <= full; mask++ {
+		low := mask & -mask
+		i := bits.TrailingZeros(uint(low))
+		sums[mask] = sums[mask^low] + entries[i].amount
+		for rest := mask; rest != 0; rest &= rest - 1 {
+			bit := rest & -rest
+			if most[mask^bit] > most[mask] {
+				most[mask] = most[mask^bit]
+			}
+		}
+		if sums[mask] == 0 {
+			most[mask]++
+		}
+	}
+
+	var groups [][]balance
+	for mask := full; mask != 0; {
+		var group []balance
+		for {
+			closes := int8(0)
+			if sums[mask] == 0 {
+				closes = 1
+			}
+			for rest := mask; rest != 0; rest &= rest - 1 {
+				bit := rest & -rest
+				if most[mask^bit]+closes == most[mask] {
+					group = append(group, entries[bits.TrailingZeros(uint(bit))])
+					mask ^= bit
+					break
+				}
+			}
+			if sums[mask] == 0 {
+				break
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// settleGreedy lets the largest debtor pay the largest creditor until everybody is even
+func settleGreedy(entries []balance) []Transfer {
+	var debtors, creditors []balance
+	for _, entry := range entries {
+		if entry.amount < 0 {
+			debtors = append(debtors, balance{entry.userID, -entry.amount})
+		} else if entry.amount > 0 {
+			creditors = append(creditors, entry)
+		}
+	}
+
+	byAmount := func(entries []balance) func(i, j int) bool {
+		return func(i, j int) bool {
+			if entries[i].amount != entries[j].amount {
+				return entries[i].amount > entries[j].amount
+			}
+			return entries[i].userID < entries[j].userID
+		}
+	}
+
+	var transfers []Transfer
+	for len(debtors) > 0 && len(creditors) > 0 {
+		sort.Slice(debtors, byAmount(debtors))
+		sort.Slice(creditors, byAmount(creditors))
+
+		amount := debtors[0].amount
+		if creditors[0].amount < amount {
+			amount = creditors[0].amount
+		}
+		transfers = append(transfers, Transfer{From: debtors[0].userID, To: creditors[0].userID, Amount: amount})
+
+		debtors[0].amount -= amount
+		creditors[0].amount -= amount
+		if debtors[0].amount == 0 {
+			debtors = debtors[1:]
+		}
+		if creditors[0].amount == 0 {
+			creditors = creditors[1:]
+		}
+	}
+	return transfers
+}
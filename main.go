@@ -5,7 +5,9 @@ import (
 	"itsplanned/models"
 	"itsplanned/routes"
 	"itsplanned/services/email"
+	"itsplanned/services/exchange"
 	"itsplanned/services/scheduler"
+	"itsplanned/services/storage"
 	"itsplanned/services/yandex"
 	"log"
 	"os"
@@ -85,15 +87,28 @@ func main() {
 		log.Fatal("Error initializing Yandex token service:", err)
 	}
 
+	if err := exchange.Init(); err != nil {
+		log.Fatal("Error initializing exchange rates:", err)
+	}
+
+	if err := storage.Init(); err != nil {
+		log.Fatal("Error initializing file storage:", err)
+	}
+
 	taskScheduler := scheduler.NewScheduler(db)
 	taskScheduler.SetupCalendarSyncTask()
+	taskScheduler.SetupPollDeadlineTask()
+	taskScheduler.SetupTaskReminderTask()
 	taskScheduler.Start()
-	log.Println("Started calendar sync background task")
+	log.Println("Started calendar sync, poll deadline and task reminder background tasks")
 
 	// Run database migrations
 	if err := models.MigrateUser(db); err != nil {
 		log.Fatal("Failed to migrate user model: ", err)
 	}
+	if err := models.MigrateSession(db); err != nil {
+		log.Fatal("Failed to migrate session model: ", err)
+	}
 	if err := models.MigrateEvent(db); err != nil {
 		log.Fatal("Failed to migrate event model: ", err)
 	}
@@ -109,6 +124,48 @@ func main() {
 	if err := models.MigrateEventParticipation(db); err != nil {
 		log.Fatal("Failed to migrate event participation model: ", err)
 	}
+	if err := models.MigrateEventOccurrenceOverride(db); err != nil {
+		log.Fatal("Failed to migrate event occurrence override model: ", err)
+	}
+	if err := models.MigrateEventTemplate(db); err != nil {
+		log.Fatal("Failed to migrate event template model: ", err)
+	}
+	if err := models.MigrateAvailability(db); err != nil {
+		log.Fatal("Failed to migrate availability model: ", err)
+	}
+	if err := models.MigrateTimePoll(db); err != nil {
+		log.Fatal("Failed to migrate time poll model: ", err)
+	}
+	if err := models.MigratePoll(db); err != nil {
+		log.Fatal("Failed to migrate poll model: ", err)
+	}
+	if err := models.MigrateExpense(db); err != nil {
+		log.Fatal("Failed to migrate expense model: ", err)
+	}
+	if err := models.MigrateSettlement(db); err != nil {
+		log.Fatal("Failed to migrate settlement model: ", err)
+	}
+	if err := models.MigrateBudgetCategory(db); err != nil {
+		log.Fatal("Failed to migrate budget category model: ", err)
+	}
+	if err := models.MigrateAttachment(db); err != nil {
+		log.Fatal("Failed to migrate attachment model: ", err)
+	}
+	if err := models.MigrateSentReminder(db); err != nil {
+		log.Fatal("Failed to migrate sent reminder model: ", err)
+	}
+	if err := models.MigrateWaitlistEntry(db); err != nil {
+		log.Fatal("Failed to migrate waitlist entry model: ", err)
+	}
+	if err := models.MigrateEmailInvitation(db); err != nil {
+		log.Fatal("Failed to migrate email invitation model: ", err)
+	}
+	if err := models.MigrateJoinRequest(db); err != nil {
+		log.Fatal("Failed to migrate join request model: ", err)
+	}
+	if err := models.MigrateEventOwnershipTransfer(db); err != nil {
+		log.Fatal("Failed to migrate event ownership transfer model: ", err)
+	}
 	if err := models.MigratePasswordReset(db); err != nil {
 		log.Fatal("Failed to migrate password reset model: ", err)
 	}
@@ -1,15 +1,18 @@
 package middleware
 
 import (
+	"itsplanned/models"
 	"itsplanned/security"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-// JWT token validating and passing userID in context
-func AuthMiddleware() gin.HandlerFunc {
+// JWT token validating, checking that its session is not revoked and passing userID in context
+func AuthMiddleware(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -19,14 +22,33 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		userID, err := security.ValidateToken(tokenString)
+		userID, tokenID, err := security.ValidateToken(tokenString)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			c.Abort()
 			return
 		}
 
+		var session models.Session
+		if err := db.Where("token_id = ? AND user_id = ?", tokenID, userID).First(&session).Error; err != nil || !session.IsActive() {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked or expired"})
+			c.Abort()
+			return
+		}
+
+		// The query checks the time again so concurrent requests of the session don't all write it
+		now := time.Now()
+		if now.Sub(session.LastSeenAt) > models.SessionLastSeenPrecision {
+			db.Model(&models.Session{}).
+				Where("id = ? AND last_seen_at < ?", session.ID, now.Add(-models.SessionLastSeenPrecision)).
+				UpdateColumns(map[string]interface{}{
+					"last_seen_at": now,
+					"ip_address":   c.ClientIP(),
+				})
+		}
+
 		c.Set("user_id", userID)
+		c.Set("session_id", session.ID)
 		c.Next()
 	}
 }
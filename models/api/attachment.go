@@ -0,0 +1,23 @@
+package api
+
+import "time"
+
+// AttachmentResponse represents a file attached to a task or an expense
+type AttachmentResponse struct {
+	ID           uint      `json:"id" example:"1"`
+	EventID      uint      `json:"event_id" example:"1"`
+	TaskID       *uint     `json:"task_id,omitempty" example:"1"`
+	ExpenseID    *uint     `json:"expense_id,omitempty" example:"1"`
+	UploadedByID uint      `json:"uploaded_by_id" example:"1"`
+	FileName     string    `json:"file_name" example:"receipt.jpg"`
+	ContentType  string    `json:"content_type" example:"image/jpeg"`
+	Size         int64     `json:"size" example:"204800"`
+	URL          string    `json:"url" example:"/attachments/1"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty" example:"/attachments/1/thumbnail"`
+	CreatedAt    time.Time `json:"created_at" example:"2024-03-15T14:30:00Z"`
+}
+
+// AttachmentsResponse represents the files attached to a task or an expense
+type AttachmentsResponse struct {
+	Attachments []AttachmentResponse `json:"attachments"`
+}
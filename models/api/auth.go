@@ -11,23 +11,53 @@ type UserResponse struct {
 	DisplayName string    `json:"display_name" example:"John Doe"`
 	Bio         string    `json:"bio,omitempty" example:"Software developer and tech enthusiast"`
 	Avatar      string    `json:"avatar,omitempty" example:"https://example.com/avatar.jpg"`
+	Timezone    string    `json:"timezone" example:"Europe/Moscow"`
+	// TaskReminderHours and EventReminderHours are how long before due dates and events reminders are sent
+	TaskReminderHours  int `json:"task_reminder_hours" example:"24"`
+	EventReminderHours int `json:"event_reminder_hours" example:"48"`
 }
 
 // RegisterRequest represents the user registration request
 type RegisterRequest struct {
 	Email    string `json:"email" example:"user@example.com"`
 	Password string `json:"password" example:"secretpassword123"`
+	// Timezone is an IANA zone name, UTC by default
+	Timezone string `json:"timezone,omitempty" example:"Europe/Moscow"`
 }
 
 // LoginRequest represents the user login request
 type LoginRequest struct {
-	Email    string `json:"email" example:"user@example.com"`
-	Password string `json:"password" example:"secretpassword123"`
+	Email      string `json:"email" example:"user@example.com"`
+	Password   string `json:"password" example:"secretpassword123"`
+	DeviceName string `json:"device_name,omitempty" example:"John's iPhone"`
 }
 
-// LoginResponse represents the response after successful login
+// LoginResponse represents the response after successful login or token refresh
 type LoginResponse struct {
-	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	Token        string    `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	ExpiresAt    time.Time `json:"expires_at" example:"2024-03-16T12:15:00Z"`
+	RefreshToken string    `json:"refresh_token" example:"q1w2e3r4t5y6u7i8o9p0..."`
+}
+
+// RefreshTokenRequest represents the request to exchange a refresh token for a new token pair
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"q1w2e3r4t5y6u7i8o9p0..."`
+}
+
+// SessionResponse represents a signed-in device in API responses
+type SessionResponse struct {
+	ID         uint      `json:"id" example:"1"`
+	DeviceName string    `json:"device_name" example:"John's iPhone"`
+	IPAddress  string    `json:"ip_address" example:"192.168.1.10"`
+	UserAgent  string    `json:"user_agent" example:"ItsPlanned/1.0 (iPhone; iOS 17.4)"`
+	LastSeenAt time.Time `json:"last_seen_at" example:"2024-03-16T12:00:00Z"`
+	CreatedAt  time.Time `json:"created_at" example:"2024-03-10T09:00:00Z"`
+	IsCurrent  bool      `json:"is_current" example:"true"`
+}
+
+// SessionsResponse represents the list of active sessions of the user
+type SessionsResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
 }
 
 // PasswordResetRequest represents the password reset request
@@ -52,6 +82,10 @@ type ProfileUpdateRequest struct {
 	DisplayName *string `json:"display_name,omitempty" example:"John Doe"`
 	Bio         *string `json:"bio,omitempty" example:"Software developer and tech enthusiast"`
 	Avatar      *string `json:"avatar,omitempty" example:"https://example.com/avatar.jpg"`
+	Timezone    *string `json:"timezone,omitempty" example:"Europe/Moscow"`
+	// TaskReminderHours and EventReminderHours are up to 720 hours, 0 turns the reminders off
+	TaskReminderHours  *int `json:"task_reminder_hours,omitempty" example:"24"`
+	EventReminderHours *int `json:"event_reminder_hours,omitempty" example:"48"`
 }
 
 // APIResponse represents a generic API response
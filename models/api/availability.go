@@ -0,0 +1,34 @@
+package api
+
+import "time"
+
+// AvailabilityRequest represents the request to declare or replace an availability entry.
+// Weekly windows need weekday, start_time and end_time, blocks need starts_at and ends_at
+type AvailabilityRequest struct {
+	Kind string `json:"kind" example:"unavailable" enums:"unavailable,preferred,block" binding:"required"`
+	// Weekday counts from Sunday as 0
+	Weekday   *int   `json:"weekday,omitempty" example:"2"`
+	StartTime string `json:"start_time,omitempty" example:"18:00"`
+	EndTime   string `json:"end_time,omitempty" example:"22:00"`
+	// StartsAt and EndsAt bound a one-off block
+	StartsAt *time.Time `json:"starts_at,omitempty" example:"2024-04-05T09:00:00Z"`
+	EndsAt   *time.Time `json:"ends_at,omitempty" example:"2024-04-07T18:00:00Z"`
+	Note     string     `json:"note,omitempty" example:"Evening classes"`
+}
+
+// AvailabilityResponse represents an availability entry in API responses
+type AvailabilityResponse struct {
+	ID        uint       `json:"id" example:"1"`
+	Kind      string     `json:"kind" example:"unavailable"`
+	Weekday   *int       `json:"weekday,omitempty" example:"2"`
+	StartTime string     `json:"start_time,omitempty" example:"18:00"`
+	EndTime   string     `json:"end_time,omitempty" example:"22:00"`
+	StartsAt  *time.Time `json:"starts_at,omitempty" example:"2024-04-05T09:00:00Z"`
+	EndsAt    *time.Time `json:"ends_at,omitempty" example:"2024-04-07T18:00:00Z"`
+	Note      string     `json:"note,omitempty" example:"Evening classes"`
+}
+
+// AvailabilitiesResponse represents the availability entries of the user
+type AvailabilitiesResponse struct {
+	Availability []AvailabilityResponse `json:"availability"`
+}
@@ -0,0 +1,23 @@
+package api
+
+// BudgetCategoryRequest represents the request to create or replace a budget category
+type BudgetCategoryRequest struct {
+	// Name is matched with the category of tasks and expenses, case doesn't matter
+	Name  string  `json:"name" example:"food" binding:"required,max=50"`
+	Limit float64 `json:"limit" example:"300.00"`
+}
+
+// BudgetCategoryResponse represents a budget category, Limit is in the event currency
+type BudgetCategoryResponse struct {
+	ID    uint    `json:"id" example:"1"`
+	Name  string  `json:"name" example:"food"`
+	Limit float64 `json:"limit" example:"300.00"`
+}
+
+// BudgetCategoriesResponse represents the budget categories of an event and how much of the initial budget they take
+type BudgetCategoriesResponse struct {
+	Categories  []BudgetCategoryResponse `json:"categories"`
+	Allocated   float64                  `json:"allocated" example:"800.00"`
+	Unallocated float64                  `json:"unallocated" example:"200.00"`
+	Currency    string                   `json:"currency" example:"RUB"`
+}
@@ -11,8 +11,31 @@ type EventResponse struct {
 	Description   string    `json:"description" example:"Celebrating John's 30th birthday"`
 	EventDateTime time.Time `json:"event_date_time" example:"2024-04-01T18:00:00Z"`
 	InitialBudget float64   `json:"initial_budget" example:"1000.00"`
+	Currency      string    `json:"currency" example:"RUB"`
 	OrganizerID   uint      `json:"organizer_id" example:"1"`
 	Place         string    `json:"place" example:"Central Park"`
+	Capacity      *int      `json:"capacity,omitempty" example:"30"`
+	// Timezone of the event, times of the event are given in it
+	Timezone string `json:"timezone" example:"Europe/Moscow"`
+	// RecurrenceRule is set for recurring events, the RRULE value without the prefix
+	RecurrenceRule string `json:"recurrence_rule,omitempty" example:"FREQ=WEEKLY;BYDAY=MO"`
+	// OccurrenceTime identifies an occurrence of a recurring event, it is the start produced by the rule
+	// and stays the same when the occurrence is moved
+	OccurrenceTime *time.Time `json:"occurrence_time,omitempty" example:"2024-04-08T10:00:00Z"`
+	// RSVPCounts is only filled in for a single event
+	RSVPCounts *RSVPCounts `json:"rsvp_counts,omitempty"`
+}
+
+// RSVPCounts represents how many participants gave each RSVP answer
+type RSVPCounts struct {
+	Going      int `json:"going" example:"5"`
+	Maybe      int `json:"maybe" example:"2"`
+	NotGoing   int `json:"not_going" example:"1"`
+	NoResponse int `json:"no_response" example:"3"`
+	// PlusOnes counts guests of participants who are going
+	PlusOnes int `json:"plus_ones" example:"2"`
+	// ExpectedGuests is going participants together with their plus-ones
+	ExpectedGuests int `json:"expected_guests" example:"7"`
 }
 
 // CreateEventRequest represents the request to create a new event
@@ -22,6 +45,13 @@ type CreateEventRequest struct {
 	EventDateTime string  `json:"event_date_time" binding:"required" example:"2024-04-01T18:00:00Z"`
 	InitialBudget float64 `json:"initial_budget" example:"1000.00"`
 	Place         string  `json:"place" example:"Central Park"`
+	Capacity      *int    `json:"capacity,omitempty" example:"30"`
+	// Currency is an ISO 4217 code the budget and tasks are planned in, RUB by default
+	Currency string `json:"currency,omitempty" example:"EUR" binding:"omitempty,len=3"`
+	// Timezone is an IANA zone name, the organizer's zone by default. Recurring events repeat at the same local time in it
+	Timezone string `json:"timezone,omitempty" example:"Europe/Moscow"`
+	// RecurrenceRule makes the event repeat, RFC 5545 RRULE with FREQ, INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY and BYMONTH
+	RecurrenceRule string `json:"recurrence_rule,omitempty" example:"FREQ=WEEKLY;BYDAY=MO"`
 }
 
 // UpdateEventRequest represents the request to update an event
@@ -31,13 +61,38 @@ type UpdateEventRequest struct {
 	EventDateTime *string  `json:"event_date_time,omitempty" example:"2024-04-01T18:00:00Z"`
 	Budget        *float64 `json:"budget,omitempty" example:"1500.00"`
 	Place         *string  `json:"place,omitempty" example:"Central Park"`
+	// Capacity of 0 removes the limit
+	Capacity *int    `json:"capacity,omitempty" example:"30"`
+	Timezone *string `json:"timezone,omitempty" example:"Europe/Moscow"`
+	// Currency changes the currency of the budget, the tasks and the budget categories, their amounts are converted at the current rate
+	Currency *string `json:"currency,omitempty" example:"EUR" binding:"omitempty,len=3"`
+	// RecurrenceRule replaces the rule of a recurring event, an empty string stops the repetition
+	RecurrenceRule *string `json:"recurrence_rule,omitempty" example:"FREQ=WEEKLY;BYDAY=MO"`
+	// Scope of the change for recurring events, "all" by default
+	Scope string `json:"scope,omitempty" example:"this" enums:"this,following,all"`
+	// OccurrenceTime selects the occurrence for the "this" and "following" scopes
+	OccurrenceTime *string `json:"occurrence_time,omitempty" example:"2024-04-08T10:00:00Z"`
 }
 
-// EventBudgetResponse represents the response for event budget information
+// EventBudgetResponse represents the response for event budget information.
+// RealBudget sums the planned budget of completed tasks, Actual sums the recorded expenses.
+// Remaining is what is left of the initial budget after the expenses, Allocated is given to budget categories.
+// OverBudget is set when the planned or spent amount exceeds the initial budget or a category does.
+// Amounts are in the event currency, expenses in other currencies are converted at the current rate.
+// Expenses in UnconvertedCurrencies have no exchange rate at the moment and are left out
 type EventBudgetResponse struct {
-	InitialBudget float64 `json:"initial_budget" example:"1000.00"`
-	RealBudget    float64 `json:"real_budget" example:"950.00"`
-	Difference    float64 `json:"difference" example:"50.00"`
+	Currency              string                   `json:"currency" example:"RUB"`
+	InitialBudget         float64                  `json:"initial_budget" example:"1000.00"`
+	RealBudget            float64                  `json:"real_budget" example:"950.00"`
+	Difference            float64                  `json:"difference" example:"50.00"`
+	Planned               float64                  `json:"planned" example:"1100.00"`
+	Actual                float64                  `json:"actual" example:"870.00"`
+	Remaining             float64                  `json:"remaining" example:"130.00"`
+	Allocated             float64                  `json:"allocated" example:"800.00"`
+	OverBudget            bool                     `json:"over_budget" example:"true"`
+	Tasks                 []TaskBudgetResponse     `json:"tasks"`
+	Categories            []CategoryBudgetResponse `json:"categories"`
+	UnconvertedCurrencies []string                 `json:"unconverted_currencies,omitempty" example:"USD"`
 }
 
 // EventLeaderboardEntry represents a single entry in the event leaderboard
@@ -53,24 +108,117 @@ type EventLeaderboardResponse struct {
 	Leaderboard []EventLeaderboardEntry `json:"leaderboard"`
 }
 
+// EventParticipantResponse represents a participant of an event with their role and RSVP
+type EventParticipantResponse struct {
+	UserID        uint       `json:"user_id" example:"2"`
+	DisplayName   string     `json:"display_name" example:"Jane Smith"`
+	Avatar        string     `json:"avatar,omitempty" example:"https://example.com/avatar.jpg"`
+	Role          string     `json:"role" example:"participant"`
+	RSVPStatus    string     `json:"rsvp_status" example:"going" enums:"going,maybe,not_going,no_response"`
+	PlusOnes      int        `json:"plus_ones" example:"1"`
+	RSVPNote      string     `json:"rsvp_note,omitempty" example:"Will be 15 minutes late"`
+	RSVPUpdatedAt *time.Time `json:"rsvp_updated_at,omitempty" example:"2024-03-20T10:00:00Z"`
+}
+
 // EventParticipantsResponse represents the response for event participants
 type EventParticipantsResponse struct {
-	Participants []string `json:"participants" example:"[\"John Doe\", \"Jane Smith\"]"`
+	Participants []EventParticipantResponse `json:"participants"`
+}
+
+// WaitlistEntryResponse represents a user waiting for a place in a full event
+type WaitlistEntryResponse struct {
+	UserID      uint      `json:"user_id" example:"4"`
+	DisplayName string    `json:"display_name" example:"Jane Smith"`
+	Position    int       `json:"position" example:"1"`
+	CreatedAt   time.Time `json:"created_at" example:"2024-03-16T12:00:00Z"`
+}
+
+// WaitlistResponse represents the waitlist of an event in order
+type WaitlistResponse struct {
+	Capacity *int                    `json:"capacity,omitempty" example:"30"`
+	Waitlist []WaitlistEntryResponse `json:"waitlist"`
+}
+
+// RSVPRequest represents the request to answer an event invitation
+type RSVPRequest struct {
+	Status   string `json:"status" binding:"required" example:"going" enums:"going,maybe,not_going"`
+	PlusOnes int    `json:"plus_ones" example:"1"`
+	Note     string `json:"note" example:"Will be 15 minutes late"`
+}
+
+// UpdateParticipantRoleRequest represents the request to promote or demote an event participant
+type UpdateParticipantRoleRequest struct {
+	Role string `json:"role" binding:"required" example:"co_organizer" enums:"co_organizer,participant,viewer"`
+}
+
+// ParticipantRoleResponse represents the role of a participant in an event
+type ParticipantRoleResponse struct {
+	EventID uint   `json:"event_id" example:"1"`
+	UserID  uint   `json:"user_id" example:"2"`
+	Role    string `json:"role" example:"co_organizer"`
+}
+
+// TransferOwnershipRequest represents the request to hand an event over to another participant
+type TransferOwnershipRequest struct {
+	NewOrganizerID uint `json:"new_organizer_id" binding:"required" example:"2"`
 }
 
 // TimeSlotSuggestion represents a suggested time slot for an event
 type TimeSlotSuggestion struct {
-	Slot      string `json:"slot" example:"2024-04-01 18:00"`
-	BusyCount int    `json:"busy_count" example:"2"`
+	Slot               string `json:"slot" example:"2024-04-01 18:00"`
+	BusyCount          int    `json:"busy_count" example:"2"`
+	BusyParticipantIDs []uint `json:"busy_participant_ids"`
+	// RequiredBusyCount counts busy participants who aren't optional
+	RequiredBusyCount int `json:"required_busy_count" example:"1"`
+	// Score is the summed weight of busy participants, lower is better
+	Score float64 `json:"score" example:"1.5"`
+	// OutsidePreferredCount counts participants whose preferred hours don't cover the slot
+	OutsidePreferredCount int `json:"outside_preferred_count" example:"0"`
+}
+
+// SchedulingParticipant changes how a participant counts when searching for a time
+type SchedulingParticipant struct {
+	UserID uint `json:"user_id" example:"2"`
+	// Optional participants can be busy in a suggested slot when nobody required is
+	Optional bool `json:"optional" example:"true"`
+	// Weight of the participant, 1 by default
+	Weight float64 `json:"weight,omitempty" example:"0.5"`
 }
 
 // FindBestTimeSlotsRequest represents the request to find the best time slots
 type FindBestTimeSlotsRequest struct {
-	EventID      uint   `json:"event_id" example:"1"`
+	EventID uint `json:"event_id" example:"1"`
+	// Date, StartTime and EndTime are read in the timezone of the requesting user, suggested slots are given in it too
 	Date         string `json:"date" example:"2024-04-01"`
 	DurationMins int64  `json:"duration_mins" example:"120"`
 	StartTime    string `json:"start_time" example:"08:00"`
 	EndTime      string `json:"end_time" example:"22:00"`
+	// Participants lists optional participants and weights, everybody else is required with weight 1
+	Participants []SchedulingParticipant `json:"participants,omitempty"`
+}
+
+// WorkingHours limits the search to a part of the day, times are in HH:MM format (24-hour)
+type WorkingHours struct {
+	StartTime string `json:"start_time" example:"09:00"`
+	EndTime   string `json:"end_time" example:"18:00"`
+}
+
+// FindBestTimeRequest represents the request to find the best time slots across a date range
+type FindBestTimeRequest struct {
+	EventID uint `json:"event_id" example:"1" binding:"required"`
+	// StartDate and EndDate are both included, dates and working hours are read in the timezone of the requesting user
+	StartDate    string `json:"start_date" example:"2024-04-01" binding:"required"`
+	EndDate      string `json:"end_date" example:"2024-04-07" binding:"required"`
+	DurationMins int64  `json:"duration_mins" example:"120"`
+	// GranularityMins is the step between start times, 15, 30 or 60
+	GranularityMins int64 `json:"granularity_mins,omitempty" example:"30"`
+	// WorkingHours by lowercase weekday name, only listed weekdays are searched.
+	// Without any entry every day is searched from 08:00 to 22:00
+	WorkingHours map[string]WorkingHours `json:"working_hours,omitempty"`
+	// Limit of suggestions, 10 by default
+	Limit int `json:"limit,omitempty" example:"10"`
+	// Participants lists optional participants and weights, everybody else is required with weight 1
+	Participants []SchedulingParticipant `json:"participants,omitempty"`
 }
 
 // FindBestTimeSlotsResponse represents the response with suggested time slots
@@ -0,0 +1,142 @@
+package api
+
+import "time"
+
+// ExpenseRequest represents the request to record or replace an expense
+type ExpenseRequest struct {
+	Amount      float64 `json:"amount" example:"42.50" binding:"required"`
+	Description string  `json:"description" example:"Balloons and garlands"`
+	// Currency is an ISO 4217 code, the event currency by default
+	Currency string `json:"currency,omitempty" example:"EUR" binding:"omitempty,len=3"`
+	// PayerID is the participant who paid, the requesting user by default
+	PayerID *uint `json:"payer_id,omitempty" example:"2"`
+	TaskID  *uint `json:"task_id,omitempty" example:"1"`
+	// Category is taken from the task when left out
+	Category string `json:"category,omitempty" example:"decor" binding:"omitempty,max=50"`
+	// SpentAt is the time of the purchase, now by default
+	SpentAt *time.Time `json:"spent_at,omitempty" example:"2024-03-30T15:00:00Z"`
+	// SplitMode is equal, shares or exact, equal by default
+	SplitMode string `json:"split_mode,omitempty" example:"equal" binding:"omitempty,oneof=equal shares exact"`
+	// Split lists who shares the expense, everybody in the event except viewers when left out in equal mode
+	Split []ExpenseSplitRequest `json:"split,omitempty" binding:"dive"`
+}
+
+// ExpenseSplitRequest is the part of a participant in an expense
+type ExpenseSplitRequest struct {
+	UserID uint `json:"user_id" example:"2" binding:"required"`
+	// Shares weighs the participant in shares mode
+	Shares int64 `json:"shares,omitempty" example:"2"`
+	// Amount is what the participant owes in exact mode
+	Amount float64 `json:"amount,omitempty" example:"21.25"`
+}
+
+// ExpenseShareResponse is what a participant owes for an expense, in the currency of the expense
+type ExpenseShareResponse struct {
+	UserID      uint    `json:"user_id" example:"2"`
+	DisplayName string  `json:"display_name" example:"John Doe"`
+	Shares      int64   `json:"shares,omitempty" example:"2"`
+	Amount      float64 `json:"amount" example:"21.25"`
+}
+
+// ExpenseResponse represents an expense in API responses
+type ExpenseResponse struct {
+	ID          uint      `json:"id" example:"1"`
+	EventID     uint      `json:"event_id" example:"1"`
+	PayerID     uint      `json:"payer_id" example:"2"`
+	PayerName   string    `json:"payer_name" example:"John Doe"`
+	CreatedByID uint      `json:"created_by_id" example:"2"`
+	TaskID      *uint     `json:"task_id,omitempty" example:"1"`
+	Amount      float64   `json:"amount" example:"42.50"`
+	Currency    string    `json:"currency" example:"RUB"`
+	Description string    `json:"description" example:"Balloons and garlands"`
+	Category    string    `json:"category,omitempty" example:"decor"`
+	SpentAt     time.Time `json:"spent_at" example:"2024-03-30T15:00:00Z"`
+	SplitMode   string    `json:"split_mode" example:"equal"`
+
+	Split []ExpenseShareResponse `json:"split"`
+}
+
+// ExpensesResponse represents the expenses of an event, Total is converted into Currency of the event.
+// Expenses in UnconvertedCurrencies have no exchange rate at the moment and are left out of Total
+type ExpensesResponse struct {
+	Expenses              []ExpenseResponse `json:"expenses"`
+	Total                 float64           `json:"total" example:"250.00"`
+	Currency              string            `json:"currency" example:"RUB"`
+	UnconvertedCurrencies []string          `json:"unconverted_currencies,omitempty" example:"USD"`
+}
+
+// TaskBudgetResponse compares the planned budget of a task with the expenses linked to it
+type TaskBudgetResponse struct {
+	TaskID      uint    `json:"task_id" example:"1"`
+	Title       string  `json:"title" example:"Buy decorations"`
+	Category    string  `json:"category,omitempty" example:"decor"`
+	IsCompleted bool    `json:"is_completed" example:"true"`
+	Planned     float64 `json:"planned" example:"50.00"`
+	Actual      float64 `json:"actual" example:"42.50"`
+}
+
+// CategoryBudgetResponse compares planned and actual spend of a category, tasks and expenses without one are uncategorized.
+// Limit and Remaining are only set for budget categories, OverBudget when the planned or spent amount exceeds the limit
+type CategoryBudgetResponse struct {
+	Category   string   `json:"category" example:"decor"`
+	Planned    float64  `json:"planned" example:"150.00"`
+	Actual     float64  `json:"actual" example:"120.00"`
+	Limit      *float64 `json:"limit,omitempty" example:"200.00"`
+	Remaining  *float64 `json:"remaining,omitempty" example:"80.00"`
+	OverBudget bool     `json:"over_budget" example:"false"`
+}
+
+// SettlementRequest records that one participant paid another to settle up
+type SettlementRequest struct {
+	FromUserID uint    `json:"from_user_id" example:"3" binding:"required"`
+	ToUserID   uint    `json:"to_user_id" example:"2" binding:"required"`
+	Amount     float64 `json:"amount" example:"21.25" binding:"required"`
+	// Currency is an ISO 4217 code, the event currency by default
+	Currency string `json:"currency,omitempty" example:"EUR" binding:"omitempty,len=3"`
+	// PaidAt is the time of the payment, now by default
+	PaidAt *time.Time `json:"paid_at,omitempty" example:"2024-04-02T10:00:00Z"`
+}
+
+// SettlementResponse represents a recorded payment between participants
+type SettlementResponse struct {
+	ID          uint      `json:"id" example:"1"`
+	FromUserID  uint      `json:"from_user_id" example:"3"`
+	FromName    string    `json:"from_name" example:"Jane Doe"`
+	ToUserID    uint      `json:"to_user_id" example:"2"`
+	ToName      string    `json:"to_name" example:"John Doe"`
+	Amount      float64   `json:"amount" example:"21.25"`
+	Currency    string    `json:"currency" example:"RUB"`
+	CreatedByID uint      `json:"created_by_id" example:"3"`
+	PaidAt      time.Time `json:"paid_at" example:"2024-04-02T10:00:00Z"`
+}
+
+// MemberBalanceResponse sums up the expenses of a participant, a positive net means the participant gets money back
+type MemberBalanceResponse struct {
+	UserID      uint    `json:"user_id" example:"2"`
+	DisplayName string  `json:"display_name" example:"John Doe"`
+	Paid        float64 `json:"paid" example:"42.50"`
+	Owed        float64 `json:"owed" example:"21.25"`
+	Sent        float64 `json:"sent" example:"0"`
+	Received    float64 `json:"received" example:"0"`
+	Net         float64 `json:"net" example:"21.25"`
+}
+
+// TransferResponse is a payment still needed to settle up
+type TransferResponse struct {
+	FromUserID uint    `json:"from_user_id" example:"3"`
+	FromName   string  `json:"from_name" example:"Jane Doe"`
+	ToUserID   uint    `json:"to_user_id" example:"2"`
+	ToName     string  `json:"to_name" example:"John Doe"`
+	Amount     float64 `json:"amount" example:"21.25"`
+}
+
+// BalancesResponse represents who owes what in an event and the fewest payments that settle it.
+// Balances and transfers are in Currency of the event, expenses and payments in other currencies are converted.
+// Those in UnconvertedCurrencies have no exchange rate at the moment and are left out of the balances
+type BalancesResponse struct {
+	Currency              string                  `json:"currency" example:"RUB"`
+	Balances              []MemberBalanceResponse `json:"balances"`
+	Transfers             []TransferResponse      `json:"transfers"`
+	Settlements           []SettlementResponse    `json:"settlements"`
+	UnconvertedCurrencies []string                `json:"unconverted_currencies,omitempty" example:"USD"`
+}
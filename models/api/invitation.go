@@ -1,16 +1,91 @@
 package api
 
+import "time"
+
 // GenerateInviteLinkRequest represents the request to generate an invite link for an event
 type GenerateInviteLinkRequest struct {
-	EventID uint `json:"event_id" example:"1"`
+	EventID   uint       `json:"event_id" example:"1"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2024-04-01T18:00:00Z"`
+	MaxUses   *int       `json:"max_uses,omitempty" example:"10"`
+	// Joining with the link only sends a join request that organizers approve or reject
+	RequiresApproval bool `json:"requires_approval,omitempty" example:"false"`
 }
 
 // GenerateInviteLinkResponse represents the response containing the generated invite link
 type GenerateInviteLinkResponse struct {
-	InviteLink string `json:"invite_link" example:"http://localhost:8080/events/join/abc123"`
+	InviteLink string     `json:"invite_link" example:"http://localhost:8080/events/redirect/abc123"`
+	InviteCode string     `json:"invite_code" example:"abc123"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" example:"2024-04-01T18:00:00Z"`
+	MaxUses    *int       `json:"max_uses,omitempty" example:"10"`
+	// RequiresApproval mirrors the request flag
+	RequiresApproval bool `json:"requires_approval" example:"false"`
+}
+
+// InvitationResponse represents an invite link of an event in API responses
+type InvitationResponse struct {
+	// InviteCode is only shown to those who can invite, the invitee finds the link in the email
+	InviteCode  string     `json:"invite_code,omitempty" example:"abc123"`
+	InviteLink  string     `json:"invite_link" example:"http://localhost:8080/events/redirect/abc123"`
+	EventID     uint       `json:"event_id" example:"1"`
+	CreatedByID uint       `json:"created_by_id" example:"1"`
+	CreatedAt   time.Time  `json:"created_at" example:"2024-03-16T12:00:00Z"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" example:"2024-04-01T18:00:00Z"`
+	MaxUses     *int       `json:"max_uses,omitempty" example:"10"`
+	Uses        int        `json:"uses" example:"3"`
+	// RequiresApproval is true for links that create join requests
+	RequiresApproval bool `json:"requires_approval" example:"false"`
+}
+
+// InvitationsResponse represents the list of active invite links of an event
+type InvitationsResponse struct {
+	Invitations []InvitationResponse `json:"invitations"`
 }
 
-// JoinEventResponse represents the response when a user successfully joins an event
+// JoinEventResponse represents the response when a user successfully joins an event or requests to join it
 type JoinEventResponse struct {
 	Message string `json:"message" example:"Successfully joined event"`
+	Status  string `json:"status,omitempty" example:"joined" enums:"joined,pending,waitlisted"`
+	// WaitlistPosition is set when the event is full
+	WaitlistPosition int `json:"waitlist_position,omitempty" example:"2"`
+}
+
+// JoinRequestResponse represents a request to join an event in API responses
+type JoinRequestResponse struct {
+	ID          uint       `json:"id" example:"1"`
+	EventID     uint       `json:"event_id" example:"1"`
+	UserID      uint       `json:"user_id" example:"3"`
+	DisplayName string     `json:"display_name" example:"Jane Smith"`
+	Status      string     `json:"status" example:"pending"`
+	CreatedAt   time.Time  `json:"created_at" example:"2024-03-16T12:00:00Z"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty" example:"2024-03-16T13:00:00Z"`
+}
+
+// JoinRequestsResponse represents the list of pending join requests of an event
+type JoinRequestsResponse struct {
+	JoinRequests []JoinRequestResponse `json:"join_requests"`
+}
+
+// EmailInvitationRequest represents the request body for inviting a person to an event by email
+type EmailInvitationRequest struct {
+	Email string `json:"email" binding:"required,email" example:"friend@example.com"`
+}
+
+// EmailInvitationResponse represents a personal email invitation in API responses
+type EmailInvitationResponse struct {
+	ID        uint   `json:"id" example:"1"`
+	EventID   uint   `json:"event_id" example:"1"`
+	EventName string `json:"event_name" example:"Birthday Party"`
+	Email     string `json:"email" example:"friend@example.com"`
+	// InviteCode is only shown to those who can invite, the invitee finds the link in the email
+	InviteCode  string     `json:"invite_code,omitempty" example:"abc123"`
+	InvitedByID uint       `json:"invited_by_id" example:"1"`
+	UserID      *uint      `json:"user_id,omitempty" example:"3"`
+	Status      string     `json:"status" example:"sent" enums:"sent,accepted,declined,failed"`
+	CreatedAt   time.Time  `json:"created_at" example:"2024-03-16T12:00:00Z"`
+	RespondedAt *time.Time `json:"responded_at,omitempty" example:"2024-03-17T09:00:00Z"`
+}
+
+// EmailInvitationsResponse represents a list of email invitations
+type EmailInvitationsResponse struct {
+	Invitations []EmailInvitationResponse `json:"invitations"`
 }
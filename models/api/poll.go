@@ -0,0 +1,54 @@
+package api
+
+import "time"
+
+// CreatePollRequest represents the request to put a question to the participants of an event
+type CreatePollRequest struct {
+	Question       string   `json:"question" example:"Where do we meet?" binding:"required"`
+	Options        []string `json:"options" example:"Central Park,Riverside" binding:"required"`
+	MultipleChoice bool     `json:"multiple_choice" example:"false"`
+	// Anonymous polls never show who voted for what
+	Anonymous bool `json:"anonymous" example:"false"`
+	// ResultsVisibility is always by default, organizers and co-organizers see results in any case
+	ResultsVisibility string `json:"results_visibility,omitempty" example:"after_vote" enums:"always,after_vote,after_close"`
+	// Deadline closes the poll automatically
+	Deadline *time.Time `json:"deadline,omitempty" example:"2024-04-01T12:00:00Z"`
+}
+
+// PollVoteRequest represents the choice of the user, it replaces the previous one and an empty list withdraws the vote
+type PollVoteRequest struct {
+	OptionIDs []uint `json:"option_ids" example:"1"`
+}
+
+// PollOptionResponse represents an option of a poll, votes are left out while the results are hidden from the user
+type PollOptionResponse struct {
+	ID    uint   `json:"id" example:"1"`
+	Text  string `json:"text" example:"Central Park"`
+	Votes *int   `json:"votes,omitempty" example:"3"`
+	// VoterIDs are shown for polls that aren't anonymous
+	VoterIDs []uint `json:"voter_ids,omitempty"`
+	// Selected tells whether the requesting user voted for the option
+	Selected bool `json:"selected" example:"true"`
+}
+
+// PollResponse represents an event poll in API responses
+type PollResponse struct {
+	ID                uint                 `json:"id" example:"1"`
+	EventID           uint                 `json:"event_id" example:"1"`
+	CreatedByID       uint                 `json:"created_by_id" example:"1"`
+	CreatedAt         time.Time            `json:"created_at" example:"2024-03-16T12:00:00Z"`
+	Question          string               `json:"question" example:"Where do we meet?"`
+	MultipleChoice    bool                 `json:"multiple_choice" example:"false"`
+	Anonymous         bool                 `json:"anonymous" example:"false"`
+	ResultsVisibility string               `json:"results_visibility" example:"always"`
+	Deadline          *time.Time           `json:"deadline,omitempty" example:"2024-04-01T12:00:00Z"`
+	Status            string               `json:"status" example:"poll_open"`
+	ClosedAt          *time.Time           `json:"closed_at,omitempty" example:"2024-04-01T12:00:00Z"`
+	ResultsVisible    bool                 `json:"results_visible" example:"true"`
+	Options           []PollOptionResponse `json:"options"`
+}
+
+// PollsResponse represents the polls of an event
+type PollsResponse struct {
+	Polls []PollResponse `json:"polls"`
+}
@@ -1,32 +1,51 @@
 package api
 
+import "time"
+
 // TaskResponse represents a task in the response
 type TaskResponse struct {
 	ID             uint    `json:"id" example:"1"`
 	Title          string  `json:"title" example:"Buy decorations"`
 	Description    string  `json:"description" example:"Purchase party decorations from the store"`
 	Budget         float64 `json:"budget" example:"50.00"`
+	Currency       string  `json:"currency" example:"RUB"`
 	Points         int     `json:"points" example:"10"`
 	EventID        uint    `json:"event_id" example:"1"`
 	AssignedTo     *uint   `json:"assigned_to,omitempty" example:"2"`
 	AssignedToName string  `json:"assigned_to_name,omitempty" example:"John Doe"`
 	IsCompleted    bool    `json:"is_completed" example:"false"`
+	Category       string  `json:"category,omitempty" example:"decor"`
+	// DueAt is when the task has to be done, IsOverdue is set for open tasks past it
+	DueAt     *time.Time `json:"due_at,omitempty" example:"2024-03-30T18:00:00Z"`
+	Priority  string     `json:"priority" example:"normal" enums:"urgent,high,normal,low"`
+	IsOverdue bool       `json:"is_overdue" example:"false"`
 }
 
 // CreateTaskRequest represents the request to create a new task
 type CreateTaskRequest struct {
-	Title       string  `json:"title" example:"Buy decorations" binding:"required"`
-	Description string  `json:"description" example:"Purchase party decorations from the store"`
-	Budget      float64 `json:"budget" example:"50.00"`
-	Points      int     `json:"points" example:"10" binding:"required"`
-	EventID     uint    `json:"event_id" example:"1" binding:"required"`
-	AssignedTo  *uint   `json:"assigned_to,omitempty" example:"2"`
+	Title       string     `json:"title" example:"Buy decorations" binding:"required"`
+	Description string     `json:"description" example:"Purchase party decorations from the store"`
+	Budget      float64    `json:"budget" example:"50.00"`
+	Points      int        `json:"points" example:"10" binding:"required"`
+	EventID     uint       `json:"event_id" example:"1" binding:"required"`
+	AssignedTo  *uint      `json:"assigned_to,omitempty" example:"2"`
+	Category    string     `json:"category,omitempty" example:"decor" binding:"omitempty,max=50"`
+	DueAt       *time.Time `json:"due_at,omitempty" example:"2024-03-30T18:00:00Z"`
+	Priority    string     `json:"priority,omitempty" example:"high" enums:"urgent,high,normal,low"`
+	// AllowDueAfterEvent accepts a due date after the event, for tasks like returning rented equipment
+	AllowDueAfterEvent bool `json:"allow_due_after_event,omitempty" example:"false"`
 }
 
 // UpdateTaskRequest represents the request to update an existing task
 type UpdateTaskRequest struct {
-	Title       *string  `json:"title,omitempty" example:"Buy party decorations"`
-	Description *string  `json:"description,omitempty" example:"Purchase decorations from the party store"`
-	Budget      *float64 `json:"budget,omitempty" example:"60.00"`
-	Points      *int     `json:"points,omitempty" example:"15"`
+	Title       *string    `json:"title,omitempty" example:"Buy party decorations"`
+	Description *string    `json:"description,omitempty" example:"Purchase decorations from the party store"`
+	Budget      *float64   `json:"budget,omitempty" example:"60.00"`
+	Points      *int       `json:"points,omitempty" example:"15"`
+	Category    *string    `json:"category,omitempty" example:"decor" binding:"omitempty,max=50"`
+	DueAt       *time.Time `json:"due_at,omitempty" example:"2024-03-30T18:00:00Z"`
+	// ClearDueAt removes the due date of the task
+	ClearDueAt         bool    `json:"clear_due_at,omitempty" example:"false"`
+	Priority           *string `json:"priority,omitempty" example:"high" enums:"urgent,high,normal,low"`
+	AllowDueAfterEvent bool    `json:"allow_due_after_event,omitempty" example:"false"`
 }
@@ -6,6 +6,7 @@ import "time"
 type TaskStatusEventResponse struct {
 	ID            uint      `json:"id" example:"1"`
 	TaskID        uint      `json:"task_id" example:"5"`
+	EventID       uint      `json:"event_id,omitempty" example:"1"`
 	TaskName      string    `json:"task_name" example:"Buy decorations"`
 	OldStatus     string    `json:"old_status,omitempty" example:"unassigned"`
 	NewStatus     string    `json:"new_status" example:"assigned"`
@@ -0,0 +1,52 @@
+package api
+
+import "time"
+
+// CloneEventRequest represents the request to copy an event with its tasks
+type CloneEventRequest struct {
+	// Name of the copy, the name of the original by default
+	Name *string `json:"name,omitempty" example:"Birthday party 2025"`
+	// ShiftDays moves the copy relative to the original date
+	ShiftDays int `json:"shift_days,omitempty" example:"365"`
+}
+
+// CreateEventTemplateRequest represents the request to save an event as a template
+type CreateEventTemplateRequest struct {
+	EventID uint `json:"event_id" example:"1" binding:"required"`
+	// Name of the template, the name of the event by default
+	Name string `json:"name,omitempty" example:"Birthday party"`
+}
+
+// InstantiateEventTemplateRequest represents the request to create an event from a template
+type InstantiateEventTemplateRequest struct {
+	EventDateTime string `json:"event_date_time" example:"2024-04-01T18:00:00Z" binding:"required"`
+	// Name of the event, the name of the template by default
+	Name *string `json:"name,omitempty" example:"Anna's birthday"`
+}
+
+// EventTemplateTaskResponse represents a task of a template
+type EventTemplateTaskResponse struct {
+	ID          uint    `json:"id" example:"1"`
+	Title       string  `json:"title" example:"Buy decorations"`
+	Description string  `json:"description" example:"Purchase party decorations from the store"`
+	Budget      float64 `json:"budget" example:"50.00"`
+	Points      int     `json:"points" example:"10"`
+}
+
+// EventTemplateResponse represents an event template in API responses
+type EventTemplateResponse struct {
+	ID            uint                        `json:"id" example:"1"`
+	CreatedAt     time.Time                   `json:"created_at" example:"2024-03-16T12:00:00Z"`
+	Name          string                      `json:"name" example:"Birthday party"`
+	Description   string                      `json:"description" example:"Celebrating a birthday"`
+	Place         string                      `json:"place" example:"Central Park"`
+	InitialBudget float64                     `json:"initial_budget" example:"1000.00"`
+	Currency      string                      `json:"currency" example:"RUB"`
+	Capacity      *int                        `json:"capacity,omitempty" example:"30"`
+	Tasks         []EventTemplateTaskResponse `json:"tasks"`
+}
+
+// EventTemplatesResponse represents the templates of the user
+type EventTemplatesResponse struct {
+	Templates []EventTemplateResponse `json:"templates"`
+}
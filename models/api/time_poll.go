@@ -0,0 +1,62 @@
+package api
+
+import "time"
+
+// TimePollSeed fills a new poll with the best slots of a day, fields mean the same as in FindBestTimeSlotsRequest
+type TimePollSeed struct {
+	Date         string                  `json:"date" example:"2024-04-01"`
+	DurationMins int64                   `json:"duration_mins" example:"120"`
+	StartTime    string                  `json:"start_time,omitempty" example:"08:00"`
+	EndTime      string                  `json:"end_time,omitempty" example:"22:00"`
+	Participants []SchedulingParticipant `json:"participants,omitempty"`
+}
+
+// CreateTimePollRequest represents the request to put candidate times of an event to a vote.
+// Options are RFC3339 times, the poll takes them from suggestions for a day when seed is given instead
+type CreateTimePollRequest struct {
+	Options []string      `json:"options,omitempty" example:"2024-04-01T18:00:00Z,2024-04-02T18:00:00Z"`
+	Seed    *TimePollSeed `json:"seed,omitempty"`
+}
+
+// TimePollVoteRequest represents answers of the user to candidate times, options left out keep their answer
+type TimePollVoteRequest struct {
+	Votes []TimePollAnswer `json:"votes" binding:"required"`
+}
+
+type TimePollAnswer struct {
+	OptionID uint   `json:"option_id" example:"1"`
+	Answer   string `json:"answer" example:"yes" enums:"yes,maybe,no"`
+}
+
+// CloseTimePollRequest represents the request to close a poll, the event moves to the chosen option
+type CloseTimePollRequest struct {
+	OptionID uint `json:"option_id" example:"1" binding:"required"`
+}
+
+// TimePollOptionResponse represents a candidate time with its tally
+type TimePollOptionResponse struct {
+	ID        uint      `json:"id" example:"1"`
+	StartTime time.Time `json:"start_time" example:"2024-04-01T18:00:00Z"`
+	Yes       int       `json:"yes" example:"3"`
+	Maybe     int       `json:"maybe" example:"1"`
+	No        int       `json:"no" example:"0"`
+	// MyAnswer is the answer of the requesting user, empty when they haven't voted
+	MyAnswer string `json:"my_answer,omitempty" example:"yes"`
+}
+
+// TimePollResponse represents a time poll in API responses
+type TimePollResponse struct {
+	ID             uint                     `json:"id" example:"1"`
+	EventID        uint                     `json:"event_id" example:"1"`
+	CreatedByID    uint                     `json:"created_by_id" example:"1"`
+	CreatedAt      time.Time                `json:"created_at" example:"2024-03-16T12:00:00Z"`
+	Status         string                   `json:"status" example:"poll_open"`
+	ChosenOptionID *uint                    `json:"chosen_option_id,omitempty" example:"1"`
+	ClosedAt       *time.Time               `json:"closed_at,omitempty" example:"2024-03-20T12:00:00Z"`
+	Options        []TimePollOptionResponse `json:"options"`
+}
+
+// TimePollsResponse represents the time polls of an event
+type TimePollsResponse struct {
+	Polls []TimePollResponse `json:"polls"`
+}
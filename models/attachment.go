@@ -0,0 +1,22 @@
+package models
+
+import "gorm.io/gorm"
+
+// Attachment is a receipt or document uploaded for a task or an expense of an event.
+// The file itself lives in the storage backend under StorageKey.
+type Attachment struct {
+	gorm.Model
+	EventID      uint   `gorm:"not null;index"`
+	TaskID       *uint  `gorm:"index"`
+	ExpenseID    *uint  `gorm:"index"`
+	UploadedByID uint   `gorm:"not null"`
+	FileName     string `gorm:"not null"`
+	ContentType  string `gorm:"type:varchar(100);not null"`
+	Size         int64  `gorm:"not null"`
+	StorageKey   string `gorm:"not null"`
+	ThumbnailKey string `gorm:"not null;default:''"` // empty when no thumbnail could be made
+}
+
+func MigrateAttachment(db *gorm.DB) error {
+	return db.AutoMigrate(&Attachment{})
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Kinds of availability entries
+const (
+	// AvailabilityUnavailable is a weekly window when the user can't attend
+	AvailabilityUnavailable = "unavailable"
+	// AvailabilityPreferred is a weekly window the user would like events to happen in
+	AvailabilityPreferred = "preferred"
+	// AvailabilityBlock is a one-off period when the user can't attend
+	AvailabilityBlock = "block"
+)
+
+// Availability is a declared busy or preferred time of a user, used next to imported calendar events
+// when searching for an event time. Weekly windows are wall clock times in the user's timezone.
+type Availability struct {
+	gorm.Model
+	UserID uint   `gorm:"not null;index"`
+	Kind   string `gorm:"type:varchar(20);not null"`
+	// Weekday, StartTime and EndTime describe weekly windows, the weekday counts from Sunday as 0
+	Weekday   *int
+	StartTime string `gorm:"type:varchar(5)"`
+	EndTime   string `gorm:"type:varchar(5)"`
+	// StartsAt and EndsAt bound one-off blocks
+	StartsAt *time.Time
+	EndsAt   *time.Time
+	Note     string
+}
+
+func IsValidAvailabilityKind(kind string) bool {
+	return kind == AvailabilityUnavailable || kind == AvailabilityPreferred || kind == AvailabilityBlock
+}
+
+func MigrateAvailability(db *gorm.DB) error {
+	return db.AutoMigrate(&Availability{})
+}
@@ -0,0 +1,16 @@
+package models
+
+import "gorm.io/gorm"
+
+// BudgetCategory sets aside part of the initial budget of an event for tasks and expenses tagged with its name,
+// Limit is in minor units of the event currency
+type BudgetCategory struct {
+	ID      uint   `gorm:"primaryKey"`
+	EventID uint   `gorm:"not null;uniqueIndex:idx_budget_category_event_name"`
+	Name    string `gorm:"type:varchar(50);not null;uniqueIndex:idx_budget_category_event_name"`
+	Limit   int64  `gorm:"column:limit_minor;not null;default:0"`
+}
+
+func MigrateBudgetCategory(db *gorm.DB) error {
+	return db.AutoMigrate(&BudgetCategory{})
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	EmailInvitationSent     = "sent"
+	EmailInvitationAccepted = "accepted"
+	EmailInvitationDeclined = "declined"
+	// EmailInvitationFailed invitations couldn't be delivered, their links are revoked
+	EmailInvitationFailed = "failed"
+)
+
+// EmailInvitation is a personal invitation sent to an email address. It owns a single use
+// EventInvitation so the emailed link goes through the usual redirect and join flow.
+type EmailInvitation struct {
+	gorm.Model
+	EventID      uint   `gorm:"not null;index"`
+	InvitationID uint   `gorm:"not null;uniqueIndex"`
+	Email        string `gorm:"not null;index"`
+	InvitedByID  uint   `gorm:"not null"`
+	// UserID is set once the email belongs to an account, either right away or after registration
+	UserID      *uint  `gorm:"index"`
+	Status      string `gorm:"type:varchar(20);not null;default:sent"`
+	RespondedAt *time.Time
+}
+
+// NormalizeEmail is used for storing and looking up invited addresses
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// IsFor reports whether the invitation was sent to the given user
+func (i *EmailInvitation) IsFor(user *User) bool {
+	if i.UserID != nil {
+		return *i.UserID == user.ID
+	}
+	return i.Email == NormalizeEmail(user.Email)
+}
+
+func MigrateEmailInvitation(db *gorm.DB) error {
+	return db.AutoMigrate(&EmailInvitation{})
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"itsplanned/recurrence"
 	"time"
 
 	"gorm.io/gorm"
@@ -11,11 +12,46 @@ type Event struct {
 	Name          string    `gorm:"not null"`
 	Description   string    `gorm:"type:text"`
 	EventDateTime time.Time `gorm:"not null"`
-	InitialBudget float64
+	// InitialBudget is in minor units of Currency
+	InitialBudget int64  `gorm:"column:initial_budget_minor;not null;default:0"`
+	Currency      string `gorm:"type:varchar(3);not null;default:RUB"`
 	OrganizerID   uint
 	Place         string       `gorm:"type:text"`
 	Tasks         []Task       `gorm:"foreignKey:EventID"`
 	EventScores   []EventScore `gorm:"foreignKey:EventID"`
+	// Capacity limits the number of participants who haven't declined, nil means unlimited
+	Capacity *int
+	// RecurrenceRule is an RFC 5545 RRULE value, EventDateTime is the start of the first occurrence
+	RecurrenceRule string `gorm:"type:text"`
+	// Timezone is the IANA zone the event takes place in, EventDateTime itself is stored in UTC
+	Timezone string `gorm:"type:varchar(64);not null;default:UTC"`
+	// RecurrenceParentID points to the series this one was split from by a "this and following" edit
+	RecurrenceParentID *uint
+	// SeriesEndsAt is the start of the last occurrence of a finite recurring event, nil for endless series.
+	// It is derived from the rule on every save so finished series can be left out of queries
+	SeriesEndsAt *time.Time `gorm:"index"`
+}
+
+// BeforeSave keeps SeriesEndsAt in line with the recurrence rule
+func (e *Event) BeforeSave(tx *gorm.DB) error {
+	e.SeriesEndsAt = e.seriesEnd()
+	return nil
+}
+
+func (e *Event) seriesEnd() *time.Time {
+	if e.RecurrenceRule == "" {
+		return nil
+	}
+	rule, err := recurrence.Parse(e.RecurrenceRule)
+	if err != nil {
+		return nil
+	}
+	last, ok := rule.Last(e.EventDateTime.In(e.Location()))
+	if !ok {
+		return nil
+	}
+	last = last.UTC()
+	return &last
 }
 
 type EventScore struct {
@@ -34,5 +70,22 @@ type CalendarEvent struct {
 }
 
 func MigrateEvent(db *gorm.DB) error {
-	return db.AutoMigrate(&Event{}, &EventScore{}, &CalendarEvent{})
+	backfillSeriesEnds := db.Migrator().HasTable(&Event{}) && !db.Migrator().HasColumn(&Event{}, "series_ends_at")
+	if err := db.AutoMigrate(&Event{}, &EventScore{}, &CalendarEvent{}); err != nil {
+		return err
+	}
+	if backfillSeriesEnds {
+		var events []Event
+		if err := db.Where("recurrence_rule <> ''").Find(&events).Error; err != nil {
+			return err
+		}
+		for i := range events {
+			if end := events[i].seriesEnd(); end != nil {
+				if err := db.Model(&events[i]).UpdateColumn("series_ends_at", end).Error; err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return migrateToMinorUnits(db, &Event{}, "events", "initial_budget", "initial_budget_minor")
 }
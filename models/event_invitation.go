@@ -3,14 +3,44 @@ package models
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+var (
+	ErrInvitationExpired   = errors.New("invite link has expired")
+	ErrInvitationExhausted = errors.New("invite link has reached its usage limit")
+	ErrInvitationRevoked   = errors.New("invite link has been revoked")
+)
+
 type EventInvitation struct {
-	ID         uint   `gorm:"primaryKey"`
-	EventID    uint   `gorm:"not null"`
-	InviteCode string `gorm:"unique;not null"`
+	ID          uint   `gorm:"primaryKey"`
+	EventID     uint   `gorm:"not null"`
+	InviteCode  string `gorm:"unique;not null"`
+	CreatedByID uint
+	ExpiresAt   *time.Time
+	MaxUses     *int
+	Uses        int `gorm:"not null;default:0"`
+	// Joining with the link creates a JoinRequest the organizers have to approve
+	RequiresApproval bool `gorm:"not null;default:false"`
+	RevokedAt        *time.Time
+	CreatedAt        time.Time
+}
+
+// CheckUsable tells why the invitation can not be used anymore, nil if it still can
+func (i *EventInvitation) CheckUsable() error {
+	if i.RevokedAt != nil {
+		return ErrInvitationRevoked
+	}
+	if i.ExpiresAt != nil && !i.ExpiresAt.After(time.Now()) {
+		return ErrInvitationExpired
+	}
+	if i.MaxUses != nil && i.Uses >= *i.MaxUses {
+		return ErrInvitationExhausted
+	}
+	return nil
 }
 
 func GenerateUniqueInviteCode(db *gorm.DB) string {
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Edit scopes of UpdateEvent and DeleteEvent for recurring events
+const (
+	EditScopeThis      = "this"
+	EditScopeFollowing = "following"
+	EditScopeAll       = "all"
+)
+
+// EventOccurrenceOverride changes or cancels a single occurrence of a recurring event.
+// OccurrenceTime is the original start of the occurrence as produced by the rule.
+type EventOccurrenceOverride struct {
+	gorm.Model
+	EventID        uint      `gorm:"not null;uniqueIndex:idx_occurrence_event_time"`
+	OccurrenceTime time.Time `gorm:"not null;uniqueIndex:idx_occurrence_event_time"`
+	Cancelled      bool      `gorm:"not null;default:false"`
+	Name           *string
+	Description    *string
+	EventDateTime  *time.Time
+	Place          *string
+}
+
+func MigrateEventOccurrenceOverride(db *gorm.DB) error {
+	return db.AutoMigrate(&EventOccurrenceOverride{})
+}
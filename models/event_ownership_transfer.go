@@ -0,0 +1,15 @@
+package models
+
+import "gorm.io/gorm"
+
+// EventOwnershipTransfer is the history of organizer changes of an event
+type EventOwnershipTransfer struct {
+	gorm.Model
+	EventID    uint `gorm:"not null;index"`
+	FromUserID uint `gorm:"not null"`
+	ToUserID   uint `gorm:"not null"`
+}
+
+func MigrateEventOwnershipTransfer(db *gorm.DB) error {
+	return db.AutoMigrate(&EventOwnershipTransfer{})
+}
@@ -1,13 +1,76 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Event-scoped roles of a participant
+const (
+	RoleOrganizer   = "organizer"
+	RoleCoOrganizer = "co_organizer"
+	RoleParticipant = "participant"
+	RoleViewer      = "viewer"
+)
+
+// RSVP answers of a participant
+const (
+	RSVPGoing      = "going"
+	RSVPMaybe      = "maybe"
+	RSVPNotGoing   = "not_going"
+	RSVPNoResponse = "no_response"
+)
+
+// MaxPlusOnes limits how many guests a participant can bring
+const MaxPlusOnes = 10
 
 type EventParticipation struct {
-	ID      uint `gorm:"primaryKey"`
-	EventID uint `gorm:"not null;index"`
-	UserID  uint `gorm:"not null;index"`
+	ID      uint   `gorm:"primaryKey"`
+	EventID uint   `gorm:"not null;index;uniqueIndex:idx_participation_event_user"`
+	UserID  uint   `gorm:"not null;index;uniqueIndex:idx_participation_event_user"`
+	Role    string `gorm:"type:varchar(20);not null;default:participant"`
+
+	RSVPStatus    string     `gorm:"column:rsvp_status;type:varchar(20);not null;default:no_response"`
+	PlusOnes      int        `gorm:"not null;default:0"`
+	RSVPNote      string     `gorm:"column:rsvp_note"`
+	RSVPUpdatedAt *time.Time `gorm:"column:rsvp_updated_at"`
+}
+
+func IsValidEventRole(role string) bool {
+	switch role {
+	case RoleOrganizer, RoleCoOrganizer, RoleParticipant, RoleViewer:
+		return true
+	}
+	return false
+}
+
+// IsValidRSVPStatus accepts the answers a participant can give, no_response is only the initial state
+func IsValidRSVPStatus(status string) bool {
+	switch status {
+	case RSVPGoing, RSVPMaybe, RSVPNotGoing:
+		return true
+	}
+	return false
 }
 
 func MigrateEventParticipation(db *gorm.DB) error {
-	return db.AutoMigrate(&EventParticipation{})
+	// Concurrent joins could add a user twice before the unique index existed, keep the first participation
+	if db.Migrator().HasTable(&EventParticipation{}) && !db.Migrator().HasIndex(&EventParticipation{}, "idx_participation_event_user") {
+		if err := db.Exec(`DELETE FROM event_participations WHERE id NOT IN (
+			SELECT MIN(id) FROM event_participations GROUP BY event_id, user_id
+		)`).Error; err != nil {
+			return err
+		}
+	}
+	if err := db.AutoMigrate(&EventParticipation{}); err != nil {
+		return err
+	}
+
+	// Participations created before roles existed get the default role, fix it for organizers
+	return db.Exec(`UPDATE event_participations SET role = ?
+		WHERE role = ? AND EXISTS (
+			SELECT 1 FROM events
+			WHERE events.id = event_participations.event_id AND events.organizer_id = event_participations.user_id
+		)`, RoleOrganizer, RoleParticipant).Error
 }
@@ -0,0 +1,37 @@
+package models
+
+import "gorm.io/gorm"
+
+// EventTemplate is a saved outline of an event that its owner can turn into new events
+type EventTemplate struct {
+	gorm.Model
+	OwnerID     uint   `gorm:"not null;index"`
+	Name        string `gorm:"not null"`
+	Description string `gorm:"type:text"`
+	Place       string `gorm:"type:text"`
+	// InitialBudget and the task budgets are in minor units of Currency
+	InitialBudget int64  `gorm:"column:initial_budget_minor;not null;default:0"`
+	Currency      string `gorm:"type:varchar(3);not null;default:RUB"`
+	Capacity      *int
+	Tasks         []EventTemplateTask `gorm:"foreignKey:TemplateID"`
+}
+
+// EventTemplateTask is a task created in every event made from the template
+type EventTemplateTask struct {
+	ID          uint   `gorm:"primaryKey"`
+	TemplateID  uint   `gorm:"not null;index"`
+	Title       string `gorm:"not null"`
+	Description string `gorm:"default:''"`
+	Budget      int64  `gorm:"column:budget_minor;not null;default:0"`
+	Points      int    `gorm:"not null"`
+}
+
+func MigrateEventTemplate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&EventTemplate{}, &EventTemplateTask{}); err != nil {
+		return err
+	}
+	if err := migrateToMinorUnits(db, &EventTemplate{}, "event_templates", "initial_budget", "initial_budget_minor"); err != nil {
+		return err
+	}
+	return migrateToMinorUnits(db, &EventTemplateTask{}, "event_template_tasks", "budget", "budget_minor")
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// How an expense is split between the participants who share it
+const (
+	SplitEqual  = "equal"
+	SplitShares = "shares"
+	SplitExact  = "exact"
+)
+
+// Expense is money actually spent for an event, optionally for one of its tasks
+type Expense struct {
+	gorm.Model
+	EventID     uint      `gorm:"not null;index"`
+	PayerID     uint      `gorm:"not null"`
+	CreatedByID uint      `gorm:"not null"`
+	TaskID      *uint     `gorm:"index"`
+	Amount      int64     `gorm:"column:amount_minor;not null;default:0"` // in minor units of Currency
+	Currency    string    `gorm:"type:varchar(3);not null;default:RUB"`
+	Description string    `gorm:"type:text"`
+	Category    string    `gorm:"type:varchar(50);not null;default:''"`
+	SpentAt     time.Time `gorm:"not null"`
+	SplitMode   string    `gorm:"type:varchar(10);not null;default:equal"`
+
+	Shares []ExpenseShare `gorm:"foreignKey:ExpenseID"`
+}
+
+// ExpenseShare is the part of an expense a participant owes, in minor units of the expense currency.
+// Weight is only set when splitting by shares.
+type ExpenseShare struct {
+	ID        uint  `gorm:"primaryKey"`
+	ExpenseID uint  `gorm:"not null;index"`
+	UserID    uint  `gorm:"not null"`
+	Weight    int64 `gorm:"not null;default:0"`
+	Amount    int64 `gorm:"column:amount_minor;not null;default:0"`
+}
+
+func IsValidSplitMode(mode string) bool {
+	switch mode {
+	case SplitEqual, SplitShares, SplitExact:
+		return true
+	}
+	return false
+}
+
+func MigrateExpense(db *gorm.DB) error {
+	if err := db.AutoMigrate(&Expense{}, &ExpenseShare{}); err != nil {
+		return err
+	}
+	if err := migrateToMinorUnits(db, &Expense{}, "expenses", "amount", "amount_minor"); err != nil {
+		return err
+	}
+	return migrateToMinorUnits(db, &ExpenseShare{}, "expense_shares", "amount", "amount_minor")
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	JoinRequestPending  = "pending"
+	JoinRequestApproved = "approved"
+	JoinRequestRejected = "rejected"
+)
+
+// JoinRequest is created instead of a participation when the invite link requires organizer approval
+type JoinRequest struct {
+	gorm.Model
+	EventID      uint   `gorm:"not null;index"`
+	UserID       uint   `gorm:"not null;index"`
+	InvitationID uint   `gorm:"not null"`
+	Status       string `gorm:"type:varchar(20);not null;default:pending"`
+	DecidedByID  *uint
+	DecidedAt    *time.Time
+}
+
+func MigrateJoinRequest(db *gorm.DB) error {
+	return db.AutoMigrate(&JoinRequest{})
+}
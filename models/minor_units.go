@@ -0,0 +1,23 @@
+package models
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// migrateToMinorUnits moves amounts of a float column into the integer minor units column that replaced it.
+// Amounts stored before currencies existed are in the default currency, which has two decimal digits.
+// Both steps run in one transaction so a failed migration leaves the old column in place to run again.
+func migrateToMinorUnits(db *gorm.DB, model interface{}, table, oldColumn, newColumn string) error {
+	if !db.Migrator().HasColumn(model, oldColumn) {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s = ROUND(COALESCE(%s, 0) * 100)", table, newColumn, oldColumn)).Error; err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn(model, oldColumn)
+	})
+}
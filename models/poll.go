@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Statuses of time polls and event polls, notifications about a closed event poll use them too
+const (
+	PollOpen   = "poll_open"
+	PollClosed = "poll_closed"
+)
+
+// Who sees the results of an event poll, organizers and co-organizers always do
+const (
+	PollResultsAlways     = "always"
+	PollResultsAfterVote  = "after_vote"
+	PollResultsAfterClose = "after_close"
+)
+
+// Poll is a question put to the participants of an event, like the venue or the menu.
+// Votes of anonymous polls still keep the voter to prevent voting twice, but the voter is never shown.
+type Poll struct {
+	gorm.Model
+	EventID           uint   `gorm:"not null;index"`
+	CreatedByID       uint   `gorm:"not null"`
+	Question          string `gorm:"type:varchar(255);not null"`
+	MultipleChoice    bool   `gorm:"not null;default:false"`
+	Anonymous         bool   `gorm:"not null;default:false"`
+	ResultsVisibility string `gorm:"type:varchar(20);not null;default:always"`
+	// Deadline closes the poll automatically
+	Deadline *time.Time `gorm:"index"`
+	Status   string     `gorm:"type:varchar(20);not null;default:poll_open;index"`
+	ClosedAt *time.Time
+	Options  []PollOption `gorm:"foreignKey:PollID"`
+}
+
+type PollOption struct {
+	ID       uint   `gorm:"primaryKey"`
+	PollID   uint   `gorm:"not null;index"`
+	Text     string `gorm:"type:varchar(255);not null"`
+	Position int    `gorm:"not null"`
+}
+
+type PollVote struct {
+	ID        uint `gorm:"primaryKey"`
+	PollID    uint `gorm:"not null;index"`
+	OptionID  uint `gorm:"not null;uniqueIndex:idx_poll_vote_option_user"`
+	UserID    uint `gorm:"not null;uniqueIndex:idx_poll_vote_option_user"`
+	CreatedAt time.Time
+}
+
+func IsValidPollResultsVisibility(visibility string) bool {
+	switch visibility {
+	case PollResultsAlways, PollResultsAfterVote, PollResultsAfterClose:
+		return true
+	}
+	return false
+}
+
+func MigratePoll(db *gorm.DB) error {
+	return db.AutoMigrate(&Poll{}, &PollOption{}, &PollVote{})
+}
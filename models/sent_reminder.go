@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Kinds of reminders the scheduler sends
+const (
+	ReminderTaskDue         = "task_due"
+	ReminderUnassignedTasks = "unassigned_tasks"
+)
+
+// MaxReminderHours bounds how long before a due date or an event users can be reminded
+const MaxReminderHours = 720
+
+// SentReminder records a delivered reminder so it is never sent twice. SubjectID is the task of a
+// due date reminder or the event of an unassigned tasks reminder, RemindedAt is the due date or the
+// event time reminded of, so a moved deadline is reminded of again.
+type SentReminder struct {
+	ID         uint      `gorm:"primaryKey"`
+	Kind       string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_sent_reminder"`
+	UserID     uint      `gorm:"not null;uniqueIndex:idx_sent_reminder"`
+	SubjectID  uint      `gorm:"not null;uniqueIndex:idx_sent_reminder"`
+	RemindedAt time.Time `gorm:"not null;uniqueIndex:idx_sent_reminder"`
+	SentAt     time.Time `gorm:"not null"`
+}
+
+func MigrateSentReminder(db *gorm.DB) error {
+	return db.AutoMigrate(&SentReminder{})
+}
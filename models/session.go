@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Session is a single login on a device. Access tokens carry TokenID as jti claim,
+// the refresh token is stored only as a hash and rotated on every refresh.
+type Session struct {
+	gorm.Model
+	UserID                   uint      `gorm:"not null;index"`
+	TokenID                  string    `gorm:"uniqueIndex;not null"`
+	RefreshTokenHash         string    `gorm:"uniqueIndex;not null"`
+	PreviousRefreshTokenHash string    `gorm:"index"`
+	DeviceName               string    `gorm:"type:varchar(255)"`
+	IPAddress                string    `gorm:"type:varchar(64)"`
+	UserAgent                string    `gorm:"type:text"`
+	LastSeenAt               time.Time `gorm:"not null"`
+	ExpiresAt                time.Time `gorm:"not null"`
+	RevokedAt                *time.Time
+}
+
+// LastSeenAt is not written on every request, only when older than this
+const SessionLastSeenPrecision = time.Minute
+
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && s.ExpiresAt.After(time.Now())
+}
+
+func MigrateSession(db *gorm.DB) error {
+	return db.AutoMigrate(&Session{})
+}
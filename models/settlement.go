@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Settlement is a payment between two participants of an event that settles their expense debts,
+// Amount is in minor units of Currency
+type Settlement struct {
+	gorm.Model
+	EventID     uint      `gorm:"not null;index"`
+	FromUserID  uint      `gorm:"not null"`
+	ToUserID    uint      `gorm:"not null"`
+	Amount      int64     `gorm:"column:amount_minor;not null;default:0"`
+	Currency    string    `gorm:"type:varchar(3);not null;default:RUB"`
+	CreatedByID uint      `gorm:"not null"`
+	PaidAt      time.Time `gorm:"not null"`
+}
+
+func MigrateSettlement(db *gorm.DB) error {
+	if err := db.AutoMigrate(&Settlement{}); err != nil {
+		return err
+	}
+	return migrateToMinorUnits(db, &Settlement{}, "settlements", "amount", "amount_minor")
+}
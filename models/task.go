@@ -1,18 +1,68 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// How urgent a task is, from the most to the least urgent
+const (
+	PriorityUrgent = "urgent"
+	PriorityHigh   = "high"
+	PriorityNormal = "normal"
+	PriorityLow    = "low"
+)
+
+// Statuses of a task in notifications about it
+const (
+	TaskUnassigned = "unassigned"
+	TaskAssigned   = "assigned"
+	TaskCompleted  = "completed"
+)
 
 type Task struct {
-	ID          uint    `gorm:"primaryKey"`
-	Title       string  `gorm:"not null"`
-	Description string  `gorm:"default:''"`
-	IsCompleted bool    `gorm:"default:false"`
-	Budget      float64 `gorm:"not null"`
-	Points      int     `gorm:"not null"`
-	EventID     uint    `gorm:"not null"`
-	AssignedTo  *uint   `gorm:"default:null"`
+	ID          uint   `gorm:"primaryKey"`
+	Title       string `gorm:"not null"`
+	Description string `gorm:"default:''"`
+	IsCompleted bool   `gorm:"default:false"`
+	Budget      int64  `gorm:"column:budget_minor;not null;default:0"` // in minor units of the event currency
+	Points      int    `gorm:"not null"`
+	EventID     uint   `gorm:"not null"`
+	AssignedTo  *uint  `gorm:"default:null"`
+	// Category groups tasks and expenses in the budget, like food or venue
+	Category string     `gorm:"type:varchar(50);not null;default:''"`
+	DueAt    *time.Time `gorm:"index"`
+	Priority string     `gorm:"type:varchar(10);not null;default:normal"`
+}
+
+func IsValidPriority(priority string) bool {
+	return PriorityRank(priority) >= 0
+}
+
+// PriorityRank orders priorities from urgent as 0 to low, unknown priorities are -1
+func PriorityRank(priority string) int {
+	switch priority {
+	case PriorityUrgent:
+		return 0
+	case PriorityHigh:
+		return 1
+	case PriorityNormal:
+		return 2
+	case PriorityLow:
+		return 3
+	}
+	return -1
+}
+
+// IsOverdue tells whether the task is still open after its due date
+func (t *Task) IsOverdue(now time.Time) bool {
+	return !t.IsCompleted && t.DueAt != nil && t.DueAt.Before(now)
 }
 
 func MigrateTask(db *gorm.DB) error {
-	return db.AutoMigrate(&Task{})
+	if err := db.AutoMigrate(&Task{}); err != nil {
+		return err
+	}
+	return migrateToMinorUnits(db, &Task{}, "tasks", "budget", "budget_minor")
 }
@@ -6,9 +6,13 @@ import (
 	"gorm.io/gorm"
 )
 
+// TaskStatusEvent is an entry of the user's notification feed. Notifications that are not about
+// a task (e.g. event ownership changes) have TaskID 0, TaskName holds the event name then
+// or the question of the poll for closed polls.
 type TaskStatusEvent struct {
 	gorm.Model
 	TaskID        uint      `gorm:"not null;index"`
+	EventID       uint      `gorm:"index"`
 	TaskName      string    `gorm:"type:varchar(255);not null"`
 	OldStatus     string    `gorm:"type:varchar(20)"`
 	NewStatus     string    `gorm:"type:varchar(20);not null"`
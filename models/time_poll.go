@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventTimeChanged is the status of notifications about a time poll moving the event to the chosen time
+const EventTimeChanged = "event_time_changed"
+
+// Answers to a candidate time of a poll
+const (
+	TimePollYes   = "yes"
+	TimePollMaybe = "maybe"
+	TimePollNo    = "no"
+)
+
+// TimePoll lets participants vote on candidate times of an event, closing it moves the event to the chosen time
+type TimePoll struct {
+	gorm.Model
+	EventID        uint   `gorm:"not null;index"`
+	CreatedByID    uint   `gorm:"not null"`
+	Status         string `gorm:"type:varchar(20);not null;default:poll_open"`
+	ChosenOptionID *uint
+	ClosedAt       *time.Time
+	Options        []TimePollOption `gorm:"foreignKey:PollID"`
+}
+
+type TimePollOption struct {
+	ID        uint      `gorm:"primaryKey"`
+	PollID    uint      `gorm:"not null;index"`
+	StartTime time.Time `gorm:"not null"`
+}
+
+// TimePollVote is the answer of a user to one candidate time
+type TimePollVote struct {
+	ID        uint   `gorm:"primaryKey"`
+	OptionID  uint   `gorm:"not null;uniqueIndex:idx_time_poll_vote_option_user"`
+	UserID    uint   `gorm:"not null;uniqueIndex:idx_time_poll_vote_option_user"`
+	Answer    string `gorm:"type:varchar(10);not null"`
+	UpdatedAt time.Time
+}
+
+func IsValidTimePollAnswer(answer string) bool {
+	return answer == TimePollYes || answer == TimePollMaybe || answer == TimePollNo
+}
+
+func MigrateTimePoll(db *gorm.DB) error {
+	return db.AutoMigrate(&TimePoll{}, &TimePollOption{}, &TimePollVote{})
+}
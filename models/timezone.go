@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+	// Zone data is embedded so that containers without a zoneinfo database still know every zone
+	_ "time/tzdata"
+)
+
+// DefaultTimezone applies to users and events without a zone, all times are stored in UTC
+const DefaultTimezone = "UTC"
+
+// LoadTimezone resolves an IANA zone name like Europe/Berlin, an empty name means UTC
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(name)
+}
+
+func locationOrUTC(name string) *time.Location {
+	location, err := LoadTimezone(name)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// Location returns the zone of the user, UTC when it is unknown
+func (u *User) Location() *time.Location {
+	return locationOrUTC(u.Timezone)
+}
+
+// Location returns the zone the event takes place in, UTC when it is unknown
+func (e *Event) Location() *time.Location {
+	return locationOrUTC(e.Timezone)
+}
@@ -10,8 +10,14 @@ type User struct {
 	Bio          string
 	Avatar       string
 	TotalScore   int
-	Events       []Event `gorm:"foreignKey:OrganizerID"`
-	Tasks        []Task  `gorm:"foreignKey:AssignedTo"`
+	// Timezone is an IANA zone name, dates and times the user sends without an offset are read in it
+	Timezone string `gorm:"type:varchar(64);not null;default:UTC"`
+	// TaskReminderHours is how long before a due date assignees are reminded of their tasks,
+	// EventReminderHours how long before their events organizers hear of unassigned tasks, 0 turns them off
+	TaskReminderHours  int     `gorm:"not null;default:24"`
+	EventReminderHours int     `gorm:"not null;default:48"`
+	Events             []Event `gorm:"foreignKey:OrganizerID"`
+	Tasks              []Task  `gorm:"foreignKey:AssignedTo"`
 }
 
 func MigrateUser(db *gorm.DB) error {
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Outcome of joining an event, also used in notifications about promotions
+const (
+	ParticipationJoined     = "joined"
+	ParticipationWaitlisted = "waitlisted"
+)
+
+// WaitlistEntry keeps a user in line for a place in a full event, entries are served in ID order
+type WaitlistEntry struct {
+	ID        uint `gorm:"primaryKey"`
+	EventID   uint `gorm:"not null;uniqueIndex:idx_waitlist_event_user"`
+	UserID    uint `gorm:"not null;uniqueIndex:idx_waitlist_event_user"`
+	CreatedAt time.Time
+}
+
+func MigrateWaitlistEntry(db *gorm.DB) error {
+	return db.AutoMigrate(&WaitlistEntry{})
+}
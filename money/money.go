@@ -0,0 +1,70 @@
+// Package money converts between decimal amounts and integer minor units of ISO 4217 currencies.
+// Amounts are stored in minor units, cents for USD and whole yen for JPY, so sums never pick up float errors.
+package money
+
+import (
+	"math"
+	"strings"
+)
+
+// DefaultCurrency is the currency of events and expenses that don't name one
+const DefaultCurrency = "RUB"
+
+// exponents lists the supported currencies with the number of digits after the decimal point
+var exponents = map[string]int{
+	"AED": 2, "AMD": 2, "AUD": 2, "AZN": 2, "BRL": 2, "BYN": 2, "CAD": 2, "CHF": 2,
+	"CNY": 2, "CZK": 2, "DKK": 2, "EUR": 2, "GBP": 2, "GEL": 2, "HKD": 2, "HUF": 2,
+	"ILS": 2, "INR": 2, "JPY": 0, "KGS": 2, "KRW": 0, "KZT": 2, "MXN": 2, "NOK": 2,
+	"NZD": 2, "PLN": 2, "RSD": 2, "RUB": 2, "SEK": 2, "SGD": 2, "THB": 2, "TRY": 2,
+	"UAH": 2, "USD": 2, "UZS": 2, "ZAR": 2, "BHD": 3, "KWD": 3, "OMR": 3,
+}
+
+// Normalize upper-cases a currency code and falls back to DefaultCurrency when it is empty
+func Normalize(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return DefaultCurrency
+	}
+	return currency
+}
+
+// IsSupported reports whether the currency code is known, codes have to be normalized
+func IsSupported(currency string) bool {
+	_, ok := exponents[currency]
+	return ok
+}
+
+// Exponent is the number of digits after the decimal point of the currency, 2 for unknown ones
+func Exponent(currency string) int {
+	exponent, ok := exponents[currency]
+	if !ok {
+		return 2
+	}
+	return exponent
+}
+
+func scale(currency string) float64 {
+	return math.Pow10(Exponent(currency))
+}
+
+// ToMinor converts a decimal amount to minor units, rounding to the nearest unit
+func ToMinor(amount float64, currency string) int64 {
+	return int64(math.Round(amount * scale(currency)))
+}
+
+// FromMinor converts minor units back to a decimal amount
+func FromMinor(minor int64, currency string) float64 {
+	return float64(minor) / scale(currency)
+}
+
+// Rescale keeps the decimal amount when minor units move from one currency to another,
+// 12.34 stays 12.34 and loses what the target currency has no digits for
+func Rescale(minor int64, from, to string) int64 {
+	return ToMinor(FromMinor(minor, from), to)
+}
+
+// Convert turns minor units of one currency into minor units of another at the rate,
+// the rate is how much of the target currency one unit of the source one buys
+func Convert(minor int64, from, to string, rate float64) int64 {
+	return ToMinor(FromMinor(minor, from)*rate, to)
+}
@@ -0,0 +1,124 @@
+package permissions
+
+import (
+	"itsplanned/models"
+
+	"gorm.io/gorm"
+)
+
+// GetRole returns the role of the user in the event and false if the user is not a participant.
+// Event.OrganizerID is the source of truth for the organizer role.
+func GetRole(db *gorm.DB, event *models.Event, userID uint) (string, bool) {
+	if event.OrganizerID == userID {
+		return models.RoleOrganizer, true
+	}
+
+	var participation models.EventParticipation
+	if err := db.Where("event_id = ? AND user_id = ?", event.ID, userID).First(&participation).Error; err != nil {
+		return "", false
+	}
+
+	// Stale organizer role of a participation that no longer owns the event
+	if participation.Role == models.RoleOrganizer {
+		return models.RoleCoOrganizer, true
+	}
+
+	return participation.Role, true
+}
+
+func isManager(role string) bool {
+	return role == models.RoleOrganizer || role == models.RoleCoOrganizer
+}
+
+// CanView allows reading the event, its tasks, participants, budget and leaderboard
+func CanView(role string) bool {
+	return models.IsValidEventRole(role)
+}
+
+func CanEditEvent(role string) bool {
+	return isManager(role)
+}
+
+func CanDeleteEvent(role string) bool {
+	return role == models.RoleOrganizer
+}
+
+// CanCreateTask allows proposing new tasks, editing and deleting them requires CanEditTask
+func CanCreateTask(role string) bool {
+	return isManager(role) || role == models.RoleParticipant
+}
+
+func CanEditTask(role string) bool {
+	return isManager(role)
+}
+
+// CanTakeTask allows assigning yourself to a task
+func CanTakeTask(role string) bool {
+	return isManager(role) || role == models.RoleParticipant
+}
+
+func CanTransferOwnership(role string) bool {
+	return role == models.RoleOrganizer
+}
+
+func CanInvite(role string) bool {
+	return isManager(role) || role == models.RoleParticipant
+}
+
+// CanManageInvites allows revoking invite links created by other participants
+func CanManageInvites(role string) bool {
+	return isManager(role)
+}
+
+// CanManageJoinRequests allows approving and rejecting requests to join the event
+func CanManageJoinRequests(role string) bool {
+	return isManager(role)
+}
+
+func CanManageBudget(role string) bool {
+	return isManager(role)
+}
+
+// CanChangeRole checks whether a user with the given role may move a participant from one role to another.
+// Nobody can grant or take away the organizer role, only the organizer manages co-organizers.
+func CanChangeRole(role, fromRole, toRole string) bool {
+	if !isManager(role) {
+		return false
+	}
+	if fromRole == models.RoleOrganizer || toRole == models.RoleOrganizer {
+		return false
+	}
+	if fromRole == models.RoleCoOrganizer || toRole == models.RoleCoOrganizer {
+		return role == models.RoleOrganizer
+	}
+	return true
+}
+
+// CanManagePolls allows creating and closing polls of the event
+func CanManagePolls(role string) bool {
+	return isManager(role)
+}
+
+// CanVote allows answering polls, viewers only see them
+func CanVote(role string) bool {
+	return isManager(role) || role == models.RoleParticipant
+}
+
+// CanCreatePoll allows putting a question to the participants of the event
+func CanCreatePoll(role string) bool {
+	return isManager(role) || role == models.RoleParticipant
+}
+
+// CanAddExpense allows recording money spent for the event, changing expenses of others requires CanManageBudget
+func CanAddExpense(role string) bool {
+	return isManager(role) || role == models.RoleParticipant
+}
+
+// CanAttachFiles allows uploading receipts and documents to tasks and expenses, deleting files of others requires CanManageAttachments
+func CanAttachFiles(role string) bool {
+	return isManager(role) || role == models.RoleParticipant
+}
+
+func CanManageAttachments(role string) bool {
+	return isManager(role)
+}
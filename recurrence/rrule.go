@@ -0,0 +1,415 @@
+// Package recurrence implements the subset of RFC 5545 recurrence rules used for repeating events:
+// FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY and BYMONTH.
+// Weeks start on Monday and ordinal weekdays like 2TU are always counted inside the month.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxPeriods bounds the expansion of rules whose filters never match, like BYMONTHDAY=30 with BYMONTH=2
+const maxPeriods = 50000
+
+const untilLayout = "20060102T150405Z"
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// WeekdayNum is a BYDAY entry, N is the optional ordinal inside the month or year (1MO, -1FR)
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+func weekdayCode(day time.Weekday) string {
+	for code, weekday := range weekdayCodes {
+		if weekday == day {
+			return code
+		}
+	}
+	return ""
+}
+
+func invalid(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidRule, fmt.Sprintf(format, args...))
+}
+
+// Parse reads an RRULE value, the "RRULE:" prefix is optional
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, invalid("empty rule")
+	}
+
+	rule := &Rule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, invalid("malformed part %q", part)
+		}
+
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(val))
+			switch rule.Freq {
+			case Daily, Weekly, Monthly, Yearly:
+			default:
+				return nil, invalid("unsupported frequency %q", val)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 {
+				return nil, invalid("interval must be a positive number")
+			}
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil || count < 1 {
+				return nil, invalid("count must be a positive number")
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return nil, invalid("until must be a UTC date or date-time")
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, day := range strings.Split(strings.ToUpper(val), ",") {
+				if len(day) < 2 {
+					return nil, invalid("invalid weekday %q", day)
+				}
+				weekday, ok := weekdayCodes[day[len(day)-2:]]
+				if !ok {
+					return nil, invalid("invalid weekday %q", day)
+				}
+				n := 0
+				if ordinal := day[:len(day)-2]; ordinal != "" {
+					var err error
+					if n, err = strconv.Atoi(ordinal); err != nil || n == 0 || n < -53 || n > 53 {
+						return nil, invalid("invalid weekday %q", day)
+					}
+				}
+				rule.ByDay = append(rule.ByDay, WeekdayNum{Weekday: weekday, N: n})
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(day)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, invalid("invalid month day %q", day)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "BYMONTH":
+			for _, month := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(month)
+				if err != nil || n < 1 || n > 12 {
+					return nil, invalid("invalid month %q", month)
+				}
+				rule.ByMonth = append(rule.ByMonth, time.Month(n))
+			}
+		case "WKST":
+			if strings.ToUpper(val) != "MO" {
+				return nil, invalid("only weeks starting on Monday are supported")
+			}
+		default:
+			return nil, invalid("unsupported part %q", name)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, invalid("FREQ is required")
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, invalid("COUNT and UNTIL cannot be combined")
+	}
+	for _, day := range rule.ByDay {
+		if day.N != 0 && rule.Freq != Monthly && rule.Freq != Yearly {
+			return nil, invalid("ordinal weekdays need a monthly or yearly frequency")
+		}
+	}
+	if len(rule.ByMonthDay) > 0 && rule.Freq == Weekly {
+		return nil, invalid("BYMONTHDAY cannot be used with a weekly frequency")
+	}
+
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	if until, err := time.Parse(untilLayout, value); err == nil {
+		return until, nil
+	}
+	date, err := time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// A date without time includes the whole day
+	return date.Add(24*time.Hour - time.Second), nil
+}
+
+// String formats the rule as an RRULE value without the prefix
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayout))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = weekdayCode(day.Weekday)
+			if day.N != 0 {
+				days[i] = strconv.Itoa(day.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, len(r.ByMonth))
+		for i, month := range r.ByMonth {
+			months[i] = strconv.Itoa(int(month))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Between returns occurrences of a series starting at start that fall into [from, to).
+// The start itself is always the first occurrence. A limit above zero caps the result.
+func (r *Rule) Between(start, from, to time.Time, limit int) []time.Time {
+	var occurrences []time.Time
+	r.iterate(start, func(occurrence time.Time) bool {
+		if !occurrence.Before(to) {
+			return false
+		}
+		if !occurrence.Before(from) {
+			occurrences = append(occurrences, occurrence)
+		}
+		return limit <= 0 || len(occurrences) < limit
+	})
+	return occurrences
+}
+
+// Occurs reports whether the series starting at start has an occurrence at exactly t
+func (r *Rule) Occurs(start, t time.Time) bool {
+	occurrences := r.Between(start, t, t.Add(time.Second), 1)
+	return len(occurrences) == 1 && occurrences[0].Equal(t)
+}
+
+// CountBefore returns how many occurrences of the series come before t
+func (r *Rule) CountBefore(start, t time.Time) int {
+	return len(r.Between(start, start, t, 0))
+}
+
+// Last returns the last occurrence of a finite series
+func (r *Rule) Last(start time.Time) (time.Time, bool) {
+	if r.Count == 0 && r.Until == nil {
+		return time.Time{}, false
+	}
+	last := start
+	r.iterate(start, func(occurrence time.Time) bool {
+		last = occurrence
+		return true
+	})
+	return last, true
+}
+
+func (r *Rule) iterate(start time.Time, yield func(time.Time) bool) {
+	if !yield(start) {
+		return
+	}
+
+	count := 1
+	for period := 0; period < maxPeriods; period++ {
+		for _, occurrence := range r.candidates(start, period) {
+			if !occurrence.After(start) {
+				continue
+			}
+			if r.Until != nil && occurrence.After(*r.Until) {
+				return
+			}
+			if r.Count > 0 && count >= r.Count {
+				return
+			}
+			count++
+			if !yield(occurrence) {
+				return
+			}
+		}
+	}
+}
+
+// candidates returns the sorted occurrences of the n-th period of the rule
+func (r *Rule) candidates(start time.Time, n int) []time.Time {
+	step := n * r.Interval
+	hour, minute, second := start.Clock()
+	loc := start.Location()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, minute, second, start.Nanosecond(), loc)
+	}
+
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		day := at(start.Year(), start.Month(), start.Day()+step)
+		if r.matchesMonth(day.Month()) && r.matchesMonthDay(day) && r.matchesWeekday(day) {
+			days = append(days, day)
+		}
+	case Weekly:
+		// Monday of the week of the start
+		offset := (int(start.Weekday()) + 6) % 7
+		monday := at(start.Year(), start.Month(), start.Day()-offset+7*step)
+		for i := 0; i < 7; i++ {
+			day := monday.AddDate(0, 0, i)
+			if len(r.ByDay) == 0 && day.Weekday() != start.Weekday() {
+				continue
+			}
+			if r.matchesMonth(day.Month()) && r.matchesWeekday(day) {
+				days = append(days, day)
+			}
+		}
+	case Monthly:
+		first := at(start.Year(), start.Month()+time.Month(step), 1)
+		if r.matchesMonth(first.Month()) {
+			days = r.daysOfMonth(first, start.Day())
+		}
+	case Yearly:
+		year := start.Year() + step
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{start.Month()}
+		}
+		for _, month := range months {
+			days = append(days, r.daysOfMonth(at(year, month, 1), start.Day())...)
+		}
+	}
+
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
+
+// daysOfMonth expands BYMONTHDAY and BYDAY inside the month starting at first, falling back to the day of the start
+func (r *Rule) daysOfMonth(first time.Time, startDay int) []time.Time {
+	daysInMonth := first.AddDate(0, 1, -1).Day()
+	var days []time.Time
+
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		// Months without that day are skipped as RFC 5545 requires
+		if startDay <= daysInMonth {
+			days = append(days, first.AddDate(0, 0, startDay-1))
+		}
+		return days
+	}
+
+	for d := 1; d <= daysInMonth; d++ {
+		day := first.AddDate(0, 0, d-1)
+		if len(r.ByMonthDay) > 0 && !r.matchesMonthDay(day) {
+			continue
+		}
+		if len(r.ByDay) > 0 && !r.matchesWeekdayInMonth(day, daysInMonth) {
+			continue
+		}
+		days = append(days, day)
+	}
+	return days
+}
+
+func (r *Rule) matchesMonth(month time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, m := range r.ByMonth {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+	for _, n := range r.ByMonthDay {
+		if n == day.Day() || (n < 0 && daysInMonth+n+1 == day.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesWeekday(day time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, d := range r.ByDay {
+		if d.Weekday == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesWeekdayInMonth(day time.Time, daysInMonth int) bool {
+	for _, d := range r.ByDay {
+		if d.Weekday != day.Weekday() {
+			continue
+		}
+		if d.N == 0 {
+			return true
+		}
+		if d.N > 0 && (day.Day()-1)/7+1 == d.N {
+			return true
+		}
+		if d.N < 0 && (daysInMonth-day.Day())/7+1 == -d.N {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// utf8BOM makes spreadsheet apps read the file as UTF-8 instead of the local code page
+const utf8BOM = "\ufeff"
+
+// WriteCSV writes the sections one after another, each starting with its title and separated by an empty line
+func WriteCSV(w io.Writer, report *Report) error {
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	for i, section := range report.Sections {
+		if i > 0 {
+			if err := writer.Write([]string{}); err != nil {
+				return err
+			}
+		}
+		if err := writer.Write([]string{csvText(section.Title)}); err != nil {
+			return err
+		}
+		if len(section.Columns) > 0 {
+			if err := writer.Write(csvTexts(section.Columns)); err != nil {
+				return err
+			}
+		}
+		for _, row := range section.Rows {
+			if err := writer.Write(csvRow(row)); err != nil {
+				return err
+			}
+		}
+		if section.Total != nil {
+			if err := writer.Write(csvRow(section.Total)); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvText keeps spreadsheet apps from running user input starting with a formula character as a formula
+func csvText(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+func csvTexts(texts []string) []string {
+	record := make([]string, len(texts))
+	for i, text := range texts {
+		record[i] = csvText(text)
+	}
+	return record
+}
+
+func csvRow(row []Cell) []string {
+	record := make([]string, len(row))
+	for i, cell := range row {
+		if cell.Numeric {
+			record[i] = cell.Text
+		} else {
+			record[i] = csvText(cell.Text)
+		}
+	}
+	return record
+}
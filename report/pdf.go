@@ -0,0 +1,477 @@
+package report
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// Pages are A4 landscape so wide tables fit, sizes are in points
+const (
+	pageWidth   = 841.89
+	pageHeight  = 595.28
+	pageMargin  = 40.0
+	footerSpace = 24.0
+	titleSize   = 16.0
+	headingSize = 12.0
+	bodySize    = 9.0
+	footerSize  = 7.0
+	rowHeight   = 15.0
+	cellPadding = 4.0
+)
+
+// pdfFont measures and encodes text for the content streams
+type pdfFont interface {
+	width(text string, size float64) float64
+	// show returns the string operand that draws the text
+	show(text string) string
+}
+
+// standardFont is the built-in Helvetica, it needs no embedding but only covers WinAnsiEncoding
+type standardFont struct{}
+
+// winAnsiSpecials are the characters WinAnsiEncoding puts between 0x80 and 0x9F, above that it matches Latin-1
+var winAnsiSpecials = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// helveticaWidths are the widths of the printable ASCII characters of Helvetica in thousandths of the font size
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// winAnsi encodes the text, characters the encoding lacks become question marks
+func winAnsi(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r >= 0x20 && r <= 0x7E, r >= 0xA0 && r <= 0xFF:
+			encoded = append(encoded, byte(r))
+		case winAnsiSpecials[r] != 0:
+			encoded = append(encoded, winAnsiSpecials[r])
+		default:
+			encoded = append(encoded, '?')
+		}
+	}
+	return encoded
+}
+
+func (standardFont) width(text string, size float64) float64 {
+	total := 0
+	for _, b := range winAnsi(text) {
+		switch {
+		case b >= 0x20 && b <= 0x7E:
+			total += helveticaWidths[b-0x20]
+		case b == 0x85 || b == 0x97:
+			total += 1000
+		default:
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+func (standardFont) show(text string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, c := range winAnsi(text) {
+		if c == '(' || c == ')' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// embeddedFont draws with glyph IDs of a TrueType font and remembers the glyphs used for the font dictionaries
+type embeddedFont struct {
+	font *Font
+	used map[uint16]rune
+}
+
+func (f *embeddedFont) width(text string, size float64) float64 {
+	total := 0
+	for _, r := range text {
+		total += f.font.scaled(f.font.advance(f.font.glyph(r)))
+	}
+	return float64(total) * size / 1000
+}
+
+func (f *embeddedFont) show(text string) string {
+	var b strings.Builder
+	b.WriteByte('<')
+	for _, r := range text {
+		glyph := f.font.glyph(r)
+		if _, ok := f.used[glyph]; !ok {
+			f.used[glyph] = r
+		}
+		fmt.Fprintf(&b, "%04X", glyph)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+// pdfLayout places the report on pages top to bottom
+type pdfLayout struct {
+	font  pdfFont
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64
+}
+
+func (l *pdfLayout) newPage() {
+	l.page = &bytes.Buffer{}
+	l.pages = append(l.pages, l.page)
+	l.y = pageHeight - pageMargin
+}
+
+// need starts a new page unless the height still fits on the current one
+func (l *pdfLayout) need(height float64) bool {
+	if l.y-height < pageMargin+footerSpace {
+		l.newPage()
+		return true
+	}
+	return false
+}
+
+// text draws the text with its baseline at y, bold text is drawn filled and stroked
+func (l *pdfLayout) text(x, y, size float64, text string, bold bool) {
+	mode := "0 Tr"
+	if bold {
+		mode = "2 Tr 0.3 w"
+	}
+	fmt.Fprintf(l.page, "BT /F1 %.2f Tf %s %.2f %.2f Td %s Tj ET\n", size, mode, x, y, l.font.show(text))
+}
+
+func (l *pdfLayout) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(l.page, "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, y1, x2, y2)
+}
+
+// fit shortens the text with an ellipsis until it fits into the width
+func (l *pdfLayout) fit(text string, size, width float64) string {
+	if l.font.width(text, size) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		shortened := strings.TrimRight(string(runes), " ") + "..."
+		if l.font.width(shortened, size) <= width {
+			return shortened
+		}
+	}
+	return ""
+}
+
+func (l *pdfLayout) heading(text string) {
+	// Keep the heading on the page of the first rows of its table
+	l.need(headingSize + 4*rowHeight)
+	l.y -= headingSize
+	l.text(pageMargin, l.y, headingSize, l.fit(text, headingSize, pageWidth-2*pageMargin), true)
+	l.y -= 8
+}
+
+// columnWidths sizes the columns to their content, text columns shrink when the table is wider than the page
+func (l *pdfLayout) columnWidths(section Section) []float64 {
+	count := len(section.Columns)
+	rows := append(append([][]Cell{}, section.Rows...), section.Total)
+	for _, row := range rows {
+		if len(row) > count {
+			count = len(row)
+		}
+	}
+
+	widths := make([]float64, count)
+	numeric := make([]bool, count)
+	for i := range numeric {
+		numeric[i] = true
+	}
+	measure := func(i int, text string) {
+		if width := l.font.width(text, bodySize) + 2*cellPadding; width > widths[i] {
+			widths[i] = width
+		}
+	}
+	for i, column := range section.Columns {
+		measure(i, column)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			measure(i, cell.Text)
+			if cell.Text != "" && !cell.Numeric {
+				numeric[i] = false
+			}
+		}
+	}
+
+	available := pageWidth - 2*pageMargin
+	var total, fixed float64
+	for i, width := range widths {
+		total += width
+		if numeric[i] {
+			fixed += width
+		}
+	}
+	if total <= available {
+		return widths
+	}
+
+	flexible := available - fixed
+	for i := range widths {
+		switch {
+		case flexible <= 0:
+			widths[i] *= available / total
+		case !numeric[i]:
+			widths[i] *= flexible / (total - fixed)
+		}
+	}
+	return widths
+}
+
+func (l *pdfLayout) row(cells []Cell, widths []float64, bold bool) {
+	baseline := l.y - rowHeight + 4
+	x := pageMargin
+	for i, width := range widths {
+		if i < len(cells) && cells[i].Text != "" {
+			text := l.fit(cells[i].Text, bodySize, width-2*cellPadding)
+			textX := x + cellPadding
+			if cells[i].Numeric {
+				textX = x + width - cellPadding - l.font.width(text, bodySize)
+			}
+			l.text(textX, baseline, bodySize, text, bold)
+		}
+		x += width
+	}
+	l.y -= rowHeight
+}
+
+func (l *pdfLayout) table(section Section) {
+	widths := l.columnWidths(section)
+	var tableWidth float64
+	for _, width := range widths {
+		tableWidth += width
+	}
+
+	header := func() {
+		if len(section.Columns) == 0 {
+			return
+		}
+		l.row(Texts(section.Columns...), widths, true)
+		l.line(pageMargin, l.y+2, pageMargin+tableWidth, l.y+2)
+	}
+	header()
+
+	for _, row := range section.Rows {
+		// Tables continuing on a new page repeat their header
+		if l.need(rowHeight) {
+			header()
+		}
+		l.row(row, widths, false)
+	}
+
+	if section.Total != nil {
+		if l.need(rowHeight) {
+			header()
+		}
+		l.line(pageMargin, l.y, pageMargin+tableWidth, l.y)
+		l.row(section.Total, widths, true)
+	}
+	l.y -= rowHeight
+}
+
+// WritePDF writes the report as a PDF document. The text is set in the font, which gets embedded,
+// without one the standard Helvetica is used and characters outside Western European scripts are lost.
+func WritePDF(w io.Writer, report *Report, font *Font) error {
+	layout := &pdfLayout{font: standardFont{}}
+	var embedded *embeddedFont
+	if font != nil {
+		embedded = &embeddedFont{font: font, used: map[uint16]rune{}}
+		layout.font = embedded
+	}
+
+	layout.newPage()
+	layout.y -= titleSize
+	layout.text(pageMargin, layout.y, titleSize, layout.fit(report.Title, titleSize, pageWidth-2*pageMargin), true)
+	layout.y -= 16
+	for _, section := range report.Sections {
+		layout.heading(section.Title)
+		layout.table(section)
+	}
+
+	for i, page := range layout.pages {
+		layout.page = page
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(layout.pages))
+		layout.text(pageMargin, pageMargin/2, footerSize, layout.fit(report.Title, footerSize, pageWidth/2), false)
+		layout.text(pageWidth-pageMargin-layout.font.width(footer, footerSize), pageMargin/2, footerSize, footer, false)
+	}
+
+	doc := &pdfDocument{}
+	catalog := doc.reserve()
+	pages := doc.reserve()
+	fontID := doc.reserve()
+	info := doc.add([]byte(fmt.Sprintf("<< /Title %s /Producer (itsplanned) >>", pdfTextString(report.Title))))
+
+	if embedded == nil {
+		doc.set(fontID, []byte("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>"))
+	} else {
+		embedded.write(doc, fontID)
+	}
+
+	kids := make([]string, 0, len(layout.pages))
+	for _, page := range layout.pages {
+		content, err := pdfStream("", page.Bytes())
+		if err != nil {
+			return err
+		}
+		contentID := doc.add(content)
+		pageID := doc.add([]byte(fmt.Sprintf(
+			"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+			pages, pageWidth, pageHeight, fontID, contentID,
+		)))
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageID))
+	}
+	doc.set(pages, []byte(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))))
+	doc.set(catalog, []byte(fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages)))
+
+	_, err := w.Write(doc.bytes(catalog, info))
+	return err
+}
+
+// write adds the font dictionaries with the widths and the Unicode mapping of the glyphs used
+func (f *embeddedFont) write(doc *pdfDocument, fontID int) {
+	glyphs := make([]int, 0, len(f.used))
+	for glyph := range f.used {
+		glyphs = append(glyphs, int(glyph))
+	}
+	sort.Ints(glyphs)
+
+	var widths strings.Builder
+	for _, glyph := range glyphs {
+		fmt.Fprintf(&widths, "%d [%d] ", glyph, f.font.scaled(f.font.advance(uint16(glyph))))
+	}
+
+	var cmap strings.Builder
+	cmap.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	for start := 0; start < len(glyphs); start += 100 {
+		end := start + 100
+		if end > len(glyphs) {
+			end = len(glyphs)
+		}
+		fmt.Fprintf(&cmap, "%d beginbfchar\n", end-start)
+		for _, glyph := range glyphs[start:end] {
+			fmt.Fprintf(&cmap, "<%04X> <", glyph)
+			for _, unit := range utf16.Encode([]rune{f.used[uint16(glyph)]}) {
+				fmt.Fprintf(&cmap, "%04X", unit)
+			}
+			cmap.WriteString(">\n")
+		}
+		cmap.WriteString("endbfchar\n")
+	}
+	cmap.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+
+	// Both streams compress from memory, zlib can't fail writing to a buffer
+	toUnicode, _ := pdfStream("", []byte(cmap.String()))
+	fontFile, _ := pdfStream(fmt.Sprintf("/Length1 %d", len(f.font.data)), f.font.data)
+
+	font := f.font
+	fontFileID := doc.add(fontFile)
+	descriptorID := doc.add([]byte(fmt.Sprintf(
+		"<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		font.name, font.scaled(font.bbox[0]), font.scaled(font.bbox[1]), font.scaled(font.bbox[2]), font.scaled(font.bbox[3]),
+		font.scaled(font.ascent), font.scaled(font.descent), font.scaled(font.ascent), fontFileID,
+	)))
+	cidFontID := doc.add([]byte(fmt.Sprintf(
+		"<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /W [%s] /CIDToGIDMap /Identity >>",
+		font.name, descriptorID, strings.TrimSpace(widths.String()),
+	)))
+	toUnicodeID := doc.add(toUnicode)
+	doc.set(fontID, []byte(fmt.Sprintf(
+		"<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		font.name, cidFontID, toUnicodeID,
+	)))
+}
+
+// pdfDocument collects numbered objects and writes them with their cross-reference table
+type pdfDocument struct {
+	objects [][]byte
+}
+
+func (d *pdfDocument) reserve() int {
+	d.objects = append(d.objects, nil)
+	return len(d.objects)
+}
+
+func (d *pdfDocument) set(id int, body []byte) {
+	d.objects[id-1] = body
+}
+
+func (d *pdfDocument) add(body []byte) int {
+	id := d.reserve()
+	d.set(id, body)
+	return id
+}
+
+func (d *pdfDocument) bytes(root, info int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	offsets := make([]int, len(d.objects))
+	for i, body := range d.objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n", i+1)
+		buf.Write(body)
+		buf.WriteString("\nendobj\n")
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(d.objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(d.objects)+1, root, info, xref)
+	return buf.Bytes()
+}
+
+// pdfStream compresses the data into a stream object with the extra dictionary entries
+func pdfStream(dict string, data []byte) ([]byte, error) {
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<< %s /Filter /FlateDecode /Length %d >>\nstream\n", dict, compressed.Len())
+	buf.Write(compressed.Bytes())
+	buf.WriteString("\nendstream")
+	return buf.Bytes(), nil
+}
+
+// pdfTextString encodes text for the document information as UTF-16 with a byte order mark
+func pdfTextString(text string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, unit := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(&b, "%04X", unit)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
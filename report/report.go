@@ -0,0 +1,50 @@
+// Package report lays out tabular reports and writes them as CSV, XLSX or PDF without external tools.
+package report
+
+import "strconv"
+
+// Report is a titled list of tables
+type Report struct {
+	Title    string
+	Sections []Section
+}
+
+// Section is one table of a report. Sections without columns are key and value lists.
+type Section struct {
+	Title   string
+	Columns []string
+	Rows    [][]Cell
+	// Total is an optional last row that sums up the table
+	Total []Cell
+}
+
+// Cell is a value in a table, numbers are aligned right and stay numbers in spreadsheets
+type Cell struct {
+	Text     string
+	Numeric  bool
+	Decimals int
+}
+
+// Text makes a cell with a string
+func Text(text string) Cell {
+	return Cell{Text: text}
+}
+
+// Number makes a numeric cell shown with a fixed number of decimals
+func Number(value float64, decimals int) Cell {
+	return Cell{Text: strconv.FormatFloat(value, 'f', decimals, 64), Numeric: true, Decimals: decimals}
+}
+
+// Int makes a numeric cell for a whole number
+func Int(value int) Cell {
+	return Cell{Text: strconv.Itoa(value), Numeric: true}
+}
+
+// Texts makes a row of string cells
+func Texts(texts ...string) []Cell {
+	cells := make([]Cell, len(texts))
+	for i, text := range texts {
+		cells[i] = Text(text)
+	}
+	return cells
+}
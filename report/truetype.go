@@ -0,0 +1,197 @@
+package report
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Font is a TrueType font embedded into PDF reports so they can show any script the font covers,
+// the standard PDF fonts only know Latin letters
+type Font struct {
+	name       string
+	data       []byte
+	unitsPerEm int
+	ascent     int
+	descent    int
+	bbox       [4]int
+	advances   []int
+	glyphs     map[rune]uint16
+}
+
+var errInvalidFont = errors.New("not a TrueType font")
+
+// LoadFont reads a TrueType font file
+func LoadFont(path string) (*Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font: %w", err)
+	}
+	font, err := ParseFont(data)
+	if err != nil {
+		return nil, err
+	}
+	font.name = fontName(path)
+	return font, nil
+}
+
+// ParseFont reads the metrics and the character map of a TrueType font
+func ParseFont(data []byte) (*Font, error) {
+	if len(data) < 12 {
+		return nil, errInvalidFont
+	}
+	version := binary.BigEndian.Uint32(data)
+	if version != 0x00010000 && version != 0x74727565 { // 1.0 or "true", "OTTO" fonts have no glyf outlines
+		return nil, errInvalidFont
+	}
+
+	tables := map[string][]byte{}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		record := 12 + 16*i
+		if record+16 > len(data) {
+			return nil, errInvalidFont
+		}
+		tag := string(data[record : record+4])
+		offset := int(binary.BigEndian.Uint32(data[record+8:]))
+		length := int(binary.BigEndian.Uint32(data[record+12:]))
+		if offset < 0 || length < 0 || offset+length > len(data) {
+			return nil, errInvalidFont
+		}
+		tables[tag] = data[offset : offset+length]
+	}
+
+	head, hhea, hmtx, cmap := tables["head"], tables["hhea"], tables["hmtx"], tables["cmap"]
+	if len(head) < 54 || len(hhea) < 36 || len(cmap) < 4 || tables["glyf"] == nil {
+		return nil, errInvalidFont
+	}
+
+	font := &Font{
+		name:       "EmbeddedFont",
+		data:       data,
+		unitsPerEm: int(binary.BigEndian.Uint16(head[18:])),
+		ascent:     int(int16(binary.BigEndian.Uint16(hhea[4:]))),
+		descent:    int(int16(binary.BigEndian.Uint16(hhea[6:]))),
+	}
+	if font.unitsPerEm == 0 {
+		return nil, errInvalidFont
+	}
+	for i := range font.bbox {
+		font.bbox[i] = int(int16(binary.BigEndian.Uint16(head[36+2*i:])))
+	}
+
+	numberOfHMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	if numberOfHMetrics == 0 || len(hmtx) < 4*numberOfHMetrics {
+		return nil, errInvalidFont
+	}
+	font.advances = make([]int, numberOfHMetrics)
+	for i := range font.advances {
+		font.advances[i] = int(binary.BigEndian.Uint16(hmtx[4*i:]))
+	}
+
+	glyphs, err := parseCmap(cmap)
+	if err != nil {
+		return nil, err
+	}
+	font.glyphs = glyphs
+	return font, nil
+}
+
+// parseCmap reads the Unicode character map, the BMP format 4 subtable is enough for any text of a report
+func parseCmap(cmap []byte) (map[rune]uint16, error) {
+	numTables := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < numTables; i++ {
+		record := 4 + 8*i
+		if record+8 > len(cmap) {
+			break
+		}
+		platform := binary.BigEndian.Uint16(cmap[record:])
+		encoding := binary.BigEndian.Uint16(cmap[record+2:])
+		offset := int(binary.BigEndian.Uint32(cmap[record+4:]))
+		unicode := platform == 0 || (platform == 3 && encoding == 1)
+		if !unicode || offset+4 > len(cmap) || binary.BigEndian.Uint16(cmap[offset:]) != 4 {
+			continue
+		}
+		return parseCmapFormat4(cmap[offset:])
+	}
+	return nil, errors.New("the font has no Unicode character map")
+}
+
+func parseCmapFormat4(table []byte) (map[rune]uint16, error) {
+	if len(table) < 14 {
+		return nil, errInvalidFont
+	}
+	segCount := int(binary.BigEndian.Uint16(table[6:])) / 2
+	endCodes := 14
+	startCodes := endCodes + 2*segCount + 2
+	idDeltas := startCodes + 2*segCount
+	idRangeOffsets := idDeltas + 2*segCount
+	if idRangeOffsets+2*segCount > len(table) {
+		return nil, errInvalidFont
+	}
+
+	glyphs := map[rune]uint16{}
+	for i := 0; i < segCount; i++ {
+		end := int(binary.BigEndian.Uint16(table[endCodes+2*i:]))
+		start := int(binary.BigEndian.Uint16(table[startCodes+2*i:]))
+		delta := int(binary.BigEndian.Uint16(table[idDeltas+2*i:]))
+		rangeOffsetAt := idRangeOffsets + 2*i
+		rangeOffset := int(binary.BigEndian.Uint16(table[rangeOffsetAt:]))
+
+		for code := start; code <= end && code != 0xFFFF; code++ {
+			glyph := 0
+			if rangeOffset == 0 {
+				glyph = (code + delta) & 0xFFFF
+			} else {
+				at := rangeOffsetAt + rangeOffset + 2*(code-start)
+				if at+2 > len(table) {
+					continue
+				}
+				glyph = int(binary.BigEndian.Uint16(table[at:]))
+				if glyph != 0 {
+					glyph = (glyph + delta) & 0xFFFF
+				}
+			}
+			if glyph != 0 {
+				glyphs[rune(code)] = uint16(glyph)
+			}
+		}
+	}
+	return glyphs, nil
+}
+
+// glyph finds the glyph of a character, 0 is the missing glyph box
+func (f *Font) glyph(r rune) uint16 {
+	return f.glyphs[r]
+}
+
+// advance is the width of a glyph in font units
+func (f *Font) advance(glyph uint16) int {
+	if int(glyph) < len(f.advances) {
+		return f.advances[glyph]
+	}
+	return f.advances[len(f.advances)-1]
+}
+
+// scaled converts font units to the thousandths of the font size PDF measures fonts in
+func (f *Font) scaled(units int) int {
+	return units * 1000 / f.unitsPerEm
+}
+
+// fontName makes a PDF name out of the file name
+func fontName(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x21 || r > 0x7E || strings.ContainsRune("()<>[]{}/%#", r) {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" {
+		return "EmbeddedFont"
+	}
+	return name
+}
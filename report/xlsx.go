@@ -0,0 +1,223 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// The styles of cells are bold or not times a number format for 0, 2 or 3 decimals, see xlsxStyles
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="1"><numFmt numFmtId="164" formatCode="#,##0.000"/></numFmts>
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="8">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="3" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+<xf numFmtId="3" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1" applyNumberFormat="1"/>
+<xf numFmtId="4" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1" applyNumberFormat="1"/>
+<xf numFmtId="164" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1" applyNumberFormat="1"/>
+</cellXfs>
+<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>
+</styleSheet>`
+
+// WriteXLSX writes an Excel workbook with a sheet for every section
+func WriteXLSX(w io.Writer, report *Report) error {
+	archive := zip.NewWriter(w)
+
+	names := sheetNames(report.Sections)
+	var contentTypes, workbookSheets, workbookRels strings.Builder
+	for i, name := range names {
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		fmt.Fprintf(&workbookSheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(name), i+1, i+1)
+		fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	stylesID := len(names) + 1
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+` + contentTypes.String() + `
+</Types>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets>` + workbookSheets.String() + `</sheets>
+</workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+` + workbookRels.String() + fmt.Sprintf(`
+<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`, stylesID)},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for i, section := range report.Sections {
+		files = append(files, struct {
+			name    string
+			content string
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), worksheet(section)})
+	}
+
+	for _, file := range files {
+		part, err := archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(part, file.content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// sheetNames turns the section titles into unique names Excel accepts
+func sheetNames(sections []Section) []string {
+	names := make([]string, len(sections))
+	used := map[string]bool{}
+	for i, section := range sections {
+		name := strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`[]:*?/\`, r) {
+				return '_'
+			}
+			return r
+		}, strings.TrimSpace(section.Title))
+		if name == "" {
+			name = "Sheet"
+		}
+		name = truncateRunes(name, 31)
+
+		unique := name
+		for n := 2; used[strings.ToLower(unique)]; n++ {
+			suffix := fmt.Sprintf(" (%d)", n)
+			unique = truncateRunes(name, 31-len(suffix)) + suffix
+		}
+		used[strings.ToLower(unique)] = true
+		names[i] = unique
+	}
+	return names
+}
+
+func worksheet(section Section) string {
+	var rows [][]Cell
+	boldRows := map[int]bool{}
+	if len(section.Columns) > 0 {
+		rows = append(rows, Texts(section.Columns...))
+		boldRows[0] = true
+	}
+	rows = append(rows, section.Rows...)
+	if section.Total != nil {
+		boldRows[len(rows)] = true
+		rows = append(rows, section.Total)
+	}
+
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, make([]int, i+1-len(widths))...)
+			}
+			if length := utf8.RuneCountInString(cell.Text); length > widths[i] {
+				widths[i] = length
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if len(section.Columns) > 0 {
+		// Keep the header row in sight while scrolling
+		b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	}
+	if len(widths) > 0 {
+		b.WriteString("<cols>")
+		for i, width := range widths {
+			width += 2
+			if width > 60 {
+				width = 60
+			}
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width)
+		}
+		b.WriteString("</cols>")
+	}
+
+	b.WriteString("<sheetData>")
+	for r, row := range rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for i, cell := range row {
+			if cell.Text == "" {
+				continue
+			}
+			style := 0
+			if boldRows[r] {
+				style = 4
+			}
+			ref := fmt.Sprintf("%s%d", columnName(i), r+1)
+			if cell.Numeric {
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style+numberFormatStyle(cell.Decimals), cell.Text)
+			} else {
+				fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style, xmlEscape(cell.Text))
+			}
+		}
+		b.WriteString("</row>")
+	}
+	b.WriteString("</sheetData></worksheet>")
+	return b.String()
+}
+
+// numberFormatStyle picks the number format for the decimals, other numbers of decimals use the general format
+func numberFormatStyle(decimals int) int {
+	switch decimals {
+	case 0:
+		return 1
+	case 2:
+		return 2
+	case 3:
+		return 3
+	}
+	return 0
+}
+
+// columnName turns a zero based column index into A, B, ..., Z, AA, AB, ...
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// xmlEscape escapes text for XML, characters XML can't hold are replaced
+func xmlEscape(text string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(text))
+	return buf.String()
+}
+
+func truncateRunes(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	return string([]rune(text)[:limit])
+}
@@ -16,6 +16,7 @@ func SetupRouter(app *common.App) *gin.Engine {
 	// Public routes (no middleware)
 	r.POST("/register", func(c *gin.Context) { handlers.Register(c, app.DB) })
 	r.POST("/login", func(c *gin.Context) { handlers.Login(c, app.DB) })
+	r.POST("/auth/refresh", func(c *gin.Context) { handlers.RefreshToken(c, app.DB) })
 	r.POST("/password/reset-request", func(c *gin.Context) { handlers.RequestPasswordReset(c, app.DB) })
 	r.POST("/password/reset", func(c *gin.Context) { handlers.ResetPassword(c, app.DB) })
 	r.GET("/password/reset-redirect", func(c *gin.Context) { handlers.HandlePasswordResetRedirect(c, app.DB) })
@@ -28,31 +29,95 @@ func SetupRouter(app *common.App) *gin.Engine {
 
 	// Protected routes
 	protected := r.Group("/")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(app.DB))
 
 	// User profile routes
 	protected.GET("/profile", func(c *gin.Context) { handlers.GetProfile(c, app.DB) })
 	protected.PUT("/profile", func(c *gin.Context) { handlers.UpdateProfile(c, app.DB) })
 	protected.POST("/logout", func(c *gin.Context) { handlers.Logout(c, app.DB) })
 
+	// Session (signed-in devices) routes
+	protected.GET("/sessions", func(c *gin.Context) { handlers.GetSessions(c, app.DB) })
+	protected.DELETE("/sessions/others", func(c *gin.Context) { handlers.RevokeOtherSessions(c, app.DB) })
+	protected.DELETE("/sessions/:id", func(c *gin.Context) { handlers.RevokeSession(c, app.DB) })
+
+	// Availability routes
+	protected.GET("/availability", func(c *gin.Context) { handlers.GetAvailability(c, app.DB) })
+	protected.POST("/availability", func(c *gin.Context) { handlers.CreateAvailability(c, app.DB) })
+	protected.PUT("/availability/:id", func(c *gin.Context) { handlers.UpdateAvailability(c, app.DB) })
+	protected.DELETE("/availability/:id", func(c *gin.Context) { handlers.DeleteAvailability(c, app.DB) })
+
 	// Event routes
 	protected.GET("/events", func(c *gin.Context) { handlers.GetEvents(c, app.DB) })
 	protected.GET("/events/:id", func(c *gin.Context) { handlers.GetEvent(c, app.DB) })
 	protected.POST("/events", func(c *gin.Context) { handlers.CreateEvent(c, app.DB) })
 	protected.POST("/events/find_best_time_for_day", func(c *gin.Context) { handlers.FindBestTimeSlotsForDay(c, app.DB) })
+	protected.POST("/events/find_best_time", func(c *gin.Context) { handlers.FindBestTimeSlots(c, app.DB) })
 	protected.PUT("/events/:id", func(c *gin.Context) { handlers.UpdateEvent(c, app.DB) })
 	protected.DELETE("/events/:id", func(c *gin.Context) { handlers.DeleteEvent(c, app.DB) })
+	protected.POST("/events/:id/transfer", func(c *gin.Context) { handlers.TransferEventOwnership(c, app.DB) })
+	protected.POST("/events/:id/clone", func(c *gin.Context) { handlers.CloneEvent(c, app.DB) })
 	protected.GET("/events/:id/leaderboard", func(c *gin.Context) { handlers.GetEventLeaderboard(c, app.DB) })
 	protected.GET("/events/:id/participants", func(c *gin.Context) { handlers.GetEventParticipants(c, app.DB) })
+	protected.PUT("/events/:id/participants/:user_id/role", func(c *gin.Context) { handlers.UpdateParticipantRole(c, app.DB) })
+	protected.PUT("/events/:id/rsvp", func(c *gin.Context) { handlers.SetRSVP(c, app.DB) })
+	protected.GET("/events/:id/rsvp", func(c *gin.Context) { handlers.GetMyRSVP(c, app.DB) })
+	protected.GET("/events/:id/waitlist", func(c *gin.Context) { handlers.GetWaitlist(c, app.DB) })
 	protected.GET("/events/:id/budget", func(c *gin.Context) { handlers.GetEventBudget(c, app.DB) })
+	protected.GET("/events/:id/budget/export", func(c *gin.Context) { handlers.ExportEventBudget(c, app.DB) })
+	protected.GET("/events/:id/budget/categories", func(c *gin.Context) { handlers.GetBudgetCategories(c, app.DB) })
+	protected.POST("/events/:id/budget/categories", func(c *gin.Context) { handlers.CreateBudgetCategory(c, app.DB) })
+	protected.PUT("/events/:id/budget/categories/:category_id", func(c *gin.Context) { handlers.UpdateBudgetCategory(c, app.DB) })
+	protected.DELETE("/events/:id/budget/categories/:category_id", func(c *gin.Context) { handlers.DeleteBudgetCategory(c, app.DB) })
+
+	// Expense routes
+	protected.POST("/events/:id/expenses", func(c *gin.Context) { handlers.CreateExpense(c, app.DB) })
+	protected.GET("/events/:id/expenses", func(c *gin.Context) { handlers.GetExpenses(c, app.DB) })
+	protected.PUT("/events/:id/expenses/:expense_id", func(c *gin.Context) { handlers.UpdateExpense(c, app.DB) })
+	protected.DELETE("/events/:id/expenses/:expense_id", func(c *gin.Context) { handlers.DeleteExpense(c, app.DB) })
+	protected.POST("/events/:id/expenses/:expense_id/attachments", func(c *gin.Context) { handlers.UploadExpenseAttachment(c, app.DB) })
+	protected.GET("/events/:id/expenses/:expense_id/attachments", func(c *gin.Context) { handlers.GetExpenseAttachments(c, app.DB) })
+	protected.GET("/events/:id/balances", func(c *gin.Context) { handlers.GetBalances(c, app.DB) })
+	protected.POST("/events/:id/settlements", func(c *gin.Context) { handlers.CreateSettlement(c, app.DB) })
+	protected.DELETE("/events/:id/settlements/:settlement_id", func(c *gin.Context) { handlers.DeleteSettlement(c, app.DB) })
+
+	// Time poll routes
+	protected.POST("/events/:id/time-polls", func(c *gin.Context) { handlers.CreateTimePoll(c, app.DB) })
+	protected.GET("/events/:id/time-polls", func(c *gin.Context) { handlers.GetTimePolls(c, app.DB) })
+	protected.PUT("/events/:id/time-polls/:poll_id/votes", func(c *gin.Context) { handlers.VoteTimePoll(c, app.DB) })
+	protected.POST("/events/:id/time-polls/:poll_id/close", func(c *gin.Context) { handlers.CloseTimePoll(c, app.DB) })
+
+	// Event poll routes
+	protected.POST("/events/:id/polls", func(c *gin.Context) { handlers.CreatePoll(c, app.DB) })
+	protected.GET("/events/:id/polls", func(c *gin.Context) { handlers.GetPolls(c, app.DB) })
+	protected.GET("/events/:id/polls/:poll_id", func(c *gin.Context) { handlers.GetPoll(c, app.DB) })
+	protected.PUT("/events/:id/polls/:poll_id/votes", func(c *gin.Context) { handlers.VotePoll(c, app.DB) })
+	protected.POST("/events/:id/polls/:poll_id/close", func(c *gin.Context) { handlers.ClosePoll(c, app.DB) })
+	protected.DELETE("/events/:id/polls/:poll_id", func(c *gin.Context) { handlers.DeletePoll(c, app.DB) })
 
 	// Event invitation routes
 	protected.POST("/events/invite", func(c *gin.Context) { handlers.GenerateInviteLink(c, app.DB) })
+	protected.DELETE("/events/invite/:invite_code", func(c *gin.Context) { handlers.RevokeInviteLink(c, app.DB) })
+	protected.GET("/events/:id/invites", func(c *gin.Context) { handlers.GetEventInvitations(c, app.DB) })
+	protected.POST("/events/:id/invitations/email", func(c *gin.Context) { handlers.SendEmailInvitation(c, app.DB) })
+	protected.GET("/events/:id/invitations/email", func(c *gin.Context) { handlers.GetEventEmailInvitations(c, app.DB) })
+	protected.GET("/invitations/email", func(c *gin.Context) { handlers.GetMyEmailInvitations(c, app.DB) })
+	protected.POST("/invitations/email/:id/decline", func(c *gin.Context) { handlers.DeclineEmailInvitation(c, app.DB) })
 	protected.GET("/events/join/:invite_code", func(c *gin.Context) { handlers.JoinEvent(c, app.DB) })
+	protected.GET("/events/:id/join-requests", func(c *gin.Context) { handlers.GetJoinRequests(c, app.DB) })
+	protected.POST("/events/:id/join-requests/:request_id/approve", func(c *gin.Context) { handlers.ApproveJoinRequest(c, app.DB) })
+	protected.POST("/events/:id/join-requests/:request_id/reject", func(c *gin.Context) { handlers.RejectJoinRequest(c, app.DB) })
 	protected.DELETE("/events/:id/leave", func(c *gin.Context) { handlers.LeaveEvent(c, app.DB) })
 	// Add route for joining an event via query parameter (for iOS app deeplink handling)
 	protected.GET("/events/join", func(c *gin.Context) { handlers.JoinEvent(c, app.DB) })
 
+	// Event template routes
+	protected.GET("/templates", func(c *gin.Context) { handlers.GetEventTemplates(c, app.DB) })
+	protected.POST("/templates", func(c *gin.Context) { handlers.CreateEventTemplate(c, app.DB) })
+	protected.GET("/templates/:id", func(c *gin.Context) { handlers.GetEventTemplate(c, app.DB) })
+	protected.DELETE("/templates/:id", func(c *gin.Context) { handlers.DeleteEventTemplate(c, app.DB) })
+	protected.POST("/templates/:id/events", func(c *gin.Context) { handlers.InstantiateEventTemplate(c, app.DB) })
+
 	// Task routes
 	protected.GET("/tasks", func(c *gin.Context) { handlers.GetTasks(c, app.DB) })
 	protected.GET("/tasks/:id", func(c *gin.Context) { handlers.GetTask(c, app.DB) })
@@ -61,6 +126,13 @@ func SetupRouter(app *common.App) *gin.Engine {
 	protected.DELETE("/tasks/:id", func(c *gin.Context) { handlers.DeleteTask(c, app.DB) })
 	protected.PUT("/tasks/:id/assign", func(c *gin.Context) { handlers.AssignToTask(c, app.DB) })
 	protected.PUT("/tasks/:id/complete", func(c *gin.Context) { handlers.CompleteTask(c, app.DB) })
+	protected.POST("/tasks/:id/attachments", func(c *gin.Context) { handlers.UploadTaskAttachment(c, app.DB) })
+	protected.GET("/tasks/:id/attachments", func(c *gin.Context) { handlers.GetTaskAttachments(c, app.DB) })
+
+	// Attachment routes
+	protected.GET("/attachments/:id", func(c *gin.Context) { handlers.DownloadAttachment(c, app.DB) })
+	protected.GET("/attachments/:id/thumbnail", func(c *gin.Context) { handlers.DownloadAttachmentThumbnail(c, app.DB) })
+	protected.DELETE("/attachments/:id", func(c *gin.Context) { handlers.DeleteAttachment(c, app.DB) })
 
 	// Task status events routes
 	protected.GET("/task-status-events/unread", func(c *gin.Context) { handlers.GetUnreadTaskStatusEvents(c, app.DB) })
@@ -0,0 +1,121 @@
+// Package scheduling scores candidate times of an event against the busy intervals of its participants.
+// A candidate is the whole window [start, start+duration), a participant is busy for it when any of
+// their busy intervals overlaps that window.
+package scheduling
+
+import (
+	"sort"
+	"time"
+)
+
+// Interval is a half-open time range [Start, End)
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Overlaps reports whether the intervals share any moment, touching ends don't overlap
+func (i Interval) Overlaps(other Interval) bool {
+	return i.Start.Before(other.End) && other.Start.Before(i.End)
+}
+
+type Participant struct {
+	UserID uint
+	Busy   []Interval
+	// Optional participants never rule a slot out, they only make it score worse
+	Optional bool
+	// Weight of the participant in the score, zero counts as 1
+	Weight float64
+	// Preferred intervals, a participant without any has no preference
+	Preferred []Interval
+}
+
+func (p *Participant) weight() float64 {
+	if p.Weight <= 0 {
+		return 1
+	}
+	return p.Weight
+}
+
+func (p *Participant) isBusy(candidate Interval) bool {
+	for _, busy := range p.Busy {
+		if busy.Overlaps(candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// prefers reports whether the candidate lies inside one of the preferred intervals
+func (p *Participant) prefers(candidate Interval) bool {
+	for _, preferred := range p.Preferred {
+		if !candidate.Start.Before(preferred.Start) && !candidate.End.After(preferred.End) {
+			return true
+		}
+	}
+	return false
+}
+
+type Slot struct {
+	Interval
+	// BusyUserIDs are in the order of the participants
+	BusyUserIDs []uint
+	// RequiredBusy counts required participants who are busy
+	RequiredBusy int
+	// Score is the summed weight of busy participants, lower is better
+	Score float64
+	// OutsidePreferred counts participants with preferred intervals for whom the slot falls outside them
+	OutsidePreferred int
+}
+
+// Suggest evaluates every start time from the start of the window in steps, the event has to end
+// inside the window. The slots come in time order, use Rank to order them by preference.
+func Suggest(participants []Participant, window Interval, duration, step time.Duration) []Slot {
+	if step <= 0 || duration < 0 {
+		return nil
+	}
+
+	var slots []Slot
+	for start := window.Start; !start.Add(duration).After(window.End) && start.Before(window.End); start = start.Add(step) {
+		slot := Slot{Interval: Interval{Start: start, End: start.Add(duration)}, BusyUserIDs: []uint{}}
+		// A zero duration still needs a moment to be free
+		candidate := slot.Interval
+		if duration == 0 {
+			candidate.End = start.Add(time.Nanosecond)
+		}
+
+		for i := range participants {
+			participant := &participants[i]
+			if len(participant.Preferred) > 0 && !participant.prefers(candidate) {
+				slot.OutsidePreferred++
+			}
+			if !participant.isBusy(candidate) {
+				continue
+			}
+			slot.BusyUserIDs = append(slot.BusyUserIDs, participant.UserID)
+			slot.Score += participant.weight()
+			if !participant.Optional {
+				slot.RequiredBusy++
+			}
+		}
+		slots = append(slots, slot)
+	}
+	return slots
+}
+
+// Rank orders slots by the number of busy required participants, then by score, then by the number
+// of participants outside their preferred intervals and then by time
+func Rank(slots []Slot) {
+	sort.SliceStable(slots, func(i, j int) bool {
+		if slots[i].RequiredBusy != slots[j].RequiredBusy {
+			return slots[i].RequiredBusy < slots[j].RequiredBusy
+		}
+		if slots[i].Score != slots[j].Score {
+			return slots[i].Score < slots[j].Score
+		}
+		if slots[i].OutsidePreferred != slots[j].OutsidePreferred {
+			return slots[i].OutsidePreferred < slots[j].OutsidePreferred
+		}
+		return slots[i].Start.Before(slots[j].Start)
+	})
+}
@@ -10,30 +10,54 @@ import (
 
 var jwtSecret = []byte(os.Getenv("JWT_SECRET"))
 
-func GenerateToken(userID uint) (string, error) {
+// Access tokens are short-lived, the session is kept alive with refresh tokens
+const AccessTokenTTL = 15 * time.Minute
+const RefreshTokenTTL = 30 * 24 * time.Hour
+
+// GenerateToken issues an access token bound to the session identified by tokenID (jti claim)
+func GenerateToken(userID uint, tokenID string) (string, time.Time, error) {
+	expiresAt := time.Now().Add(AccessTokenTTL)
 	claims := jwt.MapClaims{
 		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * 24 * 30).Unix(), // Токен действует 30 дней
+		"jti":     tokenID,
+		"exp":     expiresAt.Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	signed, err := token.SignedString(jwtSecret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
 }
 
-func ValidateToken(tokenString string) (uint, error) {
+// ValidateToken checks the signature and expiry and returns user ID and jti claim
+func ValidateToken(tokenString string) (uint, string, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method")
+		}
 		return jwtSecret, nil
 	})
 
 	if err != nil || !token.Valid {
-		return 0, fmt.Errorf("invalid token")
+		return 0, "", fmt.Errorf("invalid token")
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return 0, fmt.Errorf("invalid claims")
+		return 0, "", fmt.Errorf("invalid claims")
+	}
+
+	rawUserID, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, "", fmt.Errorf("invalid claims")
+	}
+
+	tokenID, ok := claims["jti"].(string)
+	if !ok || tokenID == "" {
+		return 0, "", fmt.Errorf("invalid claims")
 	}
 
-	userID := uint(claims["user_id"].(float64))
-	return userID, nil
+	return uint(rawUserID), tokenID, nil
 }
@@ -1,10 +1,14 @@
 package email
 
 import (
+	"bytes"
 	"crypto/tls"
 	"fmt"
+	"html/template"
+	"mime"
 	"net/smtp"
 	"os"
+	"strings"
 )
 
 type EmailConfig struct {
@@ -35,7 +39,10 @@ func Init() error {
 	return nil
 }
 
-func SendPasswordResetEmail(toEmail, resetToken string) error {
+// SendMail delivers an HTML email, it is a variable so tests can replace the SMTP delivery
+var SendMail = sendSMTP
+
+func sendSMTP(toEmail, subject, body string) error {
 	tlsConfig := &tls.Config{
 		ServerName: config.SMTPHost,
 		MinVersion: tls.VersionTLS12,
@@ -71,6 +78,25 @@ func SendPasswordResetEmail(toEmail, resetToken string) error {
 	}
 	defer writer.Close()
 
+	// Headers are ASCII, names in other scripts are sent as RFC 2047 encoded words
+	subject = mime.QEncoding.Encode("utf-8", subject)
+	msg := fmt.Sprintf("From: %s\r\n"+
+		"To: %s\r\n"+
+		"Subject: %s\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/html; charset=UTF-8\r\n"+
+		"\r\n"+
+		"%s\r\n", config.FromEmail, toEmail, subject, body)
+
+	_, err = writer.Write([]byte(msg))
+	if err != nil {
+		return fmt.Errorf("failed to write email message: %v", err)
+	}
+
+	return nil
+}
+
+func SendPasswordResetEmail(toEmail, resetToken string) error {
 	subject := "Password Reset Request"
 	resetLink := fmt.Sprintf("http://localhost:8080/password/reset-redirect?token=%s", resetToken)
 	body := fmt.Sprintf(`
@@ -88,18 +114,104 @@ func SendPasswordResetEmail(toEmail, resetToken string) error {
 		</html>
 	`, resetLink)
 
-	msg := fmt.Sprintf("From: %s\r\n"+
-		"To: %s\r\n"+
-		"Subject: %s\r\n"+
-		"MIME-Version: 1.0\r\n"+
-		"Content-Type: text/html; charset=UTF-8\r\n"+
-		"\r\n"+
-		"%s\r\n", config.FromEmail, toEmail, subject, body)
+	return SendMail(toEmail, subject, body)
+}
 
-	_, err = writer.Write([]byte(msg))
-	if err != nil {
-		return fmt.Errorf("failed to write email message: %v", err)
+// headerText puts user input on one line, line breaks would end the Subject header
+func headerText(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
+
+// Event and inviter names come from users, html/template escapes them
+var eventInvitationTemplate = template.Must(template.New("event_invitation").Parse(`
+		<html>
+		<head>
+			<meta charset="UTF-8">
+		</head>
+		<body>
+			<h2>You are invited to {{.EventName}}</h2>
+			<p>{{.InviterName}} invited you to join the event "{{.EventName}}" in ItsPlanned.</p>
+			{{if .EventDateTime}}<p>When: {{.EventDateTime}}</p>{{end}}
+			<p><a href="{{.InviteLink}}">Join the event</a></p>
+			<p>If you don't have an account yet, register with this email address and the invitation will be waiting for you.</p>
+		</body>
+		</html>
+`))
+
+type EventInvitationEmail struct {
+	EventName     string
+	EventDateTime string
+	InviterName   string
+	InviteLink    string
+}
+
+func SendEventInvitationEmail(toEmail string, invitation EventInvitationEmail) error {
+	var body bytes.Buffer
+	if err := eventInvitationTemplate.Execute(&body, invitation); err != nil {
+		return fmt.Errorf("failed to render invitation email: %v", err)
 	}
 
-	return nil
+	subject := fmt.Sprintf("Invitation to %s", headerText(invitation.EventName))
+	return SendMail(toEmail, subject, body.String())
+}
+
+var taskReminderTemplate = template.Must(template.New("task_reminder").Parse(`
+		<html>
+		<head>
+			<meta charset="UTF-8">
+		</head>
+		<body>
+			<h2>{{.TaskName}} is due soon</h2>
+			<p>Your task "{{.TaskName}}" for the event "{{.EventName}}" is due {{.DueAt}}.</p>
+			<p>Mark it as completed in ItsPlanned once it is done.</p>
+		</body>
+		</html>
+`))
+
+type TaskReminderEmail struct {
+	TaskName  string
+	EventName string
+	DueAt     string
+}
+
+func SendTaskReminderEmail(toEmail string, reminder TaskReminderEmail) error {
+	var body bytes.Buffer
+	if err := taskReminderTemplate.Execute(&body, reminder); err != nil {
+		return fmt.Errorf("failed to render task reminder email: %v", err)
+	}
+
+	subject := fmt.Sprintf("Reminder: %s is due soon", headerText(reminder.TaskName))
+	return SendMail(toEmail, subject, body.String())
+}
+
+var unassignedTasksTemplate = template.Must(template.New("unassigned_tasks").Parse(`
+		<html>
+		<head>
+			<meta charset="UTF-8">
+		</head>
+		<body>
+			<h2>{{.EventName}} has unassigned tasks</h2>
+			<p>The event "{{.EventName}}" starts {{.EventDateTime}}, nobody has taken these tasks yet:</p>
+			<ul>
+			{{range .TaskNames}}<li>{{.}}</li>
+			{{end}}</ul>
+			<p>Assign them to participants in ItsPlanned.</p>
+		</body>
+		</html>
+`))
+
+type UnassignedTasksEmail struct {
+	EventName     string
+	EventDateTime string
+	TaskNames     []string
+}
+
+func SendUnassignedTasksEmail(toEmail string, reminder UnassignedTasksEmail) error {
+	var body bytes.Buffer
+	if err := unassignedTasksTemplate.Execute(&body, reminder); err != nil {
+		return fmt.Errorf("failed to render unassigned tasks email: %v", err)
+	}
+
+	subject := fmt.Sprintf("Unassigned tasks for %s", headerText(reminder.EventName))
+	return SendMail(toEmail, subject, body.String())
 }
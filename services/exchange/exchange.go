@@ -0,0 +1,114 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"itsplanned/money"
+	"log"
+	"os"
+	"sync"
+)
+
+// Provider gives exchange rates between currencies, the rate is how much of the target currency one unit of the source one buys
+type Provider interface {
+	Rate(from, to string) (float64, error)
+}
+
+// StaticProvider knows the value of currencies against a base currency, it works offline
+type StaticProvider struct {
+	Base string `json:"base"`
+	// Rates is how much of each currency one unit of the base buys
+	Rates map[string]float64 `json:"rates"`
+}
+
+// Rate crosses the rates of both currencies through the base
+func (p *StaticProvider) Rate(from, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	perBase := func(currency string) (float64, bool) {
+		if currency == p.Base {
+			return 1, true
+		}
+		rate, ok := p.Rates[currency]
+		return rate, ok && rate > 0
+	}
+
+	fromRate, fromOK := perBase(from)
+	toRate, toOK := perBase(to)
+	if !fromOK || !toOK {
+		return 0, fmt.Errorf("no exchange rate from %s to %s", from, to)
+	}
+	return toRate / fromRate, nil
+}
+
+// LoadFile reads a StaticProvider from a JSON file like {"base": "EUR", "rates": {"USD": 1.08}}
+func LoadFile(path string) (*StaticProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates: %w", err)
+	}
+
+	var provider StaticProvider
+	if err := json.Unmarshal(data, &provider); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rates: %w", err)
+	}
+
+	provider.Base = money.Normalize(provider.Base)
+	rates := make(map[string]float64, len(provider.Rates))
+	for currency, rate := range provider.Rates {
+		currency = money.Normalize(currency)
+		if !money.IsSupported(currency) || rate <= 0 {
+			return nil, fmt.Errorf("invalid exchange rate for %s", currency)
+		}
+		rates[currency] = rate
+	}
+	provider.Rates = rates
+	return &provider, nil
+}
+
+var (
+	current      Provider = &StaticProvider{Base: money.DefaultCurrency}
+	providerLock sync.RWMutex
+)
+
+// Init loads the rates from EXCHANGE_RATES_FILE, without it only amounts in the same currency convert
+func Init() error {
+	path := os.Getenv("EXCHANGE_RATES_FILE")
+	if path == "" {
+		log.Println("EXCHANGE_RATES_FILE is not set, expenses in other currencies than the event's can't be converted")
+		return nil
+	}
+
+	provider, err := LoadFile(path)
+	if err != nil {
+		return err
+	}
+	SetProvider(provider)
+	return nil
+}
+
+// SetProvider replaces the source of exchange rates
+func SetProvider(provider Provider) {
+	providerLock.Lock()
+	defer providerLock.Unlock()
+	current = provider
+}
+
+// Convert turns minor units of one currency into minor units of another with the current provider
+func Convert(minor int64, from, to string) (int64, error) {
+	if from == to {
+		return minor, nil
+	}
+
+	providerLock.RLock()
+	provider := current
+	providerLock.RUnlock()
+
+	rate, err := provider.Rate(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return money.Convert(minor, from, to, rate), nil
+}
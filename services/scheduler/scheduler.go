@@ -71,6 +71,18 @@ func (s *Scheduler) SetupCalendarSyncTask() {
 	})
 }
 
+func (s *Scheduler) SetupPollDeadlineTask() {
+	s.AddTask(time.Minute, func() {
+		handlers.CloseExpiredPolls(s.db)
+	})
+}
+
+func (s *Scheduler) SetupTaskReminderTask() {
+	s.AddTask(5*time.Minute, func() {
+		handlers.SendTaskReminders(s.db, time.Now())
+	})
+}
+
 func (s *Scheduler) SyncCalendarEvents() {
 	var tokens []models.UserToken
 	if err := s.db.Find(&tokens).Error; err != nil {
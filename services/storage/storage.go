@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+)
+
+// ErrNotFound is returned when there is no object under the key
+var ErrNotFound = errors.New("object not found")
+
+// Backend keeps uploaded files under slash separated keys
+type Backend interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+const defaultStoragePath = "./uploads"
+
+var (
+	current     Backend = &LocalStorage{Root: defaultStoragePath}
+	backendLock sync.RWMutex
+)
+
+// Init picks the backend from STORAGE_BACKEND, files are kept on the local disk unless it is "s3"
+func Init() error {
+	switch os.Getenv("STORAGE_BACKEND") {
+	case "", "local":
+		root := os.Getenv("STORAGE_PATH")
+		if root == "" {
+			root = defaultStoragePath
+		}
+		log.Println("Storing uploads in", root)
+		SetBackend(&LocalStorage{Root: root})
+		return nil
+	case "s3":
+		backend := &S3Storage{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		}
+		if backend.Region == "" {
+			backend.Region = defaultS3Region
+		}
+		if backend.Endpoint == "" || backend.Bucket == "" || backend.AccessKey == "" || backend.SecretKey == "" {
+			return errors.New("S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY must be set for the s3 storage backend")
+		}
+		log.Println("Storing uploads in bucket", backend.Bucket, "at", backend.Endpoint)
+		SetBackend(backend)
+		return nil
+	default:
+		return fmt.Errorf("unknown storage backend %q", os.Getenv("STORAGE_BACKEND"))
+	}
+}
+
+// SetBackend replaces the place uploads are kept
+func SetBackend(backend Backend) {
+	backendLock.Lock()
+	defer backendLock.Unlock()
+	current = backend
+}
+
+// Current returns the backend uploads are kept in
+func Current() Backend {
+	backendLock.RLock()
+	defer backendLock.RUnlock()
+	return current
+}
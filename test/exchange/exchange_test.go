@@ -0,0 +1,75 @@
+package exchange_test
+
+import (
+	"itsplanned/services/exchange"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticProvider(t *testing.T) {
+	provider := &exchange.StaticProvider{Base: "EUR", Rates: map[string]float64{"USD": 1.25, "RUB": 100}}
+
+	testCases := []struct {
+		name     string
+		from     string
+		to       string
+		expected float64
+		wantErr  bool
+	}{
+		{name: "Same currency", from: "GBP", to: "GBP", expected: 1},
+		{name: "From the base", from: "EUR", to: "USD", expected: 1.25},
+		{name: "To the base", from: "USD", to: "EUR", expected: 0.8},
+		{name: "Crossed through the base", from: "USD", to: "RUB", expected: 80},
+		{name: "Unknown currency", from: "USD", to: "GBP", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rate, err := provider.Rate(tc.from, tc.to)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.expected, rate, 1e-9)
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "rates.json")
+	assert.NoError(t, os.WriteFile(valid, []byte(`{"base": "eur", "rates": {"usd": 1.25}}`), 0o600))
+	provider, err := exchange.LoadFile(valid)
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", provider.Base)
+	assert.Equal(t, map[string]float64{"USD": 1.25}, provider.Rates)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, os.WriteFile(invalid, []byte(`{"base": "EUR", "rates": {"USD": -1}}`), 0o600))
+	_, err = exchange.LoadFile(invalid)
+	assert.Error(t, err)
+
+	_, err = exchange.LoadFile(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestConvert(t *testing.T) {
+	exchange.SetProvider(&exchange.StaticProvider{Base: "EUR", Rates: map[string]float64{"JPY": 160}})
+	defer exchange.SetProvider(&exchange.StaticProvider{Base: "RUB"})
+
+	converted, err := exchange.Convert(1050, "EUR", "JPY")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1680), converted)
+
+	converted, err = exchange.Convert(1050, "USD", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1050), converted)
+
+	_, err = exchange.Convert(1050, "USD", "JPY")
+	assert.Error(t, err)
+}
@@ -0,0 +1,398 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"itsplanned/handlers"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/services/storage"
+	"itsplanned/test"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var testPDF = []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\ntrailer << /Root 1 0 R >>\n%%EOF\n")
+
+// useTestStorage keeps uploads of the test in a temporary directory
+func useTestStorage(t *testing.T) storage.Backend {
+	backend := &storage.LocalStorage{Root: t.TempDir()}
+	previous := storage.Current()
+	storage.SetBackend(backend)
+	t.Cleanup(func() { storage.SetBackend(previous) })
+	return backend
+}
+
+func testPNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// callAttachmentUpload sends the file as multipart form data, an empty file name sends no file at all
+func callAttachmentUpload(t *testing.T, userID uint, params gin.Params, fileName string, data []byte, handler func(*gin.Context, *gorm.DB)) (*httptest.ResponseRecorder, api.APIResponse) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if fileName != "" {
+		part, err := writer.CreateFormFile("file", fileName)
+		assert.NoError(t, err)
+		_, err = part.Write(data)
+		assert.NoError(t, err)
+	} else {
+		assert.NoError(t, writer.WriteField("note", "no file"))
+	}
+	assert.NoError(t, writer.Close())
+
+	c, w := test.CreateTestContext(t, userID)
+	c.Request = httptest.NewRequest("POST", "/attachments", &body)
+	c.Request.Header.Set("Content-Type", writer.FormDataContentType())
+	c.Params = params
+
+	handler(c, test.TestDB)
+
+	var response api.APIResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return w, response
+}
+
+func callAttachment(t *testing.T, userID, attachmentID uint, handler func(*gin.Context, *gorm.DB)) *httptest.ResponseRecorder {
+	c, w := test.CreateTestContext(t, userID)
+	c.Request = httptest.NewRequest("GET", fmt.Sprintf("/attachments/%d", attachmentID), nil)
+	c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", attachmentID)}}
+	handler(c, test.TestDB)
+	return w
+}
+
+func taskParams(taskID uint) gin.Params {
+	return gin.Params{{Key: "id", Value: fmt.Sprintf("%d", taskID)}}
+}
+
+func TestUploadTaskAttachment(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+	useTestStorage(t)
+
+	organizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	viewer := test.CreateTestUser(t)
+	outsider := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+	test.AddEventParticipantWithRole(t, event.ID, viewer.ID, models.RoleViewer)
+	task := test.CreateTestTask(t, event.ID)
+
+	image := testPNG(t, 40, 20)
+	damaged := append(append([]byte{}, image[:40]...), bytes.Repeat([]byte{0}, 100)...)
+
+	tests := []struct {
+		name          string
+		userID        uint
+		params        gin.Params
+		fileName      string
+		data          []byte
+		expectedCode  int
+		expectedError string
+		expectedType  string
+		withThumbnail bool
+	}{
+		{
+			name:          "Image gets a thumbnail",
+			userID:        participant.ID,
+			params:        taskParams(task.ID),
+			fileName:      "receipt.png",
+			data:          image,
+			expectedCode:  http.StatusOK,
+			expectedType:  "image/png",
+			withThumbnail: true,
+		},
+		{
+			name:         "Document whatever its name",
+			userID:       organizer.ID,
+			params:       taskParams(task.ID),
+			fileName:     "../../invoice.bin",
+			data:         testPDF,
+			expectedCode: http.StatusOK,
+			expectedType: "application/pdf",
+		},
+		{
+			name:          "Type is sniffed from the content",
+			userID:        participant.ID,
+			params:        taskParams(task.ID),
+			fileName:      "receipt.png",
+			data:          []byte("<html><script>alert(1)</script></html>"),
+			expectedCode:  http.StatusUnsupportedMediaType,
+			expectedError: "Only JPEG, PNG, GIF and WebP images and PDF documents can be attached",
+		},
+		{
+			name:          "Damaged image",
+			userID:        participant.ID,
+			params:        taskParams(task.ID),
+			fileName:      "receipt.png",
+			data:          damaged,
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "The image is damaged and can't be read",
+		},
+		{
+			name:          "Too large",
+			userID:        participant.ID,
+			params:        taskParams(task.ID),
+			fileName:      "scan.pdf",
+			data:          append(append([]byte{}, testPDF...), make([]byte, 10<<20)...),
+			expectedCode:  http.StatusRequestEntityTooLarge,
+			expectedError: "Files can't be larger than 10 MB",
+		},
+		{
+			name:          "Empty file",
+			userID:        participant.ID,
+			params:        taskParams(task.ID),
+			fileName:      "empty.pdf",
+			data:          []byte{},
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "The file is empty",
+		},
+		{
+			name:          "No file",
+			userID:        participant.ID,
+			params:        taskParams(task.ID),
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "Attach the file in the \"file\" form field",
+		},
+		{
+			name:          "Viewer",
+			userID:        viewer.ID,
+			params:        taskParams(task.ID),
+			fileName:      "receipt.png",
+			data:          image,
+			expectedCode:  http.StatusForbidden,
+			expectedError: "Viewers can't attach files",
+		},
+		{
+			name:          "Not a participant",
+			userID:        outsider.ID,
+			params:        taskParams(task.ID),
+			fileName:      "receipt.png",
+			data:          image,
+			expectedCode:  http.StatusForbidden,
+			expectedError: "You are not a participant of this event",
+		},
+		{
+			name:          "Task not found",
+			userID:        participant.ID,
+			params:        taskParams(999),
+			fileName:      "receipt.png",
+			data:          image,
+			expectedCode:  http.StatusNotFound,
+			expectedError: "Task not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, response := callAttachmentUpload(t, tt.userID, tt.params, tt.fileName, tt.data, handlers.UploadTaskAttachment)
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.expectedError, response.Error)
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+
+			var attachment models.Attachment
+			assert.NoError(t, test.TestDB.First(&attachment, responseID(t, response)).Error)
+			assert.Equal(t, event.ID, attachment.EventID)
+			assert.Equal(t, task.ID, *attachment.TaskID)
+			assert.Nil(t, attachment.ExpenseID)
+			assert.Equal(t, tt.userID, attachment.UploadedByID)
+			assert.Equal(t, tt.expectedType, attachment.ContentType)
+			assert.Equal(t, int64(len(tt.data)), attachment.Size)
+			assert.Equal(t, tt.withThumbnail, attachment.ThumbnailKey != "")
+
+			data := response.Data.(map[string]interface{})
+			assert.Equal(t, fmt.Sprintf("/attachments/%d", attachment.ID), data["url"])
+			if tt.withThumbnail {
+				assert.Equal(t, fmt.Sprintf("/attachments/%d/thumbnail", attachment.ID), data["thumbnail_url"])
+			} else {
+				assert.NotContains(t, data, "thumbnail_url")
+			}
+		})
+	}
+
+	var names []string
+	assert.NoError(t, test.TestDB.Model(&models.Attachment{}).Order("id").Pluck("file_name", &names).Error)
+	assert.Equal(t, []string{"receipt.png", "invoice.bin"}, names)
+}
+
+func TestAttachmentAccess(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+	backend := useTestStorage(t)
+
+	organizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	viewer := test.CreateTestUser(t)
+	outsider := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+	test.AddEventParticipantWithRole(t, event.ID, viewer.ID, models.RoleViewer)
+	task := test.CreateTestTask(t, event.ID)
+
+	image := testPNG(t, 600, 300)
+	w, response := callAttachmentUpload(t, participant.ID, taskParams(task.ID), "receipt.png", image, handlers.UploadTaskAttachment)
+	assert.Equal(t, http.StatusOK, w.Code)
+	imageID := responseID(t, response)
+
+	w, response = callAttachmentUpload(t, participant.ID, taskParams(task.ID), "invoice.pdf", testPDF, handlers.UploadTaskAttachment)
+	assert.Equal(t, http.StatusOK, w.Code)
+	pdfID := responseID(t, response)
+
+	// Every participant can download, outsiders can't
+	w = callAttachment(t, viewer.ID, imageID, handlers.DownloadAttachment)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, `inline; filename=receipt.png`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, image, w.Body.Bytes())
+
+	w = callAttachment(t, outsider.ID, imageID, handlers.DownloadAttachment)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = callAttachment(t, outsider.ID, imageID, handlers.DownloadAttachmentThumbnail)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// The thumbnail keeps the aspect ratio
+	w = callAttachment(t, viewer.ID, imageID, handlers.DownloadAttachmentThumbnail)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/jpeg", w.Header().Get("Content-Type"))
+	thumb, err := jpeg.DecodeConfig(bytes.NewReader(w.Body.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, 256, thumb.Width)
+	assert.Equal(t, 128, thumb.Height)
+
+	w = callAttachment(t, viewer.ID, pdfID, handlers.DownloadAttachmentThumbnail)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	c, w := test.CreateTestContext(t, outsider.ID)
+	c.Request = httptest.NewRequest("GET", fmt.Sprintf("/tasks/%d/attachments", task.ID), nil)
+	c.Params = taskParams(task.ID)
+	handlers.GetTaskAttachments(c, test.TestDB)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	c, w = test.CreateTestContext(t, viewer.ID)
+	c.Request = httptest.NewRequest("GET", fmt.Sprintf("/tasks/%d/attachments", task.ID), nil)
+	c.Params = taskParams(task.ID)
+	handlers.GetTaskAttachments(c, test.TestDB)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Data api.AttachmentsResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Len(t, list.Data.Attachments, 2)
+	assert.Equal(t, imageID, list.Data.Attachments[0].ID)
+	assert.Equal(t, pdfID, list.Data.Attachments[1].ID)
+
+	// Only the uploader and organizers can delete
+	c, w = test.CreateTestContext(t, viewer.ID)
+	c.Request = httptest.NewRequest("DELETE", fmt.Sprintf("/attachments/%d", imageID), nil)
+	c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", imageID)}}
+	handlers.DeleteAttachment(c, test.TestDB)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var stored models.Attachment
+	assert.NoError(t, test.TestDB.First(&stored, imageID).Error)
+
+	c, w = test.CreateTestContext(t, organizer.ID)
+	c.Request = httptest.NewRequest("DELETE", fmt.Sprintf("/attachments/%d", imageID), nil)
+	c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", imageID)}}
+	handlers.DeleteAttachment(c, test.TestDB)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	_, err = backend.Get(c.Request.Context(), stored.StorageKey)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = backend.Get(c.Request.Context(), stored.ThumbnailKey)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	w = callAttachment(t, participant.ID, imageID, handlers.DownloadAttachment)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Deleting the task removes what is attached to it
+	var pdf models.Attachment
+	assert.NoError(t, test.TestDB.First(&pdf, pdfID).Error)
+	c, w = test.CreateTestContext(t, organizer.ID)
+	c.Request = httptest.NewRequest("DELETE", fmt.Sprintf("/tasks/%d", task.ID), nil)
+	c.Params = taskParams(task.ID)
+	handlers.DeleteTask(c, test.TestDB)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var count int64
+	test.TestDB.Model(&models.Attachment{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+	_, err = backend.Get(c.Request.Context(), pdf.StorageKey)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestExpenseAttachments(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+	backend := useTestStorage(t)
+
+	organizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	outsider := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	otherEvent := test.CreateTestEvent(t, outsider.ID)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	expenseID := createExpense(t, participant.ID, event.ID, api.ExpenseRequest{Amount: 25})
+	params := gin.Params{{Key: "id", Value: fmt.Sprintf("%d", event.ID)}, {Key: "expense_id", Value: fmt.Sprintf("%d", expenseID)}}
+
+	w, response := callAttachmentUpload(t, participant.ID, params, "receipt.png", testPNG(t, 10, 10), handlers.UploadExpenseAttachment)
+	assert.Equal(t, http.StatusOK, w.Code)
+	attachmentID := responseID(t, response)
+
+	// The expense has to belong to the event in the path
+	otherParams := gin.Params{{Key: "id", Value: fmt.Sprintf("%d", otherEvent.ID)}, {Key: "expense_id", Value: fmt.Sprintf("%d", expenseID)}}
+	w, response = callAttachmentUpload(t, outsider.ID, otherParams, "receipt.png", testPNG(t, 10, 10), handlers.UploadExpenseAttachment)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "Expense not found", response.Error)
+
+	w, _ = callAttachmentUpload(t, outsider.ID, params, "receipt.png", testPNG(t, 10, 10), handlers.UploadExpenseAttachment)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	c, w := test.CreateTestContext(t, organizer.ID)
+	c.Request = httptest.NewRequest("GET", "/attachments", nil)
+	c.Params = params
+	handlers.GetExpenseAttachments(c, test.TestDB)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Data api.AttachmentsResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Len(t, list.Data.Attachments, 1)
+	assert.Equal(t, attachmentID, list.Data.Attachments[0].ID)
+	assert.Equal(t, expenseID, *list.Data.Attachments[0].ExpenseID)
+
+	var stored models.Attachment
+	assert.NoError(t, test.TestDB.First(&stored, attachmentID).Error)
+
+	w, _ = test.CallHandler(t, participant.ID, "DELETE", fmt.Sprintf("/events/%d/expenses/%d", event.ID, expenseID), expenseParams(event.ID, expenseID), nil, handlers.DeleteExpense)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var count int64
+	test.TestDB.Model(&models.Attachment{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+	_, err := backend.Get(c.Request.Context(), stored.StorageKey)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
@@ -0,0 +1,235 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"itsplanned/handlers"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func createAvailability(t *testing.T, userID uint, request api.AvailabilityRequest) (*httptest.ResponseRecorder, api.APIResponse) {
+	return test.CallHandler(t, userID, "POST", "/availability", nil, request,
+		handlers.CreateAvailability)
+}
+
+func TestCreateAvailability(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	user := test.CreateTestUser(t)
+	tuesday := 2
+	invalidWeekday := 7
+	startsAt := time.Date(2024, 4, 5, 9, 0, 0, 0, time.UTC)
+	endsAt := startsAt.Add(48 * time.Hour)
+
+	tests := []struct {
+		name         string
+		request      api.AvailabilityRequest
+		expectedCode int
+	}{
+		{
+			name:         "Weekly unavailable window",
+			request:      api.AvailabilityRequest{Kind: models.AvailabilityUnavailable, Weekday: &tuesday, StartTime: "18:00", EndTime: "22:00", Note: "Evening classes"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Preferred hours",
+			request:      api.AvailabilityRequest{Kind: models.AvailabilityPreferred, Weekday: &tuesday, StartTime: "09:00", EndTime: "12:00"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "One-off block",
+			request:      api.AvailabilityRequest{Kind: models.AvailabilityBlock, StartsAt: &startsAt, EndsAt: &endsAt},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Unknown kind",
+			request:      api.AvailabilityRequest{Kind: "busy", Weekday: &tuesday, StartTime: "18:00", EndTime: "22:00"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Weekday out of range",
+			request:      api.AvailabilityRequest{Kind: models.AvailabilityUnavailable, Weekday: &invalidWeekday, StartTime: "18:00", EndTime: "22:00"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Missing weekday",
+			request:      api.AvailabilityRequest{Kind: models.AvailabilityPreferred, StartTime: "09:00", EndTime: "12:00"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Window ends before it starts",
+			request:      api.AvailabilityRequest{Kind: models.AvailabilityUnavailable, Weekday: &tuesday, StartTime: "22:00", EndTime: "18:00"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Invalid time format",
+			request:      api.AvailabilityRequest{Kind: models.AvailabilityUnavailable, Weekday: &tuesday, StartTime: "6pm", EndTime: "22:00"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Block without end",
+			request:      api.AvailabilityRequest{Kind: models.AvailabilityBlock, StartsAt: &startsAt},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Block ends before it starts",
+			request:      api.AvailabilityRequest{Kind: models.AvailabilityBlock, StartsAt: &endsAt, EndsAt: &startsAt},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Block with a weekday",
+			request:      api.AvailabilityRequest{Kind: models.AvailabilityBlock, Weekday: &tuesday, StartsAt: &startsAt, EndsAt: &endsAt},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w, _ := createAvailability(t, user.ID, tc.request)
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+
+	c, w := test.CreateTestContext(t, user.ID)
+	c.Request = httptest.NewRequest("GET", "/availability", nil)
+	handlers.GetAvailability(c, test.TestDB)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response api.AvailabilitiesResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Availability, 3)
+}
+
+func TestUpdateAndDeleteAvailability(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	user := test.CreateTestUser(t)
+	otherUser := test.CreateTestUser(t)
+	tuesday, wednesday := 2, 3
+
+	_, created := createAvailability(t, user.ID, api.AvailabilityRequest{Kind: models.AvailabilityUnavailable, Weekday: &tuesday, StartTime: "18:00", EndTime: "22:00"})
+	id := fmt.Sprintf("%d", responseID(t, created))
+
+	update := func(userID uint, request api.AvailabilityRequest) *httptest.ResponseRecorder {
+		w, _ := test.CallHandler(t, userID, "PUT", "/availability/"+id, gin.Params{{Key: "id", Value: id}}, request,
+			handlers.UpdateAvailability)
+		return w
+	}
+
+	t.Run("Other user cannot update", func(t *testing.T) {
+		w := update(otherUser.ID, api.AvailabilityRequest{Kind: models.AvailabilityPreferred, Weekday: &wednesday, StartTime: "10:00", EndTime: "12:00"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Invalid update keeps the entry", func(t *testing.T) {
+		w := update(user.ID, api.AvailabilityRequest{Kind: models.AvailabilityPreferred, Weekday: &wednesday, StartTime: "12:00", EndTime: "10:00"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var availability models.Availability
+		assert.NoError(t, test.TestDB.First(&availability, id).Error)
+		assert.Equal(t, models.AvailabilityUnavailable, availability.Kind)
+	})
+
+	t.Run("Owner replaces the entry", func(t *testing.T) {
+		w := update(user.ID, api.AvailabilityRequest{Kind: models.AvailabilityPreferred, Weekday: &wednesday, StartTime: "10:00", EndTime: "12:00"})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var availability models.Availability
+		assert.NoError(t, test.TestDB.First(&availability, id).Error)
+		assert.Equal(t, models.AvailabilityPreferred, availability.Kind)
+		assert.Equal(t, wednesday, *availability.Weekday)
+		assert.Equal(t, "10:00", availability.StartTime)
+	})
+
+	remove := func(userID uint) *httptest.ResponseRecorder {
+		c, w := test.CreateTestContext(t, userID)
+		c.Request = httptest.NewRequest("DELETE", "/availability/"+id, nil)
+		c.Params = []gin.Param{{Key: "id", Value: id}}
+		handlers.DeleteAvailability(c, test.TestDB)
+		return w
+	}
+
+	t.Run("Other user cannot delete", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, remove(otherUser.ID).Code)
+	})
+
+	t.Run("Owner deletes the entry", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, remove(user.ID).Code)
+		var count int64
+		test.TestDB.Model(&models.Availability{}).Where("id = ?", id).Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+}
+
+func TestFindBestTimeSlotsWithAvailability(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	// 2024-04-01 is a Monday
+	monday := 1
+	blockStart := time.Date(2024, 4, 1, 11, 0, 0, 0, time.UTC)
+	blockEnd := blockStart.Add(time.Hour)
+	for _, request := range []struct {
+		userID  uint
+		request api.AvailabilityRequest
+	}{
+		{participant.ID, api.AvailabilityRequest{Kind: models.AvailabilityUnavailable, Weekday: &monday, StartTime: "10:00", EndTime: "11:00"}},
+		{participant.ID, api.AvailabilityRequest{Kind: models.AvailabilityBlock, StartsAt: &blockStart, EndsAt: &blockEnd}},
+		{organizer.ID, api.AvailabilityRequest{Kind: models.AvailabilityPreferred, Weekday: &monday, StartTime: "13:00", EndTime: "15:00"}},
+	} {
+		w, _ := createAvailability(t, request.userID, request.request)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	c, w := test.CreateTestContext(t, organizer.ID)
+	requestJSON, err := json.Marshal(api.FindBestTimeSlotsRequest{
+		EventID:      event.ID,
+		Date:         "2024-04-01",
+		DurationMins: 60,
+		StartTime:    "09:00",
+		EndTime:      "16:00",
+	})
+	assert.NoError(t, err)
+
+	c.Request = httptest.NewRequest("POST", "/events/find_best_time_for_day", bytes.NewBuffer(requestJSON))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handlers.FindBestTimeSlotsForDay(c, test.TestDB)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response api.FindBestTimeSlotsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	var slots []string
+	for _, suggestion := range response.Suggestions {
+		assert.Equal(t, 0, suggestion.BusyCount)
+		slots = append(slots, suggestion.Slot)
+	}
+	// Free slots inside the preferred hours come first, the unavailable window and the block keep 09:30-11:30 out
+	assert.Equal(t, []string{
+		"2024-04-01 13:00",
+		"2024-04-01 13:30",
+		"2024-04-01 14:00",
+		"2024-04-01 09:00",
+		"2024-04-01 12:00",
+	}, slots)
+	assert.Equal(t, 0, response.Suggestions[0].OutsidePreferredCount)
+	assert.Equal(t, 1, response.Suggestions[3].OutsidePreferredCount)
+}
@@ -0,0 +1,227 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"itsplanned/handlers"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func budgetCategoryParams(eventID, categoryID uint) gin.Params {
+	return gin.Params{{Key: "id", Value: fmt.Sprintf("%d", eventID)}, {Key: "category_id", Value: fmt.Sprintf("%d", categoryID)}}
+}
+
+func TestBudgetCategories(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	tests := []struct {
+		name         string
+		userID       uint
+		request      api.BudgetCategoryRequest
+		expectedCode int
+	}{
+		{
+			name:         "Participant cannot create categories",
+			userID:       participant.ID,
+			request:      api.BudgetCategoryRequest{Name: "food", Limit: 100},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "Negative limit",
+			userID:       organizer.ID,
+			request:      api.BudgetCategoryRequest{Name: "food", Limit: -1},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Reserved name",
+			userID:       organizer.ID,
+			request:      api.BudgetCategoryRequest{Name: "Uncategorized", Limit: 100},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Limit above the initial budget",
+			userID:       organizer.ID,
+			request:      api.BudgetCategoryRequest{Name: "venue", Limit: 1500},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Organizer creates a category",
+			userID:       organizer.ID,
+			request:      api.BudgetCategoryRequest{Name: " Food ", Limit: 300},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Duplicate name",
+			userID:       organizer.ID,
+			request:      api.BudgetCategoryRequest{Name: "FOOD", Limit: 100},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Limits together above the initial budget",
+			userID:       organizer.ID,
+			request:      api.BudgetCategoryRequest{Name: "venue", Limit: 800},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Second category fits",
+			userID:       organizer.ID,
+			request:      api.BudgetCategoryRequest{Name: "decor", Limit: 200},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w, _ := test.CallHandler(t, tc.userID, "POST", fmt.Sprintf("/events/%d/budget/categories", event.ID), idParams(event.ID), tc.request, handlers.CreateBudgetCategory)
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+
+	getCategories := func() api.BudgetCategoriesResponse {
+		c, w := test.CreateTestContext(t, participant.ID)
+		c.Request = httptest.NewRequest("GET", fmt.Sprintf("/events/%d/budget/categories", event.ID), nil)
+		c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", event.ID)}}
+		handlers.GetBudgetCategories(c, test.TestDB)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.BudgetCategoriesResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	categories := getCategories()
+	assert.Len(t, categories.Categories, 2)
+	assert.Equal(t, "decor", categories.Categories[0].Name)
+	assert.Equal(t, "food", categories.Categories[1].Name)
+	assert.Equal(t, 500.0, categories.Allocated)
+	assert.Equal(t, 500.0, categories.Unallocated)
+
+	t.Run("Renaming renames tasks and expenses", func(t *testing.T) {
+		task := test.CreateTestTask(t, event.ID)
+		assert.NoError(t, test.TestDB.Model(task).Update("category", "decor").Error)
+		expenseID := createExpense(t, organizer.ID, event.ID, api.ExpenseRequest{Amount: 10, Category: "decor"})
+
+		decorID := categories.Categories[0].ID
+		w, _ := test.CallHandler(t, organizer.ID, "PUT", fmt.Sprintf("/events/%d/budget/categories/%d", event.ID, decorID), budgetCategoryParams(event.ID, decorID), api.BudgetCategoryRequest{Name: "Decorations", Limit: 250}, handlers.UpdateBudgetCategory)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var renamedTask models.Task
+		assert.NoError(t, test.TestDB.First(&renamedTask, task.ID).Error)
+		assert.Equal(t, "decorations", renamedTask.Category)
+
+		var expense models.Expense
+		assert.NoError(t, test.TestDB.First(&expense, expenseID).Error)
+		assert.Equal(t, "decorations", expense.Category)
+	})
+
+	t.Run("Update is checked against the other limits", func(t *testing.T) {
+		foodID := categories.Categories[1].ID
+		w, _ := test.CallHandler(t, organizer.ID, "PUT", fmt.Sprintf("/events/%d/budget/categories/%d", event.ID, foodID), budgetCategoryParams(event.ID, foodID), api.BudgetCategoryRequest{Name: "food", Limit: 800}, handlers.UpdateBudgetCategory)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w, _ = test.CallHandler(t, organizer.ID, "PUT", fmt.Sprintf("/events/%d/budget/categories/%d", event.ID, foodID), budgetCategoryParams(event.ID, foodID), api.BudgetCategoryRequest{Name: "food", Limit: 750}, handlers.UpdateBudgetCategory)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		foodID := categories.Categories[1].ID
+		w, _ := test.CallHandler(t, participant.ID, "DELETE", fmt.Sprintf("/events/%d/budget/categories/%d", event.ID, foodID), budgetCategoryParams(event.ID, foodID), nil, handlers.DeleteBudgetCategory)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w, _ = test.CallHandler(t, organizer.ID, "DELETE", fmt.Sprintf("/events/%d/budget/categories/%d", event.ID, foodID), budgetCategoryParams(event.ID, foodID), nil, handlers.DeleteBudgetCategory)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w, _ = test.CallHandler(t, organizer.ID, "DELETE", fmt.Sprintf("/events/%d/budget/categories/%d", event.ID, foodID), budgetCategoryParams(event.ID, foodID), nil, handlers.DeleteBudgetCategory)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		assert.Len(t, getCategories().Categories, 1)
+	})
+}
+
+func TestBudgetCategoryLimitInEventCurrency(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	assert.NoError(t, test.TestDB.Model(event).Updates(map[string]interface{}{"currency": "JPY", "initial_budget_minor": 1000}).Error)
+
+	w, response := test.CallHandler(t, organizer.ID, "POST", fmt.Sprintf("/events/%d/budget/categories", event.ID), idParams(event.ID), api.BudgetCategoryRequest{Name: "venue", Limit: 1500}, handlers.CreateBudgetCategory)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "Category limits can't exceed the initial budget, 1000 is left to allocate", response.Error)
+}
+
+func TestGetEventBudgetWithCategoryLimits(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+
+	for _, request := range []api.BudgetCategoryRequest{{Name: "food", Limit: 300}, {Name: "decor", Limit: 50}, {Name: "venue", Limit: 400}} {
+		w, _ := test.CallHandler(t, organizer.ID, "POST", fmt.Sprintf("/events/%d/budget/categories", event.ID), idParams(event.ID), request, handlers.CreateBudgetCategory)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	food := test.CreateTestTask(t, event.ID)
+	assert.NoError(t, test.TestDB.Model(food).Update("category", "food").Error)
+	decor := test.CreateTestTask(t, event.ID)
+	assert.NoError(t, test.TestDB.Model(decor).Update("category", "decor").Error)
+
+	createExpense(t, organizer.ID, event.ID, api.ExpenseRequest{Amount: 120, TaskID: &food.ID})
+	createExpense(t, organizer.ID, event.ID, api.ExpenseRequest{Amount: 30, Category: "decor"})
+
+	getBudget := func() api.EventBudgetResponse {
+		c, w := test.CreateTestContext(t, organizer.ID)
+		c.Request = httptest.NewRequest("GET", fmt.Sprintf("/events/%d/budget", event.ID), nil)
+		c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", event.ID)}}
+		handlers.GetEventBudget(c, test.TestDB)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.EventBudgetResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	floatPtr := func(f float64) *float64 { return &f }
+
+	budget := getBudget()
+	assert.Equal(t, 750.0, budget.Allocated)
+	assert.Equal(t, 850.0, budget.Remaining)
+	assert.True(t, budget.OverBudget)
+	assert.Equal(t, []api.CategoryBudgetResponse{
+		{Category: "decor", Planned: 100, Actual: 30, Limit: floatPtr(50), Remaining: floatPtr(20), OverBudget: true},
+		{Category: "food", Planned: 100, Actual: 120, Limit: floatPtr(300), Remaining: floatPtr(180)},
+		{Category: "venue", Limit: floatPtr(400), Remaining: floatPtr(400)},
+	}, budget.Categories)
+
+	t.Run("Within limits", func(t *testing.T) {
+		assert.NoError(t, test.TestDB.Model(decor).Update("budget_minor", 4000).Error)
+
+		budget := getBudget()
+		assert.False(t, budget.OverBudget)
+		assert.False(t, budget.Categories[0].OverBudget)
+	})
+
+	t.Run("Event over its initial budget", func(t *testing.T) {
+		createExpense(t, organizer.ID, event.ID, api.ExpenseRequest{Amount: 900})
+
+		budget := getBudget()
+		assert.Equal(t, -50.0, budget.Remaining)
+		assert.True(t, budget.OverBudget)
+	})
+}
@@ -0,0 +1,132 @@
+package handlers_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"itsplanned/handlers"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/test"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportEventBudget(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	outsider := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	event.Timezone = "Europe/Moscow"
+	assert.NoError(t, test.TestDB.Save(event).Error)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	task := test.CreateTestTask(t, event.ID)
+	task.AssignedTo = &participant.ID
+	assert.NoError(t, test.TestDB.Save(task).Error)
+
+	expense := models.Expense{EventID: event.ID, PayerID: participant.ID, CreatedByID: participant.ID, TaskID: &task.ID, Amount: 2550, Currency: "RUB", SpentAt: time.Now()}
+	assert.NoError(t, test.TestDB.Create(&expense).Error)
+
+	tests := []struct {
+		name                string
+		userID              uint
+		format              string
+		expectedCode        int
+		expectedContentType string
+	}{
+		{
+			name:                "CSV by default",
+			userID:              participant.ID,
+			expectedCode:        http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+		},
+		{
+			name:                "XLSX",
+			userID:              organizer.ID,
+			format:              "xlsx",
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		},
+		{
+			name:                "PDF",
+			userID:              organizer.ID,
+			format:              "pdf",
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/pdf",
+		},
+		{
+			name:         "Invalid format",
+			userID:       organizer.ID,
+			format:       "docx",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Outsider",
+			userID:       outsider.ID,
+			format:       "csv",
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := test.CreateTestContext(t, tt.userID)
+			url := fmt.Sprintf("/events/%d/budget/export", event.ID)
+			if tt.format != "" {
+				url += "?format=" + tt.format
+			}
+			c.Request = httptest.NewRequest("GET", url, nil)
+			c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", event.ID)}}
+
+			handlers.ExportEventBudget(c, test.TestDB)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode != http.StatusOK {
+				var response api.APIResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.NotEmpty(t, response.Error)
+				return
+			}
+
+			format := tt.format
+			if format == "" {
+				format = "csv"
+			}
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, fmt.Sprintf("attachment; filename=budget-event-%d.%s", event.ID, format), w.Header().Get("Content-Disposition"))
+
+			body := w.Body.Bytes()
+			switch format {
+			case "csv":
+				content := string(body)
+				assert.Contains(t, content, "Event,Test Event\n")
+				assert.Contains(t, content, "Date,2024-04-01 21:00 MSK\n")
+				assert.Contains(t, content, "Initial budget,1000.00\n")
+				assert.Contains(t, content, "Task,Category,Assignee,Status,Points,Planned,Spent,Difference\n")
+				assert.Contains(t, content, "Test Task,uncategorized,Test User,Open,10,100.00,25.50,74.50\n")
+				assert.Contains(t, content, "Total,,,0 of 1 completed,10,100.00,25.50,74.50\n")
+			case "xlsx":
+				archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+				assert.NoError(t, err)
+				names := []string{}
+				for _, file := range archive.File {
+					names = append(names, file.Name)
+				}
+				assert.Contains(t, names, "xl/worksheets/sheet3.xml")
+			case "pdf":
+				assert.True(t, strings.HasPrefix(string(body), "%PDF-1.4"))
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"itsplanned/handlers"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/services/email"
+	"itsplanned/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type sentMail struct {
+	to      string
+	subject string
+	body    string
+}
+
+// captureEmails replaces the SMTP delivery for the duration of the test
+func captureEmails(t *testing.T, failWith error) *[]sentMail {
+	sent := &[]sentMail{}
+	original := email.SendMail
+	email.SendMail = func(toEmail, subject, body string) error {
+		if failWith != nil {
+			return failWith
+		}
+		*sent = append(*sent, sentMail{to: toEmail, subject: subject, body: body})
+		return nil
+	}
+	t.Cleanup(func() { email.SendMail = original })
+	return sent
+}
+
+func callSendEmailInvitation(t *testing.T, userID, eventID uint, address string) *httptest.ResponseRecorder {
+	c, w := test.CreateTestContext(t, userID)
+	requestJSON, err := json.Marshal(api.EmailInvitationRequest{Email: address})
+	assert.NoError(t, err)
+
+	c.Request = httptest.NewRequest("POST", fmt.Sprintf("/events/%d/invitations/email", eventID), bytes.NewBuffer(requestJSON))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", eventID)}}
+
+	handlers.SendEmailInvitation(c, test.TestDB)
+	return w
+}
+
+func callJoinEvent(t *testing.T, userID uint, inviteCode string) *httptest.ResponseRecorder {
+	c, w := test.CreateTestContext(t, userID)
+	c.Request = httptest.NewRequest("GET", "/events/join/"+inviteCode, nil)
+	c.Params = []gin.Param{{Key: "invite_code", Value: inviteCode}}
+
+	handlers.JoinEvent(c, test.TestDB)
+	return w
+}
+
+func TestSendEmailInvitation(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	sent := captureEmails(t, nil)
+
+	organizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	viewer := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	assert.NoError(t, test.TestDB.Model(event).Update("timezone", "Europe/Moscow").Error)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+	test.AddEventParticipantWithRole(t, event.ID, viewer.ID, models.RoleViewer)
+
+	testCases := []struct {
+		name         string
+		userID       uint
+		email        string
+		expectedCode int
+	}{
+		{
+			name:         "Organizer invites new email",
+			userID:       organizer.ID,
+			email:        "Friend@Example.com",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Same email cannot be invited twice",
+			userID:       participant.ID,
+			email:        "friend@example.com",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Existing participant cannot be invited",
+			userID:       organizer.ID,
+			email:        participant.Email,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Viewer cannot invite",
+			userID:       viewer.ID,
+			email:        "other@example.com",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "Invalid email",
+			userID:       organizer.ID,
+			email:        "not-an-email",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := callSendEmailInvitation(t, tc.userID, event.ID, tc.email)
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+
+	assert.Len(t, *sent, 1)
+	assert.Equal(t, "friend@example.com", (*sent)[0].to)
+
+	var invitation models.EmailInvitation
+	assert.NoError(t, test.TestDB.Where("event_id = ?", event.ID).First(&invitation).Error)
+	assert.Equal(t, models.EmailInvitationSent, invitation.Status)
+	assert.Nil(t, invitation.UserID)
+
+	var eventInvitation models.EventInvitation
+	assert.NoError(t, test.TestDB.First(&eventInvitation, invitation.InvitationID).Error)
+	assert.Contains(t, (*sent)[0].body, "/events/redirect/"+eventInvitation.InviteCode)
+	// The event time is given in the zone of the event
+	assert.Contains(t, (*sent)[0].body, "01.04.2024 21:00 MSK")
+
+	t.Run("Failed delivery revokes the invitation", func(t *testing.T) {
+		captureEmails(t, errors.New("smtp unavailable"))
+
+		w := callSendEmailInvitation(t, organizer.ID, event.ID, "unlucky@example.com")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var failed models.EmailInvitation
+		assert.NoError(t, test.TestDB.Where("email = ?", "unlucky@example.com").First(&failed).Error)
+		assert.Equal(t, models.EmailInvitationFailed, failed.Status)
+
+		var link models.EventInvitation
+		assert.NoError(t, test.TestDB.First(&link, failed.InvitationID).Error)
+		assert.NotNil(t, link.RevokedAt)
+
+		// The address can be invited again
+		captureEmails(t, nil)
+		assert.Equal(t, http.StatusOK, callSendEmailInvitation(t, organizer.ID, event.ID, "unlucky@example.com").Code)
+	})
+}
+
+func TestEmailInvitationLifecycle(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	captureEmails(t, nil)
+
+	organizer := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+
+	assert.Equal(t, http.StatusOK, callSendEmailInvitation(t, organizer.ID, event.ID, "newcomer@example.com").Code)
+	assert.Equal(t, http.StatusOK, callSendEmailInvitation(t, organizer.ID, event.ID, "busy@example.com").Code)
+
+	var newcomerInvitation, busyInvitation models.EmailInvitation
+	assert.NoError(t, test.TestDB.Where("email = ?", "newcomer@example.com").First(&newcomerInvitation).Error)
+	assert.NoError(t, test.TestDB.Where("email = ?", "busy@example.com").First(&busyInvitation).Error)
+
+	var newcomerLink models.EventInvitation
+	assert.NoError(t, test.TestDB.First(&newcomerLink, newcomerInvitation.InvitationID).Error)
+
+	// Registering with the invited address links the invitation to the new account
+	c, w := test.CreateTestContext(t, 0)
+	requestJSON, err := json.Marshal(api.RegisterRequest{Email: "Newcomer@example.com", Password: "password123"})
+	assert.NoError(t, err)
+	c.Request = httptest.NewRequest("POST", "/register", bytes.NewBuffer(requestJSON))
+	c.Request.Header.Set("Content-Type", "application/json")
+	handlers.Register(c, test.TestDB)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var newcomer models.User
+	assert.NoError(t, test.TestDB.Where("email = ?", "Newcomer@example.com").First(&newcomer).Error)
+
+	c, w = test.CreateTestContext(t, newcomer.ID)
+	c.Request = httptest.NewRequest("GET", "/invitations/email", nil)
+	handlers.GetMyEmailInvitations(c, test.TestDB)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var mine api.EmailInvitationsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &mine))
+	assert.Len(t, mine.Invitations, 1)
+	// Registering with an address doesn't prove owning it, the link is only in the email
+	assert.Empty(t, mine.Invitations[0].InviteCode)
+	assert.NotContains(t, w.Body.String(), newcomerLink.InviteCode)
+	assert.Equal(t, event.Name, mine.Invitations[0].EventName)
+
+	// The personal link does not work for somebody else
+	stranger := test.CreateTestUser(t)
+	assert.Equal(t, http.StatusForbidden, callJoinEvent(t, stranger.ID, newcomerLink.InviteCode).Code)
+
+	assert.Equal(t, http.StatusOK, callJoinEvent(t, newcomer.ID, newcomerLink.InviteCode).Code)
+	assert.NoError(t, test.TestDB.First(&newcomerInvitation, newcomerInvitation.ID).Error)
+	assert.Equal(t, models.EmailInvitationAccepted, newcomerInvitation.Status)
+	assert.NotNil(t, newcomerInvitation.RespondedAt)
+
+	t.Run("Decline email invitation", func(t *testing.T) {
+		busy := test.CreateTestUser(t)
+		assert.NoError(t, test.TestDB.Model(&busyInvitation).Update("user_id", busy.ID).Error)
+
+		decline := func(userID uint) int {
+			c, w := test.CreateTestContext(t, userID)
+			c.Request = httptest.NewRequest("POST", fmt.Sprintf("/invitations/email/%d/decline", busyInvitation.ID), nil)
+			c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", busyInvitation.ID)}}
+			handlers.DeclineEmailInvitation(c, test.TestDB)
+			return w.Code
+		}
+
+		assert.Equal(t, http.StatusNotFound, decline(newcomer.ID))
+		assert.Equal(t, http.StatusOK, decline(busy.ID))
+		assert.Equal(t, http.StatusBadRequest, decline(busy.ID))
+
+		var busyLink models.EventInvitation
+		assert.NoError(t, test.TestDB.First(&busyLink, busyInvitation.InvitationID).Error)
+		assert.Equal(t, http.StatusGone, callJoinEvent(t, busy.ID, busyLink.InviteCode).Code)
+	})
+
+	c, w = test.CreateTestContext(t, organizer.ID)
+	c.Request = httptest.NewRequest("GET", fmt.Sprintf("/events/%d/invitations/email", event.ID), nil)
+	c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", event.ID)}}
+	handlers.GetEventEmailInvitations(c, test.TestDB)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var all api.EmailInvitationsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &all))
+	statuses := map[string]string{}
+	for _, invitation := range all.Invitations {
+		statuses[invitation.Email] = invitation.Status
+	}
+	assert.Equal(t, map[string]string{
+		"newcomer@example.com": models.EmailInvitationAccepted,
+		"busy@example.com":     models.EmailInvitationDeclined,
+	}, statuses)
+
+	var notifications []models.TaskStatusEvent
+	assert.NoError(t, test.TestDB.Where("user_id = ? AND event_id = ?", organizer.ID, event.ID).Find(&notifications).Error)
+	assert.Len(t, notifications, 2)
+}
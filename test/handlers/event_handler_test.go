@@ -7,6 +7,8 @@ import (
 	"itsplanned/handlers"
 	"itsplanned/models"
 	"itsplanned/models/api"
+	"itsplanned/money"
+	"itsplanned/services/exchange"
 	"itsplanned/test"
 	"net/http"
 	"net/http/httptest"
@@ -147,6 +149,265 @@ func TestFindBestTimeSlotsForDay(t *testing.T) {
 	}
 }
 
+func TestFindBestTimeSlots(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	participant1 := test.CreateTestUser(t)
+	participant2 := test.CreateTestUser(t)
+
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipant(t, event.ID, participant1.ID)
+	test.AddEventParticipant(t, event.ID, participant2.ID)
+
+	createCalendarEvent(t, participant1.ID, "2024-04-01", "10:00", "11:00", "Meeting")
+	createCalendarEvent(t, participant2.ID, "2024-04-02", "10:00", "10:30", "Call")
+
+	workingHours := map[string]api.WorkingHours{
+		"monday":  {StartTime: "10:00", EndTime: "12:00"},
+		"tuesday": {StartTime: "10:00", EndTime: "11:00"},
+	}
+
+	testCases := []struct {
+		name         string
+		userID       uint
+		request      api.FindBestTimeRequest
+		expectedCode int
+		validateFunc func(t *testing.T, response *api.FindBestTimeSlotsResponse)
+	}{
+		{
+			name:   "Ranked slots across days",
+			userID: organizer.ID,
+			request: api.FindBestTimeRequest{
+				EventID:      event.ID,
+				StartDate:    "2024-04-01",
+				EndDate:      "2024-04-03",
+				DurationMins: 30,
+				WorkingHours: workingHours,
+				Limit:        4,
+			},
+			expectedCode: http.StatusOK,
+			validateFunc: func(t *testing.T, response *api.FindBestTimeSlotsResponse) {
+				assert.Len(t, response.Suggestions, 4)
+				var slots []string
+				for _, suggestion := range response.Suggestions[:3] {
+					slots = append(slots, suggestion.Slot)
+					assert.Equal(t, 0, suggestion.BusyCount)
+					assert.Empty(t, suggestion.BusyParticipantIDs)
+				}
+				assert.Equal(t, []string{"2024-04-01 11:00", "2024-04-01 11:30", "2024-04-02 10:30"}, slots)
+
+				assert.Equal(t, "2024-04-01 10:00", response.Suggestions[3].Slot)
+				assert.Equal(t, []uint{participant1.ID}, response.Suggestions[3].BusyParticipantIDs)
+			},
+		},
+		{
+			name:   "Optional participant ranks after required ones",
+			userID: organizer.ID,
+			request: api.FindBestTimeRequest{
+				EventID:      event.ID,
+				StartDate:    "2024-04-01",
+				EndDate:      "2024-04-02",
+				DurationMins: 60,
+				WorkingHours: map[string]api.WorkingHours{
+					"monday":  {StartTime: "10:00", EndTime: "11:00"},
+					"tuesday": {StartTime: "10:00", EndTime: "11:00"},
+				},
+				Participants: []api.SchedulingParticipant{{UserID: participant1.ID, Optional: true}},
+			},
+			expectedCode: http.StatusOK,
+			validateFunc: func(t *testing.T, response *api.FindBestTimeSlotsResponse) {
+				assert.Len(t, response.Suggestions, 2)
+				assert.Equal(t, "2024-04-01 10:00", response.Suggestions[0].Slot)
+				assert.Equal(t, 0, response.Suggestions[0].RequiredBusyCount)
+				assert.Equal(t, 1, response.Suggestions[1].RequiredBusyCount)
+			},
+		},
+		{
+			name:   "Preference for a non-participant",
+			userID: organizer.ID,
+			request: api.FindBestTimeRequest{
+				EventID:      event.ID,
+				StartDate:    "2024-04-01",
+				EndDate:      "2024-04-02",
+				Participants: []api.SchedulingParticipant{{UserID: 9999, Optional: true}},
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Hourly granularity without working hours",
+			userID: organizer.ID,
+			request: api.FindBestTimeRequest{
+				EventID:         event.ID,
+				StartDate:       "2024-04-02",
+				EndDate:         "2024-04-02",
+				DurationMins:    60,
+				GranularityMins: 60,
+				Limit:           100,
+			},
+			expectedCode: http.StatusOK,
+			validateFunc: func(t *testing.T, response *api.FindBestTimeSlotsResponse) {
+				assert.Len(t, response.Suggestions, 14)
+				for _, suggestion := range response.Suggestions {
+					assert.Equal(t, "00", suggestion.Slot[len(suggestion.Slot)-2:])
+				}
+			},
+		},
+		{
+			name:   "Unknown weekday",
+			userID: organizer.ID,
+			request: api.FindBestTimeRequest{
+				EventID:      event.ID,
+				StartDate:    "2024-04-01",
+				EndDate:      "2024-04-03",
+				WorkingHours: map[string]api.WorkingHours{"mon": {StartTime: "10:00", EndTime: "12:00"}},
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Unsupported granularity",
+			userID: organizer.ID,
+			request: api.FindBestTimeRequest{
+				EventID:         event.ID,
+				StartDate:       "2024-04-01",
+				EndDate:         "2024-04-03",
+				GranularityMins: 45,
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Range too long",
+			userID: organizer.ID,
+			request: api.FindBestTimeRequest{
+				EventID:   event.ID,
+				StartDate: "2024-04-01",
+				EndDate:   "2024-06-01",
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Non-participant",
+			userID: test.CreateTestUser(t).ID,
+			request: api.FindBestTimeRequest{
+				EventID:   event.ID,
+				StartDate: "2024-04-01",
+				EndDate:   "2024-04-03",
+			},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := test.CreateTestContext(t, tc.userID)
+
+			requestJSON, err := json.Marshal(tc.request)
+			assert.NoError(t, err)
+
+			c.Request = httptest.NewRequest("POST", "/events/find_best_time", bytes.NewBuffer(requestJSON))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			handlers.FindBestTimeSlots(c, test.TestDB)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+
+			if tc.expectedCode == http.StatusOK && tc.validateFunc != nil {
+				var response api.FindBestTimeSlotsResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+
+				tc.validateFunc(t, &response)
+			}
+		})
+	}
+}
+
+func TestFindBestTimeSlotsInUserTimezone(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	assert.NoError(t, test.TestDB.Model(organizer).Update("timezone", "Europe/Moscow").Error)
+	participant := test.CreateTestUser(t)
+
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	// 10:00-11:00 in Moscow
+	createCalendarEvent(t, participant.ID, "2024-04-01", "07:00", "08:00", "Meeting")
+
+	c, w := test.CreateTestContext(t, organizer.ID)
+	requestJSON, err := json.Marshal(api.FindBestTimeSlotsRequest{
+		EventID:      event.ID,
+		Date:         "2024-04-01",
+		DurationMins: 60,
+		StartTime:    "09:00",
+		EndTime:      "12:00",
+	})
+	assert.NoError(t, err)
+
+	c.Request = httptest.NewRequest("POST", "/events/find_best_time_for_day", bytes.NewBuffer(requestJSON))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handlers.FindBestTimeSlotsForDay(c, test.TestDB)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response api.FindBestTimeSlotsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	busy := map[string]int{}
+	for _, suggestion := range response.Suggestions {
+		busy[suggestion.Slot] = suggestion.BusyCount
+	}
+	assert.Equal(t, map[string]int{
+		"2024-04-01 09:00": 0,
+		"2024-04-01 09:30": 1,
+		"2024-04-01 10:00": 1,
+		"2024-04-01 10:30": 1,
+		"2024-04-01 11:00": 0,
+	}, busy)
+}
+
+func TestEventTimezone(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	user := test.CreateTestUser(t)
+	assert.NoError(t, test.TestDB.Model(user).Update("timezone", "Europe/Berlin").Error)
+
+	create := func(request api.CreateEventRequest) (*httptest.ResponseRecorder, api.APIResponse) {
+		return test.CallHandler(t, user.ID, "POST", "/events", nil, request,
+			handlers.CreateEvent)
+	}
+
+	t.Run("Invalid timezone", func(t *testing.T) {
+		w, _ := create(api.CreateEventRequest{Name: "Standup", EventDateTime: "2024-03-25T09:00:00+01:00", Timezone: "Nowhere/City"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Stored in UTC and returned in the event zone", func(t *testing.T) {
+		w, response := create(api.CreateEventRequest{Name: "Standup", EventDateTime: "2024-03-25T08:00:00Z", RecurrenceRule: "FREQ=WEEKLY;COUNT=2"})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		eventData := response.Data.(map[string]interface{})
+		assert.Equal(t, "Europe/Berlin", eventData["timezone"])
+		assert.Equal(t, "2024-03-25T09:00:00+01:00", eventData["event_date_time"])
+
+		var event models.Event
+		assert.NoError(t, test.TestDB.First(&event, responseID(t, response)).Error)
+		assert.Equal(t, time.UTC, event.EventDateTime.Location())
+	})
+
+	t.Run("Recurring event keeps its local time across daylight saving", func(t *testing.T) {
+		_, events := callGetEvents(t, user.ID, "from=2024-03-20T00:00:00Z&to=2024-04-10T00:00:00Z")
+		assert.Equal(t, []string{"2024-03-25T08:00:00Z", "2024-04-01T07:00:00Z"}, occurrenceTimes(events))
+		for _, event := range events {
+			assert.Equal(t, 9, event.EventDateTime.Hour())
+		}
+	})
+}
+
 func createCalendarEvent(t *testing.T, userID uint, date, startTime, endTime, summary string) {
 	startDateTime, err := time.Parse("2006-01-02 15:04", fmt.Sprintf("%s %s", date, startTime))
 	assert.NoError(t, err)
@@ -298,7 +559,7 @@ func TestGetEvent(t *testing.T) {
 				assert.Equal(t, float64(event.ID), eventData["id"])
 				assert.Equal(t, event.Name, eventData["name"])
 				assert.Equal(t, event.Description, eventData["description"])
-				assert.Equal(t, event.InitialBudget, eventData["initial_budget"])
+				assert.Equal(t, money.FromMinor(event.InitialBudget, event.Currency), eventData["initial_budget"])
 				assert.Equal(t, event.Place, eventData["place"])
 				assert.Equal(t, float64(organizer.ID), eventData["organizer_id"])
 			},
@@ -367,6 +628,12 @@ func TestUpdateEvent(t *testing.T) {
 
 	participant := test.CreateTestUser(t)
 	test.AddEventParticipant(t, event.ID, participant.ID)
+	task := test.CreateTestTask(t, event.ID)
+	category := models.BudgetCategory{EventID: event.ID, Name: "Food", Limit: 50000}
+	assert.NoError(t, test.TestDB.Create(&category).Error)
+
+	exchange.SetProvider(&exchange.StaticProvider{Base: "RUB", Rates: map[string]float64{"JPY": 1.6}})
+	defer exchange.SetProvider(&exchange.StaticProvider{Base: money.DefaultCurrency})
 
 	strPtr := func(s string) *string { return &s }
 	floatPtr := func(f float64) *float64 { return &f }
@@ -406,7 +673,7 @@ func TestUpdateEvent(t *testing.T) {
 
 				assert.Equal(t, "Updated Event Name", updatedEvent.Name)
 				assert.Equal(t, "Updated Description", updatedEvent.Description)
-				assert.Equal(t, float64(2000.0), updatedEvent.InitialBudget)
+				assert.Equal(t, int64(200000), updatedEvent.InitialBudget)
 				assert.Equal(t, "Updated Location", updatedEvent.Place)
 			},
 		},
@@ -427,7 +694,7 @@ func TestUpdateEvent(t *testing.T) {
 				assert.Equal(t, "Only Name Updated", updatedEvent.Name)
 				assert.Equal(t, "Only Place Updated", updatedEvent.Place)
 				assert.Equal(t, "Updated Description", updatedEvent.Description)
-				assert.Equal(t, float64(2000.0), updatedEvent.InitialBudget)
+				assert.Equal(t, int64(200000), updatedEvent.InitialBudget)
 			},
 		},
 		{
@@ -439,7 +706,7 @@ func TestUpdateEvent(t *testing.T) {
 			},
 			expectedCode: http.StatusForbidden,
 			validateFunc: func(t *testing.T, response api.APIResponse) {
-				assert.Contains(t, response.Error, "You are not the organizer of this event")
+				assert.Contains(t, response.Error, "Only organizers and co-organizers can edit this event")
 			},
 		},
 		{
@@ -454,6 +721,61 @@ func TestUpdateEvent(t *testing.T) {
 				assert.Contains(t, response.Error, "Event not found")
 			},
 		},
+		{
+			name:    "Unsupported currency",
+			userID:  organizer.ID,
+			eventID: event.ID,
+			request: api.UpdateEventRequest{
+				Currency: strPtr("XYZ"),
+			},
+			expectedCode: http.StatusBadRequest,
+			validateFunc: func(t *testing.T, response api.APIResponse) {
+				assert.Contains(t, response.Error, "Unsupported currency")
+			},
+		},
+		{
+			name:    "Currency without an exchange rate",
+			userID:  organizer.ID,
+			eventID: event.ID,
+			request: api.UpdateEventRequest{
+				Currency: strPtr("USD"),
+			},
+			expectedCode: http.StatusBadRequest,
+			validateFunc: func(t *testing.T, response api.APIResponse) {
+				assert.Contains(t, response.Error, "No exchange rate from RUB to USD")
+
+				var updatedEvent models.Event
+				assert.NoError(t, test.TestDB.First(&updatedEvent, event.ID).Error)
+				assert.Equal(t, "RUB", updatedEvent.Currency)
+				assert.Equal(t, int64(200000), updatedEvent.InitialBudget)
+			},
+		},
+		{
+			name:    "Changing the currency converts the amounts",
+			userID:  organizer.ID,
+			eventID: event.ID,
+			request: api.UpdateEventRequest{
+				Currency: strPtr("jpy"),
+			},
+			expectedCode: http.StatusOK,
+			validateFunc: func(t *testing.T, response api.APIResponse) {
+				eventData := response.Data.(map[string]interface{})
+				assert.Equal(t, "JPY", eventData["currency"])
+				assert.Equal(t, float64(3200.0), eventData["initial_budget"])
+
+				var updatedEvent models.Event
+				assert.NoError(t, test.TestDB.First(&updatedEvent, event.ID).Error)
+				assert.Equal(t, int64(3200), updatedEvent.InitialBudget)
+
+				var updatedTask models.Task
+				assert.NoError(t, test.TestDB.First(&updatedTask, task.ID).Error)
+				assert.Equal(t, int64(160), updatedTask.Budget)
+
+				var updatedCategory models.BudgetCategory
+				assert.NoError(t, test.TestDB.First(&updatedCategory, category.ID).Error)
+				assert.Equal(t, int64(800), updatedCategory.Limit)
+			},
+		},
 		{
 			name:    "Invalid date format",
 			userID:  organizer.ID,
@@ -621,3 +943,382 @@ func TestDeleteEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestTransferEventOwnership(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+
+	participant := test.CreateTestUser(t)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	outsider := test.CreateTestUser(t)
+
+	testCases := []struct {
+		name         string
+		userID       uint
+		request      api.TransferOwnershipRequest
+		expectedCode int
+	}{
+		{
+			name:         "Participant cannot transfer ownership",
+			userID:       participant.ID,
+			request:      api.TransferOwnershipRequest{NewOrganizerID: participant.ID},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "Cannot transfer to a non-participant",
+			userID:       organizer.ID,
+			request:      api.TransferOwnershipRequest{NewOrganizerID: outsider.ID},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "Cannot transfer to yourself",
+			userID:       organizer.ID,
+			request:      api.TransferOwnershipRequest{NewOrganizerID: organizer.ID},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Organizer transfers ownership to participant",
+			userID:       organizer.ID,
+			request:      api.TransferOwnershipRequest{NewOrganizerID: participant.ID},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Previous organizer cannot transfer again",
+			userID:       organizer.ID,
+			request:      api.TransferOwnershipRequest{NewOrganizerID: organizer.ID},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := test.CreateTestContext(t, tc.userID)
+
+			requestJSON, err := json.Marshal(tc.request)
+			assert.NoError(t, err)
+
+			c.Request = httptest.NewRequest("POST", fmt.Sprintf("/events/%d/transfer", event.ID), bytes.NewBuffer(requestJSON))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", event.ID)}}
+
+			handlers.TransferEventOwnership(c, test.TestDB)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+
+	var updatedEvent models.Event
+	assert.NoError(t, test.TestDB.First(&updatedEvent, event.ID).Error)
+	assert.Equal(t, participant.ID, updatedEvent.OrganizerID)
+
+	var transfers []models.EventOwnershipTransfer
+	test.TestDB.Where("event_id = ?", event.ID).Find(&transfers)
+	if assert.Len(t, transfers, 1) {
+		assert.Equal(t, organizer.ID, transfers[0].FromUserID)
+		assert.Equal(t, participant.ID, transfers[0].ToUserID)
+	}
+
+	var notification models.TaskStatusEvent
+	assert.NoError(t, test.TestDB.Where("user_id = ? AND event_id = ?", participant.ID, event.ID).First(&notification).Error)
+	assert.Equal(t, models.RoleOrganizer, notification.NewStatus)
+	assert.Equal(t, organizer.ID, notification.ChangedByID)
+
+	var previousOrganizer models.EventParticipation
+	assert.NoError(t, test.TestDB.Where("event_id = ? AND user_id = ?", event.ID, organizer.ID).First(&previousOrganizer).Error)
+	assert.Equal(t, models.RoleCoOrganizer, previousOrganizer.Role)
+
+	t.Run("Previous organizer can leave the event", func(t *testing.T) {
+		c, w := test.CreateTestContext(t, organizer.ID)
+		c.Request = httptest.NewRequest("DELETE", fmt.Sprintf("/events/%d/leave", event.ID), nil)
+		c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", event.ID)}}
+
+		handlers.LeaveEvent(c, test.TestDB)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func createRecurringEvent(t *testing.T, organizerID uint, rule string) *models.Event {
+	event := test.CreateTestEvent(t, organizerID)
+	assert.NoError(t, test.TestDB.Model(event).Update("recurrence_rule", rule).Error)
+	event.RecurrenceRule = rule
+	return event
+}
+
+func callGetEvents(t *testing.T, userID uint, query string) (*httptest.ResponseRecorder, []api.EventResponse) {
+	c, w := test.CreateTestContext(t, userID)
+	c.Request = httptest.NewRequest("GET", "/events?"+query, nil)
+
+	handlers.GetEvents(c, test.TestDB)
+
+	var response struct {
+		Data []api.EventResponse `json:"data"`
+	}
+	if w.Code == http.StatusOK {
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	}
+	return w, response.Data
+}
+
+func occurrenceTimes(events []api.EventResponse) []string {
+	var times []string
+	for _, event := range events {
+		times = append(times, event.EventDateTime.UTC().Format(time.RFC3339))
+	}
+	return times
+}
+
+const weekOfApril = "from=2024-04-01T00:00:00Z&to=2024-04-30T00:00:00Z"
+
+func TestCreateRecurringEvent(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	user := test.CreateTestUser(t)
+
+	testCases := []struct {
+		name         string
+		rule         string
+		expectedCode int
+		expectedRule string
+	}{
+		{name: "Rule is stored in canonical form", rule: "RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=3", expectedCode: http.StatusOK, expectedRule: "FREQ=WEEKLY;COUNT=3;BYDAY=MO"},
+		{name: "Invalid rule", rule: "FREQ=SOMETIMES", expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := test.CreateTestContext(t, user.ID)
+
+			requestJSON, err := json.Marshal(api.CreateEventRequest{
+				Name:           "Standup",
+				EventDateTime:  "2024-04-01T10:00:00Z",
+				RecurrenceRule: tc.rule,
+			})
+			assert.NoError(t, err)
+
+			c.Request = httptest.NewRequest("POST", "/events", bytes.NewBuffer(requestJSON))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			handlers.CreateEvent(c, test.TestDB)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+			if tc.expectedCode == http.StatusOK {
+				var response api.APIResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				eventData := response.Data.(map[string]interface{})
+				assert.Equal(t, tc.expectedRule, eventData["recurrence_rule"])
+			}
+		})
+	}
+}
+
+func TestGetEventsExpandsRecurringEvents(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	event := createRecurringEvent(t, organizer.ID, "FREQ=WEEKLY;COUNT=4")
+
+	single := test.CreateTestEvent(t, organizer.ID)
+	assert.NoError(t, test.TestDB.Model(single).Update("event_date_time", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)).Error)
+
+	movedTo := time.Date(2024, 4, 9, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, test.TestDB.Create(&models.EventOccurrenceOverride{
+		EventID:        event.ID,
+		OccurrenceTime: time.Date(2024, 4, 8, 18, 0, 0, 0, time.UTC),
+		EventDateTime:  &movedTo,
+	}).Error)
+	assert.NoError(t, test.TestDB.Create(&models.EventOccurrenceOverride{
+		EventID:        event.ID,
+		OccurrenceTime: time.Date(2024, 4, 15, 18, 0, 0, 0, time.UTC),
+		Cancelled:      true,
+	}).Error)
+
+	w, events := callGetEvents(t, organizer.ID, weekOfApril)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var occurrences []api.EventResponse
+	for _, e := range events {
+		if e.ID == event.ID {
+			occurrences = append(occurrences, e)
+			assert.NotNil(t, e.OccurrenceTime)
+		} else {
+			assert.Nil(t, e.OccurrenceTime)
+		}
+	}
+	assert.Len(t, events, 4)
+	assert.Equal(t, []string{"2024-04-01T18:00:00Z", "2024-04-09T12:00:00Z", "2024-04-22T18:00:00Z"}, occurrenceTimes(occurrences))
+
+	w, _ = callGetEvents(t, organizer.ID, "from=2024-04-30T00:00:00Z&to=2024-04-01T00:00:00Z")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUpdateEventScopes(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	event := createRecurringEvent(t, organizer.ID, "FREQ=WEEKLY;COUNT=4")
+	test.AddEventParticipant(t, event.ID, participant.ID)
+	test.CreateTestTask(t, event.ID)
+	assert.NoError(t, test.TestDB.Create(&models.BudgetCategory{EventID: event.ID, Name: "Food", Limit: 50000}).Error)
+	nonRecurring := test.CreateTestEvent(t, organizer.ID)
+
+	strPtr := func(s string) *string { return &s }
+	floatPtr := func(f float64) *float64 { return &f }
+
+	callUpdate := func(t *testing.T, eventID uint, request api.UpdateEventRequest) (*httptest.ResponseRecorder, api.APIResponse) {
+		c, w := test.CreateTestContext(t, organizer.ID)
+
+		requestJSON, err := json.Marshal(request)
+		assert.NoError(t, err)
+
+		c.Request = httptest.NewRequest("PUT", fmt.Sprintf("/events/%d", eventID), bytes.NewBuffer(requestJSON))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", eventID)}}
+
+		handlers.UpdateEvent(c, test.TestDB)
+
+		var response api.APIResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response
+	}
+
+	t.Run("Invalid scope", func(t *testing.T) {
+		w, _ := callUpdate(t, event.ID, api.UpdateEventRequest{Name: strPtr("x"), Scope: "some"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Occurrence scope on a non-recurring event", func(t *testing.T) {
+		w, response := callUpdate(t, nonRecurring.ID, api.UpdateEventRequest{Name: strPtr("x"), Scope: models.EditScopeThis, OccurrenceTime: strPtr("2024-04-01T18:00:00Z")})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, response.Error, "Only recurring events")
+	})
+
+	t.Run("Time that is not an occurrence", func(t *testing.T) {
+		w, response := callUpdate(t, event.ID, api.UpdateEventRequest{Name: strPtr("x"), Scope: models.EditScopeThis, OccurrenceTime: strPtr("2024-04-02T18:00:00Z")})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, response.Error, "no occurrence")
+	})
+
+	t.Run("Budget cannot change for one occurrence", func(t *testing.T) {
+		w, _ := callUpdate(t, event.ID, api.UpdateEventRequest{Budget: floatPtr(10), Scope: models.EditScopeThis, OccurrenceTime: strPtr("2024-04-08T18:00:00Z")})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("This occurrence only", func(t *testing.T) {
+		w, response := callUpdate(t, event.ID, api.UpdateEventRequest{Name: strPtr("Special standup"), Scope: models.EditScopeThis, OccurrenceTime: strPtr("2024-04-08T18:00:00Z")})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "Special standup", response.Data.(map[string]interface{})["name"])
+
+		_, events := callGetEvents(t, organizer.ID, weekOfApril)
+		var names []string
+		for _, e := range events {
+			if e.ID == event.ID {
+				names = append(names, e.Name)
+			}
+		}
+		assert.Equal(t, []string{"Test Event", "Special standup", "Test Event", "Test Event"}, names)
+	})
+
+	t.Run("This and following occurrences", func(t *testing.T) {
+		w, response := callUpdate(t, event.ID, api.UpdateEventRequest{Place: strPtr("New office"), Scope: models.EditScopeFollowing, OccurrenceTime: strPtr("2024-04-08T18:00:00Z")})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		eventData := response.Data.(map[string]interface{})
+		followingID := uint(eventData["id"].(float64))
+		assert.NotEqual(t, event.ID, followingID)
+		assert.Equal(t, "FREQ=WEEKLY;COUNT=3", eventData["recurrence_rule"])
+
+		var original models.Event
+		assert.NoError(t, test.TestDB.First(&original, event.ID).Error)
+		assert.Equal(t, "FREQ=WEEKLY;COUNT=1", original.RecurrenceRule)
+
+		var following models.Event
+		assert.NoError(t, test.TestDB.First(&following, followingID).Error)
+		assert.Equal(t, event.ID, *following.RecurrenceParentID)
+		assert.True(t, isParticipant(t, followingID, participant.ID))
+		assert.Equal(t, original.InitialBudget, following.InitialBudget)
+		assert.Equal(t, original.Currency, following.Currency)
+
+		// Both series keep the tasks and the budget categories
+		for _, eventID := range []uint{event.ID, followingID} {
+			var tasks []models.Task
+			assert.NoError(t, test.TestDB.Where("event_id = ?", eventID).Find(&tasks).Error)
+			assert.Len(t, tasks, 1)
+			assert.Equal(t, "Test Task", tasks[0].Title)
+			assert.Equal(t, int64(10000), tasks[0].Budget)
+
+			var categories []models.BudgetCategory
+			assert.NoError(t, test.TestDB.Where("event_id = ?", eventID).Find(&categories).Error)
+			assert.Len(t, categories, 1)
+			assert.Equal(t, "Food", categories[0].Name)
+			assert.Equal(t, int64(50000), categories[0].Limit)
+		}
+
+		// The single-occurrence change moves to the new series
+		_, events := callGetEvents(t, participant.ID, weekOfApril)
+		assert.Len(t, events, 4)
+		for _, e := range events {
+			if e.ID == followingID {
+				assert.Equal(t, "New office", e.Place)
+			}
+			if e.EventDateTime.Equal(time.Date(2024, 4, 8, 18, 0, 0, 0, time.UTC)) {
+				assert.Equal(t, "Special standup", e.Name)
+			}
+		}
+	})
+
+	t.Run("Whole series", func(t *testing.T) {
+		w, _ := callUpdate(t, event.ID, api.UpdateEventRequest{RecurrenceRule: strPtr(""), Scope: models.EditScopeAll})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var original models.Event
+		assert.NoError(t, test.TestDB.First(&original, event.ID).Error)
+		assert.Equal(t, "", original.RecurrenceRule)
+	})
+}
+
+func TestDeleteEventScopes(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	event := createRecurringEvent(t, organizer.ID, "FREQ=WEEKLY")
+
+	callDelete := func(t *testing.T, query string) *httptest.ResponseRecorder {
+		c, w := test.CreateTestContext(t, organizer.ID)
+		c.Request = httptest.NewRequest("DELETE", fmt.Sprintf("/events/%d?%s", event.ID, query), nil)
+		c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", event.ID)}}
+
+		handlers.DeleteEvent(c, test.TestDB)
+		return w
+	}
+
+	w := callDelete(t, "scope=this")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = callDelete(t, "scope=this&occurrence_time=2024-04-08T18:00:00Z")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = callDelete(t, "scope=following&occurrence_time=2024-04-22T18:00:00Z")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	_, events := callGetEvents(t, organizer.ID, weekOfApril)
+	assert.Equal(t, []string{"2024-04-01T18:00:00Z", "2024-04-15T18:00:00Z"}, occurrenceTimes(events))
+
+	w = callDelete(t, "scope=following&occurrence_time=2024-04-01T18:00:00Z")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var count int64
+	test.TestDB.Model(&models.Event{}).Where("id = ?", event.ID).Count(&count)
+	assert.Equal(t, int64(0), count)
+	test.TestDB.Model(&models.EventOccurrenceOverride{}).Where("event_id = ?", event.ID).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
@@ -0,0 +1,165 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"itsplanned/handlers"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/money"
+	"itsplanned/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func idParams(id uint) gin.Params {
+	return gin.Params{{Key: "id", Value: fmt.Sprintf("%d", id)}}
+}
+
+func responseID(t *testing.T, response api.APIResponse) uint {
+	data, ok := response.Data.(map[string]interface{})
+	assert.True(t, ok)
+	return uint(data["id"].(float64))
+}
+
+func TestCloneEvent(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	coOrganizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipantWithRole(t, event.ID, coOrganizer.ID, models.RoleCoOrganizer)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	task := test.CreateTestTask(t, event.ID)
+	task.IsCompleted = true
+	task.AssignedTo = &participant.ID
+	assert.NoError(t, test.TestDB.Save(task).Error)
+
+	clone := func(userID uint, request api.CloneEventRequest) (*httptest.ResponseRecorder, api.APIResponse) {
+		return test.CallHandler(t, userID, "POST", fmt.Sprintf("/events/%d/clone", event.ID), idParams(event.ID), request,
+			handlers.CloneEvent)
+	}
+
+	t.Run("Participant cannot clone", func(t *testing.T) {
+		w, _ := clone(participant.ID, api.CloneEventRequest{})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Clone with a date shift", func(t *testing.T) {
+		name := "Test Event next year"
+		w, response := clone(coOrganizer.ID, api.CloneEventRequest{Name: &name, ShiftDays: 365})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var copied models.Event
+		assert.NoError(t, test.TestDB.Preload("Tasks").First(&copied, responseID(t, response)).Error)
+		assert.Equal(t, name, copied.Name)
+		assert.Equal(t, coOrganizer.ID, copied.OrganizerID)
+		assert.True(t, copied.EventDateTime.Equal(event.EventDateTime.AddDate(0, 0, 365)))
+		assert.Equal(t, event.InitialBudget, copied.InitialBudget)
+
+		assert.Len(t, copied.Tasks, 1)
+		assert.Equal(t, task.Title, copied.Tasks[0].Title)
+		assert.Equal(t, task.Points, copied.Tasks[0].Points)
+		assert.False(t, copied.Tasks[0].IsCompleted)
+		assert.Nil(t, copied.Tasks[0].AssignedTo)
+
+		assert.True(t, isParticipant(t, copied.ID, coOrganizer.ID))
+		assert.False(t, isParticipant(t, copied.ID, participant.ID))
+	})
+}
+
+func TestEventTemplates(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	other := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.CreateTestTask(t, event.ID)
+	test.CreateTestTask(t, event.ID)
+
+	var templateID uint
+
+	t.Run("Only organizers can save a template", func(t *testing.T) {
+		w, _ := test.CallHandler(t, other.ID, "POST", "/templates", nil, api.CreateEventTemplateRequest{EventID: event.ID},
+			handlers.CreateEventTemplate)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Save event as template", func(t *testing.T) {
+		w, response := test.CallHandler(t, organizer.ID, "POST", "/templates", nil, api.CreateEventTemplateRequest{EventID: event.ID, Name: "Party"},
+			handlers.CreateEventTemplate)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		data := response.Data.(map[string]interface{})
+		assert.Equal(t, "Party", data["name"])
+		assert.Equal(t, money.FromMinor(event.InitialBudget, event.Currency), data["initial_budget"])
+		assert.Len(t, data["tasks"], 2)
+		templateID = responseID(t, response)
+	})
+
+	t.Run("Templates are private", func(t *testing.T) {
+		c, w := test.CreateTestContext(t, other.ID)
+		c.Request = httptest.NewRequest("GET", fmt.Sprintf("/templates/%d", templateID), nil)
+		c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", templateID)}}
+
+		handlers.GetEventTemplate(c, test.TestDB)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("List templates", func(t *testing.T) {
+		c, w := test.CreateTestContext(t, organizer.ID)
+		c.Request = httptest.NewRequest("GET", "/templates", nil)
+
+		handlers.GetEventTemplates(c, test.TestDB)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.EventTemplatesResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Templates, 1)
+	})
+
+	t.Run("Create event from template", func(t *testing.T) {
+		eventTime := time.Date(2025, 6, 1, 18, 0, 0, 0, time.UTC)
+		w, response := test.CallHandler(t, organizer.ID, "POST", fmt.Sprintf("/templates/%d/events", templateID), idParams(templateID),
+			api.InstantiateEventTemplateRequest{EventDateTime: eventTime.Format(time.RFC3339)},
+			handlers.InstantiateEventTemplate)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var created models.Event
+		assert.NoError(t, test.TestDB.Preload("Tasks").First(&created, responseID(t, response)).Error)
+		assert.Equal(t, "Party", created.Name)
+		assert.True(t, created.EventDateTime.Equal(eventTime))
+		assert.Equal(t, event.InitialBudget, created.InitialBudget)
+		assert.Len(t, created.Tasks, 2)
+		assert.True(t, isParticipant(t, created.ID, organizer.ID))
+	})
+
+	t.Run("Invalid date", func(t *testing.T) {
+		w, _ := test.CallHandler(t, organizer.ID, "POST", fmt.Sprintf("/templates/%d/events", templateID), idParams(templateID),
+			api.InstantiateEventTemplateRequest{EventDateTime: "2025-06-01"},
+			handlers.InstantiateEventTemplate)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Delete template", func(t *testing.T) {
+		c, w := test.CreateTestContext(t, organizer.ID)
+		c.Request = httptest.NewRequest("DELETE", fmt.Sprintf("/templates/%d", templateID), nil)
+		c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", templateID)}}
+
+		handlers.DeleteEventTemplate(c, test.TestDB)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var count int64
+		test.TestDB.Model(&models.EventTemplateTask{}).Where("template_id = ?", templateID).Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+}
@@ -0,0 +1,449 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"itsplanned/handlers"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/money"
+	"itsplanned/services/exchange"
+	"itsplanned/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func expenseParams(eventID, expenseID uint) gin.Params {
+	return gin.Params{{Key: "id", Value: fmt.Sprintf("%d", eventID)}, {Key: "expense_id", Value: fmt.Sprintf("%d", expenseID)}}
+}
+
+func createExpense(t *testing.T, userID, eventID uint, request api.ExpenseRequest) uint {
+	w, response := test.CallHandler(t, userID, "POST", fmt.Sprintf("/events/%d/expenses", eventID), idParams(eventID), request, handlers.CreateExpense)
+	assert.Equal(t, http.StatusOK, w.Code)
+	return responseID(t, response)
+}
+
+func TestCreateExpense(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	viewer := test.CreateTestUser(t)
+	outsider := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	otherEvent := test.CreateTestEvent(t, outsider.ID)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+	test.AddEventParticipantWithRole(t, event.ID, viewer.ID, models.RoleViewer)
+
+	task := test.CreateTestTask(t, event.ID)
+	assert.NoError(t, test.TestDB.Model(task).Update("category", "decor").Error)
+	otherTask := test.CreateTestTask(t, otherEvent.ID)
+
+	tests := []struct {
+		name          string
+		userID        uint
+		request       api.ExpenseRequest
+		expectedCode  int
+		expectedPayer uint
+		expectedCat   string
+	}{
+		{
+			name:         "Outsider cannot record expenses",
+			userID:       outsider.ID,
+			request:      api.ExpenseRequest{Amount: 10},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "Viewer cannot record expenses",
+			userID:       viewer.ID,
+			request:      api.ExpenseRequest{Amount: 10},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "Negative amount",
+			userID:       participant.ID,
+			request:      api.ExpenseRequest{Amount: -5},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Payer outside the event",
+			userID:       participant.ID,
+			request:      api.ExpenseRequest{Amount: 10, PayerID: &outsider.ID},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Task of another event",
+			userID:       participant.ID,
+			request:      api.ExpenseRequest{Amount: 10, TaskID: &otherTask.ID},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:          "Expense for a task takes its category",
+			userID:        participant.ID,
+			request:       api.ExpenseRequest{Amount: 42.5, Description: "Balloons", TaskID: &task.ID},
+			expectedCode:  http.StatusOK,
+			expectedPayer: participant.ID,
+			expectedCat:   "decor",
+		},
+		{
+			name:          "Paid by somebody else",
+			userID:        participant.ID,
+			request:       api.ExpenseRequest{Amount: 20, PayerID: &organizer.ID, Category: " Food "},
+			expectedCode:  http.StatusOK,
+			expectedPayer: organizer.ID,
+			expectedCat:   "food",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w, response := test.CallHandler(t, tc.userID, "POST", fmt.Sprintf("/events/%d/expenses", event.ID), idParams(event.ID), tc.request, handlers.CreateExpense)
+			assert.Equal(t, tc.expectedCode, w.Code)
+			if tc.expectedCode != http.StatusOK {
+				return
+			}
+
+			var expense models.Expense
+			assert.NoError(t, test.TestDB.First(&expense, responseID(t, response)).Error)
+			assert.Equal(t, tc.expectedPayer, expense.PayerID)
+			assert.Equal(t, tc.userID, expense.CreatedByID)
+			assert.Equal(t, tc.expectedCat, expense.Category)
+			assert.False(t, expense.SpentAt.IsZero())
+		})
+	}
+
+	t.Run("List with total", func(t *testing.T) {
+		c, w := test.CreateTestContext(t, viewer.ID)
+		c.Request = httptest.NewRequest("GET", fmt.Sprintf("/events/%d/expenses?task_id=%d", event.ID, task.ID), nil)
+		c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", event.ID)}}
+		handlers.GetExpenses(c, test.TestDB)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.ExpensesResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Expenses, 1)
+		assert.Equal(t, 42.5, response.Total)
+	})
+}
+
+func TestUpdateAndDeleteExpense(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	author := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipant(t, event.ID, author.ID)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	expenseID := createExpense(t, author.ID, event.ID, api.ExpenseRequest{Amount: 30, Description: "Snacks"})
+
+	t.Run("Other participant cannot update", func(t *testing.T) {
+		w, _ := test.CallHandler(t, participant.ID, "PUT", fmt.Sprintf("/events/%d/expenses/%d", event.ID, expenseID), expenseParams(event.ID, expenseID), api.ExpenseRequest{Amount: 1}, handlers.UpdateExpense)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Author updates", func(t *testing.T) {
+		w, _ := test.CallHandler(t, author.ID, "PUT", fmt.Sprintf("/events/%d/expenses/%d", event.ID, expenseID), expenseParams(event.ID, expenseID), api.ExpenseRequest{Amount: 35, Description: "Snacks and drinks"}, handlers.UpdateExpense)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var expense models.Expense
+		assert.NoError(t, test.TestDB.First(&expense, expenseID).Error)
+		assert.Equal(t, int64(3500), expense.Amount)
+		assert.Equal(t, author.ID, expense.PayerID)
+	})
+
+	t.Run("Other participant cannot delete", func(t *testing.T) {
+		w, _ := test.CallHandler(t, participant.ID, "DELETE", fmt.Sprintf("/events/%d/expenses/%d", event.ID, expenseID), expenseParams(event.ID, expenseID), nil, handlers.DeleteExpense)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Organizer deletes", func(t *testing.T) {
+		w, _ := test.CallHandler(t, organizer.ID, "DELETE", fmt.Sprintf("/events/%d/expenses/%d", event.ID, expenseID), expenseParams(event.ID, expenseID), nil, handlers.DeleteExpense)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w, _ = test.CallHandler(t, organizer.ID, "DELETE", fmt.Sprintf("/events/%d/expenses/%d", event.ID, expenseID), expenseParams(event.ID, expenseID), nil, handlers.DeleteExpense)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestGetEventBudgetWithExpenses(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+
+	decor := test.CreateTestTask(t, event.ID)
+	assert.NoError(t, test.TestDB.Model(decor).Updates(map[string]interface{}{"category": "decor", "is_completed": true}).Error)
+	food := test.CreateTestTask(t, event.ID)
+	assert.NoError(t, test.TestDB.Model(food).Updates(map[string]interface{}{"category": "food", "budget_minor": 20000}).Error)
+
+	createExpense(t, organizer.ID, event.ID, api.ExpenseRequest{Amount: 80, TaskID: &decor.ID})
+	createExpense(t, organizer.ID, event.ID, api.ExpenseRequest{Amount: 150, TaskID: &food.ID})
+	createExpense(t, organizer.ID, event.ID, api.ExpenseRequest{Amount: 70, Category: "food"})
+	createExpense(t, organizer.ID, event.ID, api.ExpenseRequest{Amount: 25})
+
+	getBudget := func() api.EventBudgetResponse {
+		c, w := test.CreateTestContext(t, organizer.ID)
+		c.Request = httptest.NewRequest("GET", fmt.Sprintf("/events/%d/budget", event.ID), nil)
+		c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", event.ID)}}
+		handlers.GetEventBudget(c, test.TestDB)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.EventBudgetResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	budget := getBudget()
+	assert.Equal(t, 100.0, budget.RealBudget)
+	assert.Equal(t, 300.0, budget.Planned)
+	assert.Equal(t, 325.0, budget.Actual)
+	assert.Equal(t, []api.TaskBudgetResponse{
+		{TaskID: decor.ID, Title: decor.Title, Category: "decor", IsCompleted: true, Planned: 100, Actual: 80},
+		{TaskID: food.ID, Title: food.Title, Category: "food", Planned: 200, Actual: 150},
+	}, budget.Tasks)
+	assert.Equal(t, []api.CategoryBudgetResponse{
+		{Category: "decor", Planned: 100, Actual: 80},
+		{Category: "food", Planned: 200, Actual: 220},
+		{Category: "uncategorized", Planned: 0, Actual: 25},
+	}, budget.Categories)
+
+	t.Run("Deleting a task keeps its expenses", func(t *testing.T) {
+		c, w := test.CreateTestContext(t, organizer.ID)
+		c.Request = httptest.NewRequest("DELETE", fmt.Sprintf("/tasks/%d", food.ID), nil)
+		c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", food.ID)}}
+		handlers.DeleteTask(c, test.TestDB)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		budget := getBudget()
+		assert.Equal(t, 325.0, budget.Actual)
+		assert.Len(t, budget.Tasks, 1)
+		assert.Equal(t, []api.CategoryBudgetResponse{
+			{Category: "decor", Planned: 100, Actual: 80},
+			{Category: "food", Planned: 0, Actual: 220},
+			{Category: "uncategorized", Planned: 0, Actual: 25},
+		}, budget.Categories)
+	})
+}
+
+func TestExpenseSplit(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	first := test.CreateTestUser(t)
+	second := test.CreateTestUser(t)
+	viewer := test.CreateTestUser(t)
+	outsider := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipant(t, event.ID, first.ID)
+	test.AddEventParticipant(t, event.ID, second.ID)
+	test.AddEventParticipantWithRole(t, event.ID, viewer.ID, models.RoleViewer)
+
+	tests := []struct {
+		name          string
+		request       api.ExpenseRequest
+		expectedCode  int
+		expectedSplit map[uint]int64
+	}{
+		{
+			name:          "Equal among everybody but viewers by default",
+			request:       api.ExpenseRequest{Amount: 100},
+			expectedCode:  http.StatusOK,
+			expectedSplit: map[uint]int64{organizer.ID: 3334, first.ID: 3333, second.ID: 3333},
+		},
+		{
+			name: "Equal among chosen participants",
+			request: api.ExpenseRequest{Amount: 30, Split: []api.ExpenseSplitRequest{
+				{UserID: first.ID}, {UserID: viewer.ID},
+			}},
+			expectedCode:  http.StatusOK,
+			expectedSplit: map[uint]int64{first.ID: 1500, viewer.ID: 1500},
+		},
+		{
+			name: "By shares",
+			request: api.ExpenseRequest{Amount: 40, SplitMode: models.SplitShares, Split: []api.ExpenseSplitRequest{
+				{UserID: organizer.ID, Shares: 3}, {UserID: first.ID, Shares: 1},
+			}},
+			expectedCode:  http.StatusOK,
+			expectedSplit: map[uint]int64{organizer.ID: 3000, first.ID: 1000},
+		},
+		{
+			name: "By exact amounts",
+			request: api.ExpenseRequest{Amount: 50, SplitMode: models.SplitExact, Split: []api.ExpenseSplitRequest{
+				{UserID: first.ID, Amount: 12.5}, {UserID: second.ID, Amount: 37.5},
+			}},
+			expectedCode:  http.StatusOK,
+			expectedSplit: map[uint]int64{first.ID: 1250, second.ID: 3750},
+		},
+		{
+			name: "Exact amounts must add up",
+			request: api.ExpenseRequest{Amount: 50, SplitMode: models.SplitExact, Split: []api.ExpenseSplitRequest{
+				{UserID: first.ID, Amount: 12.5}, {UserID: second.ID, Amount: 30},
+			}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Shares need a split",
+			request:      api.ExpenseRequest{Amount: 50, SplitMode: models.SplitShares},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "Shares must be positive",
+			request: api.ExpenseRequest{Amount: 50, SplitMode: models.SplitShares, Split: []api.ExpenseSplitRequest{
+				{UserID: first.ID, Shares: 0},
+			}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "Participant twice",
+			request: api.ExpenseRequest{Amount: 50, Split: []api.ExpenseSplitRequest{
+				{UserID: first.ID}, {UserID: first.ID},
+			}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "Outsider in the split",
+			request: api.ExpenseRequest{Amount: 50, Split: []api.ExpenseSplitRequest{
+				{UserID: first.ID}, {UserID: outsider.ID},
+			}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Unknown mode",
+			request:      api.ExpenseRequest{Amount: 50, SplitMode: "percent"},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w, response := test.CallHandler(t, organizer.ID, "POST", fmt.Sprintf("/events/%d/expenses", event.ID), idParams(event.ID), tc.request, handlers.CreateExpense)
+			assert.Equal(t, tc.expectedCode, w.Code)
+			if tc.expectedCode != http.StatusOK {
+				return
+			}
+
+			var shares []models.ExpenseShare
+			assert.NoError(t, test.TestDB.Where("expense_id = ?", responseID(t, response)).Find(&shares).Error)
+			split := map[uint]int64{}
+			for _, share := range shares {
+				split[share.UserID] = share.Amount
+			}
+			assert.Equal(t, tc.expectedSplit, split)
+		})
+	}
+
+	t.Run("Update replaces the split", func(t *testing.T) {
+		expenseID := createExpense(t, organizer.ID, event.ID, api.ExpenseRequest{Amount: 60})
+
+		w, _ := test.CallHandler(t, organizer.ID, "PUT", fmt.Sprintf("/events/%d/expenses/%d", event.ID, expenseID), expenseParams(event.ID, expenseID), api.ExpenseRequest{Amount: 60, Split: []api.ExpenseSplitRequest{
+			{UserID: second.ID},
+		}}, handlers.UpdateExpense)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var shares []models.ExpenseShare
+		assert.NoError(t, test.TestDB.Where("expense_id = ?", expenseID).Find(&shares).Error)
+		assert.Len(t, shares, 1)
+		assert.Equal(t, second.ID, shares[0].UserID)
+		assert.Equal(t, int64(6000), shares[0].Amount)
+
+		w, _ = test.CallHandler(t, organizer.ID, "DELETE", fmt.Sprintf("/events/%d/expenses/%d", event.ID, expenseID), expenseParams(event.ID, expenseID), nil, handlers.DeleteExpense)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var count int64
+		test.TestDB.Model(&models.ExpenseShare{}).Where("expense_id = ?", expenseID).Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+}
+
+func TestExpensesInOtherCurrencies(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	exchange.SetProvider(&exchange.StaticProvider{Base: "RUB", Rates: map[string]float64{"EUR": 0.01}})
+	defer exchange.SetProvider(&exchange.StaticProvider{Base: money.DefaultCurrency})
+
+	organizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	t.Run("Currency without an exchange rate", func(t *testing.T) {
+		w, _ := test.CallHandler(t, organizer.ID, "POST", fmt.Sprintf("/events/%d/expenses", event.ID), idParams(event.ID), api.ExpenseRequest{Amount: 10, Currency: "USD"}, handlers.CreateExpense)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Unknown currency", func(t *testing.T) {
+		w, _ := test.CallHandler(t, organizer.ID, "POST", fmt.Sprintf("/events/%d/expenses", event.ID), idParams(event.ID), api.ExpenseRequest{Amount: 10, Currency: "XYZ"}, handlers.CreateExpense)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	expenseID := createExpense(t, organizer.ID, event.ID, api.ExpenseRequest{Amount: 12.34, Currency: "eur"})
+	createExpense(t, participant.ID, event.ID, api.ExpenseRequest{Amount: 500})
+
+	var expense models.Expense
+	assert.NoError(t, test.TestDB.Preload("Shares").First(&expense, expenseID).Error)
+	assert.Equal(t, "EUR", expense.Currency)
+	assert.Equal(t, int64(1234), expense.Amount)
+	assert.Equal(t, int64(617), expense.Shares[0].Amount)
+
+	c, w := test.CreateTestContext(t, organizer.ID)
+	c.Request = httptest.NewRequest("GET", fmt.Sprintf("/events/%d/budget", event.ID), nil)
+	c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", event.ID)}}
+	handlers.GetEventBudget(c, test.TestDB)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var budget api.EventBudgetResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &budget))
+	assert.Equal(t, "RUB", budget.Currency)
+	assert.Equal(t, 1000.0, budget.InitialBudget)
+	assert.Equal(t, 1734.0, budget.Actual)
+
+	balances := getBalances(t, organizer.ID, event.ID)
+	assert.Equal(t, "RUB", balances.Currency)
+	assert.Equal(t, []api.TransferResponse{{
+		FromUserID: participant.ID,
+		FromName:   participant.DisplayName,
+		ToUserID:   organizer.ID,
+		ToName:     organizer.DisplayName,
+		Amount:     367,
+	}}, balances.Transfers)
+
+	t.Run("Rate no longer available", func(t *testing.T) {
+		exchange.SetProvider(&exchange.StaticProvider{Base: "RUB"})
+
+		w, _ := test.CallHandler(t, organizer.ID, "GET", fmt.Sprintf("/events/%d/budget", event.ID), idParams(event.ID), nil, handlers.GetEventBudget)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var budget api.EventBudgetResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &budget))
+		assert.Equal(t, 500.0, budget.Actual)
+		assert.Equal(t, []string{"EUR"}, budget.UnconvertedCurrencies)
+
+		w, _ = test.CallHandler(t, organizer.ID, "GET", fmt.Sprintf("/events/%d/expenses", event.ID), idParams(event.ID), nil, handlers.GetExpenses)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var expenses api.ExpensesResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &expenses))
+		assert.Len(t, expenses.Expenses, 2)
+		assert.Equal(t, 500.0, expenses.Total)
+		assert.Equal(t, []string{"EUR"}, expenses.UnconvertedCurrencies)
+
+		balances := getBalances(t, organizer.ID, event.ID)
+		assert.Equal(t, []string{"EUR"}, balances.UnconvertedCurrencies)
+		assert.Equal(t, []api.TransferResponse{{
+			FromUserID: organizer.ID,
+			FromName:   organizer.DisplayName,
+			ToUserID:   participant.ID,
+			ToName:     participant.DisplayName,
+			Amount:     250,
+		}}, balances.Transfers)
+	})
+}
@@ -3,6 +3,7 @@ package handlers_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"itsplanned/handlers"
 	"itsplanned/models"
 	"itsplanned/models/api"
@@ -10,6 +11,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -26,6 +28,11 @@ func TestGenerateInviteLink(t *testing.T) {
 
 	test.AddEventParticipant(t, event.ID, participant.ID)
 
+	future := time.Now().Add(24 * time.Hour)
+	past := time.Now().Add(-time.Hour)
+	maxUses := 5
+	zeroUses := 0
+
 	testCases := []struct {
 		name         string
 		userID       uint
@@ -41,8 +48,8 @@ func TestGenerateInviteLink(t *testing.T) {
 			},
 			expectedCode: http.StatusOK,
 			validateFunc: func(t *testing.T, response *api.GenerateInviteLinkResponse) {
-				assert.Contains(t, response.InviteLink, "http://localhost:8080/events/join/")
-				assert.Len(t, response.InviteLink, len("http://localhost:8080/events/join/")+16)
+				assert.Contains(t, response.InviteLink, "http://localhost:8080/events/redirect/")
+				assert.Len(t, response.InviteLink, len("http://localhost:8080/events/redirect/")+16)
 
 				var invitation models.EventInvitation
 				err := test.TestDB.Where("event_id = ?", event.ID).First(&invitation).Error
@@ -50,6 +57,44 @@ func TestGenerateInviteLink(t *testing.T) {
 				assert.Equal(t, event.ID, invitation.EventID)
 			},
 		},
+		{
+			name:   "Generate limited invite link",
+			userID: organizer.ID,
+			request: api.GenerateInviteLinkRequest{
+				EventID:   event.ID,
+				ExpiresAt: &future,
+				MaxUses:   &maxUses,
+			},
+			expectedCode: http.StatusOK,
+			validateFunc: func(t *testing.T, response *api.GenerateInviteLinkResponse) {
+				var invitation models.EventInvitation
+				err := test.TestDB.Where("invite_code = ?", response.InviteCode).First(&invitation).Error
+				assert.NoError(t, err)
+				assert.Equal(t, maxUses, *invitation.MaxUses)
+				assert.NotNil(t, invitation.ExpiresAt)
+				assert.Equal(t, organizer.ID, invitation.CreatedByID)
+			},
+		},
+		{
+			name:   "Expiry in the past",
+			userID: organizer.ID,
+			request: api.GenerateInviteLinkRequest{
+				EventID:   event.ID,
+				ExpiresAt: &past,
+			},
+			expectedCode: http.StatusBadRequest,
+			validateFunc: nil,
+		},
+		{
+			name:   "Zero max uses",
+			userID: organizer.ID,
+			request: api.GenerateInviteLinkRequest{
+				EventID: event.ID,
+				MaxUses: &zeroUses,
+			},
+			expectedCode: http.StatusBadRequest,
+			validateFunc: nil,
+		},
 		{
 			name:   "Generate invite link for non-existent event",
 			userID: organizer.ID,
@@ -67,7 +112,7 @@ func TestGenerateInviteLink(t *testing.T) {
 			},
 			expectedCode: http.StatusOK,
 			validateFunc: func(t *testing.T, response *api.GenerateInviteLinkResponse) {
-				assert.Contains(t, response.InviteLink, "http://localhost:8080/events/join/")
+				assert.Contains(t, response.InviteLink, "http://localhost:8080/events/redirect/")
 			},
 		},
 	}
@@ -117,6 +162,24 @@ func TestJoinEvent(t *testing.T) {
 	err := test.TestDB.Create(&invitation).Error
 	assert.NoError(t, err)
 
+	expiredAt := time.Now().Add(-time.Hour)
+	expiredInvitation := createTestInvitation(t, event.ID, organizer.ID, func(i *models.EventInvitation) { i.ExpiresAt = &expiredAt })
+
+	oneUse := 1
+	singleUseInvitation := createTestInvitation(t, event.ID, organizer.ID, func(i *models.EventInvitation) { i.MaxUses = &oneUse })
+
+	revokedAt := time.Now()
+	revokedInvitation := createTestInvitation(t, event.ID, organizer.ID, func(i *models.EventInvitation) { i.RevokedAt = &revokedAt })
+
+	approvalInvitation := createTestInvitation(t, event.ID, organizer.ID, func(i *models.EventInvitation) { i.RequiresApproval = true })
+
+	secondUser := test.CreateTestUser(t)
+	requester := test.CreateTestUser(t)
+
+	rejectedUser := test.CreateTestUser(t)
+	rejectedRequest := models.JoinRequest{EventID: event.ID, UserID: rejectedUser.ID, InvitationID: approvalInvitation.ID, Status: models.JoinRequestRejected}
+	assert.NoError(t, test.TestDB.Create(&rejectedRequest).Error)
+
 	testCases := []struct {
 		name         string
 		userID       uint
@@ -142,6 +205,39 @@ func TestJoinEvent(t *testing.T) {
 			expectedCode: http.StatusNotFound,
 			validateFunc: nil,
 		},
+		{
+			name:         "Join event with expired invite code",
+			userID:       secondUser.ID,
+			inviteCode:   expiredInvitation.InviteCode,
+			expectedCode: http.StatusGone,
+			validateFunc: nil,
+		},
+		{
+			name:         "Join event with revoked invite code",
+			userID:       secondUser.ID,
+			inviteCode:   revokedInvitation.InviteCode,
+			expectedCode: http.StatusGone,
+			validateFunc: nil,
+		},
+		{
+			name:         "Join event with single use invite code",
+			userID:       secondUser.ID,
+			inviteCode:   singleUseInvitation.InviteCode,
+			expectedCode: http.StatusOK,
+			validateFunc: func(t *testing.T, response *api.JoinEventResponse) {
+				var used models.EventInvitation
+				err := test.TestDB.First(&used, singleUseInvitation.ID).Error
+				assert.NoError(t, err)
+				assert.Equal(t, 1, used.Uses)
+			},
+		},
+		{
+			name:         "Join event with used up invite code",
+			userID:       test.CreateTestUser(t).ID,
+			inviteCode:   singleUseInvitation.InviteCode,
+			expectedCode: http.StatusGone,
+			validateFunc: nil,
+		},
 		{
 			name:         "Join event as existing participant",
 			userID:       participant.ID,
@@ -149,6 +245,42 @@ func TestJoinEvent(t *testing.T) {
 			expectedCode: http.StatusBadRequest,
 			validateFunc: nil,
 		},
+		{
+			name:         "Join event with invite code requiring approval",
+			userID:       requester.ID,
+			inviteCode:   approvalInvitation.InviteCode,
+			expectedCode: http.StatusAccepted,
+			validateFunc: func(t *testing.T, response *api.JoinEventResponse) {
+				assert.Equal(t, models.JoinRequestPending, response.Status)
+
+				var joinRequest models.JoinRequest
+				err := test.TestDB.Where("event_id = ? AND user_id = ?", event.ID, requester.ID).First(&joinRequest).Error
+				assert.NoError(t, err)
+				assert.Equal(t, models.JoinRequestPending, joinRequest.Status)
+
+				var count int64
+				test.TestDB.Model(&models.EventParticipation{}).Where("event_id = ? AND user_id = ?", event.ID, requester.ID).Count(&count)
+				assert.Equal(t, int64(0), count)
+
+				var unused models.EventInvitation
+				assert.NoError(t, test.TestDB.First(&unused, approvalInvitation.ID).Error)
+				assert.Equal(t, 0, unused.Uses)
+			},
+		},
+		{
+			name:         "Join event with pending join request",
+			userID:       requester.ID,
+			inviteCode:   approvalInvitation.InviteCode,
+			expectedCode: http.StatusBadRequest,
+			validateFunc: nil,
+		},
+		{
+			name:         "Join event after rejected join request",
+			userID:       rejectedUser.ID,
+			inviteCode:   approvalInvitation.InviteCode,
+			expectedCode: http.StatusForbidden,
+			validateFunc: nil,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -162,7 +294,7 @@ func TestJoinEvent(t *testing.T) {
 
 			assert.Equal(t, tc.expectedCode, w.Code)
 
-			if tc.expectedCode == http.StatusOK && tc.validateFunc != nil {
+			if tc.expectedCode < http.StatusBadRequest && tc.validateFunc != nil {
 				var response api.JoinEventResponse
 				err := json.Unmarshal(w.Body.Bytes(), &response)
 				assert.NoError(t, err)
@@ -172,3 +304,150 @@ func TestJoinEvent(t *testing.T) {
 		})
 	}
 }
+
+func createTestInvitation(t *testing.T, eventID, createdByID uint, configure func(*models.EventInvitation)) *models.EventInvitation {
+	invitation := &models.EventInvitation{
+		EventID:     eventID,
+		InviteCode:  models.GenerateUniqueInviteCode(test.TestDB),
+		CreatedByID: createdByID,
+	}
+	if configure != nil {
+		configure(invitation)
+	}
+
+	err := test.TestDB.Create(invitation).Error
+	assert.NoError(t, err)
+	return invitation
+}
+
+func TestGetEventInvitations(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+
+	viewer := test.CreateTestUser(t)
+	test.AddEventParticipantWithRole(t, event.ID, viewer.ID, models.RoleViewer)
+
+	active := createTestInvitation(t, event.ID, organizer.ID, nil)
+
+	expiredAt := time.Now().Add(-time.Hour)
+	createTestInvitation(t, event.ID, organizer.ID, func(i *models.EventInvitation) { i.ExpiresAt = &expiredAt })
+
+	revokedAt := time.Now()
+	createTestInvitation(t, event.ID, organizer.ID, func(i *models.EventInvitation) { i.RevokedAt = &revokedAt })
+
+	maxUses := 2
+	createTestInvitation(t, event.ID, organizer.ID, func(i *models.EventInvitation) {
+		i.MaxUses = &maxUses
+		i.Uses = 2
+	})
+
+	t.Run("Organizer sees only active invites", func(t *testing.T) {
+		c, w := test.CreateTestContext(t, organizer.ID)
+		c.Request = httptest.NewRequest("GET", fmt.Sprintf("/events/%d/invites", event.ID), nil)
+		c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", event.ID)}}
+
+		handlers.GetEventInvitations(c, test.TestDB)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.InvitationsResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		if assert.Len(t, response.Invitations, 1) {
+			assert.Equal(t, active.InviteCode, response.Invitations[0].InviteCode)
+		}
+	})
+
+	t.Run("Viewer cannot list invites", func(t *testing.T) {
+		c, w := test.CreateTestContext(t, viewer.ID)
+		c.Request = httptest.NewRequest("GET", fmt.Sprintf("/events/%d/invites", event.ID), nil)
+		c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", event.ID)}}
+
+		handlers.GetEventInvitations(c, test.TestDB)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestRevokeInviteLink(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+
+	participant := test.CreateTestUser(t)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	otherParticipant := test.CreateTestUser(t)
+	test.AddEventParticipant(t, event.ID, otherParticipant.ID)
+
+	participantInvitation := createTestInvitation(t, event.ID, participant.ID, nil)
+	organizerInvitation := createTestInvitation(t, event.ID, organizer.ID, nil)
+
+	testCases := []struct {
+		name         string
+		userID       uint
+		inviteCode   string
+		expectedCode int
+	}{
+		{
+			name:         "Participant cannot revoke someone else's link",
+			userID:       otherParticipant.ID,
+			inviteCode:   participantInvitation.InviteCode,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "Creator revokes own link",
+			userID:       participant.ID,
+			inviteCode:   participantInvitation.InviteCode,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Link cannot be revoked twice",
+			userID:       participant.ID,
+			inviteCode:   participantInvitation.InviteCode,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Organizer revokes any link",
+			userID:       organizer.ID,
+			inviteCode:   organizerInvitation.InviteCode,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Unknown link",
+			userID:       organizer.ID,
+			inviteCode:   "invalid",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := test.CreateTestContext(t, tc.userID)
+			c.Request = httptest.NewRequest("DELETE", "/events/invite/"+tc.inviteCode, nil)
+			c.Params = []gin.Param{{Key: "invite_code", Value: tc.inviteCode}}
+
+			handlers.RevokeInviteLink(c, test.TestDB)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+
+	t.Run("Revoked link shows distinct error on redirect", func(t *testing.T) {
+		c, w := test.CreateTestContext(t, 0)
+		c.Request = httptest.NewRequest("GET", "/events/redirect/"+organizerInvitation.InviteCode, nil)
+		c.Params = []gin.Param{{Key: "invite_code", Value: organizerInvitation.InviteCode}}
+
+		handlers.DeepLinkRedirect(c, test.TestDB)
+
+		assert.Equal(t, http.StatusGone, w.Code)
+
+		var response api.APIResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Invite link has been revoked", response.Error)
+	})
+}
@@ -0,0 +1,261 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"itsplanned/handlers"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestJoinRequest(t *testing.T, eventID, userID uint) *models.JoinRequest {
+	invitation := createTestInvitation(t, eventID, 0, func(i *models.EventInvitation) { i.RequiresApproval = true })
+
+	joinRequest := &models.JoinRequest{
+		EventID:      eventID,
+		UserID:       userID,
+		InvitationID: invitation.ID,
+		Status:       models.JoinRequestPending,
+	}
+	assert.NoError(t, test.TestDB.Create(joinRequest).Error)
+	return joinRequest
+}
+
+func TestGetJoinRequests(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	requester := test.CreateTestUser(t)
+	pending := createTestJoinRequest(t, event.ID, requester.ID)
+
+	decided := createTestJoinRequest(t, event.ID, test.CreateTestUser(t).ID)
+	assert.NoError(t, test.TestDB.Model(decided).Update("status", models.JoinRequestRejected).Error)
+
+	testCases := []struct {
+		name          string
+		userID        uint
+		expectedCode  int
+		expectedCount int
+	}{
+		{
+			name:          "Organizer sees pending requests",
+			userID:        organizer.ID,
+			expectedCode:  http.StatusOK,
+			expectedCount: 1,
+		},
+		{
+			name:         "Participant cannot see join requests",
+			userID:       participant.ID,
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := test.CreateTestContext(t, tc.userID)
+			c.Request = httptest.NewRequest("GET", fmt.Sprintf("/events/%d/join-requests", event.ID), nil)
+			c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", event.ID)}}
+
+			handlers.GetJoinRequests(c, test.TestDB)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				var response api.JoinRequestsResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Len(t, response.JoinRequests, tc.expectedCount)
+				assert.Equal(t, pending.ID, response.JoinRequests[0].ID)
+				assert.Equal(t, requester.DisplayName, response.JoinRequests[0].DisplayName)
+			}
+		})
+	}
+}
+
+func TestDecideJoinRequest(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	coOrganizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipantWithRole(t, event.ID, coOrganizer.ID, models.RoleCoOrganizer)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	approvedUser := test.CreateTestUser(t)
+	toApprove := createTestJoinRequest(t, event.ID, approvedUser.ID)
+
+	rejectedUser := test.CreateTestUser(t)
+	toReject := createTestJoinRequest(t, event.ID, rejectedUser.ID)
+
+	testCases := []struct {
+		name         string
+		userID       uint
+		requestID    uint
+		approve      bool
+		expectedCode int
+	}{
+		{
+			name:         "Participant cannot approve",
+			userID:       participant.ID,
+			requestID:    toApprove.ID,
+			approve:      true,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "Organizer approves request",
+			userID:       organizer.ID,
+			requestID:    toApprove.ID,
+			approve:      true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Approved request cannot be rejected",
+			userID:       coOrganizer.ID,
+			requestID:    toApprove.ID,
+			approve:      false,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Co-organizer rejects request",
+			userID:       coOrganizer.ID,
+			requestID:    toReject.ID,
+			approve:      false,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Unknown request",
+			userID:       organizer.ID,
+			requestID:    9999,
+			approve:      true,
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			action := "reject"
+			if tc.approve {
+				action = "approve"
+			}
+
+			c, w := test.CreateTestContext(t, tc.userID)
+			c.Request = httptest.NewRequest("POST", fmt.Sprintf("/events/%d/join-requests/%d/%s", event.ID, tc.requestID, action), nil)
+			c.Params = []gin.Param{
+				{Key: "id", Value: fmt.Sprintf("%d", event.ID)},
+				{Key: "request_id", Value: fmt.Sprintf("%d", tc.requestID)},
+			}
+
+			if tc.approve {
+				handlers.ApproveJoinRequest(c, test.TestDB)
+			} else {
+				handlers.RejectJoinRequest(c, test.TestDB)
+			}
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+
+	var participation models.EventParticipation
+	err := test.TestDB.Where("event_id = ? AND user_id = ?", event.ID, approvedUser.ID).First(&participation).Error
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleParticipant, participation.Role)
+
+	var count int64
+	test.TestDB.Model(&models.EventParticipation{}).Where("event_id = ? AND user_id = ?", event.ID, rejectedUser.ID).Count(&count)
+	assert.Equal(t, int64(0), count)
+
+	var notifications []models.TaskStatusEvent
+	assert.NoError(t, test.TestDB.Where("event_id = ?", event.ID).Order("user_id").Find(&notifications).Error)
+	assert.Len(t, notifications, 2)
+	assert.Equal(t, approvedUser.ID, notifications[0].UserID)
+	assert.Equal(t, models.JoinRequestApproved, notifications[0].NewStatus)
+	assert.Equal(t, rejectedUser.ID, notifications[1].UserID)
+	assert.Equal(t, models.JoinRequestRejected, notifications[1].NewStatus)
+}
+
+func TestApproveJoinRequestOfParticipant(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	user := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	joinRequest := createTestJoinRequest(t, event.ID, user.ID)
+
+	// The user joined with another link while the request was pending
+	test.AddEventParticipant(t, event.ID, user.ID)
+
+	c, w := test.CreateTestContext(t, organizer.ID)
+	c.Request = httptest.NewRequest("POST", fmt.Sprintf("/events/%d/join-requests/%d/approve", event.ID, joinRequest.ID), nil)
+	c.Params = []gin.Param{
+		{Key: "id", Value: fmt.Sprintf("%d", event.ID)},
+		{Key: "request_id", Value: fmt.Sprintf("%d", joinRequest.ID)},
+	}
+	handlers.ApproveJoinRequest(c, test.TestDB)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var count int64
+	test.TestDB.Model(&models.EventParticipation{}).Where("event_id = ? AND user_id = ?", event.ID, user.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	// A user can't be in the event twice, even when concurrent joins get past the checks
+	duplicate := models.EventParticipation{EventID: event.ID, UserID: user.ID, Role: models.RoleParticipant}
+	assert.Error(t, test.TestDB.Create(&duplicate).Error)
+}
+
+func TestApproveJoinRequestUsesInvitation(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+
+	first := createTestJoinRequest(t, event.ID, test.CreateTestUser(t).ID)
+	oneUse := 1
+	assert.NoError(t, test.TestDB.Model(&models.EventInvitation{}).Where("id = ?", first.InvitationID).Update("max_uses", oneUse).Error)
+
+	second := &models.JoinRequest{EventID: event.ID, UserID: test.CreateTestUser(t).ID, InvitationID: first.InvitationID, Status: models.JoinRequestPending}
+	assert.NoError(t, test.TestDB.Create(second).Error)
+
+	approve := func(joinRequest *models.JoinRequest) int {
+		c, w := test.CreateTestContext(t, organizer.ID)
+		c.Request = httptest.NewRequest("POST", fmt.Sprintf("/events/%d/join-requests/%d/approve", event.ID, joinRequest.ID), nil)
+		c.Params = []gin.Param{
+			{Key: "id", Value: fmt.Sprintf("%d", event.ID)},
+			{Key: "request_id", Value: fmt.Sprintf("%d", joinRequest.ID)},
+		}
+		handlers.ApproveJoinRequest(c, test.TestDB)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, approve(first))
+
+	var invitation models.EventInvitation
+	assert.NoError(t, test.TestDB.First(&invitation, first.InvitationID).Error)
+	assert.Equal(t, 1, invitation.Uses)
+
+	// The link is used up, the second request stays pending
+	assert.Equal(t, http.StatusGone, approve(second))
+
+	var pending models.JoinRequest
+	assert.NoError(t, test.TestDB.First(&pending, second.ID).Error)
+	assert.Equal(t, models.JoinRequestPending, pending.Status)
+
+	var count int64
+	test.TestDB.Model(&models.EventParticipation{}).Where("event_id = ? AND user_id = ?", event.ID, second.UserID).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
@@ -0,0 +1,157 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"itsplanned/handlers"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateParticipantRole(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+
+	coOrganizer := test.CreateTestUser(t)
+	test.AddEventParticipantWithRole(t, event.ID, coOrganizer.ID, models.RoleCoOrganizer)
+
+	participant := test.CreateTestUser(t)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	otherParticipant := test.CreateTestUser(t)
+	test.AddEventParticipant(t, event.ID, otherParticipant.ID)
+
+	outsider := test.CreateTestUser(t)
+
+	testCases := []struct {
+		name          string
+		userID        uint
+		participantID uint
+		role          string
+		expectedCode  int
+	}{
+		{
+			name:          "Organizer promotes participant to co-organizer",
+			userID:        organizer.ID,
+			participantID: participant.ID,
+			role:          models.RoleCoOrganizer,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			name:          "Co-organizer demotes participant to viewer",
+			userID:        coOrganizer.ID,
+			participantID: otherParticipant.ID,
+			role:          models.RoleViewer,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			name:          "Co-organizer cannot demote another co-organizer",
+			userID:        coOrganizer.ID,
+			participantID: participant.ID,
+			role:          models.RoleParticipant,
+			expectedCode:  http.StatusForbidden,
+		},
+		{
+			name:          "Co-organizer cannot change the organizer",
+			userID:        coOrganizer.ID,
+			participantID: organizer.ID,
+			role:          models.RoleViewer,
+			expectedCode:  http.StatusForbidden,
+		},
+		{
+			name:          "Organizer role cannot be granted",
+			userID:        organizer.ID,
+			participantID: participant.ID,
+			role:          models.RoleOrganizer,
+			expectedCode:  http.StatusForbidden,
+		},
+		{
+			name:          "Viewer cannot change roles",
+			userID:        otherParticipant.ID,
+			participantID: participant.ID,
+			role:          models.RoleViewer,
+			expectedCode:  http.StatusForbidden,
+		},
+		{
+			name:          "Unknown role",
+			userID:        organizer.ID,
+			participantID: participant.ID,
+			role:          "admin",
+			expectedCode:  http.StatusBadRequest,
+		},
+		{
+			name:          "Non-participant target",
+			userID:        organizer.ID,
+			participantID: outsider.ID,
+			role:          models.RoleViewer,
+			expectedCode:  http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := test.CreateTestContext(t, tc.userID)
+
+			requestJSON, err := json.Marshal(api.UpdateParticipantRoleRequest{Role: tc.role})
+			assert.NoError(t, err)
+
+			c.Request = httptest.NewRequest("PUT", fmt.Sprintf("/events/%d/participants/%d/role", event.ID, tc.participantID), bytes.NewBuffer(requestJSON))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = []gin.Param{
+				{Key: "id", Value: fmt.Sprintf("%d", event.ID)},
+				{Key: "user_id", Value: fmt.Sprintf("%d", tc.participantID)},
+			}
+
+			handlers.UpdateParticipantRole(c, test.TestDB)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				var participation models.EventParticipation
+				err := test.TestDB.Where("event_id = ? AND user_id = ?", event.ID, tc.participantID).First(&participation).Error
+				assert.NoError(t, err)
+				assert.Equal(t, tc.role, participation.Role)
+			}
+		})
+	}
+
+	t.Run("Promoted co-organizer can update tasks", func(t *testing.T) {
+		task := test.CreateTestTask(t, event.ID)
+		title := "Updated by co-organizer"
+
+		c, w := test.CreateTestContext(t, participant.ID)
+		requestJSON, err := json.Marshal(api.UpdateTaskRequest{Title: &title})
+		assert.NoError(t, err)
+
+		c.Request = httptest.NewRequest("PUT", fmt.Sprintf("/tasks/%d", task.ID), bytes.NewBuffer(requestJSON))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", task.ID)}}
+
+		handlers.UpdateTask(c, test.TestDB)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Demoted viewer cannot take tasks", func(t *testing.T) {
+		task := test.CreateTestTask(t, event.ID)
+
+		c, w := test.CreateTestContext(t, otherParticipant.ID)
+		c.Request = httptest.NewRequest("PUT", fmt.Sprintf("/tasks/%d/assign", task.ID), nil)
+		c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", task.ID)}}
+
+		handlers.AssignToTask(c, test.TestDB)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
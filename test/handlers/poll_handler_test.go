@@ -0,0 +1,312 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"itsplanned/handlers"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/test"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decodePoll(t *testing.T, response api.APIResponse) api.PollResponse {
+	data, err := json.Marshal(response.Data)
+	assert.NoError(t, err)
+
+	var poll api.PollResponse
+	assert.NoError(t, json.Unmarshal(data, &poll))
+	return poll
+}
+
+func createPoll(t *testing.T, userID, eventID uint, request api.CreatePollRequest) api.PollResponse {
+	w, response := test.CallHandler(t, userID, "POST", fmt.Sprintf("/events/%d/polls", eventID), idParams(eventID), request, handlers.CreatePoll)
+	assert.Equal(t, http.StatusOK, w.Code)
+	return decodePoll(t, response)
+}
+
+func getPoll(t *testing.T, userID, eventID, pollID uint) api.PollResponse {
+	w, response := test.CallHandler(t, userID, "GET", fmt.Sprintf("/events/%d/polls/%d", eventID, pollID), pollParams(eventID, pollID), nil, handlers.GetPoll)
+	assert.Equal(t, http.StatusOK, w.Code)
+	return decodePoll(t, response)
+}
+
+func TestCreatePoll(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	viewer := test.CreateTestUser(t)
+	outsider := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+	test.AddEventParticipantWithRole(t, event.ID, viewer.ID, models.RoleViewer)
+
+	options := []string{"Pizza", "Sushi"}
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name         string
+		userID       uint
+		request      api.CreatePollRequest
+		expectedCode int
+	}{
+		{
+			name:         "Outsider cannot create a poll",
+			userID:       outsider.ID,
+			request:      api.CreatePollRequest{Question: "Menu?", Options: options},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "Viewer cannot create a poll",
+			userID:       viewer.ID,
+			request:      api.CreatePollRequest{Question: "Menu?", Options: options},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "Blank question",
+			userID:       participant.ID,
+			request:      api.CreatePollRequest{Question: "  ", Options: options},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Single option",
+			userID:       participant.ID,
+			request:      api.CreatePollRequest{Question: "Menu?", Options: options[:1]},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Same option twice",
+			userID:       participant.ID,
+			request:      api.CreatePollRequest{Question: "Menu?", Options: []string{"Pizza", " pizza"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Invalid results visibility",
+			userID:       participant.ID,
+			request:      api.CreatePollRequest{Question: "Menu?", Options: options, ResultsVisibility: "never"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Deadline in the past",
+			userID:       participant.ID,
+			request:      api.CreatePollRequest{Question: "Menu?", Options: options, Deadline: &past},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Participant creates a poll",
+			userID:       participant.ID,
+			request:      api.CreatePollRequest{Question: "Menu?", Options: options, MultipleChoice: true, Deadline: &future},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w, _ := test.CallHandler(t, tc.userID, "POST", fmt.Sprintf("/events/%d/polls", event.ID), idParams(event.ID), tc.request, handlers.CreatePoll)
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+
+	t.Run("Results are visible by default", func(t *testing.T) {
+		poll := createPoll(t, organizer.ID, event.ID, api.CreatePollRequest{Question: "Theme?", Options: []string{"Retro", "Space"}})
+		assert.Equal(t, models.PollResultsAlways, poll.ResultsVisibility)
+		assert.Equal(t, models.PollOpen, poll.Status)
+		assert.True(t, poll.ResultsVisible)
+		assert.Equal(t, "Retro", poll.Options[0].Text)
+	})
+}
+
+func TestVotePoll(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	viewer := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+	test.AddEventParticipantWithRole(t, event.ID, viewer.ID, models.RoleViewer)
+
+	single := createPoll(t, organizer.ID, event.ID, api.CreatePollRequest{Question: "Venue?", Options: []string{"Park", "Cafe", "Home"}})
+	multiple := createPoll(t, organizer.ID, event.ID, api.CreatePollRequest{Question: "Menu?", Options: []string{"Pizza", "Sushi", "Salad"}, MultipleChoice: true})
+
+	tests := []struct {
+		name         string
+		userID       uint
+		poll         api.PollResponse
+		optionIDs    []uint
+		expectedCode int
+	}{
+		{"Viewer cannot vote", viewer.ID, single, []uint{single.Options[0].ID}, http.StatusForbidden},
+		{"Two options in a single choice poll", participant.ID, single, []uint{single.Options[0].ID, single.Options[1].ID}, http.StatusBadRequest},
+		{"Option of another poll", participant.ID, single, []uint{multiple.Options[0].ID}, http.StatusBadRequest},
+		{"Same option twice", participant.ID, multiple, []uint{multiple.Options[0].ID, multiple.Options[0].ID}, http.StatusBadRequest},
+		{"Single choice", participant.ID, single, []uint{single.Options[0].ID}, http.StatusOK},
+		{"Changed choice", participant.ID, single, []uint{single.Options[1].ID}, http.StatusOK},
+		{"Multiple choice", participant.ID, multiple, []uint{multiple.Options[0].ID, multiple.Options[2].ID}, http.StatusOK},
+		{"Organizer votes", organizer.ID, multiple, []uint{multiple.Options[0].ID}, http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w, _ := test.CallHandler(t, tc.userID, "PUT", fmt.Sprintf("/events/%d/polls/%d/votes", event.ID, tc.poll.ID), pollParams(event.ID, tc.poll.ID), api.PollVoteRequest{OptionIDs: tc.optionIDs}, handlers.VotePoll)
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+
+	t.Run("Tallies", func(t *testing.T) {
+		poll := getPoll(t, participant.ID, event.ID, single.ID)
+		assert.Equal(t, 0, *poll.Options[0].Votes)
+		assert.Equal(t, 1, *poll.Options[1].Votes)
+		assert.True(t, poll.Options[1].Selected)
+		assert.Equal(t, []uint{participant.ID}, poll.Options[1].VoterIDs)
+
+		poll = getPoll(t, participant.ID, event.ID, multiple.ID)
+		assert.Equal(t, 2, *poll.Options[0].Votes)
+		assert.Equal(t, 0, *poll.Options[1].Votes)
+		assert.Equal(t, 1, *poll.Options[2].Votes)
+	})
+
+	t.Run("Empty choice withdraws the vote", func(t *testing.T) {
+		w, _ := test.CallHandler(t, participant.ID, "PUT", fmt.Sprintf("/events/%d/polls/%d/votes", event.ID, multiple.ID), pollParams(event.ID, multiple.ID), api.PollVoteRequest{}, handlers.VotePoll)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		poll := getPoll(t, participant.ID, event.ID, multiple.ID)
+		assert.Equal(t, 1, *poll.Options[0].Votes)
+		assert.Equal(t, 0, *poll.Options[2].Votes)
+		assert.False(t, poll.Options[0].Selected)
+	})
+}
+
+func TestPollResultsVisibility(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	vote := func(userID uint, poll api.PollResponse) {
+		w, _ := test.CallHandler(t, userID, "PUT", fmt.Sprintf("/events/%d/polls/%d/votes", event.ID, poll.ID), pollParams(event.ID, poll.ID), api.PollVoteRequest{OptionIDs: []uint{poll.Options[0].ID}}, handlers.VotePoll)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	t.Run("After vote", func(t *testing.T) {
+		poll := createPoll(t, organizer.ID, event.ID, api.CreatePollRequest{Question: "Venue?", Options: []string{"Park", "Cafe"}, ResultsVisibility: models.PollResultsAfterVote})
+		vote(organizer.ID, poll)
+
+		hidden := getPoll(t, participant.ID, event.ID, poll.ID)
+		assert.False(t, hidden.ResultsVisible)
+		assert.Nil(t, hidden.Options[0].Votes)
+		assert.Empty(t, hidden.Options[0].VoterIDs)
+
+		vote(participant.ID, poll)
+		shown := getPoll(t, participant.ID, event.ID, poll.ID)
+		assert.True(t, shown.ResultsVisible)
+		assert.Equal(t, 2, *shown.Options[0].Votes)
+	})
+
+	t.Run("After close", func(t *testing.T) {
+		poll := createPoll(t, organizer.ID, event.ID, api.CreatePollRequest{Question: "Theme?", Options: []string{"Retro", "Space"}, ResultsVisibility: models.PollResultsAfterClose})
+		vote(participant.ID, poll)
+
+		assert.False(t, getPoll(t, participant.ID, event.ID, poll.ID).ResultsVisible)
+		assert.True(t, getPoll(t, organizer.ID, event.ID, poll.ID).ResultsVisible)
+
+		w, _ := test.CallHandler(t, organizer.ID, "POST", fmt.Sprintf("/events/%d/polls/%d/close", event.ID, poll.ID), pollParams(event.ID, poll.ID), nil, handlers.ClosePoll)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		shown := getPoll(t, participant.ID, event.ID, poll.ID)
+		assert.True(t, shown.ResultsVisible)
+		assert.Equal(t, 1, *shown.Options[0].Votes)
+	})
+
+	t.Run("Anonymous", func(t *testing.T) {
+		poll := createPoll(t, organizer.ID, event.ID, api.CreatePollRequest{Question: "Budget?", Options: []string{"Low", "High"}, Anonymous: true})
+		vote(participant.ID, poll)
+
+		shown := getPoll(t, organizer.ID, event.ID, poll.ID)
+		assert.Equal(t, 1, *shown.Options[0].Votes)
+		assert.Empty(t, shown.Options[0].VoterIDs)
+		assert.True(t, getPoll(t, participant.ID, event.ID, poll.ID).Options[0].Selected)
+	})
+}
+
+func TestClosePoll(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	creator := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipant(t, event.ID, creator.ID)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	countNotifications := func(userID uint) int64 {
+		var count int64
+		test.TestDB.Model(&models.TaskStatusEvent{}).
+			Where("user_id = ? AND event_id = ? AND new_status = ?", userID, event.ID, models.PollClosed).
+			Count(&count)
+		return count
+	}
+
+	poll := createPoll(t, creator.ID, event.ID, api.CreatePollRequest{Question: "Venue?", Options: []string{"Park", "Cafe"}})
+
+	t.Run("Other participant cannot close", func(t *testing.T) {
+		w, _ := test.CallHandler(t, participant.ID, "POST", fmt.Sprintf("/events/%d/polls/%d/close", event.ID, poll.ID), pollParams(event.ID, poll.ID), nil, handlers.ClosePoll)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Creator closes the poll", func(t *testing.T) {
+		w, response := test.CallHandler(t, creator.ID, "POST", fmt.Sprintf("/events/%d/polls/%d/close", event.ID, poll.ID), pollParams(event.ID, poll.ID), nil, handlers.ClosePoll)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, models.PollClosed, decodePoll(t, response).Status)
+
+		assert.Equal(t, int64(1), countNotifications(organizer.ID))
+		assert.Equal(t, int64(1), countNotifications(participant.ID))
+		assert.Equal(t, int64(0), countNotifications(creator.ID))
+
+		var notification models.TaskStatusEvent
+		assert.NoError(t, test.TestDB.Where("user_id = ? AND event_id = ?", participant.ID, event.ID).First(&notification).Error)
+		assert.Equal(t, "Venue?", notification.TaskName)
+	})
+
+	t.Run("Closed poll", func(t *testing.T) {
+		w, _ := test.CallHandler(t, organizer.ID, "POST", fmt.Sprintf("/events/%d/polls/%d/close", event.ID, poll.ID), pollParams(event.ID, poll.ID), nil, handlers.ClosePoll)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w, _ = test.CallHandler(t, participant.ID, "PUT", fmt.Sprintf("/events/%d/polls/%d/votes", event.ID, poll.ID), pollParams(event.ID, poll.ID), api.PollVoteRequest{OptionIDs: []uint{poll.Options[0].ID}}, handlers.VotePoll)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Deadline closes the poll", func(t *testing.T) {
+		deadline := time.Now().Add(time.Hour)
+		expiring := createPoll(t, creator.ID, event.ID, api.CreatePollRequest{Question: "Menu?", Options: []string{"Pizza", "Sushi"}, Deadline: &deadline})
+		assert.NoError(t, test.TestDB.Model(&models.Poll{}).Where("id = ?", expiring.ID).Update("deadline", time.Now().Add(-time.Minute).UTC()).Error)
+
+		handlers.CloseExpiredPolls(test.TestDB)
+
+		var closed models.Poll
+		assert.NoError(t, test.TestDB.First(&closed, expiring.ID).Error)
+		assert.Equal(t, models.PollClosed, closed.Status)
+		assert.Equal(t, int64(1), countNotifications(creator.ID))
+		assert.Equal(t, int64(2), countNotifications(participant.ID))
+	})
+
+	t.Run("Expired poll is closed when read", func(t *testing.T) {
+		deadline := time.Now().Add(time.Hour)
+		expiring := createPoll(t, organizer.ID, event.ID, api.CreatePollRequest{Question: "Theme?", Options: []string{"Retro", "Space"}, Deadline: &deadline})
+		assert.NoError(t, test.TestDB.Model(&models.Poll{}).Where("id = ?", expiring.ID).Update("deadline", time.Now().Add(-time.Minute).UTC()).Error)
+
+		assert.Equal(t, models.PollClosed, getPoll(t, participant.ID, event.ID, expiring.ID).Status)
+	})
+}
@@ -0,0 +1,179 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"itsplanned/handlers"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func callSetRSVP(t *testing.T, userID, eventID uint, request api.RSVPRequest) *httptest.ResponseRecorder {
+	c, w := test.CreateTestContext(t, userID)
+	requestJSON, err := json.Marshal(request)
+	assert.NoError(t, err)
+
+	c.Request = httptest.NewRequest("PUT", fmt.Sprintf("/events/%d/rsvp", eventID), bytes.NewBuffer(requestJSON))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", eventID)}}
+
+	handlers.SetRSVP(c, test.TestDB)
+	return w
+}
+
+func TestSetRSVP(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	outsider := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	testCases := []struct {
+		name             string
+		userID           uint
+		request          api.RSVPRequest
+		expectedCode     int
+		expectedPlusOnes int
+	}{
+		{
+			name:             "Participant is going with a friend",
+			userID:           participant.ID,
+			request:          api.RSVPRequest{Status: models.RSVPGoing, PlusOnes: 1, Note: "Bringing snacks"},
+			expectedCode:     http.StatusOK,
+			expectedPlusOnes: 1,
+		},
+		{
+			name:             "Plus-ones are dropped when not going",
+			userID:           participant.ID,
+			request:          api.RSVPRequest{Status: models.RSVPNotGoing, PlusOnes: 2},
+			expectedCode:     http.StatusOK,
+			expectedPlusOnes: 0,
+		},
+		{
+			name:         "No response cannot be set",
+			userID:       participant.ID,
+			request:      api.RSVPRequest{Status: models.RSVPNoResponse},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Too many plus-ones",
+			userID:       participant.ID,
+			request:      api.RSVPRequest{Status: models.RSVPGoing, PlusOnes: models.MaxPlusOnes + 1},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Non-participant cannot RSVP",
+			userID:       outsider.ID,
+			request:      api.RSVPRequest{Status: models.RSVPGoing},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := callSetRSVP(t, tc.userID, event.ID, tc.request)
+			assert.Equal(t, tc.expectedCode, w.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				var participation models.EventParticipation
+				err := test.TestDB.Where("event_id = ? AND user_id = ?", event.ID, tc.userID).First(&participation).Error
+				assert.NoError(t, err)
+				assert.Equal(t, tc.request.Status, participation.RSVPStatus)
+				assert.Equal(t, tc.expectedPlusOnes, participation.PlusOnes)
+				assert.Equal(t, tc.request.Note, participation.RSVPNote)
+				assert.NotNil(t, participation.RSVPUpdatedAt)
+			}
+		})
+	}
+
+	t.Run("Get my RSVP", func(t *testing.T) {
+		c, w := test.CreateTestContext(t, participant.ID)
+		c.Request = httptest.NewRequest("GET", fmt.Sprintf("/events/%d/rsvp", event.ID), nil)
+		c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", event.ID)}}
+
+		handlers.GetMyRSVP(c, test.TestDB)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Data api.EventParticipantResponse `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, models.RSVPNotGoing, response.Data.RSVPStatus)
+		assert.Equal(t, models.RoleParticipant, response.Data.Role)
+	})
+}
+
+func TestRSVPCountsAndParticipants(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+
+	going := test.CreateTestUser(t)
+	maybe := test.CreateTestUser(t)
+	silent := test.CreateTestUser(t)
+	for _, user := range []*models.User{going, maybe, silent} {
+		test.AddEventParticipant(t, event.ID, user.ID)
+	}
+
+	assert.Equal(t, http.StatusOK, callSetRSVP(t, organizer.ID, event.ID, api.RSVPRequest{Status: models.RSVPGoing}).Code)
+	assert.Equal(t, http.StatusOK, callSetRSVP(t, going.ID, event.ID, api.RSVPRequest{Status: models.RSVPGoing, PlusOnes: 2}).Code)
+	assert.Equal(t, http.StatusOK, callSetRSVP(t, maybe.ID, event.ID, api.RSVPRequest{Status: models.RSVPMaybe, PlusOnes: 1}).Code)
+
+	c, w := test.CreateTestContext(t, silent.ID)
+	c.Request = httptest.NewRequest("GET", fmt.Sprintf("/events/%d", event.ID), nil)
+	c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", event.ID)}}
+
+	handlers.GetEvent(c, test.TestDB)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var eventResponse struct {
+		Data api.EventResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &eventResponse))
+	assert.Equal(t, &api.RSVPCounts{
+		Going:          2,
+		Maybe:          1,
+		NotGoing:       0,
+		NoResponse:     1,
+		PlusOnes:       2,
+		ExpectedGuests: 4,
+	}, eventResponse.Data.RSVPCounts)
+
+	c, w = test.CreateTestContext(t, silent.ID)
+	c.Request = httptest.NewRequest("GET", fmt.Sprintf("/events/%d/participants", event.ID), nil)
+	c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", event.ID)}}
+
+	handlers.GetEventParticipants(c, test.TestDB)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var participantsResponse api.EventParticipantsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &participantsResponse))
+	assert.Len(t, participantsResponse.Participants, 4)
+
+	first := participantsResponse.Participants[0]
+	assert.Equal(t, organizer.ID, first.UserID)
+	assert.Equal(t, models.RoleOrganizer, first.Role)
+	assert.Equal(t, models.RSVPGoing, first.RSVPStatus)
+
+	byUser := map[uint]api.EventParticipantResponse{}
+	for _, participant := range participantsResponse.Participants {
+		byUser[participant.UserID] = participant
+	}
+	assert.Equal(t, 2, byUser[going.ID].PlusOnes)
+	assert.Equal(t, models.RSVPMaybe, byUser[maybe.ID].RSVPStatus)
+	assert.Equal(t, models.RSVPNoResponse, byUser[silent.ID].RSVPStatus)
+	assert.Equal(t, "Test User", byUser[silent.ID].DisplayName)
+}
@@ -0,0 +1,295 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"itsplanned/handlers"
+	"itsplanned/middleware"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/security"
+	"itsplanned/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func loginTestUser(t *testing.T, password string) (*models.User, *api.LoginResponse) {
+	hashedPassword, err := security.HashPassword(password)
+	assert.NoError(t, err)
+
+	user := &models.User{
+		Email:        fmt.Sprintf("test%d@example.com", time.Now().UnixNano()),
+		DisplayName:  "Test User",
+		PasswordHash: hashedPassword,
+	}
+	assert.NoError(t, test.TestDB.Create(user).Error)
+
+	return user, loginFromDevice(t, user, password, "")
+}
+
+func loginFromDevice(t *testing.T, user *models.User, password, deviceName string) *api.LoginResponse {
+	c, w := test.CreateTestContext(t, 0)
+	requestJSON, err := json.Marshal(api.LoginRequest{Email: user.Email, Password: password, DeviceName: deviceName})
+	assert.NoError(t, err)
+
+	c.Request = httptest.NewRequest("POST", "/login", bytes.NewBuffer(requestJSON))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set("User-Agent", "ItsPlanned-Test/1.0")
+
+	handlers.Login(c, test.TestDB)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response api.LoginResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	return &response
+}
+
+func sessionIDForToken(t *testing.T, accessToken string) uint {
+	_, tokenID, err := security.ValidateToken(accessToken)
+	assert.NoError(t, err)
+
+	var session models.Session
+	assert.NoError(t, test.TestDB.Where("token_id = ?", tokenID).First(&session).Error)
+	return session.ID
+}
+
+func callRefreshToken(t *testing.T, refreshToken string) *httptest.ResponseRecorder {
+	c, w := test.CreateTestContext(t, 0)
+	requestJSON, err := json.Marshal(api.RefreshTokenRequest{RefreshToken: refreshToken})
+	assert.NoError(t, err)
+
+	c.Request = httptest.NewRequest("POST", "/auth/refresh", bytes.NewBuffer(requestJSON))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handlers.RefreshToken(c, test.TestDB)
+	return w
+}
+
+func callProtected(t *testing.T, accessToken string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.AuthMiddleware(test.TestDB))
+	r.POST("/logout", func(c *gin.Context) { handlers.Logout(c, test.TestDB) })
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRefreshToken(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	user, tokens := loginTestUser(t, "password123")
+	assert.NotEmpty(t, tokens.Token)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	assert.True(t, tokens.ExpiresAt.Before(time.Now().Add(time.Hour)))
+
+	var session models.Session
+	assert.NoError(t, test.TestDB.Where("user_id = ?", user.ID).First(&session).Error)
+	assert.NotEqual(t, tokens.RefreshToken, session.RefreshTokenHash)
+
+	w := callRefreshToken(t, tokens.RefreshToken)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var refreshed api.LoginResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &refreshed))
+	assert.NotEmpty(t, refreshed.Token)
+	assert.NotEqual(t, tokens.RefreshToken, refreshed.RefreshToken)
+
+	// Reusing the rotated token revokes the session
+	w = callRefreshToken(t, tokens.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = callRefreshToken(t, refreshed.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = callRefreshToken(t, "invalid")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestLogout(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	_, tokens := loginTestUser(t, "password123")
+
+	w := callProtected(t, tokens.Token)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Access token of the revoked session is rejected by the middleware
+	w = callProtected(t, tokens.Token)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = callRefreshToken(t, tokens.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = callProtected(t, "invalid")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestLastSeen(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	_, tokens := loginTestUser(t, "password123")
+	sessionID := sessionIDForToken(t, tokens.Token)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.AuthMiddleware(test.TestDB))
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+	callPing := func(lastSeenAt time.Time) time.Time {
+		assert.NoError(t, test.TestDB.Model(&models.Session{}).Where("id = ?", sessionID).Update("last_seen_at", lastSeenAt).Error)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/ping", nil)
+		req.Header.Set("Authorization", "Bearer "+tokens.Token)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var session models.Session
+		assert.NoError(t, test.TestDB.First(&session, sessionID).Error)
+		return session.LastSeenAt
+	}
+
+	// Requests move the time a session was last seen, at most once a minute
+	stale := time.Now().Add(-10 * time.Minute).UTC()
+	assert.WithinDuration(t, time.Now(), callPing(stale), 5*time.Second)
+
+	recent := time.Now().Add(-30 * time.Second).UTC()
+	assert.WithinDuration(t, recent, callPing(recent), time.Millisecond)
+}
+
+func TestGetSessions(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	user, phoneTokens := loginTestUser(t, "password123")
+	laptopTokens := loginFromDevice(t, user, "password123", "Work laptop")
+	otherUser, _ := loginTestUser(t, "password123")
+
+	phoneSessionID := sessionIDForToken(t, phoneTokens.Token)
+	laptopSessionID := sessionIDForToken(t, laptopTokens.Token)
+
+	c, w := test.CreateTestContext(t, user.ID)
+	c.Set("session_id", laptopSessionID)
+	c.Request = httptest.NewRequest("GET", "/sessions", nil)
+
+	handlers.GetSessions(c, test.TestDB)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response api.SessionsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Sessions, 2)
+
+	for _, session := range response.Sessions {
+		assert.Equal(t, "ItsPlanned-Test/1.0", session.UserAgent)
+		assert.False(t, session.LastSeenAt.IsZero())
+		if session.ID == laptopSessionID {
+			assert.True(t, session.IsCurrent)
+			assert.Equal(t, "Work laptop", session.DeviceName)
+		} else {
+			assert.Equal(t, phoneSessionID, session.ID)
+			assert.False(t, session.IsCurrent)
+			assert.Equal(t, "Unknown device", session.DeviceName)
+		}
+	}
+
+	c, w = test.CreateTestContext(t, otherUser.ID)
+	c.Request = httptest.NewRequest("GET", "/sessions", nil)
+
+	handlers.GetSessions(c, test.TestDB)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Sessions, 1)
+}
+
+func TestRevokeSession(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	user, phoneTokens := loginTestUser(t, "password123")
+	laptopTokens := loginFromDevice(t, user, "password123", "Work laptop")
+	otherUser, _ := loginTestUser(t, "password123")
+
+	phoneSessionID := sessionIDForToken(t, phoneTokens.Token)
+
+	testCases := []struct {
+		name         string
+		userID       uint
+		sessionID    string
+		expectedCode int
+	}{
+		{
+			name:         "Other user cannot revoke session",
+			userID:       otherUser.ID,
+			sessionID:    fmt.Sprintf("%d", phoneSessionID),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "Invalid session ID",
+			userID:       user.ID,
+			sessionID:    "abc",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Revoke own session",
+			userID:       user.ID,
+			sessionID:    fmt.Sprintf("%d", phoneSessionID),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Revoke already revoked session",
+			userID:       user.ID,
+			sessionID:    fmt.Sprintf("%d", phoneSessionID),
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := test.CreateTestContext(t, tc.userID)
+			c.Request = httptest.NewRequest("DELETE", "/sessions/"+tc.sessionID, nil)
+			c.Params = []gin.Param{{Key: "id", Value: tc.sessionID}}
+
+			handlers.RevokeSession(c, test.TestDB)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, callProtected(t, phoneTokens.Token).Code)
+	assert.Equal(t, http.StatusOK, callProtected(t, laptopTokens.Token).Code)
+}
+
+func TestRevokeOtherSessions(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	user, phoneTokens := loginTestUser(t, "password123")
+	laptopTokens := loginFromDevice(t, user, "password123", "Work laptop")
+	tabletTokens := loginFromDevice(t, user, "password123", "Tablet")
+	otherUser, otherTokens := loginTestUser(t, "password123")
+	assert.NotEqual(t, user.ID, otherUser.ID)
+
+	c, w := test.CreateTestContext(t, user.ID)
+	c.Set("session_id", sessionIDForToken(t, laptopTokens.Token))
+	c.Request = httptest.NewRequest("DELETE", "/sessions/others", nil)
+
+	handlers.RevokeOtherSessions(c, test.TestDB)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.Equal(t, http.StatusUnauthorized, callRefreshToken(t, phoneTokens.RefreshToken).Code)
+	assert.Equal(t, http.StatusUnauthorized, callRefreshToken(t, tabletTokens.RefreshToken).Code)
+	assert.Equal(t, http.StatusOK, callRefreshToken(t, laptopTokens.RefreshToken).Code)
+	assert.Equal(t, http.StatusOK, callRefreshToken(t, otherTokens.RefreshToken).Code)
+}
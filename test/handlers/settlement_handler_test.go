@@ -0,0 +1,148 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"itsplanned/handlers"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func getBalances(t *testing.T, userID, eventID uint) api.BalancesResponse {
+	c, w := test.CreateTestContext(t, userID)
+	c.Request = httptest.NewRequest("GET", fmt.Sprintf("/events/%d/balances", eventID), nil)
+	c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", eventID)}}
+	handlers.GetBalances(c, test.TestDB)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response api.BalancesResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func settlementParams(eventID, settlementID uint) gin.Params {
+	return gin.Params{{Key: "id", Value: fmt.Sprintf("%d", eventID)}, {Key: "settlement_id", Value: fmt.Sprintf("%d", settlementID)}}
+}
+
+func TestBalancesAndSettlements(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	first := test.CreateTestUser(t)
+	second := test.CreateTestUser(t)
+	outsider := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipant(t, event.ID, first.ID)
+	test.AddEventParticipant(t, event.ID, second.ID)
+
+	// The organizer pays 90 for everybody, the first participant pays 30 for the second one
+	createExpense(t, organizer.ID, event.ID, api.ExpenseRequest{Amount: 90})
+	createExpense(t, first.ID, event.ID, api.ExpenseRequest{Amount: 30, SplitMode: models.SplitExact, Split: []api.ExpenseSplitRequest{
+		{UserID: second.ID, Amount: 30},
+	}})
+
+	balances := getBalances(t, first.ID, event.ID)
+	nets := map[uint]float64{}
+	for _, balance := range balances.Balances {
+		nets[balance.UserID] = balance.Net
+	}
+	assert.Equal(t, map[uint]float64{organizer.ID: 60, first.ID: 0, second.ID: -60}, nets)
+	assert.Len(t, balances.Transfers, 1)
+	assert.Equal(t, second.ID, balances.Transfers[0].FromUserID)
+	assert.Equal(t, organizer.ID, balances.Transfers[0].ToUserID)
+	assert.Equal(t, 60.0, balances.Transfers[0].Amount)
+
+	tests := []struct {
+		name         string
+		userID       uint
+		request      api.SettlementRequest
+		expectedCode int
+	}{
+		{
+			name:         "Outsider cannot record payments",
+			userID:       outsider.ID,
+			request:      api.SettlementRequest{FromUserID: second.ID, ToUserID: organizer.ID, Amount: 60},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "Unrelated participant cannot record payments",
+			userID:       first.ID,
+			request:      api.SettlementRequest{FromUserID: second.ID, ToUserID: organizer.ID, Amount: 60},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "Paying yourself",
+			userID:       second.ID,
+			request:      api.SettlementRequest{FromUserID: second.ID, ToUserID: second.ID, Amount: 60},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Receiver outside the event",
+			userID:       second.ID,
+			request:      api.SettlementRequest{FromUserID: second.ID, ToUserID: outsider.ID, Amount: 60},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Negative amount",
+			userID:       second.ID,
+			request:      api.SettlementRequest{FromUserID: second.ID, ToUserID: organizer.ID, Amount: -60},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Payer marks a partial payment",
+			userID:       second.ID,
+			request:      api.SettlementRequest{FromUserID: second.ID, ToUserID: organizer.ID, Amount: 20},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w, _ := test.CallHandler(t, tc.userID, "POST", fmt.Sprintf("/events/%d/settlements", event.ID), idParams(event.ID), tc.request, handlers.CreateSettlement)
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+
+	balances = getBalances(t, organizer.ID, event.ID)
+	assert.Len(t, balances.Settlements, 1)
+	assert.Equal(t, []api.TransferResponse{{
+		FromUserID: second.ID,
+		FromName:   second.DisplayName,
+		ToUserID:   organizer.ID,
+		ToName:     organizer.DisplayName,
+		Amount:     40,
+	}}, balances.Transfers)
+
+	t.Run("Receiver marks the rest as paid", func(t *testing.T) {
+		w, _ := test.CallHandler(t, organizer.ID, "POST", fmt.Sprintf("/events/%d/settlements", event.ID), idParams(event.ID), api.SettlementRequest{FromUserID: second.ID, ToUserID: organizer.ID, Amount: 40}, handlers.CreateSettlement)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		balances := getBalances(t, second.ID, event.ID)
+		assert.Empty(t, balances.Transfers)
+		for _, balance := range balances.Balances {
+			assert.Equal(t, 0.0, balance.Net)
+		}
+	})
+
+	t.Run("Deleting a payment reopens the debt", func(t *testing.T) {
+		settlementID := getBalances(t, organizer.ID, event.ID).Settlements[1].ID
+
+		w, _ := test.CallHandler(t, first.ID, "DELETE", fmt.Sprintf("/events/%d/settlements/%d", event.ID, settlementID), settlementParams(event.ID, settlementID), nil, handlers.DeleteSettlement)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w, _ = test.CallHandler(t, organizer.ID, "DELETE", fmt.Sprintf("/events/%d/settlements/%d", event.ID, settlementID), settlementParams(event.ID, settlementID), nil, handlers.DeleteSettlement)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		balances := getBalances(t, organizer.ID, event.ID)
+		assert.Len(t, balances.Transfers, 1)
+		assert.Equal(t, 40.0, balances.Transfers[0].Amount)
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -96,6 +97,12 @@ func TestCreateTask(t *testing.T) {
 	user := test.CreateTestUser(t)
 	event := test.CreateTestEvent(t, user.ID)
 
+	participant := test.CreateTestUser(t)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	viewer := test.CreateTestUser(t)
+	test.AddEventParticipantWithRole(t, event.ID, viewer.ID, models.RoleViewer)
+
 	testCases := []struct {
 		name         string
 		userID       uint
@@ -133,8 +140,8 @@ func TestCreateTask(t *testing.T) {
 			},
 		},
 		{
-			name:   "Non-organizer can create task",
-			userID: test.CreateTestUser(t).ID,
+			name:   "Participant can create task",
+			userID: participant.ID,
 			request: api.CreateTaskRequest{
 				Title:       "Another Test Task",
 				Description: "Another Description",
@@ -151,6 +158,28 @@ func TestCreateTask(t *testing.T) {
 				assert.Equal(t, int64(1), count)
 			},
 		},
+		{
+			name:   "Viewer cannot create task",
+			userID: viewer.ID,
+			request: api.CreateTaskRequest{
+				Title:   "Viewer Task",
+				Points:  5,
+				EventID: event.ID,
+			},
+			expectedCode: http.StatusForbidden,
+			validateFunc: nil,
+		},
+		{
+			name:   "Non-participant cannot create task",
+			userID: test.CreateTestUser(t).ID,
+			request: api.CreateTaskRequest{
+				Title:   "Outsider Task",
+				Points:  5,
+				EventID: event.ID,
+			},
+			expectedCode: http.StatusForbidden,
+			validateFunc: nil,
+		},
 		{
 			name:   "Invalid request - missing required fields",
 			userID: user.ID,
@@ -234,7 +263,7 @@ func TestUpdateTask(t *testing.T) {
 
 				assert.Equal(t, "Updated Task Title", updatedTask.Title)
 				assert.Equal(t, "Updated Description", updatedTask.Description)
-				assert.Equal(t, 300.0, updatedTask.Budget)
+				assert.Equal(t, int64(30000), updatedTask.Budget)
 				assert.Equal(t, 25, updatedTask.Points)
 			},
 		},
@@ -257,7 +286,7 @@ func TestUpdateTask(t *testing.T) {
 				assert.Equal(t, "Only Title Updated", updatedTask.Title)
 				assert.Equal(t, 30, updatedTask.Points)
 				assert.Equal(t, "Updated Description", updatedTask.Description)
-				assert.Equal(t, 300.0, updatedTask.Budget)
+				assert.Equal(t, int64(30000), updatedTask.Budget)
 			},
 		},
 		{
@@ -269,7 +298,7 @@ func TestUpdateTask(t *testing.T) {
 			},
 			expectedCode: http.StatusForbidden,
 			validateFunc: func(t *testing.T, response api.APIResponse) {
-				assert.Contains(t, response.Error, "Only the event organizer can update tasks")
+				assert.Contains(t, response.Error, "Only organizers and co-organizers can update tasks")
 			},
 		},
 		{
@@ -345,7 +374,7 @@ func TestDeleteTask(t *testing.T) {
 			taskID:       task.ID,
 			expectedCode: http.StatusForbidden,
 			validateFunc: func(t *testing.T, response api.APIResponse) {
-				assert.Contains(t, response.Error, "Only the event organizer can delete tasks")
+				assert.Contains(t, response.Error, "Only organizers and co-organizers can delete tasks")
 
 				var count int64
 				test.TestDB.Model(&models.Task{}).Where("id = ?", task.ID).Count(&count)
@@ -612,3 +641,251 @@ func TestCompleteTask(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskDueDatesAndPriorities(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	user := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, user.ID)
+	task := test.CreateTestTask(t, event.ID)
+
+	beforeEvent := "2024-03-30T18:00:00+03:00"
+	afterEvent := "2024-04-02T12:00:00Z"
+
+	tests := []struct {
+		name             string
+		create           bool
+		request          map[string]interface{}
+		expectedCode     int
+		expectedDueAt    string
+		expectedPriority string
+	}{
+		{
+			name:             "Create with the default priority",
+			create:           true,
+			request:          map[string]interface{}{"title": "Buy drinks", "points": 5, "event_id": event.ID},
+			expectedCode:     http.StatusOK,
+			expectedPriority: models.PriorityNormal,
+		},
+		{
+			name:             "Create with a due date before the event",
+			create:           true,
+			request:          map[string]interface{}{"title": "Buy drinks", "points": 5, "event_id": event.ID, "due_at": beforeEvent, "priority": "urgent"},
+			expectedCode:     http.StatusOK,
+			expectedDueAt:    "2024-03-30T15:00:00Z",
+			expectedPriority: models.PriorityUrgent,
+		},
+		{
+			name:         "Create with a due date after the event",
+			create:       true,
+			request:      map[string]interface{}{"title": "Return chairs", "points": 5, "event_id": event.ID, "due_at": afterEvent},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:             "Create with an allowed due date after the event",
+			create:           true,
+			request:          map[string]interface{}{"title": "Return chairs", "points": 5, "event_id": event.ID, "due_at": afterEvent, "allow_due_after_event": true},
+			expectedCode:     http.StatusOK,
+			expectedDueAt:    afterEvent,
+			expectedPriority: models.PriorityNormal,
+		},
+		{
+			name:         "Create with an unknown priority",
+			create:       true,
+			request:      map[string]interface{}{"title": "Buy drinks", "points": 5, "event_id": event.ID, "priority": "asap"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:             "Update the due date and priority",
+			request:          map[string]interface{}{"due_at": beforeEvent, "priority": "high"},
+			expectedCode:     http.StatusOK,
+			expectedDueAt:    "2024-03-30T15:00:00Z",
+			expectedPriority: models.PriorityHigh,
+		},
+		{
+			name:         "Update with a due date after the event",
+			request:      map[string]interface{}{"due_at": afterEvent},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Update with an unknown priority",
+			request:      map[string]interface{}{"priority": ""},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:             "Clear the due date",
+			request:          map[string]interface{}{"clear_due_at": true},
+			expectedCode:     http.StatusOK,
+			expectedPriority: models.PriorityHigh,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := test.CreateTestContext(t, user.ID)
+
+			requestJSON, err := json.Marshal(tt.request)
+			assert.NoError(t, err)
+
+			if tt.create {
+				c.Request = httptest.NewRequest("POST", "/tasks", bytes.NewBuffer(requestJSON))
+				c.Request.Header.Set("Content-Type", "application/json")
+				handlers.CreateTask(c, test.TestDB)
+			} else {
+				c.Request = httptest.NewRequest("PUT", fmt.Sprintf("/tasks/%d", task.ID), bytes.NewBuffer(requestJSON))
+				c.Request.Header.Set("Content-Type", "application/json")
+				c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", task.ID)}}
+				handlers.UpdateTask(c, test.TestDB)
+			}
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data api.TaskResponse `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedPriority, response.Data.Priority)
+			if tt.expectedDueAt == "" {
+				assert.Nil(t, response.Data.DueAt)
+				assert.False(t, response.Data.IsOverdue)
+			} else if assert.NotNil(t, response.Data.DueAt) {
+				assert.Equal(t, tt.expectedDueAt, response.Data.DueAt.UTC().Format(time.RFC3339))
+				// The test event took place in the past, so every open task with a due date is overdue
+				assert.True(t, response.Data.IsOverdue)
+			}
+		})
+	}
+}
+
+func TestGetTasksFiltersAndSorting(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	user := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, user.ID)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	past := time.Now().Add(-24 * time.Hour).UTC()
+	future := time.Now().Add(24 * time.Hour).UTC()
+	tasks := []models.Task{
+		{Title: "Venue", Points: 10, EventID: event.ID, Priority: models.PriorityLow, DueAt: &future},
+		{Title: "Cake", Points: 5, EventID: event.ID, Priority: models.PriorityUrgent, DueAt: &past, AssignedTo: &participant.ID},
+		{Title: "Music", Points: 20, EventID: event.ID, Priority: models.PriorityHigh},
+		{Title: "Balloons", Points: 1, EventID: event.ID, Priority: models.PriorityHigh, DueAt: &past, IsCompleted: true},
+	}
+	for i := range tasks {
+		assert.NoError(t, test.TestDB.Create(&tasks[i]).Error)
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedCode   int
+		expectedTitles []string
+	}{
+		{
+			name:           "All tasks in order of creation",
+			expectedCode:   http.StatusOK,
+			expectedTitles: []string{"Venue", "Cake", "Music", "Balloons"},
+		},
+		{
+			name:           "Open tasks",
+			query:          "&status=open",
+			expectedCode:   http.StatusOK,
+			expectedTitles: []string{"Venue", "Cake", "Music"},
+		},
+		{
+			name:           "High priority",
+			query:          "&priority=high",
+			expectedCode:   http.StatusOK,
+			expectedTitles: []string{"Music", "Balloons"},
+		},
+		{
+			name:           "Assigned to the participant",
+			query:          fmt.Sprintf("&assigned_to=%d", participant.ID),
+			expectedCode:   http.StatusOK,
+			expectedTitles: []string{"Cake"},
+		},
+		{
+			name:           "Unassigned",
+			query:          "&assigned_to=none&sort=title",
+			expectedCode:   http.StatusOK,
+			expectedTitles: []string{"Balloons", "Music", "Venue"},
+		},
+		{
+			name:           "Overdue",
+			query:          "&overdue=true",
+			expectedCode:   http.StatusOK,
+			expectedTitles: []string{"Cake"},
+		},
+		{
+			name:           "Due before",
+			query:          "&due_before=" + time.Now().UTC().Format(time.RFC3339),
+			expectedCode:   http.StatusOK,
+			expectedTitles: []string{"Cake", "Balloons"},
+		},
+		{
+			name:           "Sorted by due date, tasks without one last",
+			query:          "&sort=due_at",
+			expectedCode:   http.StatusOK,
+			expectedTitles: []string{"Cake", "Balloons", "Venue", "Music"},
+		},
+		{
+			name:           "Sorted by priority",
+			query:          "&sort=priority",
+			expectedCode:   http.StatusOK,
+			expectedTitles: []string{"Cake", "Music", "Balloons", "Venue"},
+		},
+		{
+			name:           "Sorted by points descending",
+			query:          "&sort=points&order=desc",
+			expectedCode:   http.StatusOK,
+			expectedTitles: []string{"Music", "Venue", "Cake", "Balloons"},
+		},
+		{
+			name:         "Unknown sort",
+			query:        "&sort=budget",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Unknown status",
+			query:        "&status=done",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Invalid due before",
+			query:        "&due_before=tomorrow",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := test.CreateTestContext(t, participant.ID)
+			c.Request = httptest.NewRequest("GET", fmt.Sprintf("/tasks?event_id=%d%s", event.ID, tt.query), nil)
+
+			handlers.GetTasks(c, test.TestDB)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data []api.TaskResponse `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			titles := []string{}
+			for _, task := range response.Data {
+				titles = append(titles, task.Title)
+				assert.Equal(t, task.Title == "Cake", task.IsOverdue, task.Title)
+			}
+			assert.Equal(t, tt.expectedTitles, titles)
+		})
+	}
+}
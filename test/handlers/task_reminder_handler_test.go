@@ -0,0 +1,146 @@
+package handlers_test
+
+import (
+	"itsplanned/handlers"
+	"itsplanned/models"
+	"itsplanned/test"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createReminderTask(t *testing.T, eventID uint, title string, assignee *uint, dueAt *time.Time, completed bool) *models.Task {
+	task := &models.Task{Title: title, Points: 1, EventID: eventID, AssignedTo: assignee, DueAt: dueAt, IsCompleted: completed}
+	assert.NoError(t, test.TestDB.Create(task).Error)
+	return task
+}
+
+func reminderNotifications(t *testing.T, userID uint, status string) []models.TaskStatusEvent {
+	var notifications []models.TaskStatusEvent
+	assert.NoError(t, test.TestDB.Where("user_id = ? AND new_status = ?", userID, status).Order("id").Find(&notifications).Error)
+	return notifications
+}
+
+func TestSendTaskReminders(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+	sent := captureEmails(t, nil)
+
+	organizer := test.CreateTestUser(t)
+	assignee := test.CreateTestUser(t)
+	lateAssignee := test.CreateTestUser(t)
+	quietAssignee := test.CreateTestUser(t)
+	assert.NoError(t, test.TestDB.Model(lateAssignee).Update("task_reminder_hours", 2).Error)
+	assert.NoError(t, test.TestDB.Model(quietAssignee).Update("task_reminder_hours", 0).Error)
+
+	// The test event starts on 2024-04-01 at 18:00 UTC, a day after now
+	event := test.CreateTestEvent(t, organizer.ID)
+	now := time.Date(2024, 3, 31, 18, 0, 0, 0, time.UTC)
+	at := func(hours int) *time.Time {
+		due := now.Add(time.Duration(hours) * time.Hour)
+		return &due
+	}
+
+	dueSoon := createReminderTask(t, event.ID, "Buy drinks", &assignee.ID, at(12), false)
+	lateTask := createReminderTask(t, event.ID, "Pick up the cake", &lateAssignee.ID, at(12), false)
+	createReminderTask(t, event.ID, "Bring chairs", &quietAssignee.ID, at(1), false)
+	createReminderTask(t, event.ID, "Book the venue", &assignee.ID, at(1), true)
+	createReminderTask(t, event.ID, "Send invitations", &assignee.ID, at(-1), false)
+	createReminderTask(t, event.ID, "Buy balloons", nil, at(6), false)
+	createReminderTask(t, event.ID, "Hire a DJ", nil, nil, false)
+
+	t.Run("Assignees and the organizer are reminded", func(t *testing.T) {
+		handlers.SendTaskReminders(test.TestDB, now)
+
+		notifications := reminderNotifications(t, assignee.ID, models.ReminderTaskDue)
+		if assert.Len(t, notifications, 1) {
+			assert.Equal(t, dueSoon.ID, notifications[0].TaskID)
+			assert.Equal(t, organizer.ID, notifications[0].ChangedByID)
+		}
+		assert.Empty(t, reminderNotifications(t, lateAssignee.ID, models.ReminderTaskDue))
+		assert.Empty(t, reminderNotifications(t, quietAssignee.ID, models.ReminderTaskDue))
+
+		notifications = reminderNotifications(t, organizer.ID, models.ReminderUnassignedTasks)
+		if assert.Len(t, notifications, 1) {
+			assert.Equal(t, event.ID, notifications[0].EventID)
+			assert.Equal(t, uint(0), notifications[0].TaskID)
+		}
+
+		if assert.Len(t, *sent, 2) {
+			assert.Equal(t, assignee.Email, (*sent)[0].to)
+			assert.Equal(t, "Reminder: Buy drinks is due soon", (*sent)[0].subject)
+			assert.Contains(t, (*sent)[0].body, "Mon, 1 Apr 2024 06:00 UTC")
+			assert.Equal(t, organizer.Email, (*sent)[1].to)
+			assert.Equal(t, "Unassigned tasks for Test Event", (*sent)[1].subject)
+			assert.Contains(t, (*sent)[1].body, "<li>Buy balloons</li>")
+			assert.Contains(t, (*sent)[1].body, "<li>Hire a DJ</li>")
+			assert.NotContains(t, (*sent)[1].body, "Buy drinks")
+		}
+	})
+
+	t.Run("Reminders are not sent twice", func(t *testing.T) {
+		handlers.SendTaskReminders(test.TestDB, now.Add(time.Minute))
+
+		assert.Len(t, *sent, 2)
+		assert.Len(t, reminderNotifications(t, assignee.ID, models.ReminderTaskDue), 1)
+		assert.Len(t, reminderNotifications(t, organizer.ID, models.ReminderUnassignedTasks), 1)
+	})
+
+	t.Run("Shorter offsets are reminded later", func(t *testing.T) {
+		handlers.SendTaskReminders(test.TestDB, now.Add(10*time.Hour))
+
+		notifications := reminderNotifications(t, lateAssignee.ID, models.ReminderTaskDue)
+		if assert.Len(t, notifications, 1) {
+			assert.Equal(t, lateTask.ID, notifications[0].TaskID)
+		}
+		assert.Len(t, *sent, 3)
+	})
+
+	t.Run("A moved due date is reminded of again", func(t *testing.T) {
+		assert.NoError(t, test.TestDB.Model(dueSoon).Update("due_at", now.Add(14*time.Hour)).Error)
+
+		handlers.SendTaskReminders(test.TestDB, now.Add(11*time.Hour))
+
+		assert.Len(t, reminderNotifications(t, assignee.ID, models.ReminderTaskDue), 2)
+		assert.Len(t, *sent, 4)
+	})
+}
+
+func TestSendTaskRemindersForRecurringEvents(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+	sent := captureEmails(t, nil)
+
+	organizer := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	// Weekly from 2024-04-01 18:00 UTC, the next occurrence after now is 2024-04-15
+	event.RecurrenceRule = "FREQ=WEEKLY"
+	assert.NoError(t, test.TestDB.Save(event).Error)
+	createReminderTask(t, event.ID, "Buy snacks", nil, nil, false)
+	assert.Nil(t, event.SeriesEndsAt)
+
+	// A finished series is left out
+	finished := test.CreateTestEvent(t, organizer.ID)
+	finished.RecurrenceRule = "FREQ=WEEKLY;COUNT=2"
+	assert.NoError(t, test.TestDB.Save(finished).Error)
+	createReminderTask(t, finished.ID, "Buy plates", nil, nil, false)
+	if assert.NotNil(t, finished.SeriesEndsAt) {
+		assert.Equal(t, time.Date(2024, 4, 8, 18, 0, 0, 0, time.UTC), finished.SeriesEndsAt.UTC())
+	}
+
+	handlers.SendTaskReminders(test.TestDB, time.Date(2024, 4, 10, 12, 0, 0, 0, time.UTC))
+	assert.Empty(t, *sent)
+
+	now := time.Date(2024, 4, 14, 12, 0, 0, 0, time.UTC)
+	handlers.SendTaskReminders(test.TestDB, now)
+	handlers.SendTaskReminders(test.TestDB, now.Add(time.Hour))
+	if assert.Len(t, *sent, 1) {
+		assert.Contains(t, (*sent)[0].body, "Mon, 15 Apr 2024 18:00 UTC")
+		assert.NotContains(t, (*sent)[0].body, "Buy plates")
+	}
+
+	// Every occurrence is reminded of
+	handlers.SendTaskReminders(test.TestDB, now.Add(7*24*time.Hour))
+	assert.Len(t, *sent, 2)
+}
@@ -0,0 +1,237 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"itsplanned/handlers"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func pollParams(eventID, pollID uint) gin.Params {
+	return gin.Params{{Key: "id", Value: fmt.Sprintf("%d", eventID)}, {Key: "poll_id", Value: fmt.Sprintf("%d", pollID)}}
+}
+
+func decodeTimePoll(t *testing.T, response api.APIResponse) api.TimePollResponse {
+	data, err := json.Marshal(response.Data)
+	assert.NoError(t, err)
+
+	var poll api.TimePollResponse
+	assert.NoError(t, json.Unmarshal(data, &poll))
+	return poll
+}
+
+func TestCreateTimePoll(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+
+	options := []string{"2024-04-02T18:00:00Z", "2024-04-03T18:00:00Z"}
+
+	tests := []struct {
+		name         string
+		userID       uint
+		request      api.CreateTimePollRequest
+		expectedCode int
+	}{
+		{
+			name:         "Participant cannot create a poll",
+			userID:       participant.ID,
+			request:      api.CreateTimePollRequest{Options: options},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "Single option",
+			userID:       organizer.ID,
+			request:      api.CreateTimePollRequest{Options: options[:1]},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Same time twice",
+			userID:       organizer.ID,
+			request:      api.CreateTimePollRequest{Options: []string{"2024-04-02T18:00:00Z", "2024-04-02T20:00:00+02:00"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Invalid time format",
+			userID:       organizer.ID,
+			request:      api.CreateTimePollRequest{Options: []string{"2024-04-02 18:00", "2024-04-03T18:00:00Z"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Options and seed together",
+			userID:       organizer.ID,
+			request:      api.CreateTimePollRequest{Options: options, Seed: &api.TimePollSeed{Date: "2024-04-02", DurationMins: 60}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Valid poll",
+			userID:       organizer.ID,
+			request:      api.CreateTimePollRequest{Options: options},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Second open poll",
+			userID:       organizer.ID,
+			request:      api.CreateTimePollRequest{Options: options},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w, _ := test.CallHandler(t, tc.userID, "POST", fmt.Sprintf("/events/%d/time-polls", event.ID), idParams(event.ID), tc.request, handlers.CreateTimePoll)
+			assert.Equal(t, tc.expectedCode, w.Code)
+		})
+	}
+
+	t.Run("Seeded from the best slots of a day", func(t *testing.T) {
+		seeded := test.CreateTestEvent(t, organizer.ID)
+		test.AddEventParticipant(t, seeded.ID, participant.ID)
+		createCalendarEvent(t, participant.ID, "2024-04-02", "09:00", "12:00", "Work")
+
+		w, response := test.CallHandler(t, organizer.ID, "POST", fmt.Sprintf("/events/%d/time-polls", seeded.ID), idParams(seeded.ID), api.CreateTimePollRequest{
+			Seed: &api.TimePollSeed{Date: "2024-04-02", DurationMins: 60, StartTime: "09:00", EndTime: "15:00"},
+		}, handlers.CreateTimePoll)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var starts []string
+		for _, option := range decodeTimePoll(t, response).Options {
+			starts = append(starts, option.StartTime.UTC().Format("15:04"))
+		}
+		assert.Equal(t, []string{"12:00", "12:30", "13:00", "13:30", "14:00"}, starts)
+	})
+
+	t.Run("Recurring event", func(t *testing.T) {
+		recurring := test.CreateTestEvent(t, organizer.ID)
+		assert.NoError(t, test.TestDB.Model(recurring).Update("recurrence_rule", "FREQ=WEEKLY").Error)
+
+		w, _ := test.CallHandler(t, organizer.ID, "POST", fmt.Sprintf("/events/%d/time-polls", recurring.ID), idParams(recurring.ID), api.CreateTimePollRequest{Options: options}, handlers.CreateTimePoll)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestTimePollVotingAndClosing(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	participant := test.CreateTestUser(t)
+	viewer := test.CreateTestUser(t)
+	outsider := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	test.AddEventParticipant(t, event.ID, participant.ID)
+	test.AddEventParticipantWithRole(t, event.ID, viewer.ID, models.RoleViewer)
+
+	_, created := test.CallHandler(t, organizer.ID, "POST", fmt.Sprintf("/events/%d/time-polls", event.ID), idParams(event.ID), api.CreateTimePollRequest{
+		Options: []string{"2024-04-02T18:00:00Z", "2024-04-03T18:00:00Z"},
+	}, handlers.CreateTimePoll)
+	poll := decodeTimePoll(t, created)
+	first, second := poll.Options[0].ID, poll.Options[1].ID
+
+	vote := func(userID uint, votes ...api.TimePollAnswer) (*httptest.ResponseRecorder, api.APIResponse) {
+		return test.CallHandler(t, userID, "PUT", fmt.Sprintf("/events/%d/time-polls/%d/votes", event.ID, poll.ID), pollParams(event.ID, poll.ID), api.TimePollVoteRequest{Votes: votes}, handlers.VoteTimePoll)
+	}
+
+	t.Run("Voting", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			userID       uint
+			votes        []api.TimePollAnswer
+			expectedCode int
+		}{
+			{"Viewer cannot vote", viewer.ID, []api.TimePollAnswer{{OptionID: first, Answer: models.TimePollYes}}, http.StatusForbidden},
+			{"Outsider cannot vote", outsider.ID, []api.TimePollAnswer{{OptionID: first, Answer: models.TimePollYes}}, http.StatusForbidden},
+			{"Invalid answer", participant.ID, []api.TimePollAnswer{{OptionID: first, Answer: "sure"}}, http.StatusBadRequest},
+			{"Unknown option", participant.ID, []api.TimePollAnswer{{OptionID: second + 100, Answer: models.TimePollYes}}, http.StatusBadRequest},
+			{"Participant votes", participant.ID, []api.TimePollAnswer{{OptionID: first, Answer: models.TimePollNo}, {OptionID: second, Answer: models.TimePollMaybe}}, http.StatusOK},
+			{"Participant changes an answer", participant.ID, []api.TimePollAnswer{{OptionID: first, Answer: models.TimePollYes}}, http.StatusOK},
+			{"Organizer votes", organizer.ID, []api.TimePollAnswer{{OptionID: first, Answer: models.TimePollYes}, {OptionID: second, Answer: models.TimePollNo}}, http.StatusOK},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				w, _ := vote(tc.userID, tc.votes...)
+				assert.Equal(t, tc.expectedCode, w.Code)
+			})
+		}
+	})
+
+	t.Run("Tallies", func(t *testing.T) {
+		c, w := test.CreateTestContext(t, participant.ID)
+		c.Request = httptest.NewRequest("GET", fmt.Sprintf("/events/%d/time-polls", event.ID), nil)
+		c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", event.ID)}}
+		handlers.GetTimePolls(c, test.TestDB)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.TimePollsResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Polls, 1)
+
+		options := response.Polls[0].Options
+		assert.Equal(t, 2, options[0].Yes)
+		assert.Equal(t, 0, options[0].No)
+		assert.Equal(t, models.TimePollYes, options[0].MyAnswer)
+		assert.Equal(t, 1, options[1].Maybe)
+		assert.Equal(t, 1, options[1].No)
+		assert.Equal(t, models.TimePollMaybe, options[1].MyAnswer)
+	})
+
+	closePoll := func(userID, optionID uint) (*httptest.ResponseRecorder, api.APIResponse) {
+		return test.CallHandler(t, userID, "POST", fmt.Sprintf("/events/%d/time-polls/%d/close", event.ID, poll.ID), pollParams(event.ID, poll.ID), api.CloseTimePollRequest{OptionID: optionID}, handlers.CloseTimePoll)
+	}
+
+	t.Run("Participant cannot close", func(t *testing.T) {
+		w, _ := closePoll(participant.ID, first)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Unknown option", func(t *testing.T) {
+		w, _ := closePoll(organizer.ID, second+100)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Organizer closes the poll", func(t *testing.T) {
+		w, response := closePoll(organizer.ID, first)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		closed := decodeTimePoll(t, response)
+		assert.Equal(t, models.PollClosed, closed.Status)
+		assert.Equal(t, first, *closed.ChosenOptionID)
+
+		var updated models.Event
+		assert.NoError(t, test.TestDB.First(&updated, event.ID).Error)
+		assert.True(t, updated.EventDateTime.Equal(time.Date(2024, 4, 2, 18, 0, 0, 0, time.UTC)))
+
+		for _, userID := range []uint{participant.ID, viewer.ID} {
+			var notification models.TaskStatusEvent
+			assert.NoError(t, test.TestDB.Where("user_id = ? AND event_id = ?", userID, event.ID).First(&notification).Error)
+			assert.Equal(t, models.EventTimeChanged, notification.NewStatus)
+			assert.Equal(t, organizer.ID, notification.ChangedByID)
+		}
+
+		var organizerNotifications int64
+		test.TestDB.Model(&models.TaskStatusEvent{}).Where("user_id = ?", organizer.ID).Count(&organizerNotifications)
+		assert.Equal(t, int64(0), organizerNotifications)
+	})
+
+	t.Run("Closed poll", func(t *testing.T) {
+		w, _ := closePoll(organizer.ID, second)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w, _ = vote(participant.ID, api.TimePollAnswer{OptionID: second, Answer: models.TimePollYes})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
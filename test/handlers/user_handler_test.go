@@ -262,3 +262,87 @@ func TestGetProfile(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateProfileTimezone(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	user := test.CreateTestUser(t)
+	strPtr := func(s string) *string { return &s }
+
+	testCases := []struct {
+		name             string
+		timezone         *string
+		expectedCode     int
+		expectedTimezone string
+	}{
+		{name: "Default timezone", expectedCode: http.StatusOK, expectedTimezone: "UTC"},
+		{name: "Set IANA timezone", timezone: strPtr("Europe/Moscow"), expectedCode: http.StatusOK, expectedTimezone: "Europe/Moscow"},
+		{name: "Unknown timezone", timezone: strPtr("Mars/Olympus"), expectedCode: http.StatusBadRequest, expectedTimezone: "Europe/Moscow"},
+		{name: "Empty timezone", timezone: strPtr(""), expectedCode: http.StatusBadRequest, expectedTimezone: "Europe/Moscow"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := test.CreateTestContext(t, user.ID)
+
+			requestJSON, err := json.Marshal(api.ProfileUpdateRequest{Timezone: tc.timezone})
+			assert.NoError(t, err)
+
+			c.Request = httptest.NewRequest("PUT", "/profile", bytes.NewBuffer(requestJSON))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			handlers.UpdateProfile(c, test.TestDB)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+
+			var updated models.User
+			assert.NoError(t, test.TestDB.First(&updated, user.ID).Error)
+			assert.Equal(t, tc.expectedTimezone, updated.Timezone)
+		})
+	}
+}
+
+func TestUpdateProfileReminderHours(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	user := test.CreateTestUser(t)
+	intPtr := func(i int) *int { return &i }
+
+	testCases := []struct {
+		name               string
+		taskHours          *int
+		eventHours         *int
+		expectedCode       int
+		expectedTaskHours  int
+		expectedEventHours int
+	}{
+		{name: "Default hours", expectedCode: http.StatusOK, expectedTaskHours: 24, expectedEventHours: 48},
+		{name: "Set hours", taskHours: intPtr(6), eventHours: intPtr(72), expectedCode: http.StatusOK, expectedTaskHours: 6, expectedEventHours: 72},
+		{name: "Turn task reminders off", taskHours: intPtr(0), expectedCode: http.StatusOK, expectedTaskHours: 0, expectedEventHours: 72},
+		{name: "Negative hours", eventHours: intPtr(-1), expectedCode: http.StatusBadRequest, expectedTaskHours: 0, expectedEventHours: 72},
+		{name: "Too many hours", taskHours: intPtr(721), expectedCode: http.StatusBadRequest, expectedTaskHours: 0, expectedEventHours: 72},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := test.CreateTestContext(t, user.ID)
+
+			requestJSON, err := json.Marshal(api.ProfileUpdateRequest{TaskReminderHours: tc.taskHours, EventReminderHours: tc.eventHours})
+			assert.NoError(t, err)
+
+			c.Request = httptest.NewRequest("PUT", "/profile", bytes.NewBuffer(requestJSON))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			handlers.UpdateProfile(c, test.TestDB)
+
+			assert.Equal(t, tc.expectedCode, w.Code)
+
+			var updated models.User
+			assert.NoError(t, test.TestDB.First(&updated, user.ID).Error)
+			assert.Equal(t, tc.expectedTaskHours, updated.TaskReminderHours)
+			assert.Equal(t, tc.expectedEventHours, updated.EventReminderHours)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"itsplanned/handlers"
+	"itsplanned/models"
+	"itsplanned/models/api"
+	"itsplanned/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func callLeaveEvent(t *testing.T, userID, eventID uint) *httptest.ResponseRecorder {
+	c, w := test.CreateTestContext(t, userID)
+	c.Request = httptest.NewRequest("DELETE", fmt.Sprintf("/events/%d/leave", eventID), nil)
+	c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", eventID)}}
+
+	handlers.LeaveEvent(c, test.TestDB)
+	return w
+}
+
+func callGetWaitlist(t *testing.T, userID, eventID uint) (*httptest.ResponseRecorder, api.WaitlistResponse) {
+	c, w := test.CreateTestContext(t, userID)
+	c.Request = httptest.NewRequest("GET", fmt.Sprintf("/events/%d/waitlist", eventID), nil)
+	c.Params = []gin.Param{{Key: "id", Value: fmt.Sprintf("%d", eventID)}}
+
+	handlers.GetWaitlist(c, test.TestDB)
+
+	var response api.WaitlistResponse
+	if w.Code == http.StatusOK {
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	}
+	return w, response
+}
+
+func isParticipant(t *testing.T, eventID, userID uint) bool {
+	var count int64
+	assert.NoError(t, test.TestDB.Model(&models.EventParticipation{}).Where("event_id = ? AND user_id = ?", eventID, userID).Count(&count).Error)
+	return count > 0
+}
+
+func TestJoinEventWaitlist(t *testing.T) {
+	cleanup := test.SetupTestDB(t)
+	defer cleanup()
+
+	organizer := test.CreateTestUser(t)
+	event := test.CreateTestEvent(t, organizer.ID)
+	capacity := 2
+	assert.NoError(t, test.TestDB.Model(event).Update("capacity", capacity).Error)
+
+	invitation := createTestInvitation(t, event.ID, organizer.ID, nil)
+
+	first := test.CreateTestUser(t)
+	second := test.CreateTestUser(t)
+	third := test.CreateTestUser(t)
+	outsider := test.CreateTestUser(t)
+
+	testCases := []struct {
+		name             string
+		userID           uint
+		expectedCode     int
+		expectedStatus   string
+		expectedPosition int
+	}{
+		{
+			name:           "Last place is taken",
+			userID:         first.ID,
+			expectedCode:   http.StatusOK,
+			expectedStatus: models.ParticipationJoined,
+		},
+		{
+			name:             "Full event puts user on the waitlist",
+			userID:           second.ID,
+			expectedCode:     http.StatusAccepted,
+			expectedStatus:   models.ParticipationWaitlisted,
+			expectedPosition: 1,
+		},
+		{
+			name:             "Waitlist keeps the order",
+			userID:           third.ID,
+			expectedCode:     http.StatusAccepted,
+			expectedStatus:   models.ParticipationWaitlisted,
+			expectedPosition: 2,
+		},
+		{
+			name:         "Waitlisted user cannot join twice",
+			userID:       second.ID,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := callJoinEvent(t, tc.userID, invitation.InviteCode)
+			assert.Equal(t, tc.expectedCode, w.Code)
+
+			if tc.expectedStatus != "" {
+				var response api.JoinEventResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tc.expectedStatus, response.Status)
+				assert.Equal(t, tc.expectedPosition, response.WaitlistPosition)
+			}
+		})
+	}
+
+	w, waitlist := callGetWaitlist(t, organizer.ID, event.ID)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, waitlist.Waitlist, 2)
+	assert.Equal(t, second.ID, waitlist.Waitlist[0].UserID)
+
+	w, waitlist = callGetWaitlist(t, third.ID, event.ID)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, waitlist.Waitlist, 1)
+	assert.Equal(t, 2, waitlist.Waitlist[0].Position)
+
+	w, _ = callGetWaitlist(t, outsider.ID, event.ID)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	t.Run("Leaving promotes the first waitlisted user", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, callLeaveEvent(t, first.ID, event.ID).Code)
+
+		assert.True(t, isParticipant(t, event.ID, second.ID))
+		assert.False(t, isParticipant(t, event.ID, third.ID))
+
+		var notification models.TaskStatusEvent
+		assert.NoError(t, test.TestDB.Where("user_id = ? AND event_id = ?", second.ID, event.ID).First(&notification).Error)
+		assert.Equal(t, models.ParticipationWaitlisted, notification.OldStatus)
+		assert.Equal(t, models.ParticipationJoined, notification.NewStatus)
+	})
+
+	t.Run("Declining promotes the next waitlisted user", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, callSetRSVP(t, second.ID, event.ID, api.RSVPRequest{Status: models.RSVPNotGoing}).Code)
+		assert.True(t, isParticipant(t, event.ID, third.ID))
+
+		// No place is left to change the answer back
+		assert.Equal(t, http.StatusConflict, callSetRSVP(t, second.ID, event.ID, api.RSVPRequest{Status: models.RSVPGoing}).Code)
+	})
+
+	t.Run("Waitlisted user can leave the waitlist", func(t *testing.T) {
+		fourth := test.CreateTestUser(t)
+		assert.Equal(t, http.StatusAccepted, callJoinEvent(t, fourth.ID, invitation.InviteCode).Code)
+		assert.Equal(t, http.StatusOK, callLeaveEvent(t, fourth.ID, event.ID).Code)
+
+		var count int64
+		test.TestDB.Model(&models.WaitlistEntry{}).Where("event_id = ?", event.ID).Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+}
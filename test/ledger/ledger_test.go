@@ -0,0 +1,97 @@
+package ledger_test
+
+import (
+	"itsplanned/ledger"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		total    int64
+		shares   []int64
+		expected []int64
+	}{
+		{name: "Even split", total: 900, shares: []int64{1, 1, 1}, expected: []int64{300, 300, 300}},
+		{name: "Leftover cent goes to the first", total: 1000, shares: []int64{1, 1, 1}, expected: []int64{334, 333, 333}},
+		{name: "Weighted", total: 1000, shares: []int64{2, 1, 1}, expected: []int64{500, 250, 250}},
+		{name: "Leftover goes to the largest remainder", total: 100, shares: []int64{1, 2}, expected: []int64{33, 67}},
+		{name: "Zero shares get nothing", total: 500, shares: []int64{0, 1}, expected: []int64{0, 500}},
+		{name: "No shares", total: 500, shares: []int64{0, 0}, expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parts := ledger.SplitByShares(tc.total, tc.shares)
+			assert.Equal(t, tc.expected, parts)
+		})
+	}
+
+	assert.Equal(t, []int64{34, 33, 33}, ledger.SplitEqual(100, 3))
+	assert.Nil(t, ledger.SplitEqual(100, 0))
+}
+
+func TestSettle(t *testing.T) {
+	testCases := []struct {
+		name     string
+		balances map[uint]int64
+		expected []ledger.Transfer
+	}{
+		{
+			name:     "Everybody is even",
+			balances: map[uint]int64{1: 0, 2: 0},
+			expected: nil,
+		},
+		{
+			name:     "One payer",
+			balances: map[uint]int64{1: 200, 2: -100, 3: -100},
+			expected: []ledger.Transfer{{From: 2, To: 1, Amount: 100}, {From: 3, To: 1, Amount: 100}},
+		},
+		{
+			name:     "Matching debts pay directly",
+			balances: map[uint]int64{1: 500, 2: 300, 3: -500, 4: -300},
+			expected: []ledger.Transfer{{From: 3, To: 1, Amount: 500}, {From: 4, To: 2, Amount: 300}},
+		},
+		{
+			name:     "Debtor pays two creditors",
+			balances: map[uint]int64{1: 700, 2: 100, 3: -600, 4: -200},
+			expected: []ledger.Transfer{{From: 3, To: 1, Amount: 600}, {From: 4, To: 1, Amount: 100}, {From: 4, To: 2, Amount: 100}},
+		},
+		{
+			name:     "Groups that add up to zero settle among themselves",
+			balances: map[uint]int64{1: -300, 2: 300, 3: 200, 4: -400, 5: 200},
+			expected: []ledger.Transfer{{From: 1, To: 2, Amount: 300}, {From: 4, To: 3, Amount: 200}, {From: 4, To: 5, Amount: 200}},
+		},
+		{
+			name:     "Fewest transfers for six people",
+			balances: map[uint]int64{1: 500, 2: 500, 3: -300, 4: -700, 5: 200, 6: -200},
+			expected: []ledger.Transfer{{From: 4, To: 1, Amount: 500}, {From: 3, To: 2, Amount: 300}, {From: 4, To: 2, Amount: 200}, {From: 6, To: 5, Amount: 200}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ledger.Settle(tc.balances))
+		})
+	}
+}
+
+func TestSettleManyPeople(t *testing.T) {
+	balances := map[uint]int64{}
+	for userID := uint(1); userID <= 30; userID++ {
+		balances[userID] = int64(userID) * 100
+	}
+	balances[31] = -46500
+
+	transfers := ledger.Settle(balances)
+	assert.Len(t, transfers, 30)
+	for _, transfer := range transfers {
+		balances[transfer.From] += transfer.Amount
+		balances[transfer.To] -= transfer.Amount
+	}
+	for userID, balance := range balances {
+		assert.Zero(t, balance, "user %d", userID)
+	}
+}
@@ -0,0 +1,47 @@
+package money_test
+
+import (
+	"itsplanned/money"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinorUnits(t *testing.T) {
+	testCases := []struct {
+		name     string
+		amount   float64
+		currency string
+		minor    int64
+	}{
+		{name: "Cents", amount: 42.5, currency: "USD", minor: 4250},
+		{name: "Rounded to the cent", amount: 0.1 + 0.2, currency: "EUR", minor: 30},
+		{name: "No minor units", amount: 1500, currency: "JPY", minor: 1500},
+		{name: "Three digits", amount: 1.234, currency: "KWD", minor: 1234},
+		{name: "Negative", amount: -12.34, currency: "RUB", minor: -1234},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.minor, money.ToMinor(tc.amount, tc.currency))
+			assert.InDelta(t, tc.amount, money.FromMinor(tc.minor, tc.currency), 0.005)
+		})
+	}
+}
+
+func TestCurrencies(t *testing.T) {
+	assert.Equal(t, "EUR", money.Normalize(" eur "))
+	assert.Equal(t, money.DefaultCurrency, money.Normalize(""))
+	assert.True(t, money.IsSupported("JPY"))
+	assert.False(t, money.IsSupported("XYZ"))
+	assert.False(t, money.IsSupported("usd"))
+}
+
+func TestRescaleAndConvert(t *testing.T) {
+	assert.Equal(t, int64(12), money.Rescale(1234, "USD", "JPY"))
+	assert.Equal(t, int64(1200), money.Rescale(12, "JPY", "USD"))
+	assert.Equal(t, int64(1234), money.Rescale(1234, "USD", "EUR"))
+
+	assert.Equal(t, int64(16200), money.Convert(10000, "USD", "JPY", 162))
+	assert.Equal(t, int64(333), money.Convert(1000, "EUR", "USD", 1/3.0))
+}
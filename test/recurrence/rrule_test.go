@@ -0,0 +1,161 @@
+package recurrence_test
+
+import (
+	"itsplanned/recurrence"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustTime(t *testing.T, value string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	assert.NoError(t, err)
+	return parsed
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name        string
+		rule        string
+		expectedErr bool
+		canonical   string
+	}{
+		{name: "Weekly with days", rule: "RRULE:FREQ=WEEKLY;BYDAY=MO,WE", canonical: "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{name: "Monthly last friday", rule: "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3", canonical: "FREQ=MONTHLY;COUNT=3;BYDAY=-1FR"},
+		{name: "Until date", rule: "FREQ=DAILY;INTERVAL=2;UNTIL=20240110", canonical: "FREQ=DAILY;INTERVAL=2;UNTIL=20240110T235959Z"},
+		{name: "Missing frequency", rule: "INTERVAL=2", expectedErr: true},
+		{name: "Unsupported frequency", rule: "FREQ=HOURLY", expectedErr: true},
+		{name: "Count with until", rule: "FREQ=DAILY;COUNT=2;UNTIL=20240110T000000Z", expectedErr: true},
+		{name: "Ordinal weekday on weekly rule", rule: "FREQ=WEEKLY;BYDAY=1MO", expectedErr: true},
+		{name: "Unsupported part", rule: "FREQ=DAILY;BYSETPOS=1", expectedErr: true},
+		{name: "Invalid month day", rule: "FREQ=MONTHLY;BYMONTHDAY=32", expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := recurrence.Parse(tc.rule)
+			if tc.expectedErr {
+				assert.ErrorIs(t, err, recurrence.ErrInvalidRule)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.canonical, rule.String())
+		})
+	}
+}
+
+func TestBetween(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rule     string
+		start    string
+		from     string
+		to       string
+		limit    int
+		expected []string
+	}{
+		{
+			name:  "Weekly standup on Monday and Wednesday",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4",
+			start: "2024-04-01T10:00:00Z",
+			from:  "2024-01-01T00:00:00Z",
+			to:    "2025-01-01T00:00:00Z",
+			expected: []string{
+				"2024-04-01T10:00:00Z",
+				"2024-04-03T10:00:00Z",
+				"2024-04-08T10:00:00Z",
+				"2024-04-10T10:00:00Z",
+			},
+		},
+		{
+			name:  "Every other day until a date",
+			rule:  "FREQ=DAILY;INTERVAL=2;UNTIL=20240407T100000Z",
+			start: "2024-04-01T10:00:00Z",
+			from:  "2024-01-01T00:00:00Z",
+			to:    "2025-01-01T00:00:00Z",
+			expected: []string{
+				"2024-04-01T10:00:00Z",
+				"2024-04-03T10:00:00Z",
+				"2024-04-05T10:00:00Z",
+				"2024-04-07T10:00:00Z",
+			},
+		},
+		{
+			name:  "Monthly on the 31st skips short months",
+			rule:  "FREQ=MONTHLY;COUNT=3",
+			start: "2024-01-31T18:00:00Z",
+			from:  "2024-01-01T00:00:00Z",
+			to:    "2025-01-01T00:00:00Z",
+			expected: []string{
+				"2024-01-31T18:00:00Z",
+				"2024-03-31T18:00:00Z",
+				"2024-05-31T18:00:00Z",
+			},
+		},
+		{
+			name:  "Monthly party on the last Friday",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR",
+			start: "2024-04-26T19:00:00Z",
+			from:  "2024-05-01T00:00:00Z",
+			to:    "2024-07-01T00:00:00Z",
+			expected: []string{
+				"2024-05-31T19:00:00Z",
+				"2024-06-28T19:00:00Z",
+			},
+		},
+		{
+			name:  "Yearly in March and September on the 1st",
+			rule:  "FREQ=YEARLY;BYMONTH=3,9;BYMONTHDAY=1",
+			start: "2024-03-01T12:00:00Z",
+			from:  "2024-01-01T00:00:00Z",
+			to:    "2025-06-01T00:00:00Z",
+			expected: []string{
+				"2024-03-01T12:00:00Z",
+				"2024-09-01T12:00:00Z",
+				"2025-03-01T12:00:00Z",
+			},
+		},
+		{
+			name:  "Open series is limited",
+			rule:  "FREQ=DAILY",
+			start: "2024-04-01T10:00:00Z",
+			from:  "2024-04-01T00:00:00Z",
+			to:    "2030-01-01T00:00:00Z",
+			limit: 2,
+			expected: []string{
+				"2024-04-01T10:00:00Z",
+				"2024-04-02T10:00:00Z",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := recurrence.Parse(tc.rule)
+			assert.NoError(t, err)
+
+			occurrences := rule.Between(mustTime(t, tc.start), mustTime(t, tc.from), mustTime(t, tc.to), tc.limit)
+
+			actual := make([]string, len(occurrences))
+			for i, occurrence := range occurrences {
+				actual[i] = occurrence.UTC().Format(time.RFC3339)
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestOccursAndCountBefore(t *testing.T) {
+	rule, err := recurrence.Parse("FREQ=WEEKLY;BYDAY=TU")
+	assert.NoError(t, err)
+
+	start := mustTime(t, "2024-04-02T09:00:00Z")
+	assert.True(t, rule.Occurs(start, mustTime(t, "2024-04-16T09:00:00Z")))
+	assert.False(t, rule.Occurs(start, mustTime(t, "2024-04-16T10:00:00Z")))
+	assert.False(t, rule.Occurs(start, mustTime(t, "2024-04-17T09:00:00Z")))
+	assert.Equal(t, 2, rule.CountBefore(start, mustTime(t, "2024-04-16T09:00:00Z")))
+
+	_, finite := rule.Last(start)
+	assert.False(t, finite)
+}
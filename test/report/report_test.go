@@ -0,0 +1,180 @@
+package report_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/xml"
+	"io"
+	"itsplanned/report"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const dejaVuSans = "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf"
+
+func sampleReport() *report.Report {
+	return &report.Report{
+		Title: "Budget report: Пикник",
+		Sections: []report.Section{
+			{
+				Title: "Summary",
+				Rows: [][]report.Cell{
+					report.Texts("Event", "Пикник"),
+					{report.Text("Initial budget"), report.Number(1000, 2)},
+				},
+			},
+			{
+				Title:   "Tasks",
+				Columns: []string{"Task", "Points", "Planned"},
+				Rows: [][]report.Cell{
+					{report.Text("Buy \"snacks\", drinks"), report.Int(10), report.Number(120.5, 2)},
+					{report.Text("=HYPERLINK(\"http://evil\")"), report.Int(5), report.Number(0, 2)},
+				},
+				Total: []report.Cell{report.Text("Total"), report.Int(15), report.Number(120.5, 2)},
+			},
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, report.WriteCSV(&buf, sampleReport()))
+
+	expected := "\ufeffSummary\n" +
+		"Event,Пикник\n" +
+		"Initial budget,1000.00\n" +
+		"\n" +
+		"Tasks\n" +
+		"Task,Points,Planned\n" +
+		"\"Buy \"\"snacks\"\", drinks\",10,120.50\n" +
+		"\"'=HYPERLINK(\"\"http://evil\"\")\",5,0.00\n" +
+		"Total,15,120.50\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, report.WriteXLSX(&buf, sampleReport()))
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+
+	files := map[string]string{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		assert.NoError(t, err)
+		data, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		reader.Close()
+
+		// Every part has to be well formed XML
+		decoder := xml.NewDecoder(bytes.NewReader(data))
+		for {
+			if _, err := decoder.Token(); err != nil {
+				assert.ErrorIs(t, err, io.EOF, file.Name)
+				break
+			}
+		}
+		files[file.Name] = string(data)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		assert.Contains(t, files, name)
+	}
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="Summary" sheetId="1" r:id="rId1"/>`)
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="Tasks" sheetId="2" r:id="rId2"/>`)
+
+	tasks := files["xl/worksheets/sheet2.xml"]
+	// The header is bold text, amounts are numbers with two decimals
+	assert.Contains(t, tasks, `<c r="A1" s="4" t="inlineStr"><is><t xml:space="preserve">Task</t></is></c>`)
+	assert.Contains(t, tasks, `<c r="A2" s="0" t="inlineStr"><is><t xml:space="preserve">Buy &#34;snacks&#34;, drinks</t></is></c>`)
+	assert.Contains(t, tasks, `<c r="B2" s="1"><v>10</v></c>`)
+	assert.Contains(t, tasks, `<c r="C2" s="2"><v>120.50</v></c>`)
+	assert.Contains(t, tasks, `<c r="C4" s="6"><v>120.50</v></c>`)
+	assert.Contains(t, tasks, `state="frozen"`)
+	assert.Contains(t, files["xl/worksheets/sheet1.xml"], `<t xml:space="preserve">Пикник</t>`)
+}
+
+// checkPDF verifies the cross-reference table and returns the decompressed content streams
+func checkPDF(t *testing.T, data []byte) []string {
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(data, []byte("%%EOF\n")))
+
+	match := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	assert.NotNil(t, match)
+	xref, _ := strconv.Atoi(string(match[1]))
+	assert.True(t, bytes.HasPrefix(data[xref:], []byte("xref\n")))
+
+	lines := strings.Split(string(data[xref:]), "\n")
+	count, _ := strconv.Atoi(strings.Fields(lines[1])[1])
+	for i := 1; i < count; i++ {
+		offset, err := strconv.Atoi(lines[2+i][:10])
+		assert.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(data[offset:], []byte(strconv.Itoa(i)+" 0 obj\n")), "object %d", i)
+	}
+
+	var contents []string
+	streams := regexp.MustCompile(`(?s)<< ([^\n]*?)>>\nstream\n(.*?)\nendstream`).FindAllSubmatch(data, -1)
+	for _, stream := range streams {
+		reader, err := zlib.NewReader(bytes.NewReader(stream[2]))
+		assert.NoError(t, err)
+		content, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		if bytes.Contains(content, []byte(" Tj ET")) {
+			contents = append(contents, string(content))
+		}
+	}
+	return contents
+}
+
+func TestWritePDFWithStandardFont(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, report.WritePDF(&buf, sampleReport(), nil))
+
+	pages := checkPDF(t, buf.Bytes())
+	assert.Len(t, pages, 1)
+	assert.Contains(t, buf.String(), "/BaseFont /Helvetica /Encoding /WinAnsiEncoding")
+	assert.Contains(t, pages[0], "(Buy \"snacks\", drinks) Tj")
+	// Helvetica has no Cyrillic letters
+	assert.Contains(t, pages[0], "(Budget report: ??????) Tj")
+	assert.Contains(t, pages[0], "(Page 1 of 1) Tj")
+}
+
+func TestWritePDFWithEmbeddedFont(t *testing.T) {
+	if _, err := os.Stat(dejaVuSans); err != nil {
+		t.Skip("DejaVu Sans is not installed")
+	}
+	font, err := report.LoadFont(dejaVuSans)
+	assert.NoError(t, err)
+
+	long := sampleReport()
+	for i := 0; i < 80; i++ {
+		long.Sections[1].Rows = append(long.Sections[1].Rows, []report.Cell{report.Text(strings.Repeat("Очень длинное название задачи ", 10)), report.Int(1), report.Number(1, 2)})
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, report.WritePDF(&buf, long, font))
+
+	pages := checkPDF(t, buf.Bytes())
+	assert.Len(t, pages, 3)
+	assert.Contains(t, buf.String(), "/Subtype /Type0 /BaseFont /DejaVuSans /Encoding /Identity-H")
+	assert.Contains(t, buf.String(), "/FontFile2")
+	assert.Contains(t, pages[2], "Tj ET")
+	// Glyph IDs of "Пикник" in DejaVu Sans
+	assert.Contains(t, pages[0], "<03B403CD03CF03D203CD03CF> Tj")
+	// The header row repeats on every page
+	for _, page := range pages {
+		assert.Contains(t, page, "<003700440056004E> Tj")
+	}
+}
+
+func TestParseFontRejectsOtherFiles(t *testing.T) {
+	_, err := report.ParseFont([]byte("%PDF-1.4 not a font at all"))
+	assert.Error(t, err)
+}
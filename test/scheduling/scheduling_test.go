@@ -0,0 +1,186 @@
+package scheduling_test
+
+import (
+	"itsplanned/scheduling"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func at(t *testing.T, clock string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, "2024-04-01T"+clock+":00Z")
+	assert.NoError(t, err)
+	return parsed
+}
+
+func interval(t *testing.T, start, end string) scheduling.Interval {
+	return scheduling.Interval{Start: at(t, start), End: at(t, end)}
+}
+
+func TestSuggest(t *testing.T) {
+	testCases := []struct {
+		name         string
+		busy         map[uint][]scheduling.Interval
+		window       [2]string
+		durationMins int
+		stepMins     int
+		expected     map[string][]uint
+	}{
+		{
+			name:         "Long event is scored over its whole duration",
+			busy:         map[uint][]scheduling.Interval{1: {interval(t, "11:30", "12:00")}},
+			window:       [2]string{"09:00", "13:00"},
+			durationMins: 180,
+			stepMins:     30,
+			expected: map[string][]uint{
+				"09:00": {1},
+				"09:30": {1},
+				"10:00": {1},
+			},
+		},
+		{
+			name:         "Busy interval ending at the start does not block",
+			busy:         map[uint][]scheduling.Interval{1: {interval(t, "09:00", "10:00")}},
+			window:       [2]string{"09:00", "11:00"},
+			durationMins: 60,
+			stepMins:     60,
+			expected: map[string][]uint{
+				"09:00": {1},
+				"10:00": {},
+			},
+		},
+		{
+			name:         "Busy interval starting at the end does not block",
+			busy:         map[uint][]scheduling.Interval{1: {interval(t, "10:00", "10:15")}},
+			window:       [2]string{"09:00", "10:30"},
+			durationMins: 60,
+			stepMins:     15,
+			expected: map[string][]uint{
+				"09:00": {},
+				"09:15": {1},
+				"09:30": {1},
+			},
+		},
+		{
+			name: "Unaligned busy intervals are not widened",
+			busy: map[uint][]scheduling.Interval{
+				1: {interval(t, "09:10", "09:20")},
+				2: {interval(t, "09:40", "10:05")},
+			},
+			window:       [2]string{"09:00", "10:30"},
+			durationMins: 30,
+			stepMins:     30,
+			expected: map[string][]uint{
+				"09:00": {1},
+				"09:30": {2},
+				"10:00": {2},
+			},
+		},
+		{
+			name:         "Overlapping events of one participant count once",
+			busy:         map[uint][]scheduling.Interval{1: {interval(t, "09:00", "10:00"), interval(t, "09:30", "10:30")}},
+			window:       [2]string{"09:00", "10:00"},
+			durationMins: 60,
+			stepMins:     60,
+			expected: map[string][]uint{
+				"09:00": {1},
+			},
+		},
+		{
+			name:         "Event longer than the window has no slots",
+			busy:         map[uint][]scheduling.Interval{},
+			window:       [2]string{"09:00", "10:00"},
+			durationMins: 90,
+			stepMins:     30,
+			expected:     map[string][]uint{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var participants []scheduling.Participant
+			for _, userID := range []uint{1, 2} {
+				participants = append(participants, scheduling.Participant{UserID: userID, Busy: tc.busy[userID]})
+			}
+
+			slots := scheduling.Suggest(participants, interval(t, tc.window[0], tc.window[1]),
+				time.Duration(tc.durationMins)*time.Minute, time.Duration(tc.stepMins)*time.Minute)
+
+			actual := map[string][]uint{}
+			for _, slot := range slots {
+				actual[slot.Start.Format("15:04")] = slot.BusyUserIDs
+				assert.Equal(t, time.Duration(tc.durationMins)*time.Minute, slot.End.Sub(slot.Start))
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	busyAtNine := []scheduling.Interval{interval(t, "09:00", "10:00")}
+	busyAtTen := []scheduling.Interval{interval(t, "10:00", "11:00")}
+	busyAtEleven := []scheduling.Interval{interval(t, "11:00", "12:00")}
+
+	testCases := []struct {
+		name         string
+		participants []scheduling.Participant
+		expected     []string
+	}{
+		{
+			name: "Fewer busy participants first, then earlier",
+			participants: []scheduling.Participant{
+				{UserID: 1, Busy: busyAtNine},
+				{UserID: 2, Busy: append(busyAtNine, busyAtTen...)},
+			},
+			expected: []string{"11:00", "10:00", "09:00"},
+		},
+		{
+			name: "Busy optional participant beats busy required participant",
+			participants: []scheduling.Participant{
+				{UserID: 1, Busy: busyAtNine, Optional: true},
+				{UserID: 2, Busy: append(busyAtTen, busyAtEleven...)},
+				{UserID: 3, Busy: busyAtNine, Optional: true},
+			},
+			expected: []string{"09:00", "10:00", "11:00"},
+		},
+		{
+			name: "Weights decide between equally busy slots",
+			participants: []scheduling.Participant{
+				{UserID: 1, Busy: busyAtNine, Weight: 3},
+				{UserID: 2, Busy: busyAtTen, Weight: 0.5},
+				{UserID: 3, Busy: busyAtEleven},
+			},
+			expected: []string{"10:00", "11:00", "09:00"},
+		},
+		{
+			name: "Preferred intervals decide between equally free slots",
+			participants: []scheduling.Participant{
+				{UserID: 1, Preferred: []scheduling.Interval{interval(t, "10:30", "12:00")}},
+				{UserID: 2, Preferred: []scheduling.Interval{interval(t, "10:00", "12:00")}},
+				{UserID: 3},
+			},
+			expected: []string{"11:00", "10:00", "09:00"},
+		},
+		{
+			name: "Preference does not outweigh a busy participant",
+			participants: []scheduling.Participant{
+				{UserID: 1, Busy: busyAtEleven, Preferred: []scheduling.Interval{interval(t, "11:00", "12:00")}},
+			},
+			expected: []string{"09:00", "10:00", "11:00"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			slots := scheduling.Suggest(tc.participants, interval(t, "09:00", "12:00"), time.Hour, time.Hour)
+			scheduling.Rank(slots)
+
+			var actual []string
+			for _, slot := range slots {
+				actual = append(actual, slot.Start.Format("15:04"))
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package storage_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"itsplanned/services/storage"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testBackend(t *testing.T, backend storage.Backend) {
+	ctx := context.Background()
+
+	assert.NoError(t, backend.Put(ctx, "events/1/receipt.jpg", []byte("first"), "image/jpeg"))
+	assert.NoError(t, backend.Put(ctx, "events/1/receipt.jpg", []byte("second"), "image/jpeg"))
+
+	reader, err := backend.Get(ctx, "events/1/receipt.jpg")
+	assert.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+
+	_, err = backend.Get(ctx, "events/1/missing.jpg")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	assert.NoError(t, backend.Delete(ctx, "events/1/receipt.jpg"))
+	_, err = backend.Get(ctx, "events/1/receipt.jpg")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Deleting twice is fine
+	assert.NoError(t, backend.Delete(ctx, "events/1/receipt.jpg"))
+}
+
+func TestLocalStorage(t *testing.T) {
+	backend := &storage.LocalStorage{Root: t.TempDir()}
+	testBackend(t, backend)
+
+	for _, key := range []string{"", "../outside", "events/../../outside", "/etc/passwd", "events//file", "events\\file"} {
+		assert.Error(t, backend.Put(context.Background(), key, []byte("data"), "text/plain"), key)
+	}
+}
+
+// fakeS3 keeps objects in memory and checks the signature of every request like S3 does
+type fakeS3 struct {
+	accessKey string
+	secretKey string
+	region    string
+	mu        sync.Mutex
+	objects   map[string][]byte
+}
+
+func (s *fakeS3) signature(r *http.Request, payloadHash string) string {
+	amzDate := r.Header.Get("X-Amz-Date")
+	date := amzDate[:8]
+	canonicalRequest := r.Method + "\n" + r.URL.EscapedPath() + "\n\n" +
+		"host:" + r.Host + "\nx-amz-content-sha256:" + payloadHash + "\nx-amz-date:" + amzDate + "\n\n" +
+		"host;x-amz-content-sha256;x-amz-date\n" + payloadHash
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + date + "/" + s.region + "/s3/aws4_request\n" + hex.EncodeToString(hash[:])
+
+	key := []byte("AWS4" + s.secretKey)
+	for _, part := range []string{date, s.region, "s3", "aws4_request", stringToSign} {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(part))
+		key = mac.Sum(nil)
+	}
+	return hex.EncodeToString(key)
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+
+	auth := r.Header.Get("Authorization")
+	if r.Header.Get("X-Amz-Content-Sha256") != payloadHash ||
+		!strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential="+s.accessKey+"/") ||
+		!strings.HasSuffix(auth, "Signature="+s.signature(r, payloadHash)) {
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, "<Error><Code>SignatureDoesNotMatch</Code></Error>")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		s.objects[r.URL.Path] = body
+	case http.MethodGet:
+		data, ok := s.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, "<Error><Code>NoSuchKey</Code></Error>")
+			return
+		}
+		w.Write(data)
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	fake := &fakeS3{accessKey: "minioadmin", secretKey: "minio-secret", region: "us-east-1", objects: map[string][]byte{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	backend := &storage.S3Storage{
+		Endpoint:  server.URL,
+		Region:    "us-east-1",
+		Bucket:    "itsplanned",
+		AccessKey: "minioadmin",
+		SecretKey: "minio-secret",
+	}
+	testBackend(t, backend)
+
+	assert.NoError(t, backend.Put(context.Background(), "events/2/scan (1).pdf", []byte("pdf"), "application/pdf"))
+	assert.Contains(t, fake.objects, "/itsplanned/events/2/scan (1).pdf")
+
+	wrongSecret := *backend
+	wrongSecret.SecretKey = "wrong"
+	err := wrongSecret.Put(context.Background(), "events/2/receipt.jpg", []byte("data"), "image/jpeg")
+	assert.ErrorContains(t, err, "status 403")
+}
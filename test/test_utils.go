@@ -1,9 +1,13 @@
 package test
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"itsplanned/common"
 	"itsplanned/models"
+	"itsplanned/models/api"
 	"net/http/httptest"
 	"testing"
 	"time"
@@ -27,11 +31,32 @@ func SetupTestDB(t *testing.T) func() {
 	// Run migrations
 	err = db.AutoMigrate(
 		&models.User{},
+		&models.Session{},
 		&models.Event{},
 		&models.Task{},
 		&models.UserToken{},
 		&models.EventInvitation{},
 		&models.EventParticipation{},
+		&models.EventOccurrenceOverride{},
+		&models.EventTemplate{},
+		&models.EventTemplateTask{},
+		&models.Availability{},
+		&models.TimePoll{},
+		&models.TimePollOption{},
+		&models.TimePollVote{},
+		&models.Poll{},
+		&models.PollOption{},
+		&models.PollVote{},
+		&models.Expense{},
+		&models.ExpenseShare{},
+		&models.Settlement{},
+		&models.BudgetCategory{},
+		&models.Attachment{},
+		&models.SentReminder{},
+		&models.WaitlistEntry{},
+		&models.EmailInvitation{},
+		&models.JoinRequest{},
+		&models.EventOwnershipTransfer{},
 		&models.PasswordReset{},
 		&models.TaskStatusEvent{},
 		&models.AIChat{},
@@ -82,7 +107,7 @@ func CreateTestEvent(t *testing.T, organizerID uint) *models.Event {
 		Name:          "Test Event",
 		Description:   "Test Description",
 		EventDateTime: eventTime,
-		InitialBudget: 1000.0,
+		InitialBudget: 100000,
 		OrganizerID:   organizerID,
 		Place:         "Test Place",
 	}
@@ -95,6 +120,7 @@ func CreateTestEvent(t *testing.T, organizerID uint) *models.Event {
 	participation := &models.EventParticipation{
 		EventID: event.ID,
 		UserID:  organizerID,
+		Role:    models.RoleOrganizer,
 	}
 
 	if err := TestDB.Create(participation).Error; err != nil {
@@ -109,7 +135,7 @@ func CreateTestTask(t *testing.T, eventID uint) *models.Task {
 	task := &models.Task{
 		Title:       "Test Task",
 		Description: "Test Description",
-		Budget:      100.0,
+		Budget:      10000,
 		Points:      10,
 		EventID:     eventID,
 	}
@@ -130,11 +156,46 @@ func CreateTestContext(t *testing.T, userID uint) (*gin.Context, *httptest.Respo
 	return c, w
 }
 
+// CallHandler calls the handler as the user with the body sent as JSON, a nil body sends none.
+// The response is decoded as an APIResponse
+func CallHandler(t *testing.T, userID uint, method, path string, params gin.Params, body interface{}, handler func(*gin.Context, *gorm.DB)) (*httptest.ResponseRecorder, api.APIResponse) {
+	c, w := CreateTestContext(t, userID)
+
+	var requestBody io.Reader
+	if body != nil {
+		requestJSON, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("Failed to encode request body: %v", err)
+		}
+		requestBody = bytes.NewBuffer(requestJSON)
+	}
+
+	c.Request = httptest.NewRequest(method, path, requestBody)
+	if body != nil {
+		c.Request.Header.Set("Content-Type", "application/json")
+	}
+	c.Params = params
+
+	handler(c, TestDB)
+
+	var response api.APIResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Errorf("Failed to decode response %q: %v", w.Body.String(), err)
+	}
+	return w, response
+}
+
 // AddEventParticipant adds a user as a participant to an event
 func AddEventParticipant(t *testing.T, eventID, userID uint) {
+	AddEventParticipantWithRole(t, eventID, userID, models.RoleParticipant)
+}
+
+// AddEventParticipantWithRole adds a user to an event with the given event role
+func AddEventParticipantWithRole(t *testing.T, eventID, userID uint, role string) {
 	participation := &models.EventParticipation{
 		EventID: eventID,
 		UserID:  userID,
+		Role:    role,
 	}
 
 	if err := TestDB.Create(participation).Error; err != nil {
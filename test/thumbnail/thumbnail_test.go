@@ -0,0 +1,97 @@
+package thumbnail_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"itsplanned/thumbnail"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	encodePNG := func(width, height int) []byte {
+		img := image.NewNRGBA(image.Rect(0, 0, width, height))
+		for i := range img.Pix {
+			img.Pix[i] = 200
+		}
+		var buf bytes.Buffer
+		assert.NoError(t, png.Encode(&buf, img))
+		return buf.Bytes()
+	}
+	encodeGIF := func(width, height int) []byte {
+		img := image.NewPaletted(image.Rect(0, 0, width, height), color.Palette{color.Black, color.White})
+		var buf bytes.Buffer
+		assert.NoError(t, gif.Encode(&buf, img, nil))
+		return buf.Bytes()
+	}
+
+	testCases := []struct {
+		name           string
+		data           []byte
+		expectedWidth  int
+		expectedHeight int
+		wantErr        bool
+	}{
+		{name: "Landscape", data: encodePNG(1000, 500), expectedWidth: 100, expectedHeight: 50},
+		{name: "Portrait", data: encodeGIF(30, 300), expectedWidth: 10, expectedHeight: 100},
+		{name: "Thin stripe keeps a pixel", data: encodePNG(1000, 2), expectedWidth: 100, expectedHeight: 1},
+		{name: "Small images keep their size", data: encodePNG(40, 20), expectedWidth: 40, expectedHeight: 20},
+		{name: "Not an image", data: []byte("%PDF-1.4"), wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			thumb, err := thumbnail.Generate(tc.data, 100)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			img, err := jpeg.Decode(bytes.NewReader(thumb))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedWidth, img.Bounds().Dx())
+			assert.Equal(t, tc.expectedHeight, img.Bounds().Dy())
+		})
+	}
+}
+
+func TestGenerateAveragesPixels(t *testing.T) {
+	// Black and white columns average out to grey
+	img := image.NewGray(image.Rect(0, 0, 200, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 200; x += 2 {
+			img.SetGray(x, y, color.Gray{Y: 255})
+		}
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+
+	thumb, err := thumbnail.Generate(buf.Bytes(), 50)
+	assert.NoError(t, err)
+	decoded, err := jpeg.Decode(bytes.NewReader(thumb))
+	assert.NoError(t, err)
+
+	r, _, _, _ := decoded.At(25, 25).RGBA()
+	assert.InDelta(t, 127, r>>8, 3)
+}
+
+func TestGenerateRejectsHugeImages(t *testing.T) {
+	// Only the header is read, the pixels are never decoded
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))))
+	data := buf.Bytes()
+	// Patch the size in the IHDR chunk to 100000x100000 and fix its checksum
+	copy(data[16:24], []byte{0, 1, 0x86, 0xa0, 0, 1, 0x86, 0xa0})
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+
+	_, err := thumbnail.Generate(data, 100)
+	assert.ErrorIs(t, err, thumbnail.ErrTooLarge)
+}
@@ -0,0 +1,109 @@
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+)
+
+// maxPixels keeps huge or malicious images from taking all the memory while decoding,
+// a decoded image takes 4 to 8 bytes per pixel
+const maxPixels = 25_000_000
+
+// slots limits how many images are decoded at the same time
+var slots = make(chan struct{}, 2)
+
+// ErrTooLarge is returned for images with more than maxPixels pixels
+var ErrTooLarge = errors.New("image is too large")
+
+// Supported reports whether thumbnails can be made for images of the content type,
+// the standard library has no WebP decoder so WebP images go without one
+func Supported(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+// Generate scales the image down to fit into a size x size square and encodes it as a JPEG.
+// Images that already fit keep their size.
+func Generate(data []byte, size int) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, errors.New("image is empty")
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+
+	slots <- struct{}{}
+	defer func() { <-slots }()
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, resize(src, size), &jpeg.Options{Quality: 80}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// resize shrinks the image keeping its aspect ratio, every pixel of the result averages the box of source pixels it covers
+func resize(src image.Image, size int) *image.RGBA {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	dstWidth, dstHeight := width, height
+	if width > size || height > size {
+		if width >= height {
+			dstWidth, dstHeight = size, max(1, height*size/width)
+		} else {
+			dstWidth, dstHeight = max(1, width*size/height), size
+		}
+	}
+
+	// The source is converted one row at a time, a full size copy of a large photo would take hundreds of megabytes
+	row := image.NewRGBA(image.Rect(0, 0, width, 1))
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	sums := make([]int, dstWidth*3)
+	for y := 0; y < dstHeight; y++ {
+		y0, y1 := y*height/dstHeight, max((y+1)*height/dstHeight, y*height/dstHeight+1)
+		clear(sums)
+		for sy := y0; sy < y1; sy++ {
+			// Transparent parts turn white instead of black in the JPEG
+			draw.Draw(row, row.Bounds(), image.White, image.Point{}, draw.Src)
+			draw.Draw(row, row.Bounds(), src, image.Pt(bounds.Min.X, bounds.Min.Y+sy), draw.Over)
+			for x := 0; x < dstWidth; x++ {
+				x0, x1 := x*width/dstWidth, max((x+1)*width/dstWidth, x*width/dstWidth+1)
+				for sx := x0; sx < x1; sx++ {
+					sums[x*3] += int(row.Pix[sx*4])
+					sums[x*3+1] += int(row.Pix[sx*4+1])
+					sums[x*3+2] += int(row.Pix[sx*4+2])
+				}
+			}
+		}
+
+		for x := 0; x < dstWidth; x++ {
+			x0, x1 := x*width/dstWidth, max((x+1)*width/dstWidth, x*width/dstWidth+1)
+			count := (y1 - y0) * (x1 - x0)
+			offset := y*dst.Stride + x*4
+			dst.Pix[offset] = uint8(sums[x*3] / count)
+			dst.Pix[offset+1] = uint8(sums[x*3+1] / count)
+			dst.Pix[offset+2] = uint8(sums[x*3+2] / count)
+			dst.Pix[offset+3] = 0xff
+		}
+	}
+	return dst
+}